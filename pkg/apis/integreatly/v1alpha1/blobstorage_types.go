@@ -7,11 +7,39 @@ import (
 
 // BlobStorageSpec defines the desired state of BlobStorage
 // +k8s:openapi-gen=true
-type BlobStorageSpec types.ResourceTypeSpec
+type BlobStorageSpec struct {
+	Type       string           `json:"type"`
+	Tier       string           `json:"tier"`
+	SkipCreate bool             `json:"skipCreate,omitempty"`
+	SecretRef  *types.SecretRef `json:"secretRef"`
+	// RotationInterval is how often the credentials of the resource are rotated, e.g. 720h. rotation is disabled if not set
+	RotationInterval string `json:"rotationInterval,omitempty"`
+	// AdoptIdentifier is the identifier of an existing cloud resource to adopt instead of creating a new one. the
+	// resource is verified and managed like a resource created by the operator once adopted
+	AdoptIdentifier string `json:"adoptIdentifier,omitempty"`
+	// DeletionPolicy determines what happens to the cloud resource when the resource is deleted, one of Delete, Retain
+	// or DelayedDelete. the cloud resource is deleted as described by the tier if not set
+	// +kubebuilder:validation:Enum=Delete;Retain;DelayedDelete
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+	// DeletionGracePeriod is how long the deletion of the cloud resource is delayed by the DelayedDelete deletion
+	// policy, e.g. 72h. defaults to 7 days
+	DeletionGracePeriod string `json:"deletionGracePeriod,omitempty"`
+	// CORSRules are the cross-origin resource sharing rules which allow browsers to access the bucket from other
+	// origins. the cors rules of the tier are used if not set
+	CORSRules []types.CORSRule `json:"corsRules,omitempty"`
+	// Credentials are additional named credentials of the bucket with narrower access, each written to its own secret
+	Credentials []types.ResourceCredential `json:"credentials,omitempty"`
+}
 
 // BlobStorageStatus defines the observed state of BlobStorage
 // +k8s:openapi-gen=true
-type BlobStorageStatus types.ResourceTypeStatus
+type BlobStorageStatus struct {
+	types.ResourceTypeStatus `json:",inline"`
+	// Deletion is the progress of emptying the bucket before it is deleted
+	Deletion *types.ResourceDeletionStatus `json:"deletion,omitempty"`
+	// Credentials are the secrets the named credentials of the bucket are written to
+	Credentials []types.ResourceCredentialStatus `json:"credentials,omitempty"`
+}
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
@@ -7,7 +7,30 @@ import (
 
 // PostgresSpec defines the desired state of Postgres
 // +k8s:openapi-gen=true
-type PostgresSpec types.ResourceTypeSpec
+type PostgresSpec struct {
+	Type       string           `json:"type"`
+	Tier       string           `json:"tier"`
+	SkipCreate bool             `json:"skipCreate,omitempty"`
+	SecretRef  *types.SecretRef `json:"secretRef"`
+	// RotationInterval is how often the credentials of the resource are rotated, e.g. 720h. rotation is disabled if not set
+	RotationInterval string `json:"rotationInterval,omitempty"`
+	// MaintenanceWindow is the weekly window pending maintenance is applied in, in the format ddd:hh24:mi-ddd:hh24:mi
+	// (UTC). it overrides the maintenance window of the tier
+	MaintenanceWindow string `json:"maintenanceWindow,omitempty"`
+	// BackupWindow is the daily window automated backups are taken in, in the format hh24:mi-hh24:mi (UTC). it overrides
+	// the backup window of the tier
+	BackupWindow string `json:"backupWindow,omitempty"`
+	// AdoptIdentifier is the identifier of an existing cloud resource to adopt instead of creating a new one. the
+	// resource is verified and managed like a resource created by the operator once adopted
+	AdoptIdentifier string `json:"adoptIdentifier,omitempty"`
+	// DeletionPolicy determines what happens to the cloud resource when the resource is deleted, one of Delete,
+	// Snapshot, Retain or DelayedDelete. the cloud resource is deleted as described by the tier if not set
+	// +kubebuilder:validation:Enum=Delete;Snapshot;Retain;DelayedDelete
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+	// DeletionGracePeriod is how long the deletion of the cloud resource is delayed by the DelayedDelete deletion
+	// policy, e.g. 72h. defaults to 7 days
+	DeletionGracePeriod string `json:"deletionGracePeriod,omitempty"`
+}
 
 // PostgresStatus defines the observed state of Postgres
 // +k8s:openapi-gen=true
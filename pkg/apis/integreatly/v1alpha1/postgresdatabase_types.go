@@ -0,0 +1,46 @@
+package v1alpha1
+
+import (
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PostgresDatabaseSpec defines the desired state of PostgresDatabase
+// +k8s:openapi-gen=true
+type PostgresDatabaseSpec struct {
+	// ResourceName is the name of the Postgres resource the database is created in
+	ResourceName string `json:"resourceName"`
+	// DatabaseName is the name of the logical database, derived from the resource name if not set
+	DatabaseName string           `json:"databaseName,omitempty"`
+	SecretRef    *types.SecretRef `json:"secretRef"`
+}
+
+// PostgresDatabaseStatus defines the observed state of PostgresDatabase
+// +k8s:openapi-gen=true
+type PostgresDatabaseStatus types.ResourceTypeStatus
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PostgresDatabase is the Schema for the postgresdatabases API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+type PostgresDatabase struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PostgresDatabaseSpec   `json:"spec,omitempty"`
+	Status PostgresDatabaseStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PostgresDatabaseList contains a list of PostgresDatabase
+type PostgresDatabaseList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PostgresDatabase `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PostgresDatabase{}, &PostgresDatabaseList{})
+}
@@ -0,0 +1,51 @@
+package v1alpha1
+
+import (
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PostgresRestoreSpec defines the desired state of PostgresRestore
+// +k8s:openapi-gen=true
+type PostgresRestoreSpec struct {
+	// ResourceName is the name of the Postgres resource to restore from
+	ResourceName string `json:"resourceName"`
+	// RestoreTime is the time to restore to, in RFC3339 format
+	RestoreTime string `json:"restoreTime,omitempty"`
+	// UseLatestRestorableTime restores to the latest restorable time instead of RestoreTime
+	UseLatestRestorableTime bool `json:"useLatestRestorableTime,omitempty"`
+	// TargetName is the name of the Postgres resource created to manage the restored instance
+	TargetName string `json:"targetName"`
+	// SecretRef is the result secret of the Postgres resource created to manage the restored instance
+	SecretRef *types.SecretRef `json:"secretRef"`
+}
+
+// PostgresRestoreStatus defines the observed state of PostgresRestore
+// +k8s:openapi-gen=true
+type PostgresRestoreStatus types.ResourceTypeStatus
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PostgresRestore is the Schema for the postgresrestores API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+type PostgresRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PostgresRestoreSpec   `json:"spec,omitempty"`
+	Status PostgresRestoreStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PostgresRestoreList contains a list of PostgresRestore
+type PostgresRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PostgresRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PostgresRestore{}, &PostgresRestoreList{})
+}
@@ -15,6 +15,17 @@ type PostgresSnapshotSpec struct {
 	// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
 	// Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html
 	ResourceName string `json:"resourceName"`
+	// CopyRegion is the region a copy of the snapshot is made in, no copy is made when empty
+	CopyRegion string `json:"copyRegion,omitempty"`
+	// CopyKMSKeyID is the kms key in the copy region used to encrypt the copy of an encrypted snapshot, the aws managed
+	// rds key is used when empty
+	CopyKMSKeyID string `json:"copyKMSKeyID,omitempty"`
+	// SnapshotID is the identifier of an existing snapshot, such as the final snapshot taken when an instance is
+	// deleted, which the cr refers to instead of taking a new snapshot
+	SnapshotID string `json:"snapshotID,omitempty"`
+	// Region is the region the snapshot is taken in, it is recorded when the snapshot is taken so the snapshot can be
+	// found once its resource is deleted. the region of the default strategy is used when empty
+	Region string `json:"region,omitempty"`
 }
 
 // PostgresSnapshotStatus defines the observed state of PostgresSnapshot
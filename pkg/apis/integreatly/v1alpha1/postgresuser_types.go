@@ -0,0 +1,53 @@
+package v1alpha1
+
+import (
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PostgresUserSpec defines the desired state of PostgresUser
+// +k8s:openapi-gen=true
+type PostgresUserSpec struct {
+	// ResourceName is the name of the Postgres resource the user is created in
+	ResourceName string `json:"resourceName"`
+	// DatabaseName is the logical database the user is granted access to
+	DatabaseName string `json:"databaseName"`
+	// Username is the name of the role, derived from the resource name if not set
+	Username string `json:"username,omitempty"`
+	// ReadOnly limits the user to reading tables in the database
+	ReadOnly bool `json:"readOnly,omitempty"`
+	// IAMAuthentication authenticates the user with aws credentials instead of a password, only supported by the aws
+	// strategy on instances with iam database authentication enabled
+	IAMAuthentication bool             `json:"iamAuthentication,omitempty"`
+	SecretRef         *types.SecretRef `json:"secretRef"`
+}
+
+// PostgresUserStatus defines the observed state of PostgresUser
+// +k8s:openapi-gen=true
+type PostgresUserStatus types.ResourceTypeStatus
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PostgresUser is the Schema for the postgresusers API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+type PostgresUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PostgresUserSpec   `json:"spec,omitempty"`
+	Status PostgresUserStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PostgresUserList contains a list of PostgresUser
+type PostgresUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PostgresUser `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PostgresUser{}, &PostgresUserList{})
+}
@@ -7,11 +7,41 @@ import (
 
 // RedisSpec defines the desired state of Redis
 // +k8s:openapi-gen=true
-type RedisSpec types.ResourceTypeSpec
+type RedisSpec struct {
+	Type       string           `json:"type"`
+	Tier       string           `json:"tier"`
+	SkipCreate bool             `json:"skipCreate,omitempty"`
+	SecretRef  *types.SecretRef `json:"secretRef"`
+	// MaintenanceWindow is the weekly window pending maintenance is applied in, in the format ddd:hh24:mi-ddd:hh24:mi
+	// (UTC). it overrides the maintenance window of the tier
+	MaintenanceWindow string `json:"maintenanceWindow,omitempty"`
+	// BackupWindow is the daily window automated backups are taken in, in the format hh24:mi-hh24:mi (UTC). it overrides
+	// the backup window of the tier
+	BackupWindow string `json:"backupWindow,omitempty"`
+	// AdoptIdentifier is the identifier of an existing cloud resource to adopt instead of creating a new one. the
+	// resource is verified and managed like a resource created by the operator once adopted
+	AdoptIdentifier string `json:"adoptIdentifier,omitempty"`
+	// DeletionPolicy determines what happens to the cloud resource when the resource is deleted, one of Delete,
+	// Snapshot, Retain or DelayedDelete. the cloud resource is deleted as described by the tier if not set
+	// +kubebuilder:validation:Enum=Delete;Snapshot;Retain;DelayedDelete
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+	// DeletionGracePeriod is how long the deletion of the cloud resource is delayed by the DelayedDelete deletion
+	// policy, e.g. 72h. defaults to 7 days
+	DeletionGracePeriod string `json:"deletionGracePeriod,omitempty"`
+	// EngineVersion is the engine version the resource is upgraded to, e.g. 5.0.6. a snapshot is taken before the
+	// upgrade is applied. the version of the tier is used if not set
+	EngineVersion string `json:"engineVersion,omitempty"`
+}
 
 // RedisStatus defines the observed state of Redis
 // +k8s:openapi-gen=true
-type RedisStatus types.ResourceTypeStatus
+type RedisStatus struct {
+	types.ResourceTypeStatus `json:",inline"`
+	// Action is the progress and result of the last on-demand action requested on the replication group
+	Action *types.ResourceActionStatus `json:"action,omitempty"`
+	// Upgrade is the progress of the last engine version upgrade of the replication group
+	Upgrade *types.ResourceUpgradeStatus `json:"upgrade,omitempty"`
+}
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
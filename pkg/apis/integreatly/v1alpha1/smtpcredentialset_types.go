@@ -7,7 +7,14 @@ import (
 
 // SMTPCredentialSetSpec defines the desired state of SMTPCredentials
 // +k8s:openapi-gen=true
-type SMTPCredentialSetSpec types.ResourceTypeSpec
+type SMTPCredentialSetSpec struct {
+	Type       string           `json:"type"`
+	Tier       string           `json:"tier"`
+	SkipCreate bool             `json:"skipCreate,omitempty"`
+	SecretRef  *types.SecretRef `json:"secretRef"`
+	// RotationInterval is how often the credentials of the resource are rotated, e.g. 720h. rotation is disabled if not set
+	RotationInterval string `json:"rotationInterval,omitempty"`
+}
 
 // SMTPCredentialSetStatus defines the observed state of SMTPCredentials
 // +k8s:openapi-gen=true
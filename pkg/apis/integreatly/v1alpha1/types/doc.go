@@ -0,0 +1,3 @@
+// Package types contains the types shared by the resources of the integreatly v1alpha1 API group
+// +k8s:deepcopy-gen=package
+package types
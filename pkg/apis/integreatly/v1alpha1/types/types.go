@@ -31,6 +31,42 @@ type ResourceTypeSpec struct {
 	SecretRef  *SecretRef `json:"secretRef"`
 }
 
+// ResourceCredential Represents a named credential of a resource with an access level
+// +k8s:openapi-gen=true
+type ResourceCredential struct {
+	// Name is the name of the credential, its secret is named after the secret of the resource with the name as suffix
+	Name string `json:"name"`
+	// Access is the access level of the credential, one of read-only, write-only or read-write
+	// +kubebuilder:validation:Enum=read-only;write-only;read-write
+	Access string `json:"access"`
+	// Prefix restricts the credential to the objects with the prefix
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// access levels of named credentials
+const (
+	CredentialAccessReadOnly  = "read-only"
+	CredentialAccessWriteOnly = "write-only"
+	CredentialAccessReadWrite = "read-write"
+)
+
+// ResourceCredentialStatus Represents the secret a named credential of a resource is written to
+// +k8s:openapi-gen=true
+type ResourceCredentialStatus struct {
+	Name      string    `json:"name"`
+	SecretRef SecretRef `json:"secretRef"`
+}
+
+// CORSRule Represents a cross-origin resource sharing rule of a bucket
+// +k8s:openapi-gen=true
+type CORSRule struct {
+	AllowedOrigins []string `json:"allowedOrigins"`
+	AllowedMethods []string `json:"allowedMethods"`
+	AllowedHeaders []string `json:"allowedHeaders,omitempty"`
+	ExposeHeaders  []string `json:"exposeHeaders,omitempty"`
+	MaxAgeSeconds  int64    `json:"maxAgeSeconds,omitempty"`
+}
+
 type StatusPhase string
 
 type StatusMessage string
@@ -45,16 +81,104 @@ func (sm StatusMessage) WrapError(err error) StatusMessage {
 // ResourceTypeStatus Represents the basic status information provided by a resource provider
 // +k8s:openapi-gen=true
 type ResourceTypeStatus struct {
-	Strategy  string        `json:"strategy,omitempty"`
-	Provider  string        `json:"provider,omitempty"`
-	SecretRef *SecretRef    `json:"secretRef,omitempty"`
-	Phase     StatusPhase   `json:"phase,omitempty"`
-	Message   StatusMessage `json:"message,omitempty"`
+	Strategy             string                     `json:"strategy,omitempty"`
+	Provider             string                     `json:"provider,omitempty"`
+	SecretRef            *SecretRef                 `json:"secretRef,omitempty"`
+	Phase                StatusPhase                `json:"phase,omitempty"`
+	Message              StatusMessage              `json:"message,omitempty"`
+	PendingMaintenance   []PendingMaintenanceAction `json:"pendingMaintenance,omitempty"`
+	PendingModifications []PendingModification      `json:"pendingModifications,omitempty"`
+	// PendingPlanHash identifies the plan of pending modifications which is applied once approved, when approval is
+	// required
+	PendingPlanHash string `json:"pendingPlanHash,omitempty"`
+	// PlannedActions are the actions against the cloud provider which would be performed, in dry run mode
+	PlannedActions []string `json:"plannedActions,omitempty"`
+	// Drift lists the fields of the resource which differ from the desired configuration
+	Drift []DriftedField `json:"drift,omitempty"`
+}
+
+// ResourceDeletionStatus Represents the progress of emptying a resource before it is deleted, which can take several
+// reconciles
+// +k8s:openapi-gen=true
+type ResourceDeletionStatus struct {
+	// DeletedObjects is the number of objects, object versions and delete markers deleted so far
+	DeletedObjects int64 `json:"deletedObjects"`
+	// KeyMarker and VersionIDMarker are where the listing of the objects continues in the next batch
+	KeyMarker       string `json:"keyMarker,omitempty"`
+	VersionIDMarker string `json:"versionIDMarker,omitempty"`
+	StartedAt       string `json:"startedAt,omitempty"`
+	UpdatedAt       string `json:"updatedAt,omitempty"`
+}
+
+// ResourceUpgradeStatus Represents the progress of an engine version upgrade of a resource, and how to roll it back
+// +k8s:openapi-gen=true
+type ResourceUpgradeStatus struct {
+	FromVersion string        `json:"fromVersion,omitempty"`
+	ToVersion   string        `json:"toVersion"`
+	Phase       StatusPhase   `json:"phase,omitempty"`
+	Message     StatusMessage `json:"message,omitempty"`
+	// Snapshot identifies the snapshot of the data taken before the upgrade was applied
+	Snapshot    string `json:"snapshot,omitempty"`
+	StartedAt   string `json:"startedAt,omitempty"`
+	CompletedAt string `json:"completedAt,omitempty"`
+	// RollbackGuidance describes how to restore the resource to the version it was upgraded from
+	RollbackGuidance string `json:"rollbackGuidance,omitempty"`
+}
+
+// ResourceActionStatus Represents the progress and result of an on-demand action requested on a resource
+// +k8s:openapi-gen=true
+type ResourceActionStatus struct {
+	Action      string        `json:"action"`
+	Value       string        `json:"value,omitempty"`
+	Phase       StatusPhase   `json:"phase,omitempty"`
+	Message     StatusMessage `json:"message,omitempty"`
+	StartedAt   string        `json:"startedAt,omitempty"`
+	CompletedAt string        `json:"completedAt,omitempty"`
+}
+
+// PendingModification Represents a disruptive change to a resource which is deferred to its maintenance window
+// +k8s:openapi-gen=true
+type PendingModification struct {
+	Field string `json:"field"`
+	Value string `json:"value"`
+}
+
+// DriftedField Represents a field of a resource whose observed value differs from its desired value
+// +k8s:openapi-gen=true
+type DriftedField struct {
+	Field    string `json:"field"`
+	Desired  string `json:"desired"`
+	Observed string `json:"observed"`
+}
+
+// PendingMaintenanceAction Represents a maintenance action the cloud provider has scheduled for a resource
+// +k8s:openapi-gen=true
+type PendingMaintenanceAction struct {
+	Action               string `json:"action"`
+	Description          string `json:"description,omitempty"`
+	AutoAppliedAfterDate string `json:"autoAppliedAfterDate,omitempty"`
+	ForcedApplyDate      string `json:"forcedApplyDate,omitempty"`
+	OptInStatus          string `json:"optInStatus,omitempty"`
+	// Severity, RecommendedApplyByDate, Status and NodesUpdated are set for service updates, which report their progress
+	Severity               string `json:"severity,omitempty"`
+	RecommendedApplyByDate string `json:"recommendedApplyByDate,omitempty"`
+	Status                 string `json:"status,omitempty"`
+	NodesUpdated           string `json:"nodesUpdated,omitempty"`
 }
 
 // ResourceTypeSnapshotStatus Represents the basic status information provided by snapshot controller
 // +k8s:openapi-gen=true
 type ResourceTypeSnapshotStatus struct {
+	SnapshotID string                          `json:"snapshotID,omitempty"`
+	Phase      StatusPhase                     `json:"phase,omitempty"`
+	Message    StatusMessage                   `json:"message,omitempty"`
+	Copy       *ResourceTypeSnapshotCopyStatus `json:"copy,omitempty"`
+}
+
+// ResourceTypeSnapshotCopyStatus Represents the status of a copy of a snapshot in another region
+// +k8s:openapi-gen=true
+type ResourceTypeSnapshotCopyStatus struct {
+	Region     string        `json:"region,omitempty"`
 	SnapshotID string        `json:"snapshotID,omitempty"`
 	Phase      StatusPhase   `json:"phase,omitempty"`
 	Message    StatusMessage `json:"message,omitempty"`
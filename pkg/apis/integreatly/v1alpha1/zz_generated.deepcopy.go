@@ -78,6 +78,18 @@ func (in *BlobStorageSpec) DeepCopyInto(out *BlobStorageSpec) {
 		*out = new(types.SecretRef)
 		**out = **in
 	}
+	if in.CORSRules != nil {
+		in, out := &in.CORSRules, &out.CORSRules
+		*out = make([]types.CORSRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = make([]types.ResourceCredential, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -94,11 +106,17 @@ func (in *BlobStorageSpec) DeepCopy() *BlobStorageSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BlobStorageStatus) DeepCopyInto(out *BlobStorageStatus) {
 	*out = *in
-	if in.SecretRef != nil {
-		in, out := &in.SecretRef, &out.SecretRef
-		*out = new(types.SecretRef)
+	in.ResourceTypeStatus.DeepCopyInto(&out.ResourceTypeStatus)
+	if in.Deletion != nil {
+		in, out := &in.Deletion, &out.Deletion
+		*out = new(types.ResourceDeletionStatus)
 		**out = **in
 	}
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = make([]types.ResourceCredentialStatus, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -140,6 +158,129 @@ func (in *Postgres) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresDatabase) DeepCopyInto(out *PostgresDatabase) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresDatabase.
+func (in *PostgresDatabase) DeepCopy() *PostgresDatabase {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresDatabase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PostgresDatabase) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresDatabaseList) DeepCopyInto(out *PostgresDatabaseList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PostgresDatabase, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresDatabaseList.
+func (in *PostgresDatabaseList) DeepCopy() *PostgresDatabaseList {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresDatabaseList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PostgresDatabaseList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresDatabaseSpec) DeepCopyInto(out *PostgresDatabaseSpec) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(types.SecretRef)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresDatabaseSpec.
+func (in *PostgresDatabaseSpec) DeepCopy() *PostgresDatabaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresDatabaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresDatabaseStatus) DeepCopyInto(out *PostgresDatabaseStatus) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(types.SecretRef)
+		**out = **in
+	}
+	if in.PendingMaintenance != nil {
+		in, out := &in.PendingMaintenance, &out.PendingMaintenance
+		*out = make([]types.PendingMaintenanceAction, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingModifications != nil {
+		in, out := &in.PendingModifications, &out.PendingModifications
+		*out = make([]types.PendingModification, len(*in))
+		copy(*out, *in)
+	}
+	if in.PlannedActions != nil {
+		in, out := &in.PlannedActions, &out.PlannedActions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]types.DriftedField, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresDatabaseStatus.
+func (in *PostgresDatabaseStatus) DeepCopy() *PostgresDatabaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresDatabaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresList) DeepCopyInto(out *PostgresList) {
 	*out = *in
@@ -173,13 +314,136 @@ func (in *PostgresList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresRestore) DeepCopyInto(out *PostgresRestore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresRestore.
+func (in *PostgresRestore) DeepCopy() *PostgresRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PostgresRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresRestoreList) DeepCopyInto(out *PostgresRestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PostgresRestore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresRestoreList.
+func (in *PostgresRestoreList) DeepCopy() *PostgresRestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresRestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PostgresRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresRestoreSpec) DeepCopyInto(out *PostgresRestoreSpec) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(types.SecretRef)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresRestoreSpec.
+func (in *PostgresRestoreSpec) DeepCopy() *PostgresRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresRestoreStatus) DeepCopyInto(out *PostgresRestoreStatus) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(types.SecretRef)
+		**out = **in
+	}
+	if in.PendingMaintenance != nil {
+		in, out := &in.PendingMaintenance, &out.PendingMaintenance
+		*out = make([]types.PendingMaintenanceAction, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingModifications != nil {
+		in, out := &in.PendingModifications, &out.PendingModifications
+		*out = make([]types.PendingModification, len(*in))
+		copy(*out, *in)
+	}
+	if in.PlannedActions != nil {
+		in, out := &in.PlannedActions, &out.PlannedActions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]types.DriftedField, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresRestoreStatus.
+func (in *PostgresRestoreStatus) DeepCopy() *PostgresRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresSnapshot) DeepCopyInto(out *PostgresSnapshot) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -253,6 +517,11 @@ func (in *PostgresSnapshotSpec) DeepCopy() *PostgresSnapshotSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresSnapshotStatus) DeepCopyInto(out *PostgresSnapshotStatus) {
 	*out = *in
+	if in.Copy != nil {
+		in, out := &in.Copy, &out.Copy
+		*out = new(types.ResourceTypeSnapshotCopyStatus)
+		**out = **in
+	}
 	return
 }
 
@@ -295,6 +564,26 @@ func (in *PostgresStatus) DeepCopyInto(out *PostgresStatus) {
 		*out = new(types.SecretRef)
 		**out = **in
 	}
+	if in.PendingMaintenance != nil {
+		in, out := &in.PendingMaintenance, &out.PendingMaintenance
+		*out = make([]types.PendingMaintenanceAction, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingModifications != nil {
+		in, out := &in.PendingModifications, &out.PendingModifications
+		*out = make([]types.PendingModification, len(*in))
+		copy(*out, *in)
+	}
+	if in.PlannedActions != nil {
+		in, out := &in.PlannedActions, &out.PlannedActions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]types.DriftedField, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -308,6 +597,129 @@ func (in *PostgresStatus) DeepCopy() *PostgresStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresUser) DeepCopyInto(out *PostgresUser) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresUser.
+func (in *PostgresUser) DeepCopy() *PostgresUser {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PostgresUser) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresUserList) DeepCopyInto(out *PostgresUserList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PostgresUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresUserList.
+func (in *PostgresUserList) DeepCopy() *PostgresUserList {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresUserList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PostgresUserList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresUserSpec) DeepCopyInto(out *PostgresUserSpec) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(types.SecretRef)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresUserSpec.
+func (in *PostgresUserSpec) DeepCopy() *PostgresUserSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresUserSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresUserStatus) DeepCopyInto(out *PostgresUserStatus) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(types.SecretRef)
+		**out = **in
+	}
+	if in.PendingMaintenance != nil {
+		in, out := &in.PendingMaintenance, &out.PendingMaintenance
+		*out = make([]types.PendingMaintenanceAction, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingModifications != nil {
+		in, out := &in.PendingModifications, &out.PendingModifications
+		*out = make([]types.PendingModification, len(*in))
+		copy(*out, *in)
+	}
+	if in.PlannedActions != nil {
+		in, out := &in.PlannedActions, &out.PlannedActions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]types.DriftedField, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresUserStatus.
+func (in *PostgresUserStatus) DeepCopy() *PostgresUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Redis) DeepCopyInto(out *Redis) {
 	*out = *in
@@ -375,7 +787,7 @@ func (in *RedisSnapshot) DeepCopyInto(out *RedisSnapshot) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -449,6 +861,11 @@ func (in *RedisSnapshotSpec) DeepCopy() *RedisSnapshotSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RedisSnapshotStatus) DeepCopyInto(out *RedisSnapshotStatus) {
 	*out = *in
+	if in.Copy != nil {
+		in, out := &in.Copy, &out.Copy
+		*out = new(types.ResourceTypeSnapshotCopyStatus)
+		**out = **in
+	}
 	return
 }
 
@@ -486,9 +903,15 @@ func (in *RedisSpec) DeepCopy() *RedisSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RedisStatus) DeepCopyInto(out *RedisStatus) {
 	*out = *in
-	if in.SecretRef != nil {
-		in, out := &in.SecretRef, &out.SecretRef
-		*out = new(types.SecretRef)
+	in.ResourceTypeStatus.DeepCopyInto(&out.ResourceTypeStatus)
+	if in.Action != nil {
+		in, out := &in.Action, &out.Action
+		*out = new(types.ResourceActionStatus)
+		**out = **in
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(types.ResourceUpgradeStatus)
 		**out = **in
 	}
 	return
@@ -594,6 +1017,26 @@ func (in *SMTPCredentialSetStatus) DeepCopyInto(out *SMTPCredentialSetStatus) {
 		*out = new(types.SecretRef)
 		**out = **in
 	}
+	if in.PendingMaintenance != nil {
+		in, out := &in.PendingMaintenance, &out.PendingMaintenance
+		*out = make([]types.PendingMaintenanceAction, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingModifications != nil {
+		in, out := &in.PendingModifications, &out.PendingModifications
+		*out = make([]types.PendingModification, len(*in))
+		copy(*out, *in)
+	}
+	if in.PlannedActions != nil {
+		in, out := &in.PlannedActions, &out.PlannedActions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]types.DriftedField, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -15,11 +15,20 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.BlobStorageSpec":         schema_pkg_apis_integreatly_v1alpha1_BlobStorageSpec(ref),
 		"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.BlobStorageStatus":       schema_pkg_apis_integreatly_v1alpha1_BlobStorageStatus(ref),
 		"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.Postgres":                schema_pkg_apis_integreatly_v1alpha1_Postgres(ref),
+		"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresDatabase":        schema_pkg_apis_integreatly_v1alpha1_PostgresDatabase(ref),
+		"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresDatabaseSpec":    schema_pkg_apis_integreatly_v1alpha1_PostgresDatabaseSpec(ref),
+		"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresDatabaseStatus":  schema_pkg_apis_integreatly_v1alpha1_PostgresDatabaseStatus(ref),
+		"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresRestore":         schema_pkg_apis_integreatly_v1alpha1_PostgresRestore(ref),
+		"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresRestoreSpec":     schema_pkg_apis_integreatly_v1alpha1_PostgresRestoreSpec(ref),
+		"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresRestoreStatus":   schema_pkg_apis_integreatly_v1alpha1_PostgresRestoreStatus(ref),
 		"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresSnapshot":        schema_pkg_apis_integreatly_v1alpha1_PostgresSnapshot(ref),
 		"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresSnapshotSpec":    schema_pkg_apis_integreatly_v1alpha1_PostgresSnapshotSpec(ref),
 		"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresSnapshotStatus":  schema_pkg_apis_integreatly_v1alpha1_PostgresSnapshotStatus(ref),
 		"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresSpec":            schema_pkg_apis_integreatly_v1alpha1_PostgresSpec(ref),
 		"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresStatus":          schema_pkg_apis_integreatly_v1alpha1_PostgresStatus(ref),
+		"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresUser":            schema_pkg_apis_integreatly_v1alpha1_PostgresUser(ref),
+		"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresUserSpec":        schema_pkg_apis_integreatly_v1alpha1_PostgresUserSpec(ref),
+		"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresUserStatus":      schema_pkg_apis_integreatly_v1alpha1_PostgresUserStatus(ref),
 		"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.Redis":                   schema_pkg_apis_integreatly_v1alpha1_Redis(ref),
 		"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.RedisSnapshot":           schema_pkg_apis_integreatly_v1alpha1_RedisSnapshot(ref),
 		"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.RedisSnapshotSpec":       schema_pkg_apis_integreatly_v1alpha1_RedisSnapshotSpec(ref),
@@ -104,6 +113,802 @@ func schema_pkg_apis_integreatly_v1alpha1_BlobStorageSpec(ref common.ReferenceCa
 							Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.SecretRef"),
 						},
 					},
+					"rotationInterval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RotationInterval is how often the credentials of the resource are rotated, e.g. 720h. rotation is disabled if not set",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"adoptIdentifier": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AdoptIdentifier is the identifier of an existing cloud resource to adopt instead of creating a new one. the resource is verified and managed like a resource created by the operator once adopted",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"deletionPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeletionPolicy determines what happens to the cloud resource when the resource is deleted, one of Delete, Retain or DelayedDelete. the cloud resource is deleted as described by the tier if not set",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"deletionGracePeriod": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeletionGracePeriod is how long the deletion of the cloud resource is delayed by the DelayedDelete deletion policy, e.g. 72h. defaults to 7 days",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"corsRules": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CORSRules are the cross-origin resource sharing rules which allow browsers to access the bucket from other origins. the cors rules of the tier are used if not set",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.CORSRule"),
+									},
+								},
+							},
+						},
+					},
+					"credentials": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Credentials are additional named credentials of the bucket with narrower access, each written to its own secret",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.ResourceCredential"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"type", "tier", "secretRef"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.CORSRule", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.ResourceCredential", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.SecretRef"},
+	}
+}
+
+func schema_pkg_apis_integreatly_v1alpha1_BlobStorageStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "BlobStorageStatus defines the observed state of BlobStorage",
+				Properties: map[string]spec.Schema{
+					"strategy": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"provider": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"secretRef": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.SecretRef"),
+						},
+					},
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"pendingMaintenance": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingMaintenanceAction"),
+									},
+								},
+							},
+						},
+					},
+					"pendingModifications": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingModification"),
+									},
+								},
+							},
+						},
+					},
+					"pendingPlanHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PendingPlanHash identifies the plan of pending modifications which is applied once approved, when approval is required",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"plannedActions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PlannedActions are the actions against the cloud provider which would be performed, in dry run mode",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"drift": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Drift lists the fields of the resource which differ from the desired configuration",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.DriftedField"),
+									},
+								},
+							},
+						},
+					},
+					"deletion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Deletion is the progress of emptying the bucket before it is deleted",
+							Ref:         ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.ResourceDeletionStatus"),
+						},
+					},
+					"credentials": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Credentials are the secrets the named credentials of the bucket are written to",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.ResourceCredentialStatus"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.DriftedField", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingMaintenanceAction", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingModification", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.ResourceCredentialStatus", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.ResourceDeletionStatus", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.SecretRef"},
+	}
+}
+
+func schema_pkg_apis_integreatly_v1alpha1_Postgres(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Postgres is the Schema for the postgres API",
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresSpec", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_integreatly_v1alpha1_PostgresDatabase(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PostgresDatabase is the Schema for the postgresdatabases API",
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresDatabaseSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresDatabaseStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresDatabaseSpec", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresDatabaseStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_integreatly_v1alpha1_PostgresDatabaseSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PostgresDatabaseSpec defines the desired state of PostgresDatabase",
+				Properties: map[string]spec.Schema{
+					"resourceName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResourceName is the name of the Postgres resource the database is created in",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"databaseName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DatabaseName is the name of the logical database, derived from the resource name if not set",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"secretRef": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.SecretRef"),
+						},
+					},
+				},
+				Required: []string{"resourceName", "secretRef"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.SecretRef"},
+	}
+}
+
+func schema_pkg_apis_integreatly_v1alpha1_PostgresDatabaseStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PostgresDatabaseStatus defines the observed state of PostgresDatabase",
+				Properties: map[string]spec.Schema{
+					"strategy": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"provider": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"secretRef": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.SecretRef"),
+						},
+					},
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"pendingMaintenance": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingMaintenanceAction"),
+									},
+								},
+							},
+						},
+					},
+					"pendingModifications": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingModification"),
+									},
+								},
+							},
+						},
+					},
+					"pendingPlanHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PendingPlanHash identifies the plan of pending modifications which is applied once approved, when approval is required",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"plannedActions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PlannedActions are the actions against the cloud provider which would be performed, in dry run mode",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"drift": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Drift lists the fields of the resource which differ from the desired configuration",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.DriftedField"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.DriftedField", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingMaintenanceAction", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingModification", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.SecretRef"},
+	}
+}
+
+func schema_pkg_apis_integreatly_v1alpha1_PostgresRestore(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PostgresRestore is the Schema for the postgresrestores API",
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresRestoreSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresRestoreStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresRestoreSpec", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresRestoreStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_integreatly_v1alpha1_PostgresRestoreSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PostgresRestoreSpec defines the desired state of PostgresRestore",
+				Properties: map[string]spec.Schema{
+					"resourceName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResourceName is the name of the Postgres resource to restore from",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"restoreTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RestoreTime is the time to restore to, in RFC3339 format",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"useLatestRestorableTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UseLatestRestorableTime restores to the latest restorable time instead of RestoreTime",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"targetName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TargetName is the name of the Postgres resource created to manage the restored instance",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"secretRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SecretRef is the result secret of the Postgres resource created to manage the restored instance",
+							Ref:         ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.SecretRef"),
+						},
+					},
+				},
+				Required: []string{"resourceName", "targetName", "secretRef"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.SecretRef"},
+	}
+}
+
+func schema_pkg_apis_integreatly_v1alpha1_PostgresRestoreStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PostgresRestoreStatus defines the observed state of PostgresRestore",
+				Properties: map[string]spec.Schema{
+					"strategy": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"provider": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"secretRef": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.SecretRef"),
+						},
+					},
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"pendingMaintenance": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingMaintenanceAction"),
+									},
+								},
+							},
+						},
+					},
+					"pendingModifications": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingModification"),
+									},
+								},
+							},
+						},
+					},
+					"pendingPlanHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PendingPlanHash identifies the plan of pending modifications which is applied once approved, when approval is required",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"plannedActions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PlannedActions are the actions against the cloud provider which would be performed, in dry run mode",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"drift": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Drift lists the fields of the resource which differ from the desired configuration",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.DriftedField"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.DriftedField", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingMaintenanceAction", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingModification", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.SecretRef"},
+	}
+}
+
+func schema_pkg_apis_integreatly_v1alpha1_PostgresSnapshot(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PostgresSnapshot is the Schema for the postgressnapshots API",
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresSnapshotSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresSnapshotStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresSnapshotSpec", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresSnapshotStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_integreatly_v1alpha1_PostgresSnapshotSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PostgresSnapshotSpec defines the desired state of PostgresSnapshot",
+				Properties: map[string]spec.Schema{
+					"resourceName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "INSERT ADDITIONAL SPEC FIELDS - desired state of cluster Important: Run \"operator-sdk generate k8s\" to regenerate code after modifying this file Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"copyRegion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CopyRegion is the region a copy of the snapshot is made in, no copy is made when empty",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"copyKMSKeyID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CopyKMSKeyID is the kms key in the copy region used to encrypt the copy of an encrypted snapshot, the aws managed rds key is used when empty",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"snapshotID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SnapshotID is the identifier of an existing snapshot, such as the final snapshot taken when an instance is deleted, which the cr refers to instead of taking a new snapshot",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"region": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Region is the region the snapshot is taken in, it is recorded when the snapshot is taken so the snapshot can be found once its resource is deleted. the region of the default strategy is used when empty",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"resourceName"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_integreatly_v1alpha1_PostgresSnapshotStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PostgresSnapshotStatus defines the observed state of PostgresSnapshot",
+				Properties: map[string]spec.Schema{
+					"snapshotID": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"copy": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.ResourceTypeSnapshotCopyStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.ResourceTypeSnapshotCopyStatus"},
+	}
+}
+
+func schema_pkg_apis_integreatly_v1alpha1_PostgresSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PostgresSpec defines the desired state of Postgres",
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"tier": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"skipCreate": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"boolean"},
+							Format: "",
+						},
+					},
+					"secretRef": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.SecretRef"),
+						},
+					},
+					"rotationInterval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RotationInterval is how often the credentials of the resource are rotated, e.g. 720h. rotation is disabled if not set",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"maintenanceWindow": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaintenanceWindow is the weekly window pending maintenance is applied in, in the format ddd:hh24:mi-ddd:hh24:mi (UTC). it overrides the maintenance window of the tier",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"backupWindow": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BackupWindow is the daily window automated backups are taken in, in the format hh24:mi-hh24:mi (UTC). it overrides the backup window of the tier",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"adoptIdentifier": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AdoptIdentifier is the identifier of an existing cloud resource to adopt instead of creating a new one. the resource is verified and managed like a resource created by the operator once adopted",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"deletionPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeletionPolicy determines what happens to the cloud resource when the resource is deleted, one of Delete, Snapshot, Retain or DelayedDelete. the cloud resource is deleted as described by the tier if not set",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"deletionGracePeriod": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeletionGracePeriod is how long the deletion of the cloud resource is delayed by the DelayedDelete deletion policy, e.g. 72h. defaults to 7 days",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"type", "tier", "secretRef"},
 			},
@@ -113,11 +918,11 @@ func schema_pkg_apis_integreatly_v1alpha1_BlobStorageSpec(ref common.ReferenceCa
 	}
 }
 
-func schema_pkg_apis_integreatly_v1alpha1_BlobStorageStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_integreatly_v1alpha1_PostgresStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "BlobStorageStatus defines the observed state of BlobStorage",
+				Description: "PostgresStatus defines the observed state of Postgres",
 				Properties: map[string]spec.Schema{
 					"strategy": {
 						SchemaProps: spec.SchemaProps{
@@ -148,62 +953,77 @@ func schema_pkg_apis_integreatly_v1alpha1_BlobStorageStatus(ref common.Reference
 							Format: "",
 						},
 					},
-				},
-			},
-		},
-		Dependencies: []string{
-			"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.SecretRef"},
-	}
-}
-
-func schema_pkg_apis_integreatly_v1alpha1_Postgres(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "Postgres is the Schema for the postgres API",
-				Properties: map[string]spec.Schema{
-					"kind": {
+					"pendingMaintenance": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingMaintenanceAction"),
+									},
+								},
+							},
 						},
 					},
-					"apiVersion": {
+					"pendingModifications": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
-							Type:        []string{"string"},
-							Format:      "",
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingModification"),
+									},
+								},
+							},
 						},
 					},
-					"metadata": {
+					"pendingPlanHash": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+							Description: "PendingPlanHash identifies the plan of pending modifications which is applied once approved, when approval is required",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"spec": {
+					"plannedActions": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresSpec"),
+							Description: "PlannedActions are the actions against the cloud provider which would be performed, in dry run mode",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
 						},
 					},
-					"status": {
+					"drift": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresStatus"),
+							Description: "Drift lists the fields of the resource which differ from the desired configuration",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.DriftedField"),
+									},
+								},
+							},
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresSpec", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.DriftedField", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingMaintenanceAction", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingModification", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.SecretRef"},
 	}
 }
 
-func schema_pkg_apis_integreatly_v1alpha1_PostgresSnapshot(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_integreatly_v1alpha1_PostgresUser(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "PostgresSnapshot is the Schema for the postgressnapshots API",
+				Description: "PostgresUser is the Schema for the postgresusers API",
 				Properties: map[string]spec.Schema{
 					"kind": {
 						SchemaProps: spec.SchemaProps{
@@ -226,96 +1046,61 @@ func schema_pkg_apis_integreatly_v1alpha1_PostgresSnapshot(ref common.ReferenceC
 					},
 					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresSnapshotSpec"),
+							Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresUserSpec"),
 						},
 					},
 					"status": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresSnapshotStatus"),
+							Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresUserStatus"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresSnapshotSpec", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresSnapshotStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresUserSpec", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1.PostgresUserStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_integreatly_v1alpha1_PostgresSnapshotSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_integreatly_v1alpha1_PostgresUserSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "PostgresSnapshotSpec defines the desired state of PostgresSnapshot",
+				Description: "PostgresUserSpec defines the desired state of PostgresUser",
 				Properties: map[string]spec.Schema{
 					"resourceName": {
 						SchemaProps: spec.SchemaProps{
-							Description: "INSERT ADDITIONAL SPEC FIELDS - desired state of cluster Important: Run \"operator-sdk generate k8s\" to regenerate code after modifying this file Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html",
+							Description: "ResourceName is the name of the Postgres resource the user is created in",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-				},
-				Required: []string{"resourceName"},
-			},
-		},
-		Dependencies: []string{},
-	}
-}
-
-func schema_pkg_apis_integreatly_v1alpha1_PostgresSnapshotStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "PostgresSnapshotStatus defines the observed state of PostgresSnapshot",
-				Properties: map[string]spec.Schema{
-					"snapshotID": {
-						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
-						},
-					},
-					"phase": {
-						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
-						},
-					},
-					"message": {
+					"databaseName": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "DatabaseName is the logical database the user is granted access to",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-				},
-			},
-		},
-		Dependencies: []string{},
-	}
-}
-
-func schema_pkg_apis_integreatly_v1alpha1_PostgresSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "PostgresSpec defines the desired state of Postgres",
-				Properties: map[string]spec.Schema{
-					"type": {
+					"username": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "Username is the name of the role, derived from the resource name if not set",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"tier": {
+					"readOnly": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "ReadOnly limits the user to reading tables in the database",
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
-					"skipCreate": {
+					"iamAuthentication": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"boolean"},
-							Format: "",
+							Description: "IAMAuthentication authenticates the user with aws credentials instead of a password, only supported by the aws strategy on instances with iam database authentication enabled",
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
 					"secretRef": {
@@ -324,7 +1109,7 @@ func schema_pkg_apis_integreatly_v1alpha1_PostgresSpec(ref common.ReferenceCallb
 						},
 					},
 				},
-				Required: []string{"type", "tier", "secretRef"},
+				Required: []string{"resourceName", "databaseName", "secretRef"},
 			},
 		},
 		Dependencies: []string{
@@ -332,11 +1117,11 @@ func schema_pkg_apis_integreatly_v1alpha1_PostgresSpec(ref common.ReferenceCallb
 	}
 }
 
-func schema_pkg_apis_integreatly_v1alpha1_PostgresStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_integreatly_v1alpha1_PostgresUserStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "PostgresStatus defines the observed state of Postgres",
+				Description: "PostgresUserStatus defines the observed state of PostgresUser",
 				Properties: map[string]spec.Schema{
 					"strategy": {
 						SchemaProps: spec.SchemaProps{
@@ -367,11 +1152,69 @@ func schema_pkg_apis_integreatly_v1alpha1_PostgresStatus(ref common.ReferenceCal
 							Format: "",
 						},
 					},
+					"pendingMaintenance": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingMaintenanceAction"),
+									},
+								},
+							},
+						},
+					},
+					"pendingModifications": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingModification"),
+									},
+								},
+							},
+						},
+					},
+					"pendingPlanHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PendingPlanHash identifies the plan of pending modifications which is applied once approved, when approval is required",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"plannedActions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PlannedActions are the actions against the cloud provider which would be performed, in dry run mode",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"drift": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Drift lists the fields of the resource which differ from the desired configuration",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.DriftedField"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.SecretRef"},
+			"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.DriftedField", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingMaintenanceAction", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingModification", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.SecretRef"},
 	}
 }
 
@@ -474,11 +1317,38 @@ func schema_pkg_apis_integreatly_v1alpha1_RedisSnapshotSpec(ref common.Reference
 							Format:      "",
 						},
 					},
+					"snapshotID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SnapshotID is the identifier of an existing snapshot, such as the final snapshot taken when a replication group is deleted, which the cr refers to instead of taking a new snapshot",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"region": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Region is the region the snapshot is taken in, it is recorded when the snapshot is taken so the snapshot can be found once its resource is deleted. the region of the default strategy is used when empty",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"copyRegion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CopyRegion is the region a copy of the snapshot is made in, no copy is made when empty",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"copyKMSKeyID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CopyKMSKeyID is the kms key in the copy region used to encrypt the files of the copy of the snapshot, they are encrypted with an s3 managed key when empty",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"resourceName"},
 			},
 		},
-		Dependencies: []string{},
 	}
 }
 
@@ -506,10 +1376,16 @@ func schema_pkg_apis_integreatly_v1alpha1_RedisSnapshotStatus(ref common.Referen
 							Format: "",
 						},
 					},
+					"copy": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.ResourceTypeSnapshotCopyStatus"),
+						},
+					},
 				},
 			},
 		},
-		Dependencies: []string{},
+		Dependencies: []string{
+			"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.ResourceTypeSnapshotCopyStatus"},
 	}
 }
 
@@ -542,6 +1418,48 @@ func schema_pkg_apis_integreatly_v1alpha1_RedisSpec(ref common.ReferenceCallback
 							Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.SecretRef"),
 						},
 					},
+					"maintenanceWindow": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaintenanceWindow is the weekly window pending maintenance is applied in, in the format ddd:hh24:mi-ddd:hh24:mi (UTC). it overrides the maintenance window of the tier",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"backupWindow": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BackupWindow is the daily window automated backups are taken in, in the format hh24:mi-hh24:mi (UTC). it overrides the backup window of the tier",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"adoptIdentifier": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AdoptIdentifier is the identifier of an existing cloud resource to adopt instead of creating a new one. the resource is verified and managed like a resource created by the operator once adopted",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"deletionPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeletionPolicy determines what happens to the cloud resource when the resource is deleted, one of Delete, Snapshot, Retain or DelayedDelete. the cloud resource is deleted as described by the tier if not set",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"deletionGracePeriod": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeletionGracePeriod is how long the deletion of the cloud resource is delayed by the DelayedDelete deletion policy, e.g. 72h. defaults to 7 days",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"engineVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "EngineVersion is the engine version the resource is upgraded to, e.g. 5.0.6. a snapshot is taken before the upgrade is applied. the version of the tier is used if not set",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"type", "tier", "secretRef"},
 			},
@@ -586,11 +1504,81 @@ func schema_pkg_apis_integreatly_v1alpha1_RedisStatus(ref common.ReferenceCallba
 							Format: "",
 						},
 					},
+					"pendingMaintenance": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingMaintenanceAction"),
+									},
+								},
+							},
+						},
+					},
+					"pendingModifications": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingModification"),
+									},
+								},
+							},
+						},
+					},
+					"pendingPlanHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PendingPlanHash identifies the plan of pending modifications which is applied once approved, when approval is required",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"plannedActions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PlannedActions are the actions against the cloud provider which would be performed, in dry run mode",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"drift": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Drift lists the fields of the resource which differ from the desired configuration",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.DriftedField"),
+									},
+								},
+							},
+						},
+					},
+					"action": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Action is the progress and result of the last on-demand action requested on the replication group",
+							Ref:         ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.ResourceActionStatus"),
+						},
+					},
+					"upgrade": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Upgrade is the progress of the last engine version upgrade of the replication group",
+							Ref:         ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.ResourceUpgradeStatus"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.SecretRef"},
+			"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.DriftedField", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingMaintenanceAction", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingModification", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.ResourceActionStatus", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.ResourceUpgradeStatus", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.SecretRef"},
 	}
 }
 
@@ -666,6 +1654,13 @@ func schema_pkg_apis_integreatly_v1alpha1_SMTPCredentialSetSpec(ref common.Refer
 							Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.SecretRef"),
 						},
 					},
+					"rotationInterval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RotationInterval is how often the credentials of the resource are rotated, e.g. 720h. rotation is disabled if not set",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"type", "tier", "secretRef"},
 			},
@@ -710,10 +1705,68 @@ func schema_pkg_apis_integreatly_v1alpha1_SMTPCredentialSetStatus(ref common.Ref
 							Format: "",
 						},
 					},
+					"pendingMaintenance": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingMaintenanceAction"),
+									},
+								},
+							},
+						},
+					},
+					"pendingModifications": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingModification"),
+									},
+								},
+							},
+						},
+					},
+					"pendingPlanHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PendingPlanHash identifies the plan of pending modifications which is applied once approved, when approval is required",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"plannedActions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PlannedActions are the actions against the cloud provider which would be performed, in dry run mode",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"drift": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Drift lists the fields of the resource which differ from the desired configuration",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.DriftedField"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.SecretRef"},
+			"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.DriftedField", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingMaintenanceAction", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.PendingModification", "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types.SecretRef"},
 	}
 }
@@ -0,0 +1,10 @@
+package controller
+
+import (
+	"github.com/integr8ly/cloud-resource-operator/pkg/controller/postgresrestore"
+)
+
+func init() {
+	// AddToManagerFuncs is a list of functions to create controllers and add them to a manager.
+	AddToManagerFuncs = append(AddToManagerFuncs, postgresrestore.Add)
+}
@@ -82,7 +82,7 @@ type ReconcileBlobStorage struct {
 
 func (r *ReconcileBlobStorage) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	r.logger.Info("reconciling BlobStorage")
-	ctx := context.TODO()
+	ctx := resources.NewDryRunContext(context.TODO())
 	cfgMgr := providers.NewConfigManager(providers.DefaultProviderConfigMapName, request.Namespace, r.client)
 
 	// Fetch the BlobStorage instance
@@ -130,6 +130,9 @@ func (r *ReconcileBlobStorage) Reconcile(request reconcile.Request) (reconcile.R
 
 		if instance.GetDeletionTimestamp() != nil {
 			msg, err := p.DeleteStorage(ctx, instance)
+			if reportErr := resources.ReportDryRun(ctx, instance); reportErr != nil {
+				return reconcile.Result{}, reportErr
+			}
 			if err != nil {
 				if updateErr := resources.UpdatePhase(ctx, r.client, instance, croType.PhaseFailed, msg.WrapError(err)); updateErr != nil {
 					return reconcile.Result{}, updateErr
@@ -145,6 +148,9 @@ func (r *ReconcileBlobStorage) Reconcile(request reconcile.Request) (reconcile.R
 		}
 
 		bsi, msg, err := p.CreateStorage(ctx, instance)
+		if reportErr := resources.ReportDryRun(ctx, instance); reportErr != nil {
+			return reconcile.Result{}, reportErr
+		}
 		if err != nil {
 			instance.Status.SecretRef = &croType.SecretRef{}
 			if updateErr := resources.UpdatePhase(ctx, r.client, instance, croType.PhaseFailed, msg.WrapError(err)); updateErr != nil {
@@ -165,9 +171,20 @@ func (r *ReconcileBlobStorage) Reconcile(request reconcile.Request) (reconcile.R
 			return reconcile.Result{}, errorUtil.Wrap(err, "failed to reconcile secret")
 		}
 
+		// each named credential is written to its own secret, the secrets of removed credentials are deleted
+		credentialData := map[string]map[string][]byte{}
+		for name, details := range bsi.Credentials {
+			credentialData[name] = details.Data()
+		}
+		credentials, err := r.resourceProvider.ReconcileCredentialSecrets(ctx, instance, credentialData, instance.Status.Credentials)
+		if err != nil {
+			return reconcile.Result{}, errorUtil.Wrap(err, "failed to reconcile credential secrets")
+		}
+
 		instance.Status.Phase = croType.PhaseComplete
 		instance.Status.Message = msg
 		instance.Status.SecretRef = instance.Spec.SecretRef
+		instance.Status.Credentials = credentials
 		instance.Status.Strategy = strategyToUse
 		instance.Status.Provider = p.GetName()
 		if err = r.client.Status().Update(ctx, instance); err != nil {
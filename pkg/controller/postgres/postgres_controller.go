@@ -104,7 +104,7 @@ type ReconcilePostgres struct {
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
 func (r *ReconcilePostgres) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	r.logger.Info("reconciling Postgres")
-	ctx := context.TODO()
+	ctx := resources.NewDryRunContext(context.TODO())
 	cfgMgr := providers.NewConfigManager(providers.DefaultProviderConfigMapName, request.Namespace, r.client)
 
 	// Fetch the Postgres instance
@@ -153,6 +153,9 @@ func (r *ReconcilePostgres) Reconcile(request reconcile.Request) (reconcile.Resu
 		// delete the postgres if the deletion timestamp exists
 		if instance.DeletionTimestamp != nil {
 			msg, err := p.DeletePostgres(ctx, instance)
+			if reportErr := resources.ReportDryRun(ctx, instance); reportErr != nil {
+				return reconcile.Result{}, reportErr
+			}
 			if err != nil {
 				if updateErr := resources.UpdatePhase(ctx, r.client, instance, croType.PhaseFailed, msg.WrapError(err)); updateErr != nil {
 					return reconcile.Result{}, updateErr
@@ -178,6 +181,9 @@ func (r *ReconcilePostgres) Reconcile(request reconcile.Request) (reconcile.Resu
 
 		// create the postgres instance
 		ps, msg, err := p.CreatePostgres(ctx, instance)
+		if reportErr := resources.ReportDryRun(ctx, instance); reportErr != nil {
+			return reconcile.Result{}, reportErr
+		}
 		if err != nil {
 			instance.Status.SecretRef = &croType.SecretRef{}
 			if updateErr := resources.UpdatePhase(ctx, r.client, instance, croType.PhaseFailed, msg.WrapError(err)); updateErr != nil {
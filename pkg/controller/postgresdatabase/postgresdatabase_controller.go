@@ -0,0 +1,291 @@
+package postgresdatabase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	"github.com/integr8ly/cloud-resource-operator/pkg/providers"
+	"github.com/integr8ly/cloud-resource-operator/pkg/providers/aws"
+	"github.com/integr8ly/cloud-resource-operator/pkg/providers/openshift"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+
+	errorUtil "github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	defaultFinalizer     = "finalizers.cloud-resources-operator.integreatly.org"
+	inProgressReconcile  = time.Second * 10
+	defaultReconcileTime = time.Second * 60
+)
+
+// Add creates a new PostgresDatabase Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	clientSet, err := resources.GetK8Client()
+	if err != nil {
+		return errorUtil.Wrap(err, "failed to build client set")
+	}
+	return add(mgr, newReconciler(mgr, clientSet))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, cs *kubernetes.Clientset) reconcile.Reconciler {
+	client := mgr.GetClient()
+
+	logger := logrus.WithFields(logrus.Fields{"controller": "controller_postgres_database"})
+	runnerList := []providers.PostgresSQLRunner{openshift.NewOpenShiftPostgresSQLRunner(client, cs, logger), aws.NewAWSPostgresSQLRunner(client, logger)}
+	return &ReconcilePostgresDatabase{
+		client:           client,
+		scheme:           mgr.GetScheme(),
+		logger:           logger,
+		resourceProvider: resources.NewResourceProvider(client, mgr.GetScheme(), logger),
+		runnerList:       runnerList,
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("postgresdatabase-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource PostgresDatabase
+	err = c.Watch(&source.Kind{Type: &v1alpha1.PostgresDatabase{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// blank assignment to verify that ReconcilePostgresDatabase implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcilePostgresDatabase{}
+
+// ReconcilePostgresDatabase reconciles a PostgresDatabase object
+type ReconcilePostgresDatabase struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client           client.Client
+	scheme           *runtime.Scheme
+	logger           *logrus.Entry
+	resourceProvider *resources.ReconcileResourceProvider
+	runnerList       []providers.PostgresSQLRunner
+}
+
+// Reconcile reads that state of the cluster for a PostgresDatabase object and makes changes based on the state read
+// and what is in the PostgresDatabase.Spec
+func (r *ReconcilePostgresDatabase) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	r.logger.Info("reconciling postgres database")
+	ctx := resources.NewDryRunContext(context.TODO())
+
+	// Fetch the PostgresDatabase instance
+	instance := &v1alpha1.PostgresDatabase{}
+	err := r.client.Get(ctx, request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	// get the postgres instance the database is created in
+	ps := &v1alpha1.Postgres{}
+	if err = r.client.Get(ctx, types.NamespacedName{Name: instance.Spec.ResourceName, Namespace: instance.Namespace}, ps); err != nil {
+		// the database was removed along with the postgres instance, nothing is left to drop
+		if errors.IsNotFound(err) && instance.DeletionTimestamp != nil {
+			return reconcile.Result{}, r.removeFinalizer(ctx, instance)
+		}
+		errMsg := fmt.Sprintf("failed to get postgres cr %s", instance.Spec.ResourceName)
+		if updateErr := resources.UpdatePhase(ctx, r.client, instance, croType.PhaseFailed, croType.StatusMessage(errMsg)); updateErr != nil {
+			return reconcile.Result{}, updateErr
+		}
+		return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, errorUtil.Wrap(err, errMsg)
+	}
+
+	// validate the names used in statements
+	database := resources.StringOrDefault(instance.Spec.DatabaseName, providers.BuildPostgresIdentifier(instance.Name))
+	owner := providers.BuildPostgresOwnerRole(database)
+	if err = providers.ValidatePostgresIdentifier(database); err != nil {
+		errMsg := fmt.Sprintf("invalid database name %s", database)
+		if updateErr := resources.UpdatePhase(ctx, r.client, instance, croType.PhaseFailed, croType.StatusMessage(errMsg).WrapError(err)); updateErr != nil {
+			return reconcile.Result{}, updateErr
+		}
+		return reconcile.Result{}, errorUtil.Wrap(err, errMsg)
+	}
+
+	// the postgres instance is being removed or was never provisioned, nothing is left to drop
+	if instance.DeletionTimestamp != nil && (ps.DeletionTimestamp != nil || ps.Status.Strategy == "") {
+		return reconcile.Result{}, r.removeFinalizer(ctx, instance)
+	}
+
+	// wait for the postgres instance to be ready for connections
+	if instance.DeletionTimestamp == nil && ps.Status.Phase != croType.PhaseComplete {
+		msg := croType.StatusMessage(fmt.Sprintf("waiting for postgres instance %s to complete", ps.Name))
+		if err = resources.UpdatePhase(ctx, r.client, instance, croType.PhaseInProgress, msg); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{Requeue: true, RequeueAfter: inProgressReconcile}, nil
+	}
+
+	for _, p := range r.runnerList {
+		if !p.SupportsStrategy(ps.Status.Strategy) {
+			continue
+		}
+
+		// drop the database if the deletion timestamp exists
+		if instance.DeletionTimestamp != nil {
+			if err = p.ExecStatements(ctx, ps, "", providers.BuildDropDatabaseStatements(database, owner)); err != nil {
+				errMsg := fmt.Sprintf("failed to drop database %s", database)
+				if updateErr := resources.UpdatePhase(ctx, r.client, instance, croType.PhaseFailed, croType.StatusMessage(errMsg).WrapError(err)); updateErr != nil {
+					return reconcile.Result{}, updateErr
+				}
+				return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, errorUtil.Wrap(err, errMsg)
+			}
+			// the database is not dropped in dry run mode, so the finalizer is kept
+			if resources.IsDryRun() {
+				if err = resources.ReportDryRun(ctx, instance); err != nil {
+					return reconcile.Result{}, err
+				}
+				if err = resources.UpdatePhase(ctx, r.client, instance, croType.PhaseDeleteInProgress, croType.StatusMessage(fmt.Sprintf("dry run, database %s would be dropped", database))); err != nil {
+					return reconcile.Result{}, err
+				}
+				return reconcile.Result{Requeue: true, RequeueAfter: inProgressReconcile}, nil
+			}
+			return reconcile.Result{}, r.removeFinalizer(ctx, instance)
+		}
+
+		// create the database
+		dd, msg, err := r.createDatabase(ctx, p, instance, ps, database, owner)
+		if reportErr := resources.ReportDryRun(ctx, instance); reportErr != nil {
+			return reconcile.Result{}, reportErr
+		}
+		if err != nil {
+			if updateErr := resources.UpdatePhase(ctx, r.client, instance, croType.PhaseFailed, msg.WrapError(err)); updateErr != nil {
+				return reconcile.Result{}, updateErr
+			}
+			return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, err
+		}
+
+		// return the connection secret
+		if err = r.resourceProvider.ReconcileResultSecret(ctx, instance, dd.Data()); err != nil {
+			return reconcile.Result{}, errorUtil.Wrap(err, "failed to reconcile secret")
+		}
+
+		instance.Status.Phase = croType.PhaseComplete
+		instance.Status.Message = msg
+		instance.Status.SecretRef = instance.Spec.SecretRef
+		instance.Status.Strategy = ps.Status.Strategy
+		instance.Status.Provider = p.GetName()
+		if err = r.client.Status().Update(ctx, instance); err != nil {
+			return reconcile.Result{}, errorUtil.Wrapf(err, "failed to update instance %s in namespace %s", instance.Name, instance.Namespace)
+		}
+		return reconcile.Result{Requeue: true, RequeueAfter: resources.GetForcedReconcileTimeOrDefault(defaultReconcileTime)}, nil
+	}
+
+	// unsupported strategy
+	if err = resources.UpdatePhase(ctx, r.client, instance, croType.PhaseFailed, croType.StatusUnsupportedType); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, errorUtil.New(fmt.Sprintf("unsupported deployment strategy %s", ps.Status.Strategy))
+}
+
+func (r *ReconcilePostgresDatabase) createDatabase(ctx context.Context, p providers.PostgresSQLRunner, pd *v1alpha1.PostgresDatabase, ps *v1alpha1.Postgres, database, owner string) (*providers.PostgresDeploymentDetails, croType.StatusMessage, error) {
+	// handle finalizer so the database is dropped on deletion
+	if err := resources.CreateFinalizer(ctx, r.client, pd, defaultFinalizer); err != nil {
+		errMsg := "failed to set finalizer"
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
+	master, err := providers.GetPostgresDeploymentDetails(ctx, r.client, ps)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to get connection details for postgres instance %s", ps.Name)
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	if err = providers.ValidatePostgresRole(owner, master.Username); err != nil {
+		errMsg := fmt.Sprintf("invalid owner role %s", owner)
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
+	// reuse the password from an existing result secret, the role password is reset to it on every reconcile
+	password, err := r.getOrGeneratePassword(ctx, pd)
+	if err != nil {
+		errMsg := "failed to get database owner password"
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
+	if err = p.ExecStatements(ctx, ps, "", providers.BuildCreateRoleStatements(owner, password)); err != nil {
+		errMsg := fmt.Sprintf("failed to create owner role %s", owner)
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
+	exists, err := p.DatabaseExists(ctx, ps, database)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to check if database %s exists", database)
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	if !exists {
+		r.logger.Infof("creating database %s in postgres instance %s", database, ps.Name)
+		if err = p.ExecStatements(ctx, ps, "", providers.BuildCreateDatabaseStatements(database, owner)); err != nil {
+			errMsg := fmt.Sprintf("failed to create database %s", database)
+			return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+	}
+
+	if err = p.ExecStatements(ctx, ps, "", providers.BuildRestrictDatabaseStatements(database, owner)); err != nil {
+		errMsg := fmt.Sprintf("failed to restrict access to database %s", database)
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
+	return &providers.PostgresDeploymentDetails{
+		Username: owner,
+		Password: password,
+		Host:     master.Host,
+		Database: database,
+		Port:     master.Port,
+	}, "creation successful", nil
+}
+
+func (r *ReconcilePostgresDatabase) getOrGeneratePassword(ctx context.Context, pd *v1alpha1.PostgresDatabase) (string, error) {
+	secNs := pd.Namespace
+	if pd.Spec.SecretRef.Namespace != "" {
+		secNs = pd.Spec.SecretRef.Namespace
+	}
+	sec := &v1.Secret{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: pd.Spec.SecretRef.Name, Namespace: secNs}, sec)
+	if err != nil && !errors.IsNotFound(err) {
+		return "", errorUtil.Wrapf(err, "failed to get secret %s", pd.Spec.SecretRef.Name)
+	}
+	if password := string(sec.Data["password"]); password != "" {
+		return password, nil
+	}
+	return resources.GeneratePassword()
+}
+
+func (r *ReconcilePostgresDatabase) removeFinalizer(ctx context.Context, pd *v1alpha1.PostgresDatabase) error {
+	resources.RemoveFinalizer(&pd.ObjectMeta, defaultFinalizer)
+	if err := r.client.Update(ctx, pd); err != nil {
+		return errorUtil.Wrapf(err, "failed to remove finalizer from instance %s", pd.Name)
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package postgresdatabase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis"
+	integreatlyv1alpha1 "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	"github.com/integr8ly/cloud-resource-operator/pkg/providers"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var testLogger = logrus.WithFields(logrus.Fields{"testing": "true"})
+
+func buildTestScheme() (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := apis.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	return scheme, nil
+}
+
+func buildTestPostgres() *integreatlyv1alpha1.Postgres {
+	return &integreatlyv1alpha1.Postgres{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Status: integreatlyv1alpha1.PostgresStatus{
+			Strategy:  providers.AWSDeploymentStrategy,
+			Phase:     croType.PhaseComplete,
+			SecretRef: &croType.SecretRef{Name: "test-sec"},
+		},
+	}
+}
+
+func buildTestPostgresSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sec",
+			Namespace: "test",
+		},
+		Data: (&providers.PostgresDeploymentDetails{
+			Username: "master",
+			Password: "master-password",
+			Host:     "test.rds.amazonaws.com",
+			Database: "postgres",
+			Port:     5432,
+		}).Data(),
+	}
+}
+
+func buildTestPostgresDatabase() *integreatlyv1alpha1.PostgresDatabase {
+	return &integreatlyv1alpha1.PostgresDatabase{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-db",
+			Namespace: "test",
+		},
+		Spec: integreatlyv1alpha1.PostgresDatabaseSpec{
+			ResourceName: "test",
+			SecretRef:    &croType.SecretRef{Name: "test-db-sec"},
+		},
+	}
+}
+
+func buildTestPostgresDatabaseDeleting() *integreatlyv1alpha1.PostgresDatabase {
+	pd := buildTestPostgresDatabase()
+	now := metav1.Now()
+	pd.DeletionTimestamp = &now
+	pd.Finalizers = []string{defaultFinalizer}
+	return pd
+}
+
+func buildTestSQLRunner(exists bool) *providers.PostgresSQLRunnerMock {
+	return &providers.PostgresSQLRunnerMock{
+		GetNameFunc: func() string {
+			return "test-runner"
+		},
+		SupportsStrategyFunc: func(s string) bool {
+			return s == providers.AWSDeploymentStrategy
+		},
+		DatabaseExistsFunc: func(ctx context.Context, ps *integreatlyv1alpha1.Postgres, database string) (bool, error) {
+			return exists, nil
+		},
+		ExecStatementsFunc: func(ctx context.Context, ps *integreatlyv1alpha1.Postgres, database string, stmts []string) error {
+			return nil
+		},
+	}
+}
+
+func TestReconcilePostgresDatabase_Reconcile(t *testing.T) {
+	scheme, err := buildTestScheme()
+	if err != nil {
+		t.Fatal("failed to build scheme", err)
+	}
+
+	type fields struct {
+		client client.Client
+		runner *providers.PostgresSQLRunnerMock
+	}
+	tests := []struct {
+		name           string
+		fields         fields
+		wantPhase      croType.StatusPhase
+		wantStatements [][]string
+		wantErr        bool
+	}{
+		{
+			name: "test database is created with an owner role",
+			fields: fields{
+				client: fake.NewFakeClientWithScheme(scheme, buildTestPostgres(), buildTestPostgresSecret(), buildTestPostgresDatabase()),
+				runner: buildTestSQLRunner(false),
+			},
+			wantPhase: croType.PhaseComplete,
+			wantStatements: [][]string{
+				nil,
+				providers.BuildCreateDatabaseStatements("test_db", "test_db_owner"),
+				providers.BuildRestrictDatabaseStatements("test_db", "test_db_owner"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "test existing database is not created again",
+			fields: fields{
+				client: fake.NewFakeClientWithScheme(scheme, buildTestPostgres(), buildTestPostgresSecret(), buildTestPostgresDatabase()),
+				runner: buildTestSQLRunner(true),
+			},
+			wantPhase: croType.PhaseComplete,
+			wantStatements: [][]string{
+				nil,
+				providers.BuildRestrictDatabaseStatements("test_db", "test_db_owner"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "test database and owner role are dropped on deletion",
+			fields: fields{
+				client: fake.NewFakeClientWithScheme(scheme, buildTestPostgres(), buildTestPostgresSecret(), buildTestPostgresDatabaseDeleting()),
+				runner: buildTestSQLRunner(true),
+			},
+			wantStatements: [][]string{
+				providers.BuildDropDatabaseStatements("test_db", "test_db_owner"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "test unsupported strategy",
+			fields: fields{
+				client: fake.NewFakeClientWithScheme(scheme, func() *integreatlyv1alpha1.Postgres {
+					ps := buildTestPostgres()
+					ps.Status.Strategy = "unsupported"
+					return ps
+				}(), buildTestPostgresSecret(), buildTestPostgresDatabase()),
+				runner: buildTestSQLRunner(true),
+			},
+			wantPhase: croType.PhaseFailed,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ReconcilePostgresDatabase{
+				client:           tt.fields.client,
+				scheme:           scheme,
+				logger:           testLogger,
+				resourceProvider: resources.NewResourceProvider(tt.fields.client, scheme, testLogger),
+				runnerList:       []providers.PostgresSQLRunner{tt.fields.runner},
+			}
+			_, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-db", Namespace: "test"}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Reconcile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			calls := tt.fields.runner.ExecStatementsCalls()
+			if len(calls) != len(tt.wantStatements) {
+				t.Fatalf("Reconcile() executed %d statement groups, want %d", len(calls), len(tt.wantStatements))
+			}
+			for i, want := range tt.wantStatements {
+				// the create role statements contain a generated password
+				if want == nil {
+					continue
+				}
+				if !reflect.DeepEqual(calls[i].Stmts, want) {
+					t.Errorf("Reconcile() statements = %v, want %v", calls[i].Stmts, want)
+				}
+			}
+
+			pd := &integreatlyv1alpha1.PostgresDatabase{}
+			if err := tt.fields.client.Get(context.TODO(), types.NamespacedName{Name: "test-db", Namespace: "test"}, pd); err != nil {
+				t.Fatal("failed to get postgres database", err)
+			}
+			if pd.Status.Phase != tt.wantPhase {
+				t.Errorf("Reconcile() phase = %v, want %v", pd.Status.Phase, tt.wantPhase)
+			}
+			if tt.wantPhase != croType.PhaseComplete {
+				return
+			}
+			sec := &corev1.Secret{}
+			if err := tt.fields.client.Get(context.TODO(), types.NamespacedName{Name: "test-db-sec", Namespace: "test"}, sec); err != nil {
+				t.Fatal("failed to get result secret", err)
+			}
+			if string(sec.Data["username"]) != "test_db_owner" || string(sec.Data["database"]) != "test_db" || string(sec.Data["host"]) != "test.rds.amazonaws.com" {
+				t.Errorf("Reconcile() unexpected result secret data %v", sec.Data)
+			}
+		})
+	}
+}
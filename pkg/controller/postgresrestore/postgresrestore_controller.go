@@ -0,0 +1,316 @@
+package postgresrestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+
+	"github.com/integr8ly/cloud-resource-operator/pkg/annotations"
+	integreatlyv1alpha1 "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	"github.com/integr8ly/cloud-resource-operator/pkg/providers"
+	croAws "github.com/integr8ly/cloud-resource-operator/pkg/providers/aws"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+
+	errorUtil "github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// set on the postgres resource created by a restore, with the name of the restore as the value
+	restoredFromAnnotation = "restoredFrom"
+	inProgressReconcile    = time.Second * 30
+)
+
+// Add creates a new PostgresRestore Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	logger := logrus.WithFields(logrus.Fields{"controller": "controller_postgres_restore"})
+	return &ReconcilePostgresRestore{
+		client:            mgr.GetClient(),
+		scheme:            mgr.GetScheme(),
+		logger:            logger,
+		ConfigManager:     croAws.NewDefaultConfigMapConfigManager(mgr.GetClient()),
+		CredentialManager: croAws.NewCredentialMinterCredentialManager(mgr.GetClient()),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("postgresrestore-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource PostgresRestore
+	err = c.Watch(&source.Kind{Type: &integreatlyv1alpha1.PostgresRestore{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// blank assignment to verify that ReconcilePostgresRestore implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcilePostgresRestore{}
+
+// ReconcilePostgresRestore reconciles a PostgresRestore object
+type ReconcilePostgresRestore struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client            client.Client
+	scheme            *runtime.Scheme
+	logger            *logrus.Entry
+	ConfigManager     croAws.ConfigManager
+	CredentialManager croAws.CredentialManager
+}
+
+// Reconcile reads that state of the cluster for a PostgresRestore object and makes changes based on the state read
+// and what is in the PostgresRestore.Spec
+func (r *ReconcilePostgresRestore) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	r.logger.Info("reconciling postgres restore")
+	ctx := context.TODO()
+
+	// Fetch the PostgresRestore instance
+	instance := &integreatlyv1alpha1.PostgresRestore{}
+	err := r.client.Get(ctx, request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	// check status, if complete return, the restored instance is managed by its own postgres resource from now on
+	if instance.Status.Phase == croType.PhaseComplete {
+		r.logger.Infof("skipping restore %s as phase is complete", instance.Name)
+		return reconcile.Result{}, nil
+	}
+
+	if err = validateRestore(instance); err != nil {
+		errMsg := "invalid restore"
+		if updateErr := resources.UpdatePhase(ctx, r.client, instance, croType.PhaseFailed, croType.StatusMessage(errMsg).WrapError(err)); updateErr != nil {
+			return reconcile.Result{}, updateErr
+		}
+		return reconcile.Result{}, errorUtil.Wrap(err, errMsg)
+	}
+
+	// get source postgres cr
+	postgresCr := &integreatlyv1alpha1.Postgres{}
+	err = r.client.Get(ctx, types.NamespacedName{Name: instance.Spec.ResourceName, Namespace: instance.Namespace}, postgresCr)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to get postgres resource: %s", err.Error())
+		if updateErr := resources.UpdatePhase(ctx, r.client, instance, croType.PhaseFailed, croType.StatusMessage(errMsg)); updateErr != nil {
+			return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, updateErr
+		}
+		return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, errorUtil.New(errMsg)
+	}
+
+	// check postgres deployment strategy is aws
+	if postgresCr.Status.Strategy != providers.AWSDeploymentStrategy {
+		errMsg := fmt.Sprintf("the resource %s uses an unsupported provider strategy %s, only resources using the aws provider are valid", instance.Spec.ResourceName, postgresCr.Status.Strategy)
+		if updateErr := resources.UpdatePhase(ctx, r.client, instance, croType.PhaseFailed, croType.StatusMessage(errMsg)); updateErr != nil {
+			return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, updateErr
+		}
+		return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, errorUtil.New(errMsg)
+	}
+
+	// get resource region
+	stratCfg, err := r.ConfigManager.ReadStorageStrategy(ctx, providers.PostgresResourceType, postgresCr.Spec.Tier)
+	if err != nil {
+		if updateErr := resources.UpdatePhase(ctx, r.client, instance, croType.PhaseFailed, croType.StatusMessage(err.Error())); updateErr != nil {
+			return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, updateErr
+		}
+		return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, err
+	}
+
+	defRegion, err := croAws.GetRegionFromStrategyOrDefault(ctx, r.client, stratCfg)
+	if err != nil {
+		return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, err
+	}
+	if stratCfg.Region == "" {
+		r.logger.Debugf("region not set in deployment strategy configuration, using default region %s", defRegion)
+		stratCfg.Region = defRegion
+	}
+
+	// create the credentials to be used by the aws resource providers, not to be used by end-user
+	providerCreds, err := r.CredentialManager.ReconcileProviderCredentials(ctx, postgresCr.Namespace)
+	if err != nil {
+		errMsg := "failed to reconcile rds credentials"
+		if updateErr := resources.UpdatePhase(ctx, r.client, instance, croType.PhaseFailed, croType.StatusMessage(errMsg)); updateErr != nil {
+			return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, updateErr
+		}
+		return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, errorUtil.Wrap(err, errMsg)
+	}
+
+	// setup aws rds session
+	rdsSvc := rds.New(croAws.ConfigureDryRun(ctx, session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String(stratCfg.Region),
+		Credentials: credentials.NewStaticCredentials(providerCreds.AccessKeyID, providerCreds.SecretAccessKey, ""),
+	}))))
+
+	// restore the instance and return the phase
+	phase, msg, err := r.restoreInstance(ctx, rdsSvc, instance, postgresCr)
+	if updateErr := resources.UpdatePhase(ctx, r.client, instance, phase, msg); updateErr != nil {
+		return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, updateErr
+	}
+	if err != nil {
+		return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, err
+	}
+	if phase == croType.PhaseComplete {
+		instance.Status.SecretRef = instance.Spec.SecretRef
+		instance.Status.Strategy = postgresCr.Status.Strategy
+		instance.Status.Provider = postgresCr.Status.Provider
+		if err = r.client.Status().Update(ctx, instance); err != nil {
+			return reconcile.Result{}, errorUtil.Wrapf(err, "failed to update instance %s in namespace %s", instance.Name, instance.Namespace)
+		}
+		return reconcile.Result{}, nil
+	}
+	return reconcile.Result{Requeue: true, RequeueAfter: inProgressReconcile}, nil
+}
+
+// restoreInstance restores the rds instance of the source postgres resource in to a new instance, then creates a
+// postgres resource to manage the restored instance. the restore is complete once that postgres resource is complete
+func (r *ReconcilePostgresRestore) restoreInstance(ctx context.Context, rdsSvc rdsiface.RDSAPI, restore *integreatlyv1alpha1.PostgresRestore, postgres *integreatlyv1alpha1.Postgres) (croType.StatusPhase, croType.StatusMessage, error) {
+	// an existing postgres resource must have been created by this restore
+	target := &integreatlyv1alpha1.Postgres{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: restore.Spec.TargetName, Namespace: restore.Namespace}, target)
+	if err != nil && !errors.IsNotFound(err) {
+		errMsg := fmt.Sprintf("failed to get postgres resource %s", restore.Spec.TargetName)
+		return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	if err == nil {
+		if target.Annotations[restoredFromAnnotation] != restore.Name {
+			errMsg := fmt.Sprintf("postgres resource %s already exists and was not created by restore %s", target.Name, restore.Name)
+			return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.New(errMsg)
+		}
+		if target.Status.Phase != croType.PhaseComplete {
+			return croType.PhaseInProgress, croType.StatusMessage(fmt.Sprintf("waiting for restored postgres resource %s to complete", target.Name)), nil
+		}
+		return croType.PhaseComplete, croType.StatusMessage(fmt.Sprintf("restored in to postgres resource %s", target.Name)), nil
+	}
+
+	// get source and restored instance names
+	sourceID, err := croAws.BuildResourceIdentifierFromObject(ctx, r.client, postgres.ObjectMeta, postgres.Spec.AdoptIdentifier, croAws.DefaultAwsIdentifierLength)
+	if err != nil {
+		errMsg := "failed to get cluster name"
+		return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	target, targetID, err := croAws.BuildRestoredPostgres(ctx, r.client, postgres, restore.Spec.TargetName, restore.Spec.SecretRef)
+	if err != nil {
+		errMsg := "failed to build restored postgres resource"
+		return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	annotations.Add(target, restoredFromAnnotation, restore.Name)
+
+	listOutput, err := rdsSvc.DescribeDBInstances(&rds.DescribeDBInstancesInput{})
+	if err != nil {
+		errMsg := "failed to describe rds instances"
+		return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	var sourceInstance, targetInstance *rds.DBInstance
+	for _, i := range listOutput.DBInstances {
+		switch *i.DBInstanceIdentifier {
+		case sourceID:
+			sourceInstance = i
+		case targetID:
+			targetInstance = i
+		}
+	}
+
+	// start the restore, the restored instance is placed in the same network as the source instance
+	if targetInstance == nil {
+		if sourceInstance == nil {
+			errMsg := fmt.Sprintf("rds instance %s not found", sourceID)
+			return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.New(errMsg)
+		}
+		if _, err = rdsSvc.RestoreDBInstanceToPointInTime(buildRestoreInput(restore, sourceInstance, targetID)); err != nil {
+			errMsg := fmt.Sprintf("failed to restore rds instance %s", sourceID)
+			return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+		r.logger.Infof("started restore of rds instance %s in to %s", sourceID, targetID)
+	}
+
+	// the restored instance does not exist in dry run mode, its postgres resource and credentials are not created
+	if resources.IsDryRun() {
+		return croType.PhaseInProgress, croType.StatusMessage(fmt.Sprintf("dry run, rds instance %s would be restored in to postgres resource %s", sourceID, target.Name)), nil
+	}
+
+	// the restored instance keeps the master credentials of the source instance
+	if err = croAws.ReconcileRestoredRDSCredentials(ctx, r.client, postgres, target); err != nil {
+		errMsg := "failed to reconcile restored rds credentials"
+		return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	if err = r.client.Create(ctx, target); err != nil {
+		errMsg := fmt.Sprintf("failed to create postgres resource %s", target.Name)
+		return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	return croType.PhaseInProgress, croType.StatusMessage(fmt.Sprintf("restore started in to postgres resource %s", target.Name)), nil
+}
+
+func buildRestoreInput(restore *integreatlyv1alpha1.PostgresRestore, source *rds.DBInstance, targetID string) *rds.RestoreDBInstanceToPointInTimeInput {
+	input := &rds.RestoreDBInstanceToPointInTimeInput{
+		SourceDBInstanceIdentifier: source.DBInstanceIdentifier,
+		TargetDBInstanceIdentifier: aws.String(targetID),
+		DBInstanceClass:            source.DBInstanceClass,
+		MultiAZ:                    source.MultiAZ,
+		PubliclyAccessible:         source.PubliclyAccessible,
+		DeletionProtection:         source.DeletionProtection,
+		CopyTagsToSnapshot:         source.CopyTagsToSnapshot,
+	}
+	if restore.Spec.UseLatestRestorableTime {
+		input.UseLatestRestorableTime = aws.Bool(true)
+	} else {
+		// the restore time is validated before the restore is started
+		restoreTime, _ := time.Parse(time.RFC3339, restore.Spec.RestoreTime)
+		input.RestoreTime = aws.Time(restoreTime)
+	}
+	if source.DBSubnetGroup != nil {
+		input.DBSubnetGroupName = source.DBSubnetGroup.DBSubnetGroupName
+	}
+	for _, sg := range source.VpcSecurityGroups {
+		input.VpcSecurityGroupIds = append(input.VpcSecurityGroupIds, sg.VpcSecurityGroupId)
+	}
+	return input
+}
+
+// validateRestore ensures exactly one restore time is requested, and the restore does not target its source
+func validateRestore(restore *integreatlyv1alpha1.PostgresRestore) error {
+	if restore.Spec.TargetName == restore.Spec.ResourceName {
+		return errorUtil.New("target name must differ from the resource name")
+	}
+	if restore.Spec.UseLatestRestorableTime == (restore.Spec.RestoreTime != "") {
+		return errorUtil.New("exactly one of restore time or use latest restorable time must be set")
+	}
+	if restore.Spec.RestoreTime != "" {
+		if _, err := time.Parse(time.RFC3339, restore.Spec.RestoreTime); err != nil {
+			return errorUtil.Wrapf(err, "failed to parse restore time %s", restore.Spec.RestoreTime)
+		}
+	}
+	return nil
+}
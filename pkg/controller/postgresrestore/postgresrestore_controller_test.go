@@ -0,0 +1,334 @@
+package postgresrestore
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis"
+	crov1 "github.com/integr8ly/cloud-resource-operator/pkg/apis/config/v1"
+	integreatlyv1alpha1 "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	croAws "github.com/integr8ly/cloud-resource-operator/pkg/providers/aws"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var testLogger = logrus.WithFields(logrus.Fields{"testing": "true"})
+
+type mockRdsClient struct {
+	rdsiface.RDSAPI
+	dbInstances  []*rds.DBInstance
+	restoreCalls []*rds.RestoreDBInstanceToPointInTimeInput
+}
+
+func (m *mockRdsClient) DescribeDBInstances(*rds.DescribeDBInstancesInput) (*rds.DescribeDBInstancesOutput, error) {
+	return &rds.DescribeDBInstancesOutput{
+		DBInstances: m.dbInstances,
+	}, nil
+}
+
+func (m *mockRdsClient) RestoreDBInstanceToPointInTime(input *rds.RestoreDBInstanceToPointInTimeInput) (*rds.RestoreDBInstanceToPointInTimeOutput, error) {
+	m.restoreCalls = append(m.restoreCalls, input)
+	return &rds.RestoreDBInstanceToPointInTimeOutput{}, nil
+}
+
+func buildTestScheme() (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+	err := crov1.SchemeBuilder.AddToScheme(scheme)
+	err = apis.AddToScheme(scheme)
+	err = corev1.AddToScheme(scheme)
+	if err != nil {
+		return nil, err
+	}
+	return scheme, nil
+}
+
+func buildTestInfrastructure() *crov1.Infrastructure {
+	return &crov1.Infrastructure{
+		ObjectMeta: controllerruntime.ObjectMeta{
+			Name: "cluster",
+		},
+		Status: crov1.InfrastructureStatus{
+			InfrastructureName: "test",
+		},
+	}
+}
+
+func buildPostgresRestore() *integreatlyv1alpha1.PostgresRestore {
+	return &integreatlyv1alpha1.PostgresRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-restore",
+			Namespace: "test",
+		},
+		Spec: integreatlyv1alpha1.PostgresRestoreSpec{
+			ResourceName:            "test",
+			UseLatestRestorableTime: true,
+			TargetName:              "test-restored",
+			SecretRef:               &croType.SecretRef{Name: "test-restored-sec"},
+		},
+	}
+}
+
+func buildPostgres() *integreatlyv1alpha1.Postgres {
+	return &integreatlyv1alpha1.Postgres{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+			Labels:    map[string]string{"productName": "test"},
+		},
+		Spec: integreatlyv1alpha1.PostgresSpec{
+			Type: "managed",
+			Tier: "production",
+		},
+		Status: integreatlyv1alpha1.PostgresStatus{
+			Strategy: "aws",
+			Phase:    croType.PhaseComplete,
+		},
+	}
+}
+
+func buildRestoredPostgres(restoredFrom string, phase croType.StatusPhase) *integreatlyv1alpha1.Postgres {
+	return &integreatlyv1alpha1.Postgres{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-restored",
+			Namespace:   "test",
+			Annotations: map[string]string{restoredFromAnnotation: restoredFrom},
+		},
+		Status: integreatlyv1alpha1.PostgresStatus{
+			Phase: phase,
+		},
+	}
+}
+
+func buildCredSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-aws-rds-credentials",
+			Namespace: "test",
+		},
+		Data: map[string][]byte{
+			"user":     []byte("postgres"),
+			"password": []byte("test"),
+		},
+	}
+}
+
+func buildDBInstances(ids ...string) []*rds.DBInstance {
+	var instances []*rds.DBInstance
+	for _, id := range ids {
+		instances = append(instances, &rds.DBInstance{
+			DBInstanceIdentifier: aws.String(id),
+			DBInstanceClass:      aws.String("db.t2.small"),
+			MultiAZ:              aws.Bool(true),
+			DBSubnetGroup:        &rds.DBSubnetGroup{DBSubnetGroupName: aws.String("test-subnet-group")},
+			VpcSecurityGroups:    []*rds.VpcSecurityGroupMembership{{VpcSecurityGroupId: aws.String("test-sg")}},
+		})
+	}
+	return instances
+}
+
+func TestReconcilePostgresRestore_restoreInstance(t *testing.T) {
+	ctx := context.TODO()
+	scheme, err := buildTestScheme()
+	if err != nil {
+		t.Fatal("failed to build scheme", err)
+	}
+	infraClient := fake.NewFakeClientWithScheme(scheme, buildTestInfrastructure())
+	sourceID, err := croAws.BuildInfraNameFromObject(ctx, infraClient, buildPostgres().ObjectMeta, croAws.DefaultAwsIdentifierLength)
+	if err != nil {
+		t.Fatal("failed to build source identifier", err)
+	}
+	_, targetID, err := croAws.BuildRestoredPostgres(ctx, infraClient, buildPostgres(), "test-restored", nil)
+	if err != nil {
+		t.Fatal("failed to build target identifier", err)
+	}
+	tests := []struct {
+		name             string
+		client           client.Client
+		rdsSvc           *mockRdsClient
+		want             croType.StatusPhase
+		wantRestoreCalls int
+		wantTarget       bool
+		wantErr          bool
+		dryRun           bool
+	}{
+		{
+			name:             "test restore is started and the restored postgres resource created",
+			client:           fake.NewFakeClientWithScheme(scheme, buildTestInfrastructure(), buildPostgres(), buildCredSecret()),
+			rdsSvc:           &mockRdsClient{dbInstances: buildDBInstances(sourceID)},
+			want:             croType.PhaseInProgress,
+			wantRestoreCalls: 1,
+			wantTarget:       true,
+		},
+		{
+			name:             "test restored postgres resource is not created in dry run mode",
+			client:           fake.NewFakeClientWithScheme(scheme, buildTestInfrastructure(), buildPostgres(), buildCredSecret()),
+			rdsSvc:           &mockRdsClient{dbInstances: buildDBInstances(sourceID)},
+			want:             croType.PhaseInProgress,
+			wantRestoreCalls: 1,
+			dryRun:           true,
+		},
+		{
+			name:       "test restore is not started again when the restored instance exists",
+			client:     fake.NewFakeClientWithScheme(scheme, buildTestInfrastructure(), buildPostgres(), buildCredSecret()),
+			rdsSvc:     &mockRdsClient{dbInstances: buildDBInstances(sourceID, targetID)},
+			want:       croType.PhaseInProgress,
+			wantTarget: true,
+		},
+		{
+			name:    "test restore fails when the source instance does not exist",
+			client:  fake.NewFakeClientWithScheme(scheme, buildTestInfrastructure(), buildPostgres(), buildCredSecret()),
+			rdsSvc:  &mockRdsClient{},
+			want:    croType.PhaseFailed,
+			wantErr: true,
+		},
+		{
+			name:   "test restore waits for the restored postgres resource to complete",
+			client: fake.NewFakeClientWithScheme(scheme, buildTestInfrastructure(), buildPostgres(), buildRestoredPostgres("test-restore", croType.PhaseInProgress)),
+			rdsSvc: &mockRdsClient{dbInstances: buildDBInstances(sourceID, targetID)},
+			want:   croType.PhaseInProgress,
+		},
+		{
+			name:   "test restore completes with the restored postgres resource",
+			client: fake.NewFakeClientWithScheme(scheme, buildTestInfrastructure(), buildPostgres(), buildRestoredPostgres("test-restore", croType.PhaseComplete)),
+			rdsSvc: &mockRdsClient{dbInstances: buildDBInstances(sourceID, targetID)},
+			want:   croType.PhaseComplete,
+		},
+		{
+			name:    "test restore fails when the target postgres resource was not created by it",
+			client:  fake.NewFakeClientWithScheme(scheme, buildTestInfrastructure(), buildPostgres(), buildRestoredPostgres("", croType.PhaseComplete)),
+			rdsSvc:  &mockRdsClient{dbInstances: buildDBInstances(sourceID, targetID)},
+			want:    croType.PhaseFailed,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.dryRun {
+				if err := os.Setenv(resources.EnvDryRun, "true"); err != nil {
+					t.Fatal("failed to set dry run env var", err)
+				}
+				defer os.Unsetenv(resources.EnvDryRun)
+			}
+			r := &ReconcilePostgresRestore{
+				client: tt.client,
+				scheme: scheme,
+				logger: testLogger,
+			}
+			got, _, err := r.restoreInstance(ctx, tt.rdsSvc, buildPostgresRestore(), buildPostgres())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("restoreInstance() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("restoreInstance() got = %v, want %v", got, tt.want)
+			}
+			if len(tt.rdsSvc.restoreCalls) != tt.wantRestoreCalls {
+				t.Fatalf("restoreInstance() restore called %d times, want %d", len(tt.rdsSvc.restoreCalls), tt.wantRestoreCalls)
+			}
+			if tt.wantRestoreCalls > 0 {
+				input := tt.rdsSvc.restoreCalls[0]
+				if *input.SourceDBInstanceIdentifier != sourceID || *input.TargetDBInstanceIdentifier != targetID || !*input.UseLatestRestorableTime {
+					t.Errorf("restoreInstance() unexpected restore input %v", input)
+				}
+				if *input.DBSubnetGroupName != "test-subnet-group" || *input.VpcSecurityGroupIds[0] != "test-sg" {
+					t.Errorf("restoreInstance() restored instance is not in the source network %v", input)
+				}
+			}
+			if tt.dryRun {
+				if err := tt.client.Get(ctx, types.NamespacedName{Name: "test-restored", Namespace: "test"}, &integreatlyv1alpha1.Postgres{}); !errors.IsNotFound(err) {
+					t.Errorf("restoreInstance() restored postgres resource was created in dry run mode, err = %v", err)
+				}
+			}
+			if !tt.wantTarget {
+				return
+			}
+			target := &integreatlyv1alpha1.Postgres{}
+			if err := tt.client.Get(ctx, types.NamespacedName{Name: "test-restored", Namespace: "test"}, target); err != nil {
+				t.Fatal("failed to get restored postgres resource", err)
+			}
+			if target.Annotations[restoredFromAnnotation] != "test-restore" || target.Spec.Tier != "production" || target.Labels["productName"] != "test" {
+				t.Errorf("restoreInstance() unexpected restored postgres resource %v", target)
+			}
+			sec := &corev1.Secret{}
+			if err := tt.client.Get(ctx, types.NamespacedName{Name: "test-restored-aws-rds-credentials", Namespace: "test"}, sec); err != nil {
+				t.Fatal("failed to get restored credentials secret", err)
+			}
+			if string(sec.Data["password"]) != "test" {
+				t.Errorf("restoreInstance() restored password = %s, want test", sec.Data["password"])
+			}
+		})
+	}
+}
+
+func TestValidateRestore(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(restore *integreatlyv1alpha1.PostgresRestore)
+		wantErr bool
+	}{
+		{
+			name:   "test latest restorable time is valid",
+			modify: func(restore *integreatlyv1alpha1.PostgresRestore) {},
+		},
+		{
+			name: "test restore time is valid",
+			modify: func(restore *integreatlyv1alpha1.PostgresRestore) {
+				restore.Spec.UseLatestRestorableTime = false
+				restore.Spec.RestoreTime = "2019-11-01T10:00:00Z"
+			},
+		},
+		{
+			name: "test error when both restore times are set",
+			modify: func(restore *integreatlyv1alpha1.PostgresRestore) {
+				restore.Spec.RestoreTime = "2019-11-01T10:00:00Z"
+			},
+			wantErr: true,
+		},
+		{
+			name: "test error when no restore time is set",
+			modify: func(restore *integreatlyv1alpha1.PostgresRestore) {
+				restore.Spec.UseLatestRestorableTime = false
+			},
+			wantErr: true,
+		},
+		{
+			name: "test error when restore time is invalid",
+			modify: func(restore *integreatlyv1alpha1.PostgresRestore) {
+				restore.Spec.UseLatestRestorableTime = false
+				restore.Spec.RestoreTime = "yesterday"
+			},
+			wantErr: true,
+		},
+		{
+			name: "test error when restoring in to the source resource",
+			modify: func(restore *integreatlyv1alpha1.PostgresRestore) {
+				restore.Spec.TargetName = restore.Spec.ResourceName
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restore := buildPostgresRestore()
+			tt.modify(restore)
+			if err := validateRestore(restore); (err != nil) != tt.wantErr {
+				t.Errorf("validateRestore() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
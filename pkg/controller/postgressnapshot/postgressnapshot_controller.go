@@ -6,6 +6,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/rds"
@@ -30,6 +31,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+// defaultCopyKMSKeyID is the aws managed key used to encrypt copies of encrypted snapshots
+const defaultCopyKMSKeyID = "alias/aws/rds"
+
 // Add creates a new PostgresSnapshot Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
@@ -108,15 +112,26 @@ func (r *ReconcilePostgresSnapshot) Reconcile(request reconcile.Request) (reconc
 		return reconcile.Result{}, err
 	}
 
-	// check status, if complete return
-	if instance.Status.Phase == croType.PhaseComplete {
+	// check status, if the snapshot and its copy are complete return
+	if instance.DeletionTimestamp == nil && instance.Status.Phase == croType.PhaseComplete && isCopyComplete(instance) {
 		r.logger.Infof("skipping creation of snapshot for %s as phase is complete", instance.Name)
 		return reconcile.Result{Requeue: true, RequeueAfter: resources.SuccessReconcileTime}, nil
 	}
 
+	// nothing to clean up if no snapshot was started
+	if instance.DeletionTimestamp != nil && !resources.HasFinalizer(&instance.ObjectMeta, croAws.DefaultFinalizer) {
+		return reconcile.Result{}, nil
+	}
+
 	// get postgres cr
 	postgresCr := &integreatlyv1alpha1.Postgres{}
 	err = r.client.Get(ctx, types.NamespacedName{Name: instance.Spec.ResourceName, Namespace: instance.Namespace}, postgresCr)
+	if err != nil && (instance.DeletionTimestamp != nil || instance.Spec.SnapshotID != "") && errors.IsNotFound(err) {
+		// snapshots can outlive their postgres resource, such as the final snapshot taken when the resource is deleted,
+		// they are found in the region recorded when they were taken
+		r.logger.Infof("postgres resource %s not found, reconciling snapshot %s using the default strategy", instance.Spec.ResourceName, instance.Name)
+		postgresCr, err = nil, nil
+	}
 	if err != nil {
 		errMsg := fmt.Sprintf("failed to get postgres resource: %s", err.Error())
 		if updateErr := resources.UpdateSnapshotPhase(ctx, r.client, instance, croType.PhaseFailed, croType.StatusMessage(errMsg)); updateErr != nil {
@@ -126,7 +141,7 @@ func (r *ReconcilePostgresSnapshot) Reconcile(request reconcile.Request) (reconc
 	}
 
 	// check postgres deployment strategy is aws
-	if postgresCr.Status.Strategy != providers.AWSDeploymentStrategy {
+	if postgresCr != nil && postgresCr.Status.Strategy != providers.AWSDeploymentStrategy {
 		errMsg := fmt.Sprintf("the resource %s uses an unsupported provider strategy %s, only resources using the aws provider are valid", instance.Spec.ResourceName, postgresCr.Status.Strategy)
 		if updateErr := resources.UpdateSnapshotPhase(ctx, r.client, instance, croType.PhaseFailed, croType.StatusMessage(errMsg)); updateErr != nil {
 			return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, updateErr
@@ -135,7 +150,10 @@ func (r *ReconcilePostgresSnapshot) Reconcile(request reconcile.Request) (reconc
 	}
 
 	// get resource region
-	stratCfg, err := r.ConfigManager.ReadStorageStrategy(ctx, providers.PostgresResourceType, postgresCr.Spec.Tier)
+	stratCfg := &croAws.StrategyConfig{}
+	if postgresCr != nil {
+		stratCfg, err = r.ConfigManager.ReadStorageStrategy(ctx, providers.PostgresResourceType, postgresCr.Spec.Tier)
+	}
 	if err != nil {
 		if updateErr := resources.UpdateSnapshotPhase(ctx, r.client, instance, croType.PhaseFailed, croType.StatusMessage(err.Error())); updateErr != nil {
 			return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, updateErr
@@ -152,8 +170,19 @@ func (r *ReconcilePostgresSnapshot) Reconcile(request reconcile.Request) (reconc
 		stratCfg.Region = defRegion
 	}
 
+	// snapshots stay in the region they are taken in, the region is recorded so the snapshot can be found once the postgres
+	// resource is deleted or its strategy changes
+	if instance.Spec.Region != "" {
+		stratCfg.Region = instance.Spec.Region
+	} else if postgresCr != nil {
+		instance.Spec.Region = stratCfg.Region
+		if err := r.client.Update(ctx, instance); err != nil {
+			return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, errorUtil.Wrapf(err, "failed to record region of snapshot %s", instance.Name)
+		}
+	}
+
 	// create the credentials to be used by the aws resource providers, not to be used by end-user
-	providerCreds, err := r.CredentialManager.ReconcileProviderCredentials(ctx, postgresCr.Namespace)
+	providerCreds, err := r.CredentialManager.ReconcileProviderCredentials(ctx, instance.Namespace)
 	if err != nil {
 		errMsg := "failed to reconcile rds credentials"
 		if updateErr := resources.UpdateSnapshotPhase(ctx, r.client, instance, croType.PhaseFailed, croType.StatusMessage(errMsg)); updateErr != nil {
@@ -163,13 +192,49 @@ func (r *ReconcilePostgresSnapshot) Reconcile(request reconcile.Request) (reconc
 	}
 
 	// setup aws rds session
-	rdsSvc := rds.New(session.Must(session.NewSession(&aws.Config{
+	rdsSvc := rds.New(croAws.ConfigureDryRun(ctx, session.Must(session.NewSession(&aws.Config{
 		Region:      aws.String(stratCfg.Region),
 		Credentials: credentials.NewStaticCredentials(providerCreds.AccessKeyID, providerCreds.SecretAccessKey, ""),
-	})))
+	}))))
+
+	// setup aws rds session in the copy region
+	var copySvc rdsiface.RDSAPI
+	if copyRegion := getCopyRegion(instance); copyRegion != "" {
+		copySvc = rds.New(croAws.ConfigureDryRun(ctx, session.Must(session.NewSession(&aws.Config{
+			Region:      aws.String(copyRegion),
+			Credentials: credentials.NewStaticCredentials(providerCreds.AccessKeyID, providerCreds.SecretAccessKey, ""),
+		}))))
+	}
+
+	// delete the snapshot and its copy when the cr is deleted
+	if instance.DeletionTimestamp != nil {
+		if err := r.deleteSnapshot(ctx, rdsSvc, copySvc, instance); err != nil {
+			return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, err
+		}
+		// the cr is kept in dry run mode, its snapshots are deleted once dry run mode is disabled
+		if resources.IsDryRun() {
+			return reconcile.Result{Requeue: true, RequeueAfter: resources.SuccessReconcileTime}, nil
+		}
+		return reconcile.Result{}, nil
+	}
+	if err := resources.CreateFinalizer(ctx, r.client, instance, croAws.DefaultFinalizer); err != nil {
+		return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, err
+	}
 
 	// create the snapshot and return the phase
 	phase, msg, err := r.createSnapshot(ctx, rdsSvc, instance, postgresCr)
+
+	// copy the snapshot to the copy region once it has been created
+	if err == nil && phase == croType.PhaseComplete && copySvc != nil {
+		copyPhase, copyMsg, copyErr := r.copySnapshot(ctx, rdsSvc, copySvc, instance, stratCfg.Region)
+		instance.Status.Copy = &croType.ResourceTypeSnapshotCopyStatus{
+			Region:     instance.Spec.CopyRegion,
+			SnapshotID: instance.Status.SnapshotID,
+			Phase:      copyPhase,
+			Message:    copyMsg,
+		}
+		err = copyErr
+	}
 	if updateErr := resources.UpdateSnapshotPhase(ctx, r.client, instance, phase, msg); updateErr != nil {
 		return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, updateErr
 	}
@@ -180,6 +245,11 @@ func (r *ReconcilePostgresSnapshot) Reconcile(request reconcile.Request) (reconc
 }
 
 func (r *ReconcilePostgresSnapshot) createSnapshot(ctx context.Context, rdsSvc rdsiface.RDSAPI, snapshot *integreatlyv1alpha1.PostgresSnapshot, postgres *integreatlyv1alpha1.Postgres) (croType.StatusPhase, croType.StatusMessage, error) {
+	// an existing snapshot is referred to instead of taking a new one
+	if snapshot.Spec.SnapshotID != "" {
+		return r.reconcileExistingSnapshot(rdsSvc, snapshot)
+	}
+
 	// generate snapshot name
 	snapshotName, err := croAws.BuildTimestampedInfraNameFromObjectCreation(ctx, r.client, snapshot.ObjectMeta, croAws.DefaultAwsIdentifierLength)
 	if err != nil {
@@ -195,7 +265,7 @@ func (r *ReconcilePostgresSnapshot) createSnapshot(ctx context.Context, rdsSvc r
 	}
 
 	// get instance name
-	instanceName, err := croAws.BuildInfraNameFromObject(ctx, r.client, postgres.ObjectMeta, croAws.DefaultAwsIdentifierLength)
+	instanceName, err := croAws.BuildResourceIdentifierFromObject(ctx, r.client, postgres.ObjectMeta, postgres.Spec.AdoptIdentifier, croAws.DefaultAwsIdentifierLength)
 	if err != nil {
 		errMsg := "failed to get cluster name"
 		return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
@@ -237,3 +307,133 @@ func (r *ReconcilePostgresSnapshot) createSnapshot(ctx context.Context, rdsSvc r
 	r.logger.Info(msg)
 	return croType.PhaseInProgress, croType.StatusMessage(msg), nil
 }
+
+// reconcileExistingSnapshot waits for the existing snapshot a cr refers to to become available
+func (r *ReconcilePostgresSnapshot) reconcileExistingSnapshot(rdsSvc rdsiface.RDSAPI, snapshot *integreatlyv1alpha1.PostgresSnapshot) (croType.StatusPhase, croType.StatusMessage, error) {
+	snapshot.Status.SnapshotID = snapshot.Spec.SnapshotID
+	listOutput, err := rdsSvc.DescribeDBSnapshots(&rds.DescribeDBSnapshotsInput{
+		DBSnapshotIdentifier: aws.String(snapshot.Spec.SnapshotID),
+	})
+	if err != nil && !isSnapshotNotFound(err) {
+		errMsg := fmt.Sprintf("failed to describe rds snapshot %s", snapshot.Spec.SnapshotID)
+		return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	if listOutput == nil || len(listOutput.DBSnapshots) == 0 {
+		return croType.PhaseInProgress, croType.StatusMessage(fmt.Sprintf("waiting for snapshot %s to be created", snapshot.Spec.SnapshotID)), nil
+	}
+	if status := aws.StringValue(listOutput.DBSnapshots[0].Status); status != "available" {
+		return croType.PhaseInProgress, croType.StatusMessage(fmt.Sprintf("current snapshot status : %s", status)), nil
+	}
+	return croType.PhaseComplete, "snapshot created", nil
+}
+
+func (r *ReconcilePostgresSnapshot) copySnapshot(ctx context.Context, rdsSvc rdsiface.RDSAPI, copySvc rdsiface.RDSAPI, snapshot *integreatlyv1alpha1.PostgresSnapshot, sourceRegion string) (croType.StatusPhase, croType.StatusMessage, error) {
+	snapshotName := snapshot.Status.SnapshotID
+
+	// check copy exists
+	copyOutput, err := copySvc.DescribeDBSnapshots(&rds.DescribeDBSnapshotsInput{
+		DBSnapshotIdentifier: aws.String(snapshotName),
+	})
+	if err != nil && !isSnapshotNotFound(err) {
+		errMsg := fmt.Sprintf("failed to describe rds snapshot copy in region %s", snapshot.Spec.CopyRegion)
+		return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	if copyOutput != nil && len(copyOutput.DBSnapshots) != 0 {
+		foundCopy := copyOutput.DBSnapshots[0]
+		if aws.StringValue(foundCopy.Status) == "available" {
+			return croType.PhaseComplete, "snapshot copied", nil
+		}
+		msg := fmt.Sprintf("current snapshot copy status : %s", aws.StringValue(foundCopy.Status))
+		r.logger.Info(msg)
+		return croType.PhaseInProgress, croType.StatusMessage(msg), nil
+	}
+
+	// the copy must reference the source snapshot by arn
+	listOutput, err := rdsSvc.DescribeDBSnapshots(&rds.DescribeDBSnapshotsInput{
+		DBSnapshotIdentifier: aws.String(snapshotName),
+	})
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to describe rds snapshot %s to copy", snapshotName)
+		return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	if len(listOutput.DBSnapshots) == 0 {
+		errMsg := fmt.Sprintf("rds snapshot %s to copy not found", snapshotName)
+		return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.New(errMsg)
+	}
+	foundSnapshot := listOutput.DBSnapshots[0]
+
+	r.logger.Infof("copying rds snapshot %s to region %s", snapshotName, snapshot.Spec.CopyRegion)
+	if _, err = copySvc.CopyDBSnapshot(buildCopyInput(snapshot, foundSnapshot, sourceRegion)); err != nil {
+		errMsg := fmt.Sprintf("error copying rds snapshot to region %s", snapshot.Spec.CopyRegion)
+		return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	return croType.PhaseInProgress, "snapshot copy started", nil
+}
+
+func (r *ReconcilePostgresSnapshot) deleteSnapshot(ctx context.Context, rdsSvc rdsiface.RDSAPI, copySvc rdsiface.RDSAPI, snapshot *integreatlyv1alpha1.PostgresSnapshot) error {
+	if copySvc != nil && snapshot.Status.Copy != nil && snapshot.Status.Copy.SnapshotID != "" {
+		r.logger.Infof("deleting rds snapshot copy %s in region %s", snapshot.Status.Copy.SnapshotID, snapshot.Status.Copy.Region)
+		if _, err := copySvc.DeleteDBSnapshot(&rds.DeleteDBSnapshotInput{
+			DBSnapshotIdentifier: aws.String(snapshot.Status.Copy.SnapshotID),
+		}); err != nil && !isSnapshotNotFound(err) {
+			return errorUtil.Wrapf(err, "failed to delete rds snapshot copy %s", snapshot.Status.Copy.SnapshotID)
+		}
+	}
+	if snapshot.Status.SnapshotID != "" {
+		r.logger.Infof("deleting rds snapshot %s", snapshot.Status.SnapshotID)
+		if _, err := rdsSvc.DeleteDBSnapshot(&rds.DeleteDBSnapshotInput{
+			DBSnapshotIdentifier: aws.String(snapshot.Status.SnapshotID),
+		}); err != nil && !isSnapshotNotFound(err) {
+			return errorUtil.Wrapf(err, "failed to delete rds snapshot %s", snapshot.Status.SnapshotID)
+		}
+	}
+	// the snapshots are not deleted in dry run mode, so the finalizer is kept
+	if resources.IsDryRun() {
+		return resources.UpdateSnapshotPhase(ctx, r.client, snapshot, croType.PhaseDeleteInProgress, croType.StatusMessage(fmt.Sprintf("dry run, rds snapshot %s would be deleted", snapshot.Status.SnapshotID)))
+	}
+	resources.RemoveFinalizer(&snapshot.ObjectMeta, croAws.DefaultFinalizer)
+	if err := r.client.Update(ctx, snapshot); err != nil {
+		return errorUtil.Wrapf(err, "failed to remove finalizer from instance %s", snapshot.Name)
+	}
+	return nil
+}
+
+func buildCopyInput(snapshot *integreatlyv1alpha1.PostgresSnapshot, foundSnapshot *rds.DBSnapshot, sourceRegion string) *rds.CopyDBSnapshotInput {
+	input := &rds.CopyDBSnapshotInput{
+		SourceDBSnapshotIdentifier: foundSnapshot.DBSnapshotArn,
+		TargetDBSnapshotIdentifier: aws.String(snapshot.Status.SnapshotID),
+		SourceRegion:               aws.String(sourceRegion),
+		CopyTags:                   aws.Bool(true),
+	}
+	// kms keys are regional, an encrypted snapshot must be re-encrypted with a key in the copy region
+	if aws.BoolValue(foundSnapshot.Encrypted) {
+		input.KmsKeyId = aws.String(defaultCopyKMSKeyID)
+		if snapshot.Spec.CopyKMSKeyID != "" {
+			input.KmsKeyId = aws.String(snapshot.Spec.CopyKMSKeyID)
+		}
+	}
+	return input
+}
+
+// getCopyRegion returns the region a copy of the snapshot is expected in, the status is preferred so a copy is still
+// deleted after the copy region is removed from the spec
+func getCopyRegion(snapshot *integreatlyv1alpha1.PostgresSnapshot) string {
+	if snapshot.Status.Copy != nil && snapshot.Status.Copy.Region != "" {
+		return snapshot.Status.Copy.Region
+	}
+	return snapshot.Spec.CopyRegion
+}
+
+func isCopyComplete(snapshot *integreatlyv1alpha1.PostgresSnapshot) bool {
+	if snapshot.Spec.CopyRegion == "" {
+		return true
+	}
+	return snapshot.Status.Copy != nil && snapshot.Status.Copy.Phase == croType.PhaseComplete
+}
+
+func isSnapshotNotFound(err error) bool {
+	if awsErr, ok := err.(awserr.Error); ok {
+		return awsErr.Code() == rds.ErrCodeDBSnapshotNotFoundFault
+	}
+	return false
+}
@@ -2,6 +2,8 @@ package postgressnapshot
 
 import (
 	"context"
+	"os"
+	"reflect"
 	"testing"
 
 	controllerruntime "sigs.k8s.io/controller-runtime"
@@ -12,14 +14,17 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/rds"
 
 	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
 	integreatlyv1alpha1 "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
 	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
 	croAws "github.com/integr8ly/cloud-resource-operator/pkg/providers/aws"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/runtime"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -33,6 +38,9 @@ type mockRdsClient struct {
 	wantErrDelete bool
 	dbSnapshots   []*rds.DBSnapshot
 	dbSnapshot    *rds.DBSnapshot
+	deleteErr     error
+	copyCalls     []*rds.CopyDBSnapshotInput
+	deleteCalls   []string
 }
 
 func buildTestScheme() (*runtime.Scheme, error) {
@@ -54,6 +62,12 @@ func buildPostgresSnapshot() *integreatlyv1alpha1.PostgresSnapshot {
 	}
 }
 
+func buildExistingPostgresSnapshot(snapshotID string) *integreatlyv1alpha1.PostgresSnapshot {
+	snapshot := buildPostgresSnapshot()
+	snapshot.Spec.SnapshotID = snapshotID
+	return snapshot
+}
+
 func buildPostgres() *integreatlyv1alpha1.Postgres {
 	return &integreatlyv1alpha1.Postgres{
 		ObjectMeta: metav1.ObjectMeta{
@@ -101,6 +115,16 @@ func (m *mockRdsClient) CreateDBSnapshot(*rds.CreateDBSnapshotInput) (*rds.Creat
 	}, nil
 }
 
+func (m *mockRdsClient) CopyDBSnapshot(input *rds.CopyDBSnapshotInput) (*rds.CopyDBSnapshotOutput, error) {
+	m.copyCalls = append(m.copyCalls, input)
+	return &rds.CopyDBSnapshotOutput{}, nil
+}
+
+func (m *mockRdsClient) DeleteDBSnapshot(input *rds.DeleteDBSnapshotInput) (*rds.DeleteDBSnapshotOutput, error) {
+	m.deleteCalls = append(m.deleteCalls, *input.DBSnapshotIdentifier)
+	return &rds.DeleteDBSnapshotOutput{}, m.deleteErr
+}
+
 func TestReconcilePostgresSnapshot_createSnapshot(t *testing.T) {
 	ctx := context.TODO()
 	scheme, err := buildTestScheme()
@@ -187,6 +211,42 @@ func TestReconcilePostgresSnapshot_createSnapshot(t *testing.T) {
 			want:    types.PhaseInProgress,
 			wantErr: false,
 		},
+		{
+			name: "test existing snapshot is available without postgres resource",
+			args: args{
+				ctx:      ctx,
+				rdsSvc:   &mockRdsClient{dbSnapshots: buildSnapshots("final", "available")},
+				snapshot: buildExistingPostgresSnapshot("final"),
+				postgres: nil,
+			},
+			fields: fields{
+				client:            fake.NewFakeClientWithScheme(scheme, buildTestInfrastructure(), buildExistingPostgresSnapshot("final")),
+				scheme:            scheme,
+				logger:            testLogger,
+				ConfigManager:     nil,
+				CredentialManager: nil,
+			},
+			want:    types.PhaseComplete,
+			wantErr: false,
+		},
+		{
+			name: "test waiting for existing snapshot to be created",
+			args: args{
+				ctx:      ctx,
+				rdsSvc:   &mockRdsClient{},
+				snapshot: buildExistingPostgresSnapshot("final"),
+				postgres: nil,
+			},
+			fields: fields{
+				client:            fake.NewFakeClientWithScheme(scheme, buildTestInfrastructure(), buildExistingPostgresSnapshot("final")),
+				scheme:            scheme,
+				logger:            testLogger,
+				ConfigManager:     nil,
+				CredentialManager: nil,
+			},
+			want:    types.PhaseInProgress,
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -208,3 +268,237 @@ func TestReconcilePostgresSnapshot_createSnapshot(t *testing.T) {
 		})
 	}
 }
+
+func buildCopiedPostgresSnapshot(snapshotName string) *integreatlyv1alpha1.PostgresSnapshot {
+	snapshot := buildPostgresSnapshot()
+	snapshot.Spec.CopyRegion = "eu-central-1"
+	snapshot.Status.SnapshotID = snapshotName
+	return snapshot
+}
+
+func buildSourceSnapshots(snapshotName string, encrypted bool) []*rds.DBSnapshot {
+	snapshots := buildSnapshots(snapshotName, "available")
+	snapshots[0].DBSnapshotArn = aws.String("arn:aws:rds:eu-west-1:test:snapshot:" + snapshotName)
+	snapshots[0].Encrypted = aws.Bool(encrypted)
+	return snapshots
+}
+
+func TestReconcilePostgresSnapshot_copySnapshot(t *testing.T) {
+	snapshotName := "test-snapshot"
+	tests := []struct {
+		name     string
+		snapshot *integreatlyv1alpha1.PostgresSnapshot
+		rdsSvc   *mockRdsClient
+		copySvc  *mockRdsClient
+		want     types.StatusPhase
+		wantCopy *rds.CopyDBSnapshotInput
+		wantErr  bool
+	}{
+		{
+			name:     "test copy of unencrypted snapshot started",
+			snapshot: buildCopiedPostgresSnapshot(snapshotName),
+			rdsSvc:   &mockRdsClient{dbSnapshots: buildSourceSnapshots(snapshotName, false)},
+			copySvc:  &mockRdsClient{},
+			want:     types.PhaseInProgress,
+			wantCopy: &rds.CopyDBSnapshotInput{
+				SourceDBSnapshotIdentifier: aws.String("arn:aws:rds:eu-west-1:test:snapshot:" + snapshotName),
+				TargetDBSnapshotIdentifier: aws.String(snapshotName),
+				SourceRegion:               aws.String("eu-west-1"),
+				CopyTags:                   aws.Bool(true),
+			},
+		},
+		{
+			name:     "test copy of encrypted snapshot started with the default kms key",
+			snapshot: buildCopiedPostgresSnapshot(snapshotName),
+			rdsSvc:   &mockRdsClient{dbSnapshots: buildSourceSnapshots(snapshotName, true)},
+			copySvc:  &mockRdsClient{},
+			want:     types.PhaseInProgress,
+			wantCopy: &rds.CopyDBSnapshotInput{
+				SourceDBSnapshotIdentifier: aws.String("arn:aws:rds:eu-west-1:test:snapshot:" + snapshotName),
+				TargetDBSnapshotIdentifier: aws.String(snapshotName),
+				SourceRegion:               aws.String("eu-west-1"),
+				CopyTags:                   aws.Bool(true),
+				KmsKeyId:                   aws.String(defaultCopyKMSKeyID),
+			},
+		},
+		{
+			name: "test copy of encrypted snapshot started with the configured kms key",
+			snapshot: func() *integreatlyv1alpha1.PostgresSnapshot {
+				snapshot := buildCopiedPostgresSnapshot(snapshotName)
+				snapshot.Spec.CopyKMSKeyID = "test-key"
+				return snapshot
+			}(),
+			rdsSvc:  &mockRdsClient{dbSnapshots: buildSourceSnapshots(snapshotName, true)},
+			copySvc: &mockRdsClient{},
+			want:    types.PhaseInProgress,
+			wantCopy: &rds.CopyDBSnapshotInput{
+				SourceDBSnapshotIdentifier: aws.String("arn:aws:rds:eu-west-1:test:snapshot:" + snapshotName),
+				TargetDBSnapshotIdentifier: aws.String(snapshotName),
+				SourceRegion:               aws.String("eu-west-1"),
+				CopyTags:                   aws.Bool(true),
+				KmsKeyId:                   aws.String("test-key"),
+			},
+		},
+		{
+			name:     "test copy in progress",
+			snapshot: buildCopiedPostgresSnapshot(snapshotName),
+			rdsSvc:   &mockRdsClient{dbSnapshots: buildSourceSnapshots(snapshotName, false)},
+			copySvc:  &mockRdsClient{dbSnapshots: buildSnapshots(snapshotName, "copying")},
+			want:     types.PhaseInProgress,
+		},
+		{
+			name:     "test copy complete",
+			snapshot: buildCopiedPostgresSnapshot(snapshotName),
+			rdsSvc:   &mockRdsClient{dbSnapshots: buildSourceSnapshots(snapshotName, false)},
+			copySvc:  &mockRdsClient{dbSnapshots: buildSnapshots(snapshotName, "available")},
+			want:     types.PhaseComplete,
+		},
+		{
+			name:     "test copy fails when source snapshot is not found",
+			snapshot: buildCopiedPostgresSnapshot(snapshotName),
+			rdsSvc:   &mockRdsClient{},
+			copySvc:  &mockRdsClient{},
+			want:     types.PhaseFailed,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ReconcilePostgresSnapshot{
+				logger: testLogger,
+			}
+			got, _, err := r.copySnapshot(context.TODO(), tt.rdsSvc, tt.copySvc, tt.snapshot, "eu-west-1")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("copySnapshot() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("copySnapshot() got = %v, want %v", got, tt.want)
+			}
+			if tt.wantCopy == nil {
+				if len(tt.copySvc.copyCalls) != 0 {
+					t.Errorf("copySnapshot() unexpected copy %v", tt.copySvc.copyCalls)
+				}
+				return
+			}
+			if len(tt.copySvc.copyCalls) != 1 || !reflect.DeepEqual(tt.copySvc.copyCalls[0], tt.wantCopy) {
+				t.Errorf("copySnapshot() copy calls = %v, want %v", tt.copySvc.copyCalls, tt.wantCopy)
+			}
+		})
+	}
+}
+
+func TestReconcilePostgresSnapshot_deleteSnapshot(t *testing.T) {
+	scheme, err := buildTestScheme()
+	if err != nil {
+		t.Fatal("failed to build scheme", err)
+	}
+	snapshotName := "test-snapshot"
+	buildDeletedSnapshot := func(copied bool) *integreatlyv1alpha1.PostgresSnapshot {
+		snapshot := buildPostgresSnapshot()
+		snapshot.Finalizers = []string{croAws.DefaultFinalizer}
+		snapshot.Status.SnapshotID = snapshotName
+		if copied {
+			snapshot.Status.Copy = &types.ResourceTypeSnapshotCopyStatus{
+				Region:     "eu-central-1",
+				SnapshotID: snapshotName,
+			}
+		}
+		return snapshot
+	}
+	tests := []struct {
+		name            string
+		snapshot        *integreatlyv1alpha1.PostgresSnapshot
+		rdsSvc          *mockRdsClient
+		copySvc         *mockRdsClient
+		wantDeleted     []string
+		wantCopyDeleted []string
+		dryRun          bool
+		wantErr         bool
+	}{
+		{
+			name:            "test snapshot and copy are deleted",
+			snapshot:        buildDeletedSnapshot(true),
+			rdsSvc:          &mockRdsClient{},
+			copySvc:         &mockRdsClient{},
+			wantDeleted:     []string{snapshotName},
+			wantCopyDeleted: []string{snapshotName},
+		},
+		{
+			name:        "test snapshot without copy is deleted",
+			snapshot:    buildDeletedSnapshot(false),
+			rdsSvc:      &mockRdsClient{},
+			wantDeleted: []string{snapshotName},
+		},
+		{
+			name:            "test no error when snapshots are already deleted",
+			snapshot:        buildDeletedSnapshot(true),
+			rdsSvc:          &mockRdsClient{deleteErr: awserr.New(rds.ErrCodeDBSnapshotNotFoundFault, "not found", nil)},
+			copySvc:         &mockRdsClient{deleteErr: awserr.New(rds.ErrCodeDBSnapshotNotFoundFault, "not found", nil)},
+			wantDeleted:     []string{snapshotName},
+			wantCopyDeleted: []string{snapshotName},
+		},
+		{
+			name:            "test error when snapshot deletion fails",
+			snapshot:        buildDeletedSnapshot(true),
+			rdsSvc:          &mockRdsClient{deleteErr: awserr.New(rds.ErrCodeInvalidDBSnapshotStateFault, "copying", nil)},
+			copySvc:         &mockRdsClient{},
+			wantDeleted:     []string{snapshotName},
+			wantCopyDeleted: []string{snapshotName},
+			wantErr:         true,
+		},
+		{
+			name:        "test finalizer is kept in dry run mode",
+			snapshot:    buildDeletedSnapshot(false),
+			rdsSvc:      &mockRdsClient{},
+			wantDeleted: []string{snapshotName},
+			dryRun:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.dryRun {
+				if err := os.Setenv(resources.EnvDryRun, "true"); err != nil {
+					t.Fatal("failed to enable dry run mode", err)
+				}
+				defer os.Unsetenv(resources.EnvDryRun)
+			}
+			c := fake.NewFakeClientWithScheme(scheme, tt.snapshot)
+			r := &ReconcilePostgresSnapshot{
+				client: c,
+				scheme: scheme,
+				logger: testLogger,
+			}
+			var copySvc rdsiface.RDSAPI
+			if tt.copySvc != nil {
+				copySvc = tt.copySvc
+			}
+			if err := r.deleteSnapshot(context.TODO(), tt.rdsSvc, copySvc, tt.snapshot); (err != nil) != tt.wantErr {
+				t.Errorf("deleteSnapshot() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(tt.rdsSvc.deleteCalls, tt.wantDeleted) {
+				t.Errorf("deleteSnapshot() deleted = %v, want %v", tt.rdsSvc.deleteCalls, tt.wantDeleted)
+			}
+			if tt.copySvc != nil && !reflect.DeepEqual(tt.copySvc.deleteCalls, tt.wantCopyDeleted) {
+				t.Errorf("deleteSnapshot() deleted copies = %v, want %v", tt.copySvc.deleteCalls, tt.wantCopyDeleted)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := &integreatlyv1alpha1.PostgresSnapshot{}
+			if err := c.Get(context.TODO(), k8sTypes.NamespacedName{Name: tt.snapshot.Name, Namespace: tt.snapshot.Namespace}, got); err != nil {
+				t.Fatal("failed to get snapshot", err)
+			}
+			if tt.dryRun {
+				if !resources.HasFinalizer(&got.ObjectMeta, croAws.DefaultFinalizer) {
+					t.Error("deleteSnapshot() removed the finalizer in dry run mode")
+				}
+				return
+			}
+			if len(got.Finalizers) != 0 {
+				t.Errorf("deleteSnapshot() finalizers = %v, want none", got.Finalizers)
+			}
+		})
+	}
+}
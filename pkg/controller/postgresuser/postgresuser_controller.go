@@ -0,0 +1,390 @@
+package postgresuser
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	"github.com/integr8ly/cloud-resource-operator/pkg/providers"
+	"github.com/integr8ly/cloud-resource-operator/pkg/providers/aws"
+	"github.com/integr8ly/cloud-resource-operator/pkg/providers/openshift"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+
+	errorUtil "github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	defaultFinalizer     = "finalizers.cloud-resources-operator.integreatly.org"
+	inProgressReconcile  = time.Second * 10
+	defaultReconcileTime = time.Second * 60
+)
+
+// Add creates a new PostgresUser Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	clientSet, err := resources.GetK8Client()
+	if err != nil {
+		return errorUtil.Wrap(err, "failed to build client set")
+	}
+	return add(mgr, newReconciler(mgr, clientSet))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, cs *kubernetes.Clientset) reconcile.Reconciler {
+	client := mgr.GetClient()
+
+	logger := logrus.WithFields(logrus.Fields{"controller": "controller_postgres_user"})
+	runnerList := []providers.PostgresSQLRunner{openshift.NewOpenShiftPostgresSQLRunner(client, cs, logger), aws.NewAWSPostgresSQLRunner(client, logger)}
+	iamProviderList := []providers.PostgresIAMCredentialProvider{aws.NewAWSPostgresIAMCredentialProvider(client, logger)}
+	return &ReconcilePostgresUser{
+		client:           client,
+		scheme:           mgr.GetScheme(),
+		logger:           logger,
+		resourceProvider: resources.NewResourceProvider(client, mgr.GetScheme(), logger),
+		runnerList:       runnerList,
+		iamProviderList:  iamProviderList,
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("postgresuser-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource PostgresUser
+	err = c.Watch(&source.Kind{Type: &v1alpha1.PostgresUser{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// blank assignment to verify that ReconcilePostgresUser implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcilePostgresUser{}
+
+// ReconcilePostgresUser reconciles a PostgresUser object
+type ReconcilePostgresUser struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client           client.Client
+	scheme           *runtime.Scheme
+	logger           *logrus.Entry
+	resourceProvider *resources.ReconcileResourceProvider
+	runnerList       []providers.PostgresSQLRunner
+	iamProviderList  []providers.PostgresIAMCredentialProvider
+}
+
+// Reconcile reads that state of the cluster for a PostgresUser object and makes changes based on the state read
+// and what is in the PostgresUser.Spec
+func (r *ReconcilePostgresUser) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	r.logger.Info("reconciling postgres user")
+	ctx := resources.NewDryRunContext(context.TODO())
+
+	// Fetch the PostgresUser instance
+	instance := &v1alpha1.PostgresUser{}
+	err := r.client.Get(ctx, request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	// get the postgres instance the user is created in
+	ps := &v1alpha1.Postgres{}
+	if err = r.client.Get(ctx, types.NamespacedName{Name: instance.Spec.ResourceName, Namespace: instance.Namespace}, ps); err != nil {
+		// the user was removed along with the postgres instance, nothing is left to drop
+		if errors.IsNotFound(err) && instance.DeletionTimestamp != nil {
+			return reconcile.Result{}, r.cleanupAndRemoveFinalizer(ctx, instance)
+		}
+		errMsg := fmt.Sprintf("failed to get postgres cr %s", instance.Spec.ResourceName)
+		if updateErr := resources.UpdatePhase(ctx, r.client, instance, croType.PhaseFailed, croType.StatusMessage(errMsg)); updateErr != nil {
+			return reconcile.Result{}, updateErr
+		}
+		return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, errorUtil.Wrap(err, errMsg)
+	}
+
+	// validate the names used in statements
+	username := resources.StringOrDefault(instance.Spec.Username, providers.BuildPostgresIdentifier(instance.Name))
+	for _, id := range []string{instance.Spec.DatabaseName, username} {
+		if err = providers.ValidatePostgresIdentifier(id); err != nil {
+			errMsg := fmt.Sprintf("invalid name %s", id)
+			if updateErr := resources.UpdatePhase(ctx, r.client, instance, croType.PhaseFailed, croType.StatusMessage(errMsg).WrapError(err)); updateErr != nil {
+				return reconcile.Result{}, updateErr
+			}
+			return reconcile.Result{}, errorUtil.Wrap(err, errMsg)
+		}
+	}
+
+	// the postgres instance is being removed or was never provisioned, nothing is left to drop
+	if instance.DeletionTimestamp != nil && (ps.DeletionTimestamp != nil || ps.Status.Strategy == "") {
+		return reconcile.Result{}, r.cleanupAndRemoveFinalizer(ctx, instance)
+	}
+
+	// wait for the postgres instance to be ready for connections
+	if instance.DeletionTimestamp == nil && ps.Status.Phase != croType.PhaseComplete {
+		msg := croType.StatusMessage(fmt.Sprintf("waiting for postgres instance %s to complete", ps.Name))
+		if err = resources.UpdatePhase(ctx, r.client, instance, croType.PhaseInProgress, msg); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{Requeue: true, RequeueAfter: inProgressReconcile}, nil
+	}
+
+	for _, p := range r.runnerList {
+		if !p.SupportsStrategy(ps.Status.Strategy) {
+			continue
+		}
+
+		// drop the user if the deletion timestamp exists
+		if instance.DeletionTimestamp != nil {
+			if msg, err := r.deleteUser(ctx, p, instance, ps, username); err != nil {
+				if updateErr := resources.UpdatePhase(ctx, r.client, instance, croType.PhaseFailed, msg.WrapError(err)); updateErr != nil {
+					return reconcile.Result{}, updateErr
+				}
+				return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, err
+			}
+			// the role is not dropped in dry run mode, so the finalizer is kept
+			if resources.IsDryRun() {
+				if err = resources.ReportDryRun(ctx, instance); err != nil {
+					return reconcile.Result{}, err
+				}
+				if err = resources.UpdatePhase(ctx, r.client, instance, croType.PhaseDeleteInProgress, croType.StatusMessage(fmt.Sprintf("dry run, role %s would be dropped", username))); err != nil {
+					return reconcile.Result{}, err
+				}
+				return reconcile.Result{Requeue: true, RequeueAfter: inProgressReconcile}, nil
+			}
+			return reconcile.Result{}, r.removeFinalizer(ctx, instance)
+		}
+
+		// create the user
+		ud, msg, err := r.createUser(ctx, p, instance, ps, username)
+		if reportErr := resources.ReportDryRun(ctx, instance); reportErr != nil {
+			return reconcile.Result{}, reportErr
+		}
+		if err != nil {
+			if updateErr := resources.UpdatePhase(ctx, r.client, instance, croType.PhaseFailed, msg.WrapError(err)); updateErr != nil {
+				return reconcile.Result{}, updateErr
+			}
+			return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, err
+		}
+		if ud == nil {
+			if err = resources.UpdatePhase(ctx, r.client, instance, croType.PhaseInProgress, msg); err != nil {
+				return reconcile.Result{}, err
+			}
+			return reconcile.Result{Requeue: true, RequeueAfter: inProgressReconcile}, nil
+		}
+
+		// return the connection secret
+		if err = r.resourceProvider.ReconcileResultSecret(ctx, instance, ud.Data()); err != nil {
+			return reconcile.Result{}, errorUtil.Wrap(err, "failed to reconcile secret")
+		}
+
+		instance.Status.Phase = croType.PhaseComplete
+		instance.Status.Message = msg
+		instance.Status.SecretRef = instance.Spec.SecretRef
+		instance.Status.Strategy = ps.Status.Strategy
+		instance.Status.Provider = p.GetName()
+		if err = r.client.Status().Update(ctx, instance); err != nil {
+			return reconcile.Result{}, errorUtil.Wrapf(err, "failed to update instance %s in namespace %s", instance.Name, instance.Namespace)
+		}
+		return reconcile.Result{Requeue: true, RequeueAfter: resources.GetForcedReconcileTimeOrDefault(defaultReconcileTime)}, nil
+	}
+
+	// unsupported strategy
+	if err = resources.UpdatePhase(ctx, r.client, instance, croType.PhaseFailed, croType.StatusUnsupportedType); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, errorUtil.New(fmt.Sprintf("unsupported deployment strategy %s", ps.Status.Strategy))
+}
+
+func (r *ReconcilePostgresUser) createUser(ctx context.Context, p providers.PostgresSQLRunner, pu *v1alpha1.PostgresUser, ps *v1alpha1.Postgres, username string) (providers.DeploymentDetails, croType.StatusMessage, error) {
+	// handle finalizer so the user is dropped on deletion
+	if err := resources.CreateFinalizer(ctx, r.client, pu, defaultFinalizer); err != nil {
+		errMsg := "failed to set finalizer"
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
+	master, err := providers.GetPostgresDeploymentDetails(ctx, r.client, ps)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to get connection details for postgres instance %s", ps.Name)
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	if err = providers.ValidatePostgresRole(username, master.Username); err != nil {
+		errMsg := fmt.Sprintf("invalid username %s", username)
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
+	// the database is expected to be created by a postgres database resource, or to already exist in the instance
+	exists, err := p.DatabaseExists(ctx, ps, pu.Spec.DatabaseName)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to check if database %s exists", pu.Spec.DatabaseName)
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	if !exists {
+		return nil, croType.StatusMessage(fmt.Sprintf("waiting for database %s to be created", pu.Spec.DatabaseName)), nil
+	}
+
+	// users authenticating through iam have no password, their credentials are minted by the provider
+	iamProvider := r.getIAMCredentialProvider(ps.Status.Strategy)
+	if pu.Spec.IAMAuthentication {
+		if iamProvider == nil {
+			errMsg := fmt.Sprintf("iam authentication is not supported by the %s strategy", ps.Status.Strategy)
+			return nil, croType.StatusMessage(errMsg), errorUtil.New(errMsg)
+		}
+		if msg, err := r.grantUser(ctx, p, pu, ps, username, providers.BuildCreateIAMRoleStatements(username)); err != nil {
+			return nil, msg, err
+		}
+		creds, msg, err := iamProvider.ReconcileIAMCredentials(ctx, ps, pu, username)
+		if err != nil {
+			return nil, msg, err
+		}
+		return &providers.PostgresIAMDeploymentDetails{
+			Username:    username,
+			Host:        master.Host,
+			Database:    pu.Spec.DatabaseName,
+			Port:        master.Port,
+			Credentials: creds,
+		}, "creation successful", nil
+	}
+
+	// remove credentials minted while the user authenticated through iam
+	if iamProvider != nil {
+		if err = iamProvider.DeleteIAMCredentials(ctx, pu); err != nil {
+			errMsg := "failed to delete iam credentials"
+			return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+	}
+
+	// reuse the password from an existing result secret, the role password is reset to it on every reconcile
+	password, err := r.getOrGeneratePassword(ctx, pu)
+	if err != nil {
+		errMsg := "failed to get user password"
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
+	stmts := append(providers.BuildCreateRoleStatements(username, password), providers.BuildRevokeIAMRoleStatements(username)...)
+	if msg, err := r.grantUser(ctx, p, pu, ps, username, stmts); err != nil {
+		return nil, msg, err
+	}
+
+	return &providers.PostgresDeploymentDetails{
+		Username: username,
+		Password: password,
+		Host:     master.Host,
+		Database: pu.Spec.DatabaseName,
+		Port:     master.Port,
+	}, "creation successful", nil
+}
+
+// grantUser creates or updates a role with the given statements and grants it access to the database of the user
+func (r *ReconcilePostgresUser) grantUser(ctx context.Context, p providers.PostgresSQLRunner, pu *v1alpha1.PostgresUser, ps *v1alpha1.Postgres, username string, createRoleStmts []string) (croType.StatusMessage, error) {
+	stmts := append(createRoleStmts, providers.BuildGrantConnectStatements(pu.Spec.DatabaseName, username)...)
+	if err := p.ExecStatements(ctx, ps, "", stmts); err != nil {
+		errMsg := fmt.Sprintf("failed to create role %s", username)
+		return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
+	if err := p.ExecStatements(ctx, ps, pu.Spec.DatabaseName, providers.BuildGrantUserStatements(username, pu.Spec.ReadOnly)); err != nil {
+		errMsg := fmt.Sprintf("failed to grant role %s access to database %s", username, pu.Spec.DatabaseName)
+		return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	return croType.StatusEmpty, nil
+}
+
+func (r *ReconcilePostgresUser) deleteUser(ctx context.Context, p providers.PostgresSQLRunner, pu *v1alpha1.PostgresUser, ps *v1alpha1.Postgres, username string) (croType.StatusMessage, error) {
+	// privileges held in a database must be removed from within that database before the role can be dropped
+	exists, err := p.DatabaseExists(ctx, ps, pu.Spec.DatabaseName)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to check if database %s exists", pu.Spec.DatabaseName)
+		return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	if exists {
+		if err = p.ExecStatements(ctx, ps, pu.Spec.DatabaseName, providers.BuildRevokeUserStatements(username)); err != nil {
+			errMsg := fmt.Sprintf("failed to revoke privileges of role %s", username)
+			return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+	}
+
+	r.logger.Infof("dropping role %s from postgres instance %s", username, ps.Name)
+	if err = p.ExecStatements(ctx, ps, "", providers.BuildDropRoleStatements(username)); err != nil {
+		errMsg := fmt.Sprintf("failed to drop role %s", username)
+		return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
+	if iamProvider := r.getIAMCredentialProvider(ps.Status.Strategy); iamProvider != nil {
+		if err = iamProvider.DeleteIAMCredentials(ctx, pu); err != nil {
+			errMsg := "failed to delete iam credentials"
+			return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+	}
+	return "deletion complete", nil
+}
+
+func (r *ReconcilePostgresUser) getOrGeneratePassword(ctx context.Context, pu *v1alpha1.PostgresUser) (string, error) {
+	secNs := pu.Namespace
+	if pu.Spec.SecretRef.Namespace != "" {
+		secNs = pu.Spec.SecretRef.Namespace
+	}
+	sec := &v1.Secret{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: pu.Spec.SecretRef.Name, Namespace: secNs}, sec)
+	if err != nil && !errors.IsNotFound(err) {
+		return "", errorUtil.Wrapf(err, "failed to get secret %s", pu.Spec.SecretRef.Name)
+	}
+	if password := string(sec.Data["password"]); password != "" {
+		return password, nil
+	}
+	return resources.GeneratePassword()
+}
+
+func (r *ReconcilePostgresUser) getIAMCredentialProvider(strategy string) providers.PostgresIAMCredentialProvider {
+	for _, p := range r.iamProviderList {
+		if p.SupportsStrategy(strategy) {
+			return p
+		}
+	}
+	return nil
+}
+
+// cleanupAndRemoveFinalizer removes iam credentials minted for the user when its role no longer needs to be dropped
+func (r *ReconcilePostgresUser) cleanupAndRemoveFinalizer(ctx context.Context, pu *v1alpha1.PostgresUser) error {
+	if iamProvider := r.getIAMCredentialProvider(pu.Status.Strategy); iamProvider != nil {
+		if err := iamProvider.DeleteIAMCredentials(ctx, pu); err != nil {
+			return errorUtil.Wrap(err, "failed to delete iam credentials")
+		}
+	}
+	return r.removeFinalizer(ctx, pu)
+}
+
+func (r *ReconcilePostgresUser) removeFinalizer(ctx context.Context, pu *v1alpha1.PostgresUser) error {
+	// the role is not dropped in dry run mode, so the finalizer is kept
+	if resources.IsDryRun() {
+		return nil
+	}
+	resources.RemoveFinalizer(&pu.ObjectMeta, defaultFinalizer)
+	if err := r.client.Update(ctx, pu); err != nil {
+		return errorUtil.Wrapf(err, "failed to remove finalizer from instance %s", pu.Name)
+	}
+	return nil
+}
@@ -0,0 +1,320 @@
+package postgresuser
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis"
+	integreatlyv1alpha1 "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	"github.com/integr8ly/cloud-resource-operator/pkg/providers"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var testLogger = logrus.WithFields(logrus.Fields{"testing": "true"})
+
+func buildTestScheme() (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := apis.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	return scheme, nil
+}
+
+func buildTestPostgres() *integreatlyv1alpha1.Postgres {
+	return &integreatlyv1alpha1.Postgres{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+		Status: integreatlyv1alpha1.PostgresStatus{
+			Strategy:  providers.AWSDeploymentStrategy,
+			Phase:     croType.PhaseComplete,
+			SecretRef: &croType.SecretRef{Name: "test-sec"},
+		},
+	}
+}
+
+func buildTestPostgresSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sec",
+			Namespace: "test",
+		},
+		Data: (&providers.PostgresDeploymentDetails{
+			Username: "master",
+			Password: "master-password",
+			Host:     "test.rds.amazonaws.com",
+			Database: "postgres",
+			Port:     5432,
+		}).Data(),
+	}
+}
+
+func buildTestPostgresUser() *integreatlyv1alpha1.PostgresUser {
+	return &integreatlyv1alpha1.PostgresUser{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-user",
+			Namespace: "test",
+		},
+		Spec: integreatlyv1alpha1.PostgresUserSpec{
+			ResourceName: "test",
+			DatabaseName: "test_db",
+			ReadOnly:     true,
+			SecretRef:    &croType.SecretRef{Name: "test-user-sec"},
+		},
+	}
+}
+
+func buildTestPostgresUserDeleting() *integreatlyv1alpha1.PostgresUser {
+	pu := buildTestPostgresUser()
+	now := metav1.Now()
+	pu.DeletionTimestamp = &now
+	pu.Finalizers = []string{defaultFinalizer}
+	return pu
+}
+
+func buildTestSQLRunner(exists bool) *providers.PostgresSQLRunnerMock {
+	return &providers.PostgresSQLRunnerMock{
+		GetNameFunc: func() string {
+			return "test-runner"
+		},
+		SupportsStrategyFunc: func(s string) bool {
+			return s == providers.AWSDeploymentStrategy
+		},
+		DatabaseExistsFunc: func(ctx context.Context, ps *integreatlyv1alpha1.Postgres, database string) (bool, error) {
+			return exists, nil
+		},
+		ExecStatementsFunc: func(ctx context.Context, ps *integreatlyv1alpha1.Postgres, database string, stmts []string) error {
+			return nil
+		},
+	}
+}
+
+func buildTestPostgresUserIAM() *integreatlyv1alpha1.PostgresUser {
+	pu := buildTestPostgresUser()
+	pu.Spec.IAMAuthentication = true
+	return pu
+}
+
+func buildTestIAMCredentialProvider() *providers.PostgresIAMCredentialProviderMock {
+	return &providers.PostgresIAMCredentialProviderMock{
+		GetNameFunc: func() string {
+			return "test-iam-provider"
+		},
+		SupportsStrategyFunc: func(s string) bool {
+			return s == providers.AWSDeploymentStrategy
+		},
+		ReconcileIAMCredentialsFunc: func(ctx context.Context, ps *integreatlyv1alpha1.Postgres, pu *integreatlyv1alpha1.PostgresUser, role string) (*providers.PostgresIAMCredentials, croType.StatusMessage, error) {
+			return &providers.PostgresIAMCredentials{
+				Region:          "eu-west-1",
+				AccessKeyID:     "test-id",
+				SecretAccessKey: "test-key",
+			}, croType.StatusEmpty, nil
+		},
+		DeleteIAMCredentialsFunc: func(ctx context.Context, pu *integreatlyv1alpha1.PostgresUser) error {
+			return nil
+		},
+	}
+}
+
+func TestReconcilePostgresUser_Reconcile(t *testing.T) {
+	scheme, err := buildTestScheme()
+	if err != nil {
+		t.Fatal("failed to build scheme", err)
+	}
+
+	type fields struct {
+		client      client.Client
+		runner      *providers.PostgresSQLRunnerMock
+		iamProvider *providers.PostgresIAMCredentialProviderMock
+	}
+	tests := []struct {
+		name             string
+		fields           fields
+		wantPhase        croType.StatusPhase
+		wantDatabases    []string
+		wantStatements   [][]string
+		wantSecretData   map[string]string
+		wantIAMDeletions int
+		wantErr          bool
+	}{
+		{
+			name: "test user is granted read only access",
+			fields: fields{
+				client: fake.NewFakeClientWithScheme(scheme, buildTestPostgres(), buildTestPostgresSecret(), buildTestPostgresUser()),
+				runner: buildTestSQLRunner(true),
+			},
+			wantPhase:     croType.PhaseComplete,
+			wantDatabases: []string{"", "test_db"},
+			wantStatements: [][]string{
+				nil,
+				providers.BuildGrantUserStatements("test_user", true),
+			},
+			wantErr: false,
+		},
+		{
+			name: "test user waits for the database to be created",
+			fields: fields{
+				client: fake.NewFakeClientWithScheme(scheme, buildTestPostgres(), buildTestPostgresSecret(), buildTestPostgresUser()),
+				runner: buildTestSQLRunner(false),
+			},
+			wantPhase: croType.PhaseInProgress,
+			wantErr:   false,
+		},
+		{
+			name: "test privileges are revoked and the role dropped on deletion",
+			fields: fields{
+				client: fake.NewFakeClientWithScheme(scheme, buildTestPostgres(), buildTestPostgresSecret(), buildTestPostgresUserDeleting()),
+				runner: buildTestSQLRunner(true),
+			},
+			wantDatabases: []string{"test_db", ""},
+			wantStatements: [][]string{
+				providers.BuildRevokeUserStatements("test_user"),
+				providers.BuildDropRoleStatements("test_user"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "test iam user is granted access and receives iam credentials",
+			fields: fields{
+				client:      fake.NewFakeClientWithScheme(scheme, buildTestPostgres(), buildTestPostgresSecret(), buildTestPostgresUserIAM()),
+				runner:      buildTestSQLRunner(true),
+				iamProvider: buildTestIAMCredentialProvider(),
+			},
+			wantPhase:     croType.PhaseComplete,
+			wantDatabases: []string{"", "test_db"},
+			wantStatements: [][]string{
+				append(providers.BuildCreateIAMRoleStatements("test_user"), providers.BuildGrantConnectStatements("test_db", "test_user")...),
+				providers.BuildGrantUserStatements("test_user", true),
+			},
+			wantSecretData: map[string]string{
+				"username":            "test_user",
+				"host":                "test.rds.amazonaws.com",
+				"database":            "test_db",
+				"port":                "5432",
+				"region":              "eu-west-1",
+				"credentialKeyID":     "test-id",
+				"credentialSecretKey": "test-key",
+			},
+			wantErr: false,
+		},
+		{
+			name: "test password user removes previously minted iam credentials",
+			fields: fields{
+				client:      fake.NewFakeClientWithScheme(scheme, buildTestPostgres(), buildTestPostgresSecret(), buildTestPostgresUser()),
+				runner:      buildTestSQLRunner(true),
+				iamProvider: buildTestIAMCredentialProvider(),
+			},
+			wantPhase:     croType.PhaseComplete,
+			wantDatabases: []string{"", "test_db"},
+			wantStatements: [][]string{
+				nil,
+				providers.BuildGrantUserStatements("test_user", true),
+			},
+			wantIAMDeletions: 1,
+			wantErr:          false,
+		},
+		{
+			name: "test iam user fails when not supported by the strategy",
+			fields: fields{
+				client: fake.NewFakeClientWithScheme(scheme, buildTestPostgres(), buildTestPostgresSecret(), buildTestPostgresUserIAM()),
+				runner: buildTestSQLRunner(true),
+			},
+			wantPhase: croType.PhaseFailed,
+			wantErr:   true,
+		},
+		{
+			name: "test iam credentials are removed on deletion",
+			fields: fields{
+				client:      fake.NewFakeClientWithScheme(scheme, buildTestPostgres(), buildTestPostgresSecret(), buildTestPostgresUserDeleting()),
+				runner:      buildTestSQLRunner(true),
+				iamProvider: buildTestIAMCredentialProvider(),
+			},
+			wantDatabases: []string{"test_db", ""},
+			wantStatements: [][]string{
+				providers.BuildRevokeUserStatements("test_user"),
+				providers.BuildDropRoleStatements("test_user"),
+			},
+			wantIAMDeletions: 1,
+			wantErr:          false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var iamProviderList []providers.PostgresIAMCredentialProvider
+			if tt.fields.iamProvider != nil {
+				iamProviderList = append(iamProviderList, tt.fields.iamProvider)
+			}
+			r := &ReconcilePostgresUser{
+				client:           tt.fields.client,
+				scheme:           scheme,
+				logger:           testLogger,
+				resourceProvider: resources.NewResourceProvider(tt.fields.client, scheme, testLogger),
+				runnerList:       []providers.PostgresSQLRunner{tt.fields.runner},
+				iamProviderList:  iamProviderList,
+			}
+			_, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "test-user", Namespace: "test"}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Reconcile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			calls := tt.fields.runner.ExecStatementsCalls()
+			if len(calls) != len(tt.wantStatements) {
+				t.Fatalf("Reconcile() executed %d statement groups, want %d", len(calls), len(tt.wantStatements))
+			}
+			for i, want := range tt.wantStatements {
+				if calls[i].Database != tt.wantDatabases[i] {
+					t.Errorf("Reconcile() database = %v, want %v", calls[i].Database, tt.wantDatabases[i])
+				}
+				// the create role statements contain a generated password
+				if want == nil {
+					continue
+				}
+				if !reflect.DeepEqual(calls[i].Stmts, want) {
+					t.Errorf("Reconcile() statements = %v, want %v", calls[i].Stmts, want)
+				}
+			}
+
+			pu := &integreatlyv1alpha1.PostgresUser{}
+			if err := tt.fields.client.Get(context.TODO(), types.NamespacedName{Name: "test-user", Namespace: "test"}, pu); err != nil {
+				t.Fatal("failed to get postgres user", err)
+			}
+			if pu.Status.Phase != tt.wantPhase {
+				t.Errorf("Reconcile() phase = %v, want %v", pu.Status.Phase, tt.wantPhase)
+			}
+
+			if tt.fields.iamProvider != nil && len(tt.fields.iamProvider.DeleteIAMCredentialsCalls()) != tt.wantIAMDeletions {
+				t.Errorf("Reconcile() deleted iam credentials %d times, want %d", len(tt.fields.iamProvider.DeleteIAMCredentialsCalls()), tt.wantIAMDeletions)
+			}
+
+			if tt.wantSecretData != nil {
+				sec := &corev1.Secret{}
+				if err := tt.fields.client.Get(context.TODO(), types.NamespacedName{Name: "test-user-sec", Namespace: "test"}, sec); err != nil {
+					t.Fatal("failed to get result secret", err)
+				}
+				for k, v := range tt.wantSecretData {
+					if string(sec.Data[k]) != v {
+						t.Errorf("Reconcile() secret %s = %s, want %s", k, sec.Data[k], v)
+					}
+				}
+				if _, ok := sec.Data["password"]; ok {
+					t.Errorf("Reconcile() secret contains a password")
+				}
+			}
+		})
+	}
+}
@@ -94,7 +94,7 @@ type ReconcileRedis struct {
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
 func (r *ReconcileRedis) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	r.logger.Info("reconciling Redis")
-	ctx := context.TODO()
+	ctx := resources.NewDryRunContext(context.TODO())
 	cfgMgr := providers.NewConfigManager(providers.DefaultProviderConfigMapName, request.Namespace, r.client)
 
 	// Fetch the Redis instance
@@ -143,6 +143,9 @@ func (r *ReconcileRedis) Reconcile(request reconcile.Request) (reconcile.Result,
 		// handle deletion of redis and remove any finalizers added
 		if instance.GetDeletionTimestamp() != nil {
 			msg, err := p.DeleteRedis(ctx, instance)
+			if reportErr := resources.ReportDryRun(ctx, instance); reportErr != nil {
+				return reconcile.Result{}, reportErr
+			}
 			if err != nil {
 				if updateErr := resources.UpdatePhase(ctx, r.client, instance, croType.PhaseFailed, msg.WrapError(err)); updateErr != nil {
 					return reconcile.Result{}, updateErr
@@ -168,6 +171,9 @@ func (r *ReconcileRedis) Reconcile(request reconcile.Request) (reconcile.Result,
 
 		// handle creation of redis and apply any finalizers to instance required for deletion
 		redis, msg, err := p.CreateRedis(ctx, instance)
+		if reportErr := resources.ReportDryRun(ctx, instance); reportErr != nil {
+			return reconcile.Result{}, reportErr
+		}
 		if err != nil {
 			instance.Status.SecretRef = &croType.SecretRef{}
 			if updateErr := resources.UpdatePhase(ctx, r.client, instance, croType.PhaseFailed, msg.WrapError(err)); updateErr != nil {
@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/elasticache"
 	"github.com/aws/aws-sdk-go/service/elasticache/elasticacheiface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
 	"github.com/integr8ly/cloud-resource-operator/pkg/providers"
 	croAws "github.com/integr8ly/cloud-resource-operator/pkg/providers/aws"
@@ -29,6 +32,19 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+const (
+	// snapshots are exported to s3 and copied to the copy region in buckets of at most this length
+	maxBucketNameLength = 63
+	regionUSEast1       = "us-east-1"
+	// maxCopyObjectSize is the size of the largest object s3 copies in a single request, larger snapshot files are
+	// copied in parts of copyPartSize
+	maxCopyObjectSize = 5 * 1024 * 1024 * 1024
+	copyPartSize      = 512 * 1024 * 1024
+	// snapshotExportPolicyFormat allows the elasticache snapshot service principal of a region to export snapshots to a
+	// bucket
+	snapshotExportPolicyFormat = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":"%s"},"Action":["s3:PutObject","s3:GetObject","s3:ListBucket","s3:GetBucketAcl","s3:ListMultipartUploadParts","s3:ListBucketMultipartUploads"],"Resource":["arn:aws:s3:::%s","arn:aws:s3:::%s/*"]}]}`
+)
+
 // Add creates a new RedisSnapshot Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
@@ -109,15 +125,26 @@ func (r *ReconcileRedisSnapshot) Reconcile(request reconcile.Request) (reconcile
 		return reconcile.Result{}, err
 	}
 
-	// check status, if complete return
-	if instance.Status.Phase == croType.PhaseComplete {
+	// check status, if the snapshot and its copy are complete return
+	if instance.DeletionTimestamp == nil && instance.Status.Phase == croType.PhaseComplete && isCopyComplete(instance) {
 		r.logger.Infof("skipping creation of snapshot for %s as phase is complete", instance.Name)
 		return reconcile.Result{Requeue: true, RequeueAfter: resources.SuccessReconcileTime}, nil
 	}
 
+	// nothing to clean up if no snapshot was started
+	if instance.DeletionTimestamp != nil && !resources.HasFinalizer(&instance.ObjectMeta, croAws.DefaultFinalizer) {
+		return reconcile.Result{}, nil
+	}
+
 	// get redis cr
 	redisCr := &integreatlyv1alpha1.Redis{}
 	err = r.client.Get(ctx, types.NamespacedName{Name: instance.Spec.ResourceName, Namespace: instance.Namespace}, redisCr)
+	if err != nil && (instance.DeletionTimestamp != nil || instance.Spec.SnapshotID != "") && errors.IsNotFound(err) {
+		// snapshots can outlive their redis resource, such as the final snapshot taken when the resource is deleted,
+		// they are found in the region recorded when they were taken
+		r.logger.Infof("redis resource %s not found, reconciling snapshot %s using the default strategy", instance.Spec.ResourceName, instance.Name)
+		redisCr, err = nil, nil
+	}
 	if err != nil {
 		errMsg := fmt.Sprintf("failed to get redis cr : %s", err.Error())
 		if updateErr := resources.UpdateSnapshotPhase(ctx, r.client, instance, croType.PhaseFailed, croType.StatusMessage(errMsg)); updateErr != nil {
@@ -127,7 +154,7 @@ func (r *ReconcileRedisSnapshot) Reconcile(request reconcile.Request) (reconcile
 	}
 
 	// check redis cr deployment type is aws
-	if redisCr.Status.Strategy != providers.AWSDeploymentStrategy {
+	if redisCr != nil && redisCr.Status.Strategy != providers.AWSDeploymentStrategy {
 		errMsg := fmt.Sprintf("the resource %s uses an unsupported provider strategy %s, only resources using the aws provider are valid", instance.Spec.ResourceName, redisCr.Status.Strategy)
 		if updateErr := resources.UpdateSnapshotPhase(ctx, r.client, instance, croType.PhaseFailed, croType.StatusMessage(errMsg)); updateErr != nil {
 			return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, updateErr
@@ -136,7 +163,10 @@ func (r *ReconcileRedisSnapshot) Reconcile(request reconcile.Request) (reconcile
 	}
 
 	// get resource region
-	stratCfg, err := r.ConfigManager.ReadStorageStrategy(ctx, providers.RedisResourceType, redisCr.Spec.Tier)
+	stratCfg := &croAws.StrategyConfig{}
+	if redisCr != nil {
+		stratCfg, err = r.ConfigManager.ReadStorageStrategy(ctx, providers.RedisResourceType, redisCr.Spec.Tier)
+	}
 	if err != nil {
 		if updateErr := resources.UpdateSnapshotPhase(ctx, r.client, instance, croType.PhaseFailed, croType.StatusMessage(err.Error())); updateErr != nil {
 			return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, updateErr
@@ -153,8 +183,19 @@ func (r *ReconcileRedisSnapshot) Reconcile(request reconcile.Request) (reconcile
 		stratCfg.Region = defRegion
 	}
 
+	// snapshots stay in the region they are taken in, the region is recorded so the snapshot can be found once the redis
+	// resource is deleted or its strategy changes
+	if instance.Spec.Region != "" {
+		stratCfg.Region = instance.Spec.Region
+	} else if redisCr != nil {
+		instance.Spec.Region = stratCfg.Region
+		if err := r.client.Update(ctx, instance); err != nil {
+			return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, errorUtil.Wrapf(err, "failed to record region of snapshot %s", instance.Name)
+		}
+	}
+
 	// create the credentials to be used by the aws resource providers, not to be used by end-user
-	providerCreds, err := r.CredentialManager.ReconcileProviderCredentials(ctx, redisCr.Namespace)
+	providerCreds, err := r.CredentialManager.ReconcileProviderCredentials(ctx, instance.Namespace)
 	if err != nil {
 		errMsg := "failed to reconcile elasticache credentials"
 		if updateErr := resources.UpdateSnapshotPhase(ctx, r.client, instance, croType.PhaseFailed, croType.StatusMessage(errMsg)); updateErr != nil {
@@ -164,13 +205,51 @@ func (r *ReconcileRedisSnapshot) Reconcile(request reconcile.Request) (reconcile
 	}
 
 	// setup aws elasticache cluster sdk session
-	cacheSvc := elasticache.New(session.Must(session.NewSession(&aws.Config{
+	sess := croAws.ConfigureDryRun(ctx, session.Must(session.NewSession(&aws.Config{
 		Region:      aws.String(stratCfg.Region),
 		Credentials: credentials.NewStaticCredentials(providerCreds.AccessKeyID, providerCreds.SecretAccessKey, ""),
 	})))
+	cacheSvc := elasticache.New(sess)
+
+	// setup aws s3 session in the copy region, snapshots are exported to s3 in their own region and the exported files
+	// are copied to the copy region
+	var copyS3Svc s3iface.S3API
+	if copyRegion := getCopyRegion(instance); copyRegion != "" {
+		copyS3Svc = s3.New(croAws.ConfigureDryRun(ctx, session.Must(session.NewSession(&aws.Config{
+			Region:      aws.String(copyRegion),
+			Credentials: credentials.NewStaticCredentials(providerCreds.AccessKeyID, providerCreds.SecretAccessKey, ""),
+		}))))
+	}
+
+	// delete the snapshot and its copy when the cr is deleted
+	if instance.DeletionTimestamp != nil {
+		if err := r.deleteSnapshot(ctx, cacheSvc, s3.New(sess), copyS3Svc, instance, stratCfg.Region); err != nil {
+			return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, err
+		}
+		// the cr is kept in dry run mode, its snapshots are deleted once dry run mode is disabled
+		if resources.IsDryRun() {
+			return reconcile.Result{Requeue: true, RequeueAfter: resources.SuccessReconcileTime}, nil
+		}
+		return reconcile.Result{}, nil
+	}
+	if err := resources.CreateFinalizer(ctx, r.client, instance, croAws.DefaultFinalizer); err != nil {
+		return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, err
+	}
 
 	// create snapshot of primary node
 	phase, msg, err := r.createSnapshot(ctx, cacheSvc, instance, redisCr)
+
+	// copy the snapshot to the copy region once it has been created
+	if err == nil && phase == croType.PhaseComplete && copyS3Svc != nil {
+		copyPhase, copyMsg, copyErr := r.copySnapshot(ctx, cacheSvc, s3.New(sess), copyS3Svc, instance, stratCfg.Region)
+		instance.Status.Copy = &croType.ResourceTypeSnapshotCopyStatus{
+			Region:     instance.Spec.CopyRegion,
+			SnapshotID: instance.Status.SnapshotID,
+			Phase:      copyPhase,
+			Message:    copyMsg,
+		}
+		err = copyErr
+	}
 	if updateErr := resources.UpdateSnapshotPhase(ctx, r.client, instance, phase, msg); updateErr != nil {
 		return reconcile.Result{Requeue: true, RequeueAfter: resources.ErrorReconcileTime}, updateErr
 	}
@@ -182,6 +261,11 @@ func (r *ReconcileRedisSnapshot) Reconcile(request reconcile.Request) (reconcile
 }
 
 func (r *ReconcileRedisSnapshot) createSnapshot(ctx context.Context, cacheSvc elasticacheiface.ElastiCacheAPI, snapshot *integreatlyv1alpha1.RedisSnapshot, redis *integreatlyv1alpha1.Redis) (croType.StatusPhase, croType.StatusMessage, error) {
+	// an existing snapshot is referred to instead of taking a new one
+	if snapshot.Spec.SnapshotID != "" {
+		return r.reconcileExistingSnapshot(cacheSvc, snapshot)
+	}
+
 	// generate snapshot name
 	snapshotName, err := croAws.BuildTimestampedInfraNameFromObjectCreation(ctx, r.client, snapshot.ObjectMeta, croAws.DefaultAwsIdentifierLength)
 	if err != nil {
@@ -197,7 +281,7 @@ func (r *ReconcileRedisSnapshot) createSnapshot(ctx context.Context, cacheSvc el
 	}
 
 	// generate cluster name
-	clusterName, err := croAws.BuildInfraNameFromObject(ctx, r.client, redis.ObjectMeta, croAws.DefaultAwsIdentifierLength)
+	clusterName, err := croAws.BuildResourceIdentifierFromObject(ctx, r.client, redis.ObjectMeta, redis.Spec.AdoptIdentifier, croAws.DefaultAwsIdentifierLength)
 	if err != nil {
 		errMsg := "failed to get cluster name"
 		return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
@@ -230,22 +314,28 @@ func (r *ReconcileRedisSnapshot) createSnapshot(ctx context.Context, cacheSvc el
 		return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
 	}
 
-	// find primary cache node
-	cacheName := ""
-	for _, i := range cacheOutput.ReplicationGroups[0].NodeGroups[0].NodeGroupMembers {
-		if *i.CurrentRole == "primary" {
-			cacheName = *i.CacheClusterId
-			break
+	// a cluster mode enabled replication group is snapshot as a whole, otherwise the primary cache node is snapshot
+	snapshotInput := &elasticache.CreateSnapshotInput{
+		SnapshotName: aws.String(snapshotName),
+	}
+	if aws.BoolValue(cacheOutput.ReplicationGroups[0].ClusterEnabled) {
+		snapshotInput.ReplicationGroupId = aws.String(clusterName)
+	} else {
+		// find primary cache node
+		cacheName := ""
+		for _, i := range cacheOutput.ReplicationGroups[0].NodeGroups[0].NodeGroupMembers {
+			if *i.CurrentRole == "primary" {
+				cacheName = *i.CacheClusterId
+				break
+			}
 		}
+		snapshotInput.CacheClusterId = aws.String(cacheName)
 	}
 
-	// create snapshot of primary cache node
+	// create snapshot of the replication group
 	if foundSnapshot == nil {
 		r.logger.Info("creating elasticache snapshot")
-		if _, err = cacheSvc.CreateSnapshot(&elasticache.CreateSnapshotInput{
-			CacheClusterId: aws.String(cacheName),
-			SnapshotName:   aws.String(snapshotName),
-		}); err != nil {
+		if _, err = cacheSvc.CreateSnapshot(snapshotInput); err != nil {
 			errMsg := fmt.Sprintf("error creating elasticache snapshot %s", err)
 			return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
 		}
@@ -261,3 +351,344 @@ func (r *ReconcileRedisSnapshot) createSnapshot(ctx context.Context, cacheSvc el
 	r.logger.Info(msg)
 	return croType.PhaseInProgress, croType.StatusMessage(msg), nil
 }
+
+// reconcileExistingSnapshot waits for the existing snapshot a cr refers to to become available
+func (r *ReconcileRedisSnapshot) reconcileExistingSnapshot(cacheSvc elasticacheiface.ElastiCacheAPI, snapshot *integreatlyv1alpha1.RedisSnapshot) (croType.StatusPhase, croType.StatusMessage, error) {
+	snapshot.Status.SnapshotID = snapshot.Spec.SnapshotID
+	listOutput, err := cacheSvc.DescribeSnapshots(&elasticache.DescribeSnapshotsInput{
+		SnapshotName: aws.String(snapshot.Spec.SnapshotID),
+	})
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == elasticache.ErrCodeSnapshotNotFoundFault {
+		return croType.PhaseInProgress, croType.StatusMessage(fmt.Sprintf("waiting for snapshot %s to be created", snapshot.Spec.SnapshotID)), nil
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to describe elasticache snapshot %s", snapshot.Spec.SnapshotID)
+		return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	if len(listOutput.Snapshots) == 0 {
+		return croType.PhaseInProgress, croType.StatusMessage(fmt.Sprintf("waiting for snapshot %s to be created", snapshot.Spec.SnapshotID)), nil
+	}
+	if status := aws.StringValue(listOutput.Snapshots[0].SnapshotStatus); status != "available" {
+		return croType.PhaseInProgress, croType.StatusMessage(fmt.Sprintf("current snapshot status : %s", status)), nil
+	}
+	return croType.PhaseComplete, "snapshot created", nil
+}
+
+// copySnapshot copies a snapshot to the copy region. elasticache can not copy snapshots across regions, so the snapshot
+// is exported to the snapshot bucket of its region, and the exported files are copied to the snapshot bucket of the
+// copy region, one file per reconcile. a replication group can be seeded from the copied files in the copy region
+func (r *ReconcileRedisSnapshot) copySnapshot(ctx context.Context, cacheSvc elasticacheiface.ElastiCacheAPI, s3Svc, copyS3Svc s3iface.S3API, snapshot *integreatlyv1alpha1.RedisSnapshot, sourceRegion string) (croType.StatusPhase, croType.StatusMessage, error) {
+	snapshotName := snapshot.Status.SnapshotID
+	exportBucket, err := buildSnapshotBucketName(ctx, r.client, sourceRegion)
+	if err != nil {
+		errMsg := "failed to build snapshot bucket name"
+		return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	copyBucket, err := buildSnapshotBucketName(ctx, r.client, snapshot.Spec.CopyRegion)
+	if err != nil {
+		errMsg := "failed to build snapshot bucket name"
+		return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
+	// the snapshot is exported unless its files are in the snapshot bucket of its region
+	listOutput, err := cacheSvc.DescribeSnapshots(&elasticache.DescribeSnapshotsInput{
+		SnapshotName: aws.String(snapshotName),
+	})
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to describe elasticache snapshot %s to copy", snapshotName)
+		return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	if len(listOutput.Snapshots) == 0 {
+		errMsg := fmt.Sprintf("elasticache snapshot %s to copy not found", snapshotName)
+		return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.New(errMsg)
+	}
+	if status := aws.StringValue(listOutput.Snapshots[0].SnapshotStatus); status != "available" {
+		msg := fmt.Sprintf("current snapshot status : %s", status)
+		r.logger.Info(msg)
+		return croType.PhaseInProgress, croType.StatusMessage(msg), nil
+	}
+	if err := reconcileSnapshotBucket(s3Svc, exportBucket, sourceRegion, buildSnapshotExportPolicy(exportBucket, sourceRegion)); err != nil {
+		errMsg := fmt.Sprintf("failed to reconcile snapshot bucket %s", exportBucket)
+		return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	exported, err := listSnapshotFiles(s3Svc, exportBucket, snapshotName)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to list exported files of snapshot %s", snapshotName)
+		return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	if len(exported) == 0 {
+		r.logger.Infof("exporting elasticache snapshot %s to bucket %s", snapshotName, exportBucket)
+		if _, err := cacheSvc.CopySnapshot(&elasticache.CopySnapshotInput{
+			SourceSnapshotName: aws.String(snapshotName),
+			TargetSnapshotName: aws.String(snapshotName),
+			TargetBucket:       aws.String(exportBucket),
+		}); err != nil {
+			errMsg := fmt.Sprintf("error exporting elasticache snapshot %s to bucket %s", snapshotName, exportBucket)
+			return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+		return croType.PhaseInProgress, "snapshot export started", nil
+	}
+
+	// the exported files are copied to the copy region one at a time
+	if err := reconcileSnapshotBucket(copyS3Svc, copyBucket, snapshot.Spec.CopyRegion, ""); err != nil {
+		errMsg := fmt.Sprintf("failed to reconcile snapshot bucket %s", copyBucket)
+		return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	copied, err := listSnapshotFiles(copyS3Svc, copyBucket, snapshotName)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to list copied files of snapshot %s", snapshotName)
+		return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	for i, file := range exported {
+		if copiedFile := findSnapshotFile(copied, aws.StringValue(file.Key)); copiedFile != nil && aws.Int64Value(copiedFile.Size) == aws.Int64Value(file.Size) {
+			continue
+		}
+		r.logger.Infof("copying file %s of elasticache snapshot %s to region %s", aws.StringValue(file.Key), snapshotName, snapshot.Spec.CopyRegion)
+		if err := copySnapshotFile(copyS3Svc, exportBucket, copyBucket, file, snapshot.Spec.CopyKMSKeyID); err != nil {
+			errMsg := fmt.Sprintf("error copying file %s of elasticache snapshot %s to region %s", aws.StringValue(file.Key), snapshotName, snapshot.Spec.CopyRegion)
+			return croType.PhaseFailed, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+		return croType.PhaseInProgress, croType.StatusMessage(fmt.Sprintf("copied %d of %d snapshot files", i+1, len(exported))), nil
+	}
+	return croType.PhaseComplete, croType.StatusMessage(fmt.Sprintf("snapshot copied to s3://%s/%s-*", copyBucket, snapshotName)), nil
+}
+
+// deleteSnapshot deletes the files of the copy of a snapshot and its exported files, then the snapshot
+func (r *ReconcileRedisSnapshot) deleteSnapshot(ctx context.Context, cacheSvc elasticacheiface.ElastiCacheAPI, s3Svc, copyS3Svc s3iface.S3API, snapshot *integreatlyv1alpha1.RedisSnapshot, sourceRegion string) error {
+	if copyS3Svc != nil && snapshot.Status.Copy != nil && snapshot.Status.Copy.SnapshotID != "" {
+		copyBucket, err := buildSnapshotBucketName(ctx, r.client, snapshot.Status.Copy.Region)
+		if err != nil {
+			return errorUtil.Wrap(err, "failed to build snapshot bucket name")
+		}
+		exportBucket, err := buildSnapshotBucketName(ctx, r.client, sourceRegion)
+		if err != nil {
+			return errorUtil.Wrap(err, "failed to build snapshot bucket name")
+		}
+		r.logger.Infof("deleting elasticache snapshot copy %s in region %s", snapshot.Status.Copy.SnapshotID, snapshot.Status.Copy.Region)
+		if err := deleteSnapshotFiles(copyS3Svc, copyBucket, snapshot.Status.Copy.SnapshotID); err != nil {
+			return errorUtil.Wrapf(err, "failed to delete elasticache snapshot copy %s", snapshot.Status.Copy.SnapshotID)
+		}
+		if err := deleteSnapshotFiles(s3Svc, exportBucket, snapshot.Status.Copy.SnapshotID); err != nil {
+			return errorUtil.Wrapf(err, "failed to delete exported files of elasticache snapshot %s", snapshot.Status.Copy.SnapshotID)
+		}
+	}
+	if snapshot.Status.SnapshotID != "" {
+		r.logger.Infof("deleting elasticache snapshot %s", snapshot.Status.SnapshotID)
+		if _, err := cacheSvc.DeleteSnapshot(&elasticache.DeleteSnapshotInput{
+			SnapshotName: aws.String(snapshot.Status.SnapshotID),
+		}); err != nil && !isSnapshotNotFound(err) {
+			return errorUtil.Wrapf(err, "failed to delete elasticache snapshot %s", snapshot.Status.SnapshotID)
+		}
+	}
+	// the snapshots are not deleted in dry run mode, so the finalizer is kept
+	if resources.IsDryRun() {
+		return resources.UpdateSnapshotPhase(ctx, r.client, snapshot, croType.PhaseDeleteInProgress, croType.StatusMessage(fmt.Sprintf("dry run, elasticache snapshot %s would be deleted", snapshot.Status.SnapshotID)))
+	}
+	resources.RemoveFinalizer(&snapshot.ObjectMeta, croAws.DefaultFinalizer)
+	if err := r.client.Update(ctx, snapshot); err != nil {
+		return errorUtil.Wrapf(err, "failed to remove finalizer from instance %s", snapshot.Name)
+	}
+	return nil
+}
+
+// buildSnapshotBucketName returns the name of the bucket snapshots are exported to and copied to in a region
+func buildSnapshotBucketName(ctx context.Context, c client.Client, region string) (string, error) {
+	return croAws.BuildInfraName(ctx, c, fmt.Sprintf("redissnapshots-%s", region), maxBucketNameLength)
+}
+
+// buildSnapshotExportPolicy returns the bucket policy which allows elasticache to export snapshots of a region to a
+// bucket
+func buildSnapshotExportPolicy(bucket, region string) string {
+	return fmt.Sprintf(snapshotExportPolicyFormat, fmt.Sprintf("%s.elasticache-snapshot.amazonaws.com", region), bucket, bucket)
+}
+
+// reconcileSnapshotBucket creates a private, encrypted snapshot bucket in a region, and sets its bucket policy
+func reconcileSnapshotBucket(s3Svc s3iface.S3API, bucket, region, policy string) error {
+	createInput := &s3.CreateBucketInput{
+		Bucket: aws.String(bucket),
+	}
+	// buckets are created in us-east-1 unless another location is set
+	if region != regionUSEast1 {
+		createInput.CreateBucketConfiguration = &s3.CreateBucketConfiguration{
+			LocationConstraint: aws.String(region),
+		}
+	}
+	if _, err := s3Svc.CreateBucket(createInput); err != nil {
+		if awsErr, ok := err.(awserr.Error); !ok || awsErr.Code() != s3.ErrCodeBucketAlreadyOwnedByYou {
+			return errorUtil.Wrapf(err, "failed to create bucket %s", bucket)
+		}
+	}
+	if _, err := s3Svc.PutPublicAccessBlock(&s3.PutPublicAccessBlockInput{
+		Bucket: aws.String(bucket),
+		PublicAccessBlockConfiguration: &s3.PublicAccessBlockConfiguration{
+			BlockPublicAcls:       aws.Bool(true),
+			BlockPublicPolicy:     aws.Bool(true),
+			IgnorePublicAcls:      aws.Bool(true),
+			RestrictPublicBuckets: aws.Bool(true),
+		},
+	}); err != nil {
+		return errorUtil.Wrapf(err, "failed to block public access to bucket %s", bucket)
+	}
+	if _, err := s3Svc.PutBucketEncryption(&s3.PutBucketEncryptionInput{
+		Bucket: aws.String(bucket),
+		ServerSideEncryptionConfiguration: &s3.ServerSideEncryptionConfiguration{
+			Rules: []*s3.ServerSideEncryptionRule{
+				{
+					ApplyServerSideEncryptionByDefault: &s3.ServerSideEncryptionByDefault{
+						SSEAlgorithm: aws.String(s3.ServerSideEncryptionAes256),
+					},
+				},
+			},
+		},
+	}); err != nil {
+		return errorUtil.Wrapf(err, "failed to set encryption of bucket %s", bucket)
+	}
+	if policy == "" {
+		return nil
+	}
+	if _, err := s3Svc.PutBucketPolicy(&s3.PutBucketPolicyInput{
+		Bucket: aws.String(bucket),
+		Policy: aws.String(policy),
+	}); err != nil {
+		return errorUtil.Wrapf(err, "failed to set policy of bucket %s", bucket)
+	}
+	return nil
+}
+
+// listSnapshotFiles returns the files of a snapshot in a bucket, which are named after the snapshot followed by the
+// number of the node group
+func listSnapshotFiles(s3Svc s3iface.S3API, bucket, snapshotName string) ([]*s3.Object, error) {
+	var files []*s3.Object
+	err := s3Svc.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(snapshotName + "-"),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		files = append(files, page.Contents...)
+		return true
+	})
+	if err != nil {
+		return nil, errorUtil.Wrapf(err, "failed to list objects of bucket %s", bucket)
+	}
+	return files, nil
+}
+
+func findSnapshotFile(files []*s3.Object, key string) *s3.Object {
+	for _, file := range files {
+		if aws.StringValue(file.Key) == key {
+			return file
+		}
+	}
+	return nil
+}
+
+// copySnapshotFile copies a snapshot file to a bucket in another region, encrypted with the kms key or an s3 managed
+// key. files larger than a single copy allows are copied in parts
+func copySnapshotFile(copyS3Svc s3iface.S3API, sourceBucket, targetBucket string, file *s3.Object, kmsKeyID string) error {
+	copySource := fmt.Sprintf("%s/%s", sourceBucket, aws.StringValue(file.Key))
+	sseAlgorithm := aws.String(s3.ServerSideEncryptionAes256)
+	var sseKMSKeyID *string
+	if kmsKeyID != "" {
+		sseAlgorithm = aws.String(s3.ServerSideEncryptionAwsKms)
+		sseKMSKeyID = aws.String(kmsKeyID)
+	}
+	if aws.Int64Value(file.Size) <= maxCopyObjectSize {
+		_, err := copyS3Svc.CopyObject(&s3.CopyObjectInput{
+			Bucket:               aws.String(targetBucket),
+			Key:                  file.Key,
+			CopySource:           aws.String(copySource),
+			ServerSideEncryption: sseAlgorithm,
+			SSEKMSKeyId:          sseKMSKeyID,
+		})
+		return err
+	}
+
+	upload, err := copyS3Svc.CreateMultipartUpload(&s3.CreateMultipartUploadInput{
+		Bucket:               aws.String(targetBucket),
+		Key:                  file.Key,
+		ServerSideEncryption: sseAlgorithm,
+		SSEKMSKeyId:          sseKMSKeyID,
+	})
+	if err != nil {
+		return err
+	}
+	var parts []*s3.CompletedPart
+	for start, part := int64(0), int64(1); start < aws.Int64Value(file.Size); start, part = start+copyPartSize, part+1 {
+		end := start + copyPartSize - 1
+		if end >= aws.Int64Value(file.Size) {
+			end = aws.Int64Value(file.Size) - 1
+		}
+		output, err := copyS3Svc.UploadPartCopy(&s3.UploadPartCopyInput{
+			Bucket:          aws.String(targetBucket),
+			Key:             file.Key,
+			UploadId:        upload.UploadId,
+			PartNumber:      aws.Int64(part),
+			CopySource:      aws.String(copySource),
+			CopySourceRange: aws.String(fmt.Sprintf("bytes=%d-%d", start, end)),
+		})
+		if err != nil {
+			// the parts copied so far are discarded, the file is copied again on the next reconcile
+			if _, abortErr := copyS3Svc.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+				Bucket:   aws.String(targetBucket),
+				Key:      file.Key,
+				UploadId: upload.UploadId,
+			}); abortErr != nil {
+				return errorUtil.Wrapf(err, "failed to abort upload after error %s", abortErr)
+			}
+			return err
+		}
+		parts = append(parts, &s3.CompletedPart{
+			ETag:       output.CopyPartResult.ETag,
+			PartNumber: aws.Int64(part),
+		})
+	}
+	_, err = copyS3Svc.CompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(targetBucket),
+		Key:             file.Key,
+		UploadId:        upload.UploadId,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+	})
+	return err
+}
+
+// deleteSnapshotFiles deletes the files of a snapshot in a bucket, a bucket which does not exist is ignored
+func deleteSnapshotFiles(s3Svc s3iface.S3API, bucket, snapshotName string) error {
+	files, err := listSnapshotFiles(s3Svc, bucket, snapshotName)
+	if err != nil {
+		if awsErr, ok := errorUtil.Cause(err).(awserr.Error); ok && awsErr.Code() == s3.ErrCodeNoSuchBucket {
+			return nil
+		}
+		return err
+	}
+	for _, file := range files {
+		if _, err := s3Svc.DeleteObject(&s3.DeleteObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    file.Key,
+		}); err != nil {
+			return errorUtil.Wrapf(err, "failed to delete object %s of bucket %s", aws.StringValue(file.Key), bucket)
+		}
+	}
+	return nil
+}
+
+// getCopyRegion returns the region a copy of the snapshot is expected in, the status is preferred so a copy is still
+// deleted after the copy region is removed from the spec
+func getCopyRegion(snapshot *integreatlyv1alpha1.RedisSnapshot) string {
+	if snapshot.Status.Copy != nil && snapshot.Status.Copy.Region != "" {
+		return snapshot.Status.Copy.Region
+	}
+	return snapshot.Spec.CopyRegion
+}
+
+func isCopyComplete(snapshot *integreatlyv1alpha1.RedisSnapshot) bool {
+	if snapshot.Spec.CopyRegion == "" {
+		return true
+	}
+	return snapshot.Status.Copy != nil && snapshot.Status.Copy.Phase == croType.PhaseComplete
+}
+
+func isSnapshotNotFound(err error) bool {
+	if awsErr, ok := err.(awserr.Error); ok {
+		return awsErr.Code() == elasticache.ErrCodeSnapshotNotFoundFault
+	}
+	return false
+}
@@ -2,11 +2,22 @@ package redissnapshot
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/elasticache"
 	"github.com/aws/aws-sdk-go/service/elasticache/elasticacheiface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/integr8ly/cloud-resource-operator/pkg/apis"
 	v12 "github.com/integr8ly/cloud-resource-operator/pkg/apis/config/v1"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,6 +45,79 @@ type mockElasticacheClient struct {
 	rep           *elasticache.ReplicationGroup
 	snapshots     []*elasticache.Snapshot
 	nodeSnapshot  *elasticache.Snapshot
+	copyCalls     []*elasticache.CopySnapshotInput
+	deleteCalls   []string
+	deleteErr     error
+}
+
+type mockS3Client struct {
+	s3iface.S3API
+	objects     map[string][]*s3.Object
+	policies    map[string]string
+	copyCalls   []*s3.CopyObjectInput
+	deleteCalls []string
+}
+
+func (m *mockS3Client) CreateBucket(input *s3.CreateBucketInput) (*s3.CreateBucketOutput, error) {
+	if _, ok := m.objects[aws.StringValue(input.Bucket)]; ok {
+		return nil, awserr.New(s3.ErrCodeBucketAlreadyOwnedByYou, "exists", nil)
+	}
+	m.objects[aws.StringValue(input.Bucket)] = nil
+	return &s3.CreateBucketOutput{}, nil
+}
+
+func (m *mockS3Client) PutPublicAccessBlock(*s3.PutPublicAccessBlockInput) (*s3.PutPublicAccessBlockOutput, error) {
+	return &s3.PutPublicAccessBlockOutput{}, nil
+}
+
+func (m *mockS3Client) PutBucketEncryption(*s3.PutBucketEncryptionInput) (*s3.PutBucketEncryptionOutput, error) {
+	return &s3.PutBucketEncryptionOutput{}, nil
+}
+
+func (m *mockS3Client) PutBucketPolicy(input *s3.PutBucketPolicyInput) (*s3.PutBucketPolicyOutput, error) {
+	m.policies[aws.StringValue(input.Bucket)] = aws.StringValue(input.Policy)
+	return &s3.PutBucketPolicyOutput{}, nil
+}
+
+func (m *mockS3Client) ListObjectsV2Pages(input *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool) error {
+	objects, ok := m.objects[aws.StringValue(input.Bucket)]
+	if !ok {
+		return awserr.New(s3.ErrCodeNoSuchBucket, "not found", nil)
+	}
+	var contents []*s3.Object
+	for _, object := range objects {
+		if strings.HasPrefix(aws.StringValue(object.Key), aws.StringValue(input.Prefix)) {
+			contents = append(contents, object)
+		}
+	}
+	fn(&s3.ListObjectsV2Output{Contents: contents}, true)
+	return nil
+}
+
+func (m *mockS3Client) CopyObject(input *s3.CopyObjectInput) (*s3.CopyObjectOutput, error) {
+	m.copyCalls = append(m.copyCalls, input)
+	m.objects[aws.StringValue(input.Bucket)] = append(m.objects[aws.StringValue(input.Bucket)], &s3.Object{Key: input.Key, Size: aws.Int64(1)})
+	return &s3.CopyObjectOutput{}, nil
+}
+
+func (m *mockS3Client) DeleteObject(input *s3.DeleteObjectInput) (*s3.DeleteObjectOutput, error) {
+	m.deleteCalls = append(m.deleteCalls, fmt.Sprintf("%s/%s", aws.StringValue(input.Bucket), aws.StringValue(input.Key)))
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func buildMockS3Client(objects map[string][]*s3.Object) *mockS3Client {
+	if objects == nil {
+		objects = map[string][]*s3.Object{}
+	}
+	return &mockS3Client{objects: objects, policies: map[string]string{}}
+}
+
+func buildSnapshotFiles(snapshotName string, n int) []*s3.Object {
+	var files []*s3.Object
+	for i := 1; i <= n; i++ {
+		files = append(files, &s3.Object{Key: aws.String(fmt.Sprintf("%s-000%d.rdb", snapshotName, i)), Size: aws.Int64(1)})
+	}
+	return files
 }
 
 func buildTestScheme() (*runtime.Scheme, error) {
@@ -66,6 +150,12 @@ func buildSnapshot() *integreatlyv1alpha1.RedisSnapshot {
 	}
 }
 
+func buildExistingSnapshot(snapshotID string) *integreatlyv1alpha1.RedisSnapshot {
+	snapshot := buildSnapshot()
+	snapshot.Spec.SnapshotID = snapshotID
+	return snapshot
+}
+
 func buildRedisCR() *integreatlyv1alpha1.Redis {
 	return &integreatlyv1alpha1.Redis{
 		ObjectMeta: metav1.ObjectMeta{
@@ -93,6 +183,22 @@ func buildAvailableReplicationGroup() *elasticache.ReplicationGroup {
 	}
 }
 
+func buildClusterModeReplicationGroup() *elasticache.ReplicationGroup {
+	return &elasticache.ReplicationGroup{
+		ReplicationGroupId: aws.String("test"),
+		Status:             aws.String("available"),
+		ClusterEnabled:     aws.Bool(true),
+		NodeGroups: []*elasticache.NodeGroup{
+			{
+				NodeGroupId: aws.String("0001"),
+			},
+			{
+				NodeGroupId: aws.String("0002"),
+			},
+		},
+	}
+}
+
 func buildReplicationGroups() []*elasticache.ReplicationGroup {
 	var groups []*elasticache.ReplicationGroup
 	groups = append(groups, buildAvailableReplicationGroup())
@@ -120,7 +226,20 @@ func (m *mockElasticacheClient) DescribeReplicationGroups(*elasticache.DescribeR
 	}, nil
 }
 
-func (m *mockElasticacheClient) CreateSnapshot(*elasticache.CreateSnapshotInput) (*elasticache.CreateSnapshotOutput, error) {
+func (m *mockElasticacheClient) CopySnapshot(input *elasticache.CopySnapshotInput) (*elasticache.CopySnapshotOutput, error) {
+	m.copyCalls = append(m.copyCalls, input)
+	return &elasticache.CopySnapshotOutput{}, nil
+}
+
+func (m *mockElasticacheClient) DeleteSnapshot(input *elasticache.DeleteSnapshotInput) (*elasticache.DeleteSnapshotOutput, error) {
+	m.deleteCalls = append(m.deleteCalls, aws.StringValue(input.SnapshotName))
+	return &elasticache.DeleteSnapshotOutput{}, m.deleteErr
+}
+
+func (m *mockElasticacheClient) CreateSnapshot(input *elasticache.CreateSnapshotInput) (*elasticache.CreateSnapshotOutput, error) {
+	if aws.StringValue(input.CacheClusterId) == "" && aws.StringValue(input.ReplicationGroupId) == "" {
+		return nil, errors.New("either a cache cluster or a replication group is required")
+	}
 	return &elasticache.CreateSnapshotOutput{}, nil
 }
 
@@ -176,6 +295,25 @@ func TestReconcileRedisSnapshot_createSnapshot(t *testing.T) {
 			want1:   "snapshot started",
 			wantErr: false,
 		},
+		{
+			name: "test successful snapshot of cluster mode enabled replication group started",
+			args: args{
+				ctx:      ctx,
+				cacheSvc: &mockElasticacheClient{repGroups: []*elasticache.ReplicationGroup{buildClusterModeReplicationGroup()}},
+				snapshot: buildSnapshot(),
+				redis:    buildRedisCR(),
+			},
+			fields: fields{
+				client:            fake.NewFakeClientWithScheme(scheme, buildTestInfrastructure(), buildSnapshot()),
+				scheme:            scheme,
+				logger:            testLogger,
+				ConfigManager:     nil,
+				CredentialManager: nil,
+			},
+			want:    types.PhaseInProgress,
+			want1:   "snapshot started",
+			wantErr: false,
+		},
 		{
 			name: "test successful snapshot created",
 			args: args{
@@ -214,6 +352,44 @@ func TestReconcileRedisSnapshot_createSnapshot(t *testing.T) {
 			want1:   "current snapshot status : creating",
 			wantErr: false,
 		},
+		{
+			name: "test existing snapshot is available without redis resource",
+			args: args{
+				ctx:      ctx,
+				cacheSvc: &mockElasticacheClient{snapshots: buildSnapshots("final", "available")},
+				snapshot: buildExistingSnapshot("final"),
+				redis:    nil,
+			},
+			fields: fields{
+				client:            fake.NewFakeClientWithScheme(scheme, buildTestInfrastructure(), buildExistingSnapshot("final")),
+				scheme:            scheme,
+				logger:            testLogger,
+				ConfigManager:     nil,
+				CredentialManager: nil,
+			},
+			want:    types.PhaseComplete,
+			want1:   "snapshot created",
+			wantErr: false,
+		},
+		{
+			name: "test waiting for existing snapshot to be created",
+			args: args{
+				ctx:      ctx,
+				cacheSvc: &mockElasticacheClient{},
+				snapshot: buildExistingSnapshot("final"),
+				redis:    nil,
+			},
+			fields: fields{
+				client:            fake.NewFakeClientWithScheme(scheme, buildTestInfrastructure(), buildExistingSnapshot("final")),
+				scheme:            scheme,
+				logger:            testLogger,
+				ConfigManager:     nil,
+				CredentialManager: nil,
+			},
+			want:    types.PhaseInProgress,
+			want1:   "waiting for snapshot final to be created",
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -238,3 +414,280 @@ func TestReconcileRedisSnapshot_createSnapshot(t *testing.T) {
 		})
 	}
 }
+
+func buildCopiedSnapshot(snapshotName string) *integreatlyv1alpha1.RedisSnapshot {
+	snapshot := buildSnapshot()
+	snapshot.Spec.CopyRegion = "eu-central-1"
+	snapshot.Status.SnapshotID = snapshotName
+	return snapshot
+}
+
+func TestReconcileRedisSnapshot_copySnapshot(t *testing.T) {
+	ctx := context.TODO()
+	scheme, err := buildTestScheme()
+	if err != nil {
+		t.Fatal("failed to build scheme", err)
+	}
+	c := fake.NewFakeClientWithScheme(scheme, buildTestInfrastructure())
+	exportBucket, err := buildSnapshotBucketName(ctx, c, "eu-west-1")
+	if err != nil {
+		t.Fatal("failed to build snapshot bucket name", err)
+	}
+	copyBucket, err := buildSnapshotBucketName(ctx, c, "eu-central-1")
+	if err != nil {
+		t.Fatal("failed to build snapshot bucket name", err)
+	}
+	snapshotName := "test-snapshot"
+	tests := []struct {
+		name       string
+		snapshot   *integreatlyv1alpha1.RedisSnapshot
+		cacheSvc   *mockElasticacheClient
+		s3Svc      *mockS3Client
+		copyS3Svc  *mockS3Client
+		want       types.StatusPhase
+		wantExport bool
+		wantCopied []string
+		wantSSE    string
+		wantErr    bool
+	}{
+		{
+			name:      "test export waits for the snapshot to be available",
+			snapshot:  buildCopiedSnapshot(snapshotName),
+			cacheSvc:  &mockElasticacheClient{snapshots: buildSnapshots(snapshotName, "exporting")},
+			s3Svc:     buildMockS3Client(nil),
+			copyS3Svc: buildMockS3Client(nil),
+			want:      types.PhaseInProgress,
+		},
+		{
+			name:       "test export of snapshot started",
+			snapshot:   buildCopiedSnapshot(snapshotName),
+			cacheSvc:   &mockElasticacheClient{snapshots: buildSnapshots(snapshotName, "available")},
+			s3Svc:      buildMockS3Client(nil),
+			copyS3Svc:  buildMockS3Client(nil),
+			want:       types.PhaseInProgress,
+			wantExport: true,
+		},
+		{
+			name:       "test exported file copied with an s3 managed key",
+			snapshot:   buildCopiedSnapshot(snapshotName),
+			cacheSvc:   &mockElasticacheClient{snapshots: buildSnapshots(snapshotName, "available")},
+			s3Svc:      buildMockS3Client(map[string][]*s3.Object{exportBucket: buildSnapshotFiles(snapshotName, 2)}),
+			copyS3Svc:  buildMockS3Client(nil),
+			want:       types.PhaseInProgress,
+			wantCopied: []string{snapshotName + "-0001.rdb"},
+			wantSSE:    s3.ServerSideEncryptionAes256,
+		},
+		{
+			name: "test exported file copied with the configured kms key",
+			snapshot: func() *integreatlyv1alpha1.RedisSnapshot {
+				snapshot := buildCopiedSnapshot(snapshotName)
+				snapshot.Spec.CopyKMSKeyID = "test-key"
+				return snapshot
+			}(),
+			cacheSvc: &mockElasticacheClient{snapshots: buildSnapshots(snapshotName, "available")},
+			s3Svc:    buildMockS3Client(map[string][]*s3.Object{exportBucket: buildSnapshotFiles(snapshotName, 2)}),
+			copyS3Svc: buildMockS3Client(map[string][]*s3.Object{
+				copyBucket: buildSnapshotFiles(snapshotName, 1),
+			}),
+			want:       types.PhaseInProgress,
+			wantCopied: []string{snapshotName + "-0002.rdb"},
+			wantSSE:    s3.ServerSideEncryptionAwsKms,
+		},
+		{
+			name:     "test copy complete",
+			snapshot: buildCopiedSnapshot(snapshotName),
+			cacheSvc: &mockElasticacheClient{snapshots: buildSnapshots(snapshotName, "available")},
+			s3Svc:    buildMockS3Client(map[string][]*s3.Object{exportBucket: buildSnapshotFiles(snapshotName, 2)}),
+			copyS3Svc: buildMockS3Client(map[string][]*s3.Object{
+				copyBucket: buildSnapshotFiles(snapshotName, 2),
+			}),
+			want: types.PhaseComplete,
+		},
+		{
+			name:      "test copy fails when source snapshot is not found",
+			snapshot:  buildCopiedSnapshot(snapshotName),
+			cacheSvc:  &mockElasticacheClient{},
+			s3Svc:     buildMockS3Client(nil),
+			copyS3Svc: buildMockS3Client(nil),
+			want:      types.PhaseFailed,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ReconcileRedisSnapshot{
+				client: c,
+				scheme: scheme,
+				logger: testLogger,
+			}
+			got, _, err := r.copySnapshot(ctx, tt.cacheSvc, tt.s3Svc, tt.copyS3Svc, tt.snapshot, "eu-west-1")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("copySnapshot() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("copySnapshot() got = %v, want %v", got, tt.want)
+			}
+			if tt.wantExport {
+				wantExport := &elasticache.CopySnapshotInput{
+					SourceSnapshotName: aws.String(snapshotName),
+					TargetSnapshotName: aws.String(snapshotName),
+					TargetBucket:       aws.String(exportBucket),
+				}
+				if len(tt.cacheSvc.copyCalls) != 1 || !reflect.DeepEqual(tt.cacheSvc.copyCalls[0], wantExport) {
+					t.Errorf("copySnapshot() export calls = %v, want %v", tt.cacheSvc.copyCalls, wantExport)
+				}
+				if !strings.Contains(tt.s3Svc.policies[exportBucket], "eu-west-1.elasticache-snapshot.amazonaws.com") {
+					t.Errorf("copySnapshot() export bucket policy = %v, want elasticache snapshot principal", tt.s3Svc.policies[exportBucket])
+				}
+			} else if len(tt.cacheSvc.copyCalls) != 0 {
+				t.Errorf("copySnapshot() unexpected export %v", tt.cacheSvc.copyCalls)
+			}
+			var copied []string
+			for _, call := range tt.copyS3Svc.copyCalls {
+				copied = append(copied, aws.StringValue(call.Key))
+				if aws.StringValue(call.Bucket) != copyBucket || aws.StringValue(call.CopySource) != exportBucket+"/"+aws.StringValue(call.Key) {
+					t.Errorf("copySnapshot() copied %v to %v, want %v/%v to %v", aws.StringValue(call.CopySource), aws.StringValue(call.Bucket), exportBucket, aws.StringValue(call.Key), copyBucket)
+				}
+				if aws.StringValue(call.ServerSideEncryption) != tt.wantSSE || aws.StringValue(call.SSEKMSKeyId) != tt.snapshot.Spec.CopyKMSKeyID {
+					t.Errorf("copySnapshot() encryption = %v %v, want %v %v", aws.StringValue(call.ServerSideEncryption), aws.StringValue(call.SSEKMSKeyId), tt.wantSSE, tt.snapshot.Spec.CopyKMSKeyID)
+				}
+			}
+			if !reflect.DeepEqual(copied, tt.wantCopied) {
+				t.Errorf("copySnapshot() copied = %v, want %v", copied, tt.wantCopied)
+			}
+		})
+	}
+}
+
+func TestReconcileRedisSnapshot_deleteSnapshot(t *testing.T) {
+	ctx := context.TODO()
+	scheme, err := buildTestScheme()
+	if err != nil {
+		t.Fatal("failed to build scheme", err)
+	}
+	exportBucket, err := buildSnapshotBucketName(ctx, fake.NewFakeClientWithScheme(scheme, buildTestInfrastructure()), "eu-west-1")
+	if err != nil {
+		t.Fatal("failed to build snapshot bucket name", err)
+	}
+	copyBucket, err := buildSnapshotBucketName(ctx, fake.NewFakeClientWithScheme(scheme, buildTestInfrastructure()), "eu-central-1")
+	if err != nil {
+		t.Fatal("failed to build snapshot bucket name", err)
+	}
+	snapshotName := "test-snapshot"
+	buildDeletedSnapshot := func(copied bool) *integreatlyv1alpha1.RedisSnapshot {
+		snapshot := buildSnapshot()
+		snapshot.Finalizers = []string{croAws.DefaultFinalizer}
+		snapshot.Status.SnapshotID = snapshotName
+		if copied {
+			snapshot.Status.Copy = &types.ResourceTypeSnapshotCopyStatus{
+				Region:     "eu-central-1",
+				SnapshotID: snapshotName,
+			}
+		}
+		return snapshot
+	}
+	tests := []struct {
+		name            string
+		snapshot        *integreatlyv1alpha1.RedisSnapshot
+		cacheSvc        *mockElasticacheClient
+		s3Svc           *mockS3Client
+		copyS3Svc       *mockS3Client
+		wantDeleted     []string
+		wantFileDeleted []string
+		dryRun          bool
+		wantErr         bool
+	}{
+		{
+			name:            "test snapshot, its exported files and its copy are deleted",
+			snapshot:        buildDeletedSnapshot(true),
+			cacheSvc:        &mockElasticacheClient{},
+			s3Svc:           buildMockS3Client(map[string][]*s3.Object{exportBucket: buildSnapshotFiles(snapshotName, 1)}),
+			copyS3Svc:       buildMockS3Client(map[string][]*s3.Object{copyBucket: buildSnapshotFiles(snapshotName, 1)}),
+			wantDeleted:     []string{snapshotName},
+			wantFileDeleted: []string{copyBucket + "/" + snapshotName + "-0001.rdb", exportBucket + "/" + snapshotName + "-0001.rdb"},
+		},
+		{
+			name:        "test snapshot without copy is deleted",
+			snapshot:    buildDeletedSnapshot(false),
+			cacheSvc:    &mockElasticacheClient{},
+			s3Svc:       buildMockS3Client(nil),
+			wantDeleted: []string{snapshotName},
+		},
+		{
+			name:        "test no error when snapshots and buckets are already deleted",
+			snapshot:    buildDeletedSnapshot(true),
+			cacheSvc:    &mockElasticacheClient{deleteErr: awserr.New(elasticache.ErrCodeSnapshotNotFoundFault, "not found", nil)},
+			s3Svc:       buildMockS3Client(nil),
+			copyS3Svc:   buildMockS3Client(nil),
+			wantDeleted: []string{snapshotName},
+		},
+		{
+			name:        "test error when snapshot deletion fails",
+			snapshot:    buildDeletedSnapshot(false),
+			cacheSvc:    &mockElasticacheClient{deleteErr: awserr.New(elasticache.ErrCodeInvalidSnapshotStateFault, "exporting", nil)},
+			s3Svc:       buildMockS3Client(nil),
+			wantDeleted: []string{snapshotName},
+			wantErr:     true,
+		},
+		{
+			name:        "test finalizer is kept in dry run mode",
+			snapshot:    buildDeletedSnapshot(false),
+			cacheSvc:    &mockElasticacheClient{},
+			s3Svc:       buildMockS3Client(nil),
+			wantDeleted: []string{snapshotName},
+			dryRun:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.dryRun {
+				if err := os.Setenv(resources.EnvDryRun, "true"); err != nil {
+					t.Fatal("failed to enable dry run mode", err)
+				}
+				defer os.Unsetenv(resources.EnvDryRun)
+			}
+			c := fake.NewFakeClientWithScheme(scheme, buildTestInfrastructure(), tt.snapshot)
+			r := &ReconcileRedisSnapshot{
+				client: c,
+				scheme: scheme,
+				logger: testLogger,
+			}
+			var copyS3Svc s3iface.S3API
+			if tt.copyS3Svc != nil {
+				copyS3Svc = tt.copyS3Svc
+			}
+			if err := r.deleteSnapshot(ctx, tt.cacheSvc, tt.s3Svc, copyS3Svc, tt.snapshot, "eu-west-1"); (err != nil) != tt.wantErr {
+				t.Errorf("deleteSnapshot() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(tt.cacheSvc.deleteCalls, tt.wantDeleted) {
+				t.Errorf("deleteSnapshot() deleted = %v, want %v", tt.cacheSvc.deleteCalls, tt.wantDeleted)
+			}
+			var fileDeleted []string
+			if tt.copyS3Svc != nil {
+				fileDeleted = append(fileDeleted, tt.copyS3Svc.deleteCalls...)
+			}
+			fileDeleted = append(fileDeleted, tt.s3Svc.deleteCalls...)
+			if !reflect.DeepEqual(fileDeleted, tt.wantFileDeleted) {
+				t.Errorf("deleteSnapshot() deleted files = %v, want %v", fileDeleted, tt.wantFileDeleted)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := &integreatlyv1alpha1.RedisSnapshot{}
+			if err := c.Get(ctx, k8sTypes.NamespacedName{Name: tt.snapshot.Name, Namespace: tt.snapshot.Namespace}, got); err != nil {
+				t.Fatal("failed to get snapshot", err)
+			}
+			if tt.dryRun {
+				if !resources.HasFinalizer(&got.ObjectMeta, croAws.DefaultFinalizer) {
+					t.Error("deleteSnapshot() removed the finalizer in dry run mode")
+				}
+				return
+			}
+			if len(got.Finalizers) != 0 {
+				t.Errorf("deleteSnapshot() finalizers = %v, want none", got.Finalizers)
+			}
+		})
+	}
+}
@@ -83,7 +83,7 @@ type ReconcileSMTPCredentialSet struct {
 // and what is in the SMTPCredentials.Spec
 func (r *ReconcileSMTPCredentialSet) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	r.logger.Info("reconciling SMTPCredentials")
-	ctx := context.TODO()
+	ctx := resources.NewDryRunContext(context.TODO())
 	cfgMgr := providers.NewConfigManager(providers.DefaultProviderConfigMapName, request.Namespace, r.client)
 
 	// Fetch the SMTPCredentials instance
@@ -136,6 +136,9 @@ func (r *ReconcileSMTPCredentialSet) Reconcile(request reconcile.Request) (recon
 		if instance.GetDeletionTimestamp() != nil {
 			r.logger.Infof("running deletion handler on smtp credential instance %s", instance.Name)
 			msg, err := p.DeleteSMTPCredentials(ctx, instance)
+			if reportErr := resources.ReportDryRun(ctx, instance); reportErr != nil {
+				return reconcile.Result{}, reportErr
+			}
 			if err != nil {
 				if updateErr := resources.UpdatePhase(ctx, r.client, instance, croType.PhaseFailed, msg.WrapError(err)); updateErr != nil {
 					return reconcile.Result{}, updateErr
@@ -151,6 +154,9 @@ func (r *ReconcileSMTPCredentialSet) Reconcile(request reconcile.Request) (recon
 		}
 
 		smtpCredentialSetInst, msg, err := p.CreateSMTPCredentials(ctx, instance)
+		if reportErr := resources.ReportDryRun(ctx, instance); reportErr != nil {
+			return reconcile.Result{}, reportErr
+		}
 		if err != nil {
 			if updateErr := resources.UpdatePhase(ctx, r.client, instance, croType.PhaseFailed, msg.WrapError(err)); updateErr != nil {
 				return reconcile.Result{}, updateErr
@@ -0,0 +1,236 @@
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	errorUtil "github.com/pkg/errors"
+)
+
+// bucketPolicySidRegexp matches the ids of bucket policy statements
+var bucketPolicySidRegexp = regexp.MustCompile("^[a-zA-Z0-9]+$")
+
+// BucketPolicyStatement is a statement of the bucket policy of a bucket, which applies to the objects with Prefix or
+// to all objects when it is empty. the statement allows the actions unless Effect is Deny
+type BucketPolicyStatement struct {
+	Sid       string                       `json:"sid"`
+	Effect    string                       `json:"effect,omitempty"`
+	Principal map[string]string            `json:"principal"`
+	Actions   []string                     `json:"actions"`
+	Prefix    string                       `json:"prefix,omitempty"`
+	Condition map[string]map[string]string `json:"condition,omitempty"`
+}
+
+// buildS3PublicAccessBlock returns the public access block of a bucket. public acls are always blocked, public bucket
+// policies are only allowed if the public access block is explicitly relaxed
+func buildS3PublicAccessBlock(allowPublicPolicy bool) *s3.PublicAccessBlockConfiguration {
+	return &s3.PublicAccessBlockConfiguration{
+		BlockPublicAcls:       aws.Bool(defaultBlockPublicAcls),
+		BlockPublicPolicy:     aws.Bool(defaultBlockPublicPolicy && !allowPublicPolicy),
+		IgnorePublicAcls:      aws.Bool(defaultIgnorePublicAcls),
+		RestrictPublicBuckets: aws.Bool(defaultRestrictPublicBuckets && !allowPublicPolicy),
+	}
+}
+
+// getCORSRules returns the cors rules of a bucket, the rules of the cr take precedence over the rules of the tier
+func getCORSRules(bs *v1alpha1.BlobStorage, strategy *BucketStrategy) []croType.CORSRule {
+	if len(bs.Spec.CORSRules) > 0 || strategy == nil {
+		return bs.Spec.CORSRules
+	}
+	return strategy.CORSRules
+}
+
+// buildS3CORSRules returns the s3 cors rules of a list of cors rules
+func buildS3CORSRules(rules []croType.CORSRule) ([]*s3.CORSRule, error) {
+	var s3Rules []*s3.CORSRule
+	for i, rule := range rules {
+		if len(rule.AllowedOrigins) == 0 || len(rule.AllowedMethods) == 0 {
+			return nil, fmt.Errorf("cors rule %d requires allowed origins and methods", i)
+		}
+		s3Rule := &s3.CORSRule{
+			AllowedOrigins: aws.StringSlice(rule.AllowedOrigins),
+			AllowedMethods: aws.StringSlice(rule.AllowedMethods),
+		}
+		if len(rule.AllowedHeaders) > 0 {
+			s3Rule.AllowedHeaders = aws.StringSlice(rule.AllowedHeaders)
+		}
+		if len(rule.ExposeHeaders) > 0 {
+			s3Rule.ExposeHeaders = aws.StringSlice(rule.ExposeHeaders)
+		}
+		if rule.MaxAgeSeconds > 0 {
+			s3Rule.MaxAgeSeconds = aws.Int64(rule.MaxAgeSeconds)
+		}
+		s3Rules = append(s3Rules, s3Rule)
+	}
+	return s3Rules, nil
+}
+
+// buildS3BucketPolicy returns the bucket policy of a bucket with the policy statements of a strategy, an empty policy
+// is returned if there are no statements
+func buildS3BucketPolicy(bucket string, statements []*BucketPolicyStatement) (string, error) {
+	if len(statements) == 0 {
+		return "", nil
+	}
+	var policyStatements []iamPolicyStatement
+	sids := map[string]bool{}
+	for _, statement := range statements {
+		if !bucketPolicySidRegexp.MatchString(statement.Sid) {
+			return "", fmt.Errorf("policy statement id %q must be alphanumeric", statement.Sid)
+		}
+		if sids[statement.Sid] {
+			return "", fmt.Errorf("policy statement id %s is not unique", statement.Sid)
+		}
+		sids[statement.Sid] = true
+		if len(statement.Principal) == 0 || len(statement.Actions) == 0 {
+			return "", fmt.Errorf("policy statement %s requires a principal and actions", statement.Sid)
+		}
+		effect := statement.Effect
+		if effect == "" {
+			effect = "Allow"
+		}
+		if effect != "Allow" && effect != "Deny" {
+			return "", fmt.Errorf("policy statement %s has effect %s, expected Allow or Deny", statement.Sid, effect)
+		}
+		policyStatements = append(policyStatements, iamPolicyStatement{
+			Sid:       statement.Sid,
+			Effect:    effect,
+			Principal: statement.Principal,
+			Action:    statement.Actions,
+			Resource:  []string{fmt.Sprintf("%s/%s*", buildS3BucketArn(bucket), statement.Prefix)},
+			Condition: statement.Condition,
+		})
+	}
+	return buildIAMPolicyDocument(policyStatements...)
+}
+
+// reconcileS3BucketAccess sets the cors rules and bucket policy of a bucket, the cors configuration and policy of the
+// bucket are deleted if there are no rules or statements
+func reconcileS3BucketAccess(bs *v1alpha1.BlobStorage, bucket string, strategy *BucketStrategy, s3svc s3iface.S3API) error {
+	corsRules, err := buildS3CORSRules(getCORSRules(bs, strategy))
+	if err != nil {
+		return errorUtil.Wrap(err, "invalid cors rules")
+	}
+	if err := reconcileS3BucketCORS(bucket, corsRules, s3svc); err != nil {
+		return err
+	}
+	var statements []*BucketPolicyStatement
+	if strategy != nil {
+		statements = strategy.PolicyStatements
+	}
+	policy, err := buildS3BucketPolicy(bucket, statements)
+	if err != nil {
+		return errorUtil.Wrap(err, "invalid bucket policy statements")
+	}
+	return reconcileS3BucketPolicy(bucket, policy, s3svc)
+}
+
+// reconcileS3BucketCORS sets the cors rules of a bucket
+func reconcileS3BucketCORS(bucket string, rules []*s3.CORSRule, s3svc s3iface.S3API) error {
+	if len(rules) > 0 {
+		if _, err := s3svc.PutBucketCors(&s3.PutBucketCorsInput{
+			Bucket: aws.String(bucket),
+			CORSConfiguration: &s3.CORSConfiguration{
+				CORSRules: rules,
+			},
+		}); err != nil {
+			return errorUtil.Wrapf(err, "failed to set cors rules on bucket %s", bucket)
+		}
+		return nil
+	}
+	if _, err := s3svc.GetBucketCors(&s3.GetBucketCorsInput{
+		Bucket: aws.String(bucket),
+	}); err != nil {
+		if s3err, isAwsErr := err.(awserr.Error); isAwsErr && s3err.Code() == s3ErrCodeNoSuchCORSConfiguration {
+			return nil
+		}
+		return errorUtil.Wrapf(err, "failed to get cors rules of bucket %s", bucket)
+	}
+	if _, err := s3svc.DeleteBucketCors(&s3.DeleteBucketCorsInput{
+		Bucket: aws.String(bucket),
+	}); err != nil {
+		return errorUtil.Wrapf(err, "failed to delete cors rules of bucket %s", bucket)
+	}
+	return nil
+}
+
+// reconcileS3BucketPolicy sets the bucket policy of a bucket
+func reconcileS3BucketPolicy(bucket, policy string, s3svc s3iface.S3API) error {
+	if policy != "" {
+		if _, err := s3svc.PutBucketPolicy(&s3.PutBucketPolicyInput{
+			Bucket: aws.String(bucket),
+			Policy: aws.String(policy),
+		}); err != nil {
+			return errorUtil.Wrapf(err, "failed to set policy on bucket %s", bucket)
+		}
+		return nil
+	}
+	if _, err := s3svc.GetBucketPolicy(&s3.GetBucketPolicyInput{
+		Bucket: aws.String(bucket),
+	}); err != nil {
+		if s3err, isAwsErr := err.(awserr.Error); isAwsErr && s3err.Code() == s3ErrCodeNoSuchBucketPolicy {
+			return nil
+		}
+		return errorUtil.Wrapf(err, "failed to get policy of bucket %s", bucket)
+	}
+	if _, err := s3svc.DeleteBucketPolicy(&s3.DeleteBucketPolicyInput{
+		Bucket: aws.String(bucket),
+	}); err != nil {
+		return errorUtil.Wrapf(err, "failed to delete policy of bucket %s", bucket)
+	}
+	return nil
+}
+
+// getS3BucketAccessDrift returns the number of cors rules and the ids of the policy statements of a bucket which differ
+// from the cr and its bucket strategy
+func getS3BucketAccessDrift(bs *v1alpha1.BlobStorage, s3svc s3iface.S3API, bucket string, strategy *BucketStrategy) ([]croType.DriftedField, error) {
+	var drift []croType.DriftedField
+
+	observedCORSRules := 0
+	corsOutput, err := s3svc.GetBucketCors(&s3.GetBucketCorsInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		if s3err, isAwsErr := err.(awserr.Error); !isAwsErr || s3err.Code() != s3ErrCodeNoSuchCORSConfiguration {
+			return nil, errorUtil.Wrapf(err, "failed to get cors rules of bucket %s", bucket)
+		}
+	} else {
+		observedCORSRules = len(corsOutput.CORSRules)
+	}
+	drift = appendDrift(drift, "CORSRules", strconv.Itoa(len(getCORSRules(bs, strategy))), strconv.Itoa(observedCORSRules))
+
+	var desiredSids, observedSids []*string
+	if strategy != nil {
+		for _, statement := range strategy.PolicyStatements {
+			desiredSids = append(desiredSids, aws.String(statement.Sid))
+		}
+	}
+	policyOutput, err := s3svc.GetBucketPolicy(&s3.GetBucketPolicyInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		if s3err, isAwsErr := err.(awserr.Error); !isAwsErr || s3err.Code() != s3ErrCodeNoSuchBucketPolicy {
+			return nil, errorUtil.Wrapf(err, "failed to get policy of bucket %s", bucket)
+		}
+	} else {
+		// only the ids of the statements are compared, a policy which can not be parsed has no statements
+		observed := &struct {
+			Statement []struct {
+				Sid string
+			}
+		}{}
+		if err := json.Unmarshal([]byte(aws.StringValue(policyOutput.Policy)), observed); err == nil {
+			for _, statement := range observed.Statement {
+				observedSids = append(observedSids, aws.String(statement.Sid))
+			}
+		}
+	}
+	return appendDrift(drift, "Policy.Statements", formatDriftList(desiredSids), formatDriftList(observedSids)), nil
+}
@@ -0,0 +1,176 @@
+package aws
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+)
+
+func Test_buildS3PublicAccessBlock(t *testing.T) {
+	tests := []struct {
+		name              string
+		allowPublicPolicy bool
+		want              *s3.PublicAccessBlockConfiguration
+	}{
+		{
+			name:              "test all public access is blocked by default",
+			allowPublicPolicy: false,
+			want: &s3.PublicAccessBlockConfiguration{
+				BlockPublicAcls:       aws.Bool(true),
+				BlockPublicPolicy:     aws.Bool(true),
+				IgnorePublicAcls:      aws.Bool(true),
+				RestrictPublicBuckets: aws.Bool(true),
+			},
+		},
+		{
+			name:              "test public policies are allowed when relaxed",
+			allowPublicPolicy: true,
+			want: &s3.PublicAccessBlockConfiguration{
+				BlockPublicAcls:       aws.Bool(true),
+				BlockPublicPolicy:     aws.Bool(false),
+				IgnorePublicAcls:      aws.Bool(true),
+				RestrictPublicBuckets: aws.Bool(false),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildS3PublicAccessBlock(tt.allowPublicPolicy); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildS3PublicAccessBlock() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_buildS3BucketPolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		statements []*BucketPolicyStatement
+		want       string
+		wantErr    bool
+	}{
+		{
+			name: "test read only prefix for a cdn origin",
+			statements: []*BucketPolicyStatement{
+				{
+					Sid:       "CDNRead",
+					Principal: map[string]string{"AWS": "arn:aws:iam::cloudfront:user/test"},
+					Actions:   []string{"s3:GetObject"},
+					Prefix:    "public/",
+				},
+			},
+			want: `{"Version":"2012-10-17","Statement":[{"Sid":"CDNRead","Effect":"Allow","Principal":{"AWS":"arn:aws:iam::cloudfront:user/test"},"Action":["s3:GetObject"],"Resource":["arn:aws:s3:::test/public/*"]}]}`,
+		},
+		{
+			name:       "test no policy without statements",
+			statements: nil,
+			want:       "",
+		},
+		{
+			name: "test error when a statement id is not alphanumeric",
+			statements: []*BucketPolicyStatement{
+				{Sid: "cdn-read", Principal: map[string]string{"AWS": "*"}, Actions: []string{"s3:GetObject"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "test error when a statement has no principal",
+			statements: []*BucketPolicyStatement{
+				{Sid: "CDNRead", Actions: []string{"s3:GetObject"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "test error when a statement has an unknown effect",
+			statements: []*BucketPolicyStatement{
+				{Sid: "CDNRead", Effect: "Permit", Principal: map[string]string{"AWS": "*"}, Actions: []string{"s3:GetObject"}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildS3BucketPolicy("test", tt.statements)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildS3BucketPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("buildS3BucketPolicy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_reconcileS3BucketAccess(t *testing.T) {
+	corsRule := types.CORSRule{
+		AllowedOrigins: []string{"https://example.com"},
+		AllowedMethods: []string{"GET", "PUT"},
+	}
+	tests := []struct {
+		name          string
+		specCORSRules []types.CORSRule
+		strategy      *BucketStrategy
+		s3svc         *mockS3Svc
+		wantCORSRules int
+		wantPolicy    bool
+		wantErr       bool
+	}{
+		{
+			name:          "test cors rules of the cr take precedence over the tier",
+			specCORSRules: []types.CORSRule{corsRule},
+			strategy: &BucketStrategy{
+				CORSRules: []types.CORSRule{corsRule, corsRule},
+			},
+			s3svc:         &mockS3Svc{},
+			wantCORSRules: 1,
+		},
+		{
+			name: "test cors rules and policy of the tier are set",
+			strategy: &BucketStrategy{
+				CORSRules: []types.CORSRule{corsRule},
+				PolicyStatements: []*BucketPolicyStatement{
+					{Sid: "CDNRead", Principal: map[string]string{"AWS": "arn:aws:iam::cloudfront:user/test"}, Actions: []string{"s3:GetObject"}},
+				},
+			},
+			s3svc:         &mockS3Svc{},
+			wantCORSRules: 1,
+			wantPolicy:    true,
+		},
+		{
+			name: "test cors rules and policy are removed without a strategy",
+			s3svc: &mockS3Svc{
+				corsRules: []*s3.CORSRule{{}},
+				policy:    aws.String("{}"),
+			},
+		},
+		{
+			name: "test error when a cors rule has no origins",
+			strategy: &BucketStrategy{
+				CORSRules: []types.CORSRule{{AllowedMethods: []string{"GET"}}},
+			},
+			s3svc:   &mockS3Svc{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bs := buildTestBlobStorageCR()
+			bs.Spec.CORSRules = tt.specCORSRules
+			if err := reconcileS3BucketAccess(bs, "test", tt.strategy, tt.s3svc); (err != nil) != tt.wantErr {
+				t.Fatalf("reconcileS3BucketAccess() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(tt.s3svc.corsRules) != tt.wantCORSRules {
+				t.Errorf("reconcileS3BucketAccess() set %d cors rules, want %d", len(tt.s3svc.corsRules), tt.wantCORSRules)
+			}
+			if (tt.s3svc.policy != nil) != tt.wantPolicy {
+				t.Errorf("reconcileS3BucketAccess() policy = %v, want policy %v", aws.StringValue(tt.s3svc.policy), tt.wantPolicy)
+			}
+		})
+	}
+}
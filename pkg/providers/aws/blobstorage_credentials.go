@@ -0,0 +1,173 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/cloud-resource-operator/pkg/providers"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+	errorUtil "github.com/pkg/errors"
+	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// credentialNameRegexp matches the names of named credentials, which are used in the names of secrets
+var credentialNameRegexp = regexp.MustCompile("^[a-z0-9]([-a-z0-9]*[a-z0-9])?$")
+
+// reconcileBucketCredentials mints a credential for each named credential of a blob storage cr, with access to the
+// bucket restricted to the access level and prefix of the credential. the credential requests of named credentials
+// which are no longer requested are deleted, the details of the credentials are returned by name. the credentials are
+// rotated with the end-user credentials
+func (p *BlobStorageProvider) reconcileBucketCredentials(ctx context.Context, bs *v1alpha1.BlobStorage, bucket, region, kmsKeyArn string, iamSvc iamiface.IAMAPI, gracePeriod time.Duration) (map[string]providers.DeploymentDetails, error) {
+	requested := map[string]bool{}
+	for _, credential := range bs.Spec.Credentials {
+		if !credentialNameRegexp.MatchString(credential.Name) {
+			return nil, fmt.Errorf("credential name %q must consist of lower case alphanumeric characters or '-'", credential.Name)
+		}
+		if requested[credential.Name] {
+			return nil, fmt.Errorf("credential name %s is not unique", credential.Name)
+		}
+		requested[credential.Name] = true
+	}
+
+	details := map[string]providers.DeploymentDetails{}
+	for _, credential := range bs.Spec.Credentials {
+		entries, err := buildBucketAccessEntries(bucket, kmsKeyArn, credential.Access, credential.Prefix)
+		if err != nil {
+			return nil, errorUtil.Wrapf(err, "invalid credential %s", credential.Name)
+		}
+		credsName := buildNamedCredentialsNameFromBucket(bucket, credential.Name)
+		p.Logger.Infof("creating %s credentials with name %s for s3 bucket %s", credential.Access, credsName, bucket)
+		_, creds, err := p.CredentialManager.ReconcileCredentials(ctx, credsName, bs.Namespace, entries)
+		if err != nil {
+			return nil, errorUtil.Wrapf(err, "failed to reconcile credential %s", credential.Name)
+		}
+		creds, err = reconcileAccessKeyRotation(ctx, p.Client, iamSvc, bs, bs.Spec.RotationInterval, gracePeriod, creds, credsName, bs.Namespace)
+		if err != nil {
+			return nil, errorUtil.Wrapf(err, "failed to rotate credential %s", credential.Name)
+		}
+		details[credential.Name] = &BlobStorageDeploymentDetails{
+			BucketName:          bucket,
+			BucketRegion:        region,
+			CredentialKeyID:     creds.AccessKeyID,
+			CredentialSecretKey: creds.SecretAccessKey,
+		}
+	}
+
+	for _, credential := range bs.Status.Credentials {
+		if requested[credential.Name] {
+			continue
+		}
+		if err := p.deleteCredentialRequest(ctx, buildNamedCredentialsNameFromBucket(bucket, credential.Name), bs.Namespace); err != nil {
+			return nil, err
+		}
+	}
+	return details, nil
+}
+
+// reconcileEndUserCredentials returns the credentials of the end-user of a bucket, with full access to the bucket. the
+// credentials are static iam user credentials minted by a credentials request, unless the credential strategy of the
+// tier issues them for an iam role of the bucket, in which case the credentials of the role are returned too. switching
+// between the modes removes the credentials request or the iam role of the previous mode. static credentials are
+// rotated as configured by the cr
+func (p *BlobStorageProvider) reconcileEndUserCredentials(ctx context.Context, bs *v1alpha1.BlobStorage, bucket, kmsKeyArn string, strategy *CredentialStrategy, iamSvc iamiface.IAMAPI, stsSvc stsiface.STSAPI) (*Credentials, *RoleCredentials, error) {
+	endUserCredsName := buildEndUserCredentialsNameFromBucket(bucket)
+	previous, err := p.getBucketRoleCredentials(ctx, bs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !strategy.isRoleBased() {
+		if previous != nil && previous.RoleArn != "" {
+			p.Logger.Infof("deleting iam role of s3 bucket %s, credentials are no longer issued for a role", bucket)
+			if err := deleteIAMRole(iamSvc, buildBucketRoleName(bucket), defaultRolePolicyName); err != nil {
+				return nil, nil, errorUtil.Wrapf(err, "failed to delete iam role of bucket %s", bucket)
+			}
+		}
+		p.Logger.Infof("creating end-user credentials with name %s for managing s3 bucket %s", endUserCredsName, bucket)
+		endUserCreds, _, err := p.CredentialManager.ReoncileBucketOwnerCredentials(ctx, endUserCredsName, bs.Namespace, bucket, kmsKeyArn)
+		if err != nil {
+			return nil, nil, err
+		}
+		endUserCreds, err = reconcileAccessKeyRotation(ctx, p.Client, iamSvc, bs, bs.Spec.RotationInterval, strategy.getRotationGracePeriod(), endUserCreds, endUserCredsName, bs.Namespace)
+		if err != nil {
+			return nil, nil, errorUtil.Wrapf(err, "failed to rotate end-user credentials of bucket %s", bucket)
+		}
+		return endUserCreds, nil, nil
+	}
+
+	clusterID, err := resources.GetClusterID(ctx, p.Client)
+	if err != nil {
+		return nil, nil, errorUtil.Wrap(err, "failed to get cluster id")
+	}
+	var roleTags []*iam.Tag
+	for _, tag := range buildBlobStorageTags(bs, clusterID) {
+		roleTags = append(roleTags, &iam.Tag{Key: tag.Key, Value: tag.Value})
+	}
+	p.Logger.Infof("creating %s credentials of an iam role for managing s3 bucket %s", strategy.Mode, bucket)
+	roleCreds, err := reconcileRoleCredentials(iamSvc, stsSvc, strategy, clusterID, buildBucketRoleName(bucket), bs.Namespace, buildPutBucketObjectEntries(bucket, kmsKeyArn), roleTags, previous)
+	if err != nil {
+		return nil, nil, err
+	}
+	// the static credentials of the end-user are revoked once credentials are issued for the role, no role is created
+	// in dry run mode
+	if roleCreds.RoleArn != "" && (previous == nil || previous.RoleArn == "") {
+		if err := p.deleteCredentialRequest(ctx, endUserCredsName, bs.Namespace); err != nil {
+			return nil, nil, err
+		}
+	}
+	return &Credentials{
+		AccessKeyID:     roleCreds.AccessKeyID,
+		SecretAccessKey: roleCreds.SecretAccessKey,
+	}, roleCreds, nil
+}
+
+// getBucketRoleCredentials returns the role credentials in the result secret of a blob storage cr, nil is returned if
+// the secret does not exist yet
+func (p *BlobStorageProvider) getBucketRoleCredentials(ctx context.Context, bs *v1alpha1.BlobStorage) (*RoleCredentials, error) {
+	if bs.Spec.SecretRef == nil {
+		return nil, nil
+	}
+	ns := bs.Spec.SecretRef.Namespace
+	if ns == "" {
+		ns = bs.Namespace
+	}
+	sec := &v12.Secret{}
+	if err := p.Client.Get(ctx, types.NamespacedName{Name: bs.Spec.SecretRef.Name, Namespace: ns}, sec); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errorUtil.Wrapf(err, "failed to get secret %s", bs.Spec.SecretRef.Name)
+	}
+	return &RoleCredentials{
+		RoleArn:              string(sec.Data[DetailsBlobStorageRoleArn]),
+		AccessKeyID:          string(sec.Data[DetailsBlobStorageCredentialKeyID]),
+		SecretAccessKey:      string(sec.Data[DetailsBlobStorageCredentialSecretKey]),
+		SessionToken:         string(sec.Data[DetailsBlobStorageSessionToken]),
+		Expiration:           string(sec.Data[DetailsBlobStorageExpiration]),
+		WebIdentityTokenFile: string(sec.Data[DetailsBlobStorageTokenFile]),
+	}, nil
+}
+
+// deleteCredentialRequest deletes a credential request created by the provider, a request which does not exist is
+// ignored
+func (p *BlobStorageProvider) deleteCredentialRequest(ctx context.Context, name, ns string) error {
+	p.Logger.Infof("deleting credential request %s in namespace %s", name, ns)
+	return deleteCredentialsRequest(ctx, p.Client, name, ns)
+}
+
+// buildBucketRoleName returns the name of the iam role the credentials of the end-user of a bucket are issued for
+func buildBucketRoleName(bucket string) string {
+	return resources.ShortenString(fmt.Sprintf("%s-access", bucket), maxIAMRoleNameLength)
+}
+
+func buildNamedCredentialsNameFromBucket(b, name string) string {
+	return fmt.Sprintf("%s-%s", buildEndUserCredentialsNameFromBucket(b), name)
+}
@@ -0,0 +1,189 @@
+package aws
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	v1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_buildBucketAccessEntries(t *testing.T) {
+	tests := []struct {
+		name      string
+		kmsKeyArn string
+		access    string
+		prefix    string
+		want      []v1.StatementEntry
+		wantErr   bool
+	}{
+		{
+			name:   "test read-only credentials can list the bucket and get objects",
+			access: croType.CredentialAccessReadOnly,
+			want: []v1.StatementEntry{
+				{Effect: "Allow", Action: []string{"s3:GetBucketLocation", "s3:ListBucket"}, Resource: "arn:aws:s3:::test"},
+				{Effect: "Allow", Action: []string{"s3:GetObject"}, Resource: "arn:aws:s3:::test/*"},
+			},
+		},
+		{
+			name:   "test write-only credentials can only put objects",
+			access: croType.CredentialAccessWriteOnly,
+			want: []v1.StatementEntry{
+				{Effect: "Allow", Action: []string{"s3:GetBucketLocation"}, Resource: "arn:aws:s3:::test"},
+				{Effect: "Allow", Action: []string{"s3:PutObject", "s3:AbortMultipartUpload", "s3:ListMultipartUploadParts"}, Resource: "arn:aws:s3:::test/*"},
+			},
+		},
+		{
+			name:   "test prefix restricted credentials can not list the bucket",
+			access: croType.CredentialAccessReadOnly,
+			prefix: "logs/",
+			want: []v1.StatementEntry{
+				{Effect: "Allow", Action: []string{"s3:GetBucketLocation"}, Resource: "arn:aws:s3:::test"},
+				{Effect: "Allow", Action: []string{"s3:GetObject"}, Resource: "arn:aws:s3:::test/logs/*"},
+			},
+		},
+		{
+			name:      "test read-write credentials can use the kms key of the bucket",
+			kmsKeyArn: "arn:aws:kms:us-east-1:test:key/test",
+			access:    croType.CredentialAccessReadWrite,
+			want: []v1.StatementEntry{
+				{Effect: "Allow", Action: []string{"s3:GetBucketLocation", "s3:ListBucket", "s3:ListBucketMultipartUploads"}, Resource: "arn:aws:s3:::test"},
+				{Effect: "Allow", Action: []string{"s3:GetObject", "s3:PutObject", "s3:DeleteObject", "s3:AbortMultipartUpload", "s3:ListMultipartUploadParts"}, Resource: "arn:aws:s3:::test/*"},
+				{Effect: "Allow", Action: []string{"kms:Decrypt", "kms:GenerateDataKey"}, Resource: "arn:aws:kms:us-east-1:test:key/test"},
+			},
+		},
+		{
+			name:    "test error with an unsupported access level",
+			access:  "admin",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildBucketAccessEntries("test", tt.kmsKeyArn, tt.access, tt.prefix)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildBucketAccessEntries() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildBucketAccessEntries() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlobStorageProvider_reconcileBucketCredentials(t *testing.T) {
+	scheme, err := buildTestScheme()
+	if err != nil {
+		t.Fatal("failed to build test scheme", err)
+	}
+	buildCR := func(credentials []croType.ResourceCredential, statuses []croType.ResourceCredentialStatus) *v1alpha1.BlobStorage {
+		bs := buildTestBlobStorageCR()
+		bs.Spec.Credentials = credentials
+		bs.Status.Credentials = statuses
+		return bs
+	}
+	buildCredsReq := func(name string) *v1.CredentialsRequest {
+		return &v1.CredentialsRequest{
+			ObjectMeta: controllerruntime.ObjectMeta{
+				Name:      buildNamedCredentialsNameFromBucket("test", name),
+				Namespace: "test",
+			},
+		}
+	}
+	tests := []struct {
+		name        string
+		bs          *v1alpha1.BlobStorage
+		credsReqs   []*v1.CredentialsRequest
+		want        []string
+		wantDeleted []string
+		wantErr     bool
+	}{
+		{
+			name: "test credentials are minted for each named credential",
+			bs: buildCR([]croType.ResourceCredential{
+				{Name: "reader", Access: croType.CredentialAccessReadOnly},
+				{Name: "writer", Access: croType.CredentialAccessWriteOnly, Prefix: "uploads/"},
+			}, nil),
+			want: []string{"reader", "writer"},
+		},
+		{
+			name: "test credential requests of removed credentials are deleted",
+			bs: buildCR([]croType.ResourceCredential{
+				{Name: "reader", Access: croType.CredentialAccessReadOnly},
+			}, []croType.ResourceCredentialStatus{
+				{Name: "reader"},
+				{Name: "writer"},
+			}),
+			credsReqs:   []*v1.CredentialsRequest{buildCredsReq("reader"), buildCredsReq("writer")},
+			want:        []string{"reader"},
+			wantDeleted: []string{"writer"},
+		},
+		{
+			name: "test error when credential names are not unique",
+			bs: buildCR([]croType.ResourceCredential{
+				{Name: "reader", Access: croType.CredentialAccessReadOnly},
+				{Name: "reader", Access: croType.CredentialAccessReadWrite},
+			}, nil),
+			wantErr: true,
+		},
+		{
+			name: "test error when a credential name is not a valid secret name suffix",
+			bs: buildCR([]croType.ResourceCredential{
+				{Name: "Reader_1", Access: croType.CredentialAccessReadOnly},
+			}, nil),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewFakeClientWithScheme(scheme, tt.bs, buildTestInfrastructure())
+			for _, credsReq := range tt.credsReqs {
+				if err := client.Create(context.TODO(), credsReq); err != nil {
+					t.Fatal("failed to create credential request", err)
+				}
+			}
+			var minted []string
+			p := &BlobStorageProvider{
+				Client: client,
+				Logger: testLogger,
+				CredentialManager: &CredentialManagerMock{
+					ReconcileCredentialsFunc: func(ctx context.Context, name string, ns string, entries []v1.StatementEntry) (*v1.CredentialsRequest, *Credentials, error) {
+						minted = append(minted, name)
+						return &v1.CredentialsRequest{}, &Credentials{AccessKeyID: name, SecretAccessKey: name}, nil
+					},
+				},
+			}
+			got, err := p.reconcileBucketCredentials(context.TODO(), tt.bs, "test", "us-east-1", "", buildMockIAMClient(), defaultRotationGracePeriod)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("reconcileBucketCredentials() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) || len(minted) != len(tt.want) {
+				t.Fatalf("reconcileBucketCredentials() returned %d credentials and minted %d, want %d", len(got), len(minted), len(tt.want))
+			}
+			for _, name := range tt.want {
+				details, ok := got[name].(*BlobStorageDeploymentDetails)
+				if !ok {
+					t.Fatalf("reconcileBucketCredentials() did not return credential %s", name)
+				}
+				if details.CredentialKeyID != buildNamedCredentialsNameFromBucket("test", name) {
+					t.Errorf("reconcileBucketCredentials() credential %s has key %s", name, details.CredentialKeyID)
+				}
+			}
+			for _, name := range tt.wantDeleted {
+				err := client.Get(context.TODO(), types.NamespacedName{Name: buildNamedCredentialsNameFromBucket("test", name), Namespace: "test"}, &v1.CredentialsRequest{})
+				if !errors.IsNotFound(err) {
+					t.Errorf("reconcileBucketCredentials() did not delete credential request of %s, error = %v", name, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package aws
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+	errorUtil "github.com/pkg/errors"
+)
+
+const (
+	// bucketUsageInterval is how often the objects of a bucket are listed to compute its usage metrics
+	bucketUsageInterval = time.Hour
+	// maxBucketUsageListings is the number of buckets whose objects are listed at the same time
+	maxBucketUsageListings = 5
+)
+
+// bucketUsage is the number and total size of the objects of a bucket, and when an object was last modified
+type bucketUsage struct {
+	Objects      int64
+	Bytes        int64
+	LastModified *time.Time
+}
+
+// getBucketUsage lists all objects of a bucket page by page, the last modified time is nil for an empty bucket
+func getBucketUsage(s3svc s3iface.S3API, bucket string) (*bucketUsage, error) {
+	usage := &bucketUsage{}
+	if err := s3svc.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, object := range page.Contents {
+			usage.Objects++
+			usage.Bytes += aws.Int64Value(object.Size)
+			if object.LastModified != nil && (usage.LastModified == nil || object.LastModified.After(*usage.LastModified)) {
+				usage.LastModified = object.LastModified
+			}
+		}
+		return true
+	}); err != nil {
+		return nil, errorUtil.Wrapf(err, "unable to list objects in bucket %q", bucket)
+	}
+	return usage, nil
+}
+
+// bucketUsageCache keeps the usage of each bucket between listings, listing the objects of a large bucket can take
+// many requests so it is done in the background at most once per interval, for a limited number of buckets at a time
+type bucketUsageCache struct {
+	mu       sync.Mutex
+	interval time.Duration
+	entries  map[string]*bucketUsageEntry
+	listings chan struct{}
+}
+
+type bucketUsageEntry struct {
+	usage    *bucketUsage
+	listedAt time.Time
+	listing  bool
+}
+
+var bucketUsages = newBucketUsageCache(bucketUsageInterval, maxBucketUsageListings)
+
+func newBucketUsageCache(interval time.Duration, maxListings int) *bucketUsageCache {
+	return &bucketUsageCache{
+		interval: interval,
+		entries:  map[string]*bucketUsageEntry{},
+		listings: make(chan struct{}, maxListings),
+	}
+}
+
+// get returns the last known usage of a bucket, which is nil until the bucket has been listed once. a listing is
+// started in the background when the usage is older than the interval and no listing is in progress, unless the
+// maximum number of listings are already in progress, in which case it is started by a later call
+func (c *bucketUsageCache) get(s3svc s3iface.S3API, bucket string, onErr func(error)) *bucketUsage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[bucket]
+	if !ok {
+		entry = &bucketUsageEntry{}
+		c.entries[bucket] = entry
+	}
+	if !entry.listing && time.Since(entry.listedAt) >= c.interval {
+		select {
+		case c.listings <- struct{}{}:
+			entry.listing = true
+			go c.list(s3svc, bucket, entry, onErr)
+		default:
+		}
+	}
+	return entry.usage
+}
+
+// list computes the usage of a bucket and stores it, a failed listing is retried after the interval. the usage is
+// dropped if the bucket was evicted while it was listed
+func (c *bucketUsageCache) list(s3svc s3iface.S3API, bucket string, entry *bucketUsageEntry, onErr func(error)) {
+	usage, err := getBucketUsage(s3svc, bucket)
+	<-c.listings
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries[bucket] != entry {
+		return
+	}
+	entry.listing = false
+	entry.listedAt = time.Now()
+	if err != nil {
+		onErr(err)
+		return
+	}
+	entry.usage = usage
+}
+
+// evict removes the usage of a deleted bucket, so it is not kept for the lifetime of the operator or exposed for a new
+// bucket with the same name
+func (c *bucketUsageCache) evict(bucket string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, bucket)
+}
+
+// exposeBlobStorageMetrics exposes the availability of a bucket, and the number and total size of its objects and the
+// age of its last modified object. the usage of a bucket which is not available, or has not been listed yet, is not
+// exposed
+func (p *BlobStorageProvider) exposeBlobStorageMetrics(ctx context.Context, bs *v1alpha1.BlobStorage, bucket string, s3svc s3iface.S3API) {
+	clusterID, err := resources.GetClusterID(ctx, p.Client)
+	if err != nil {
+		p.Logger.Errorf("failed to get cluster id while exposing metrics for %s", bucket)
+		return
+	}
+	genericLabels := buildBlobStorageGenericMetricLabels(bs, bucket, clusterID)
+
+	if _, err := s3svc.HeadBucket(&s3.HeadBucketInput{
+		Bucket: aws.String(bucket),
+	}); err != nil {
+		p.Logger.Errorf("s3 bucket %s is not available: %v", bucket, err)
+		resources.SetMetric(resources.DefaultBlobStorageAvailMetricName, genericLabels, 0)
+		return
+	}
+	resources.SetMetric(resources.DefaultBlobStorageAvailMetricName, genericLabels, 1)
+
+	usage := bucketUsages.get(s3svc, bucket, func(err error) {
+		p.Logger.Errorf("failed to get usage of s3 bucket %s while exposing metrics: %v", bucket, err)
+	})
+	if usage == nil {
+		return
+	}
+	resources.SetMetric(resources.DefaultBlobStorageObjectsMetricName, genericLabels, float64(usage.Objects))
+	resources.SetMetric(resources.DefaultBlobStorageBytesMetricName, genericLabels, float64(usage.Bytes))
+	if usage.LastModified != nil {
+		resources.SetMetric(resources.DefaultBlobStorageAgeMetricName, genericLabels, time.Since(*usage.LastModified).Seconds())
+	}
+}
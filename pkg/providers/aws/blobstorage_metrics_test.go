@@ -0,0 +1,157 @@
+package aws
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func Test_getBucketUsage(t *testing.T) {
+	lastModified := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		s3svc *mockS3Svc
+		want  *bucketUsage
+	}{
+		{
+			name: "test objects of all pages are counted",
+			s3svc: &mockS3Svc{
+				objects: []*s3.Object{
+					{Key: aws.String("a"), Size: aws.Int64(10), LastModified: aws.Time(lastModified.AddDate(0, 0, -1))},
+					{Key: aws.String("b"), Size: aws.Int64(20), LastModified: aws.Time(lastModified)},
+					{Key: aws.String("c"), Size: aws.Int64(30), LastModified: aws.Time(lastModified.AddDate(0, -1, 0))},
+				},
+			},
+			want: &bucketUsage{Objects: 3, Bytes: 60, LastModified: &lastModified},
+		},
+		{
+			name:  "test empty bucket has no last modified time",
+			s3svc: &mockS3Svc{},
+			want:  &bucketUsage{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getBucketUsage(tt.s3svc, "test")
+			if err != nil {
+				t.Fatal("getBucketUsage() returned an error", err)
+			}
+			if got.Objects != tt.want.Objects || got.Bytes != tt.want.Bytes {
+				t.Errorf("getBucketUsage() = %d objects of %d bytes, want %d objects of %d bytes", got.Objects, got.Bytes, tt.want.Objects, tt.want.Bytes)
+			}
+			if aws.TimeValue(got.LastModified) != aws.TimeValue(tt.want.LastModified) {
+				t.Errorf("getBucketUsage() last modified = %v, want %v", got.LastModified, tt.want.LastModified)
+			}
+		})
+	}
+}
+
+// mockCountingS3Svc counts the listings of the objects of a bucket
+type mockCountingS3Svc struct {
+	*mockS3Svc
+	listings int32
+}
+
+func (s *mockCountingS3Svc) ListObjectsV2Pages(input *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool) error {
+	atomic.AddInt32(&s.listings, 1)
+	return s.mockS3Svc.ListObjectsV2Pages(input, fn)
+}
+
+func Test_bucketUsageCache(t *testing.T) {
+	s3svc := &mockCountingS3Svc{mockS3Svc: &mockS3Svc{
+		objects: []*s3.Object{{Key: aws.String("a"), Size: aws.Int64(10)}},
+	}}
+	cache := newBucketUsageCache(time.Hour, 1)
+	onErr := func(err error) {
+		t.Error("bucket usage listing returned an error", err)
+	}
+	if got := cache.get(s3svc, "test", onErr); got != nil {
+		t.Fatalf("get() = %v before the bucket was listed, want nil", got)
+	}
+	var got *bucketUsage
+	for i := 0; i < 100 && got == nil; i++ {
+		time.Sleep(10 * time.Millisecond)
+		got = cache.get(s3svc, "test", onErr)
+	}
+	if got == nil || got.Objects != 1 || got.Bytes != 10 {
+		t.Fatalf("get() = %v once the bucket was listed, want 1 object of 10 bytes", got)
+	}
+	if listings := atomic.LoadInt32(&s3svc.listings); listings != 1 {
+		t.Errorf("bucket listed %d times within the interval, want 1", listings)
+	}
+}
+
+// mockBlockingS3Svc blocks the listings of the objects of a bucket until it is released
+type mockBlockingS3Svc struct {
+	*mockCountingS3Svc
+	release chan struct{}
+}
+
+func (s *mockBlockingS3Svc) ListObjectsV2Pages(input *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool) error {
+	<-s.release
+	return s.mockCountingS3Svc.ListObjectsV2Pages(input, fn)
+}
+
+func Test_bucketUsageCache_maxListings(t *testing.T) {
+	s3svc := &mockBlockingS3Svc{
+		mockCountingS3Svc: &mockCountingS3Svc{mockS3Svc: &mockS3Svc{}},
+		release:           make(chan struct{}),
+	}
+	cache := newBucketUsageCache(time.Hour, 1)
+	onErr := func(err error) {
+		t.Error("bucket usage listing returned an error", err)
+	}
+	listed := func(bucket string) bool {
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		entry := cache.entries[bucket]
+		return entry.listing || !entry.listedAt.IsZero()
+	}
+	cache.get(s3svc, "first", onErr)
+	cache.get(s3svc, "second", onErr)
+	if listed("second") {
+		t.Fatal("get() started a listing of the second bucket while the first bucket was listed, want at most 1 listing")
+	}
+	close(s3svc.release)
+	for i := 0; i < 100 && !listed("second"); i++ {
+		time.Sleep(10 * time.Millisecond)
+		cache.get(s3svc, "second", onErr)
+	}
+	if !listed("second") {
+		t.Error("get() did not list the second bucket once the first bucket was listed")
+	}
+}
+
+func Test_bucketUsageCache_evict(t *testing.T) {
+	s3svc := &mockBlockingS3Svc{
+		mockCountingS3Svc: &mockCountingS3Svc{mockS3Svc: &mockS3Svc{
+			objects: []*s3.Object{{Key: aws.String("a"), Size: aws.Int64(10)}},
+		}},
+		release: make(chan struct{}),
+	}
+	cache := newBucketUsageCache(time.Hour, 1)
+	onErr := func(err error) {
+		t.Error("bucket usage listing returned an error", err)
+	}
+	cache.get(s3svc, "test", onErr)
+	cache.evict("test")
+	close(s3svc.release)
+	for i := 0; i < 100 && atomic.LoadInt32(&s3svc.listings) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	// the listing slot is released once the evicted bucket has been listed
+	select {
+	case cache.listings <- struct{}{}:
+		<-cache.listings
+	case <-time.After(time.Second):
+		t.Fatal("listing of the evicted bucket did not release its slot")
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if entry, ok := cache.entries["test"]; ok {
+		t.Errorf("evicted bucket has usage %v, want none", entry.usage)
+	}
+}
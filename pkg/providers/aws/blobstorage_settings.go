@@ -0,0 +1,495 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+	errorUtil "github.com/pkg/errors"
+)
+
+const (
+	// defaultReplicationRuleID is the id of the rule objects are replicated to the replica bucket with
+	defaultReplicationRuleID = "cro-replication"
+	// defaultReplicationPolicyName is the name of the inline policy of the iam role s3 replicates objects with
+	defaultReplicationPolicyName = "cro-s3-replication"
+	// maxBucketNameLength and maxIAMRoleNameLength are the maximum lengths of the names of buckets and iam roles
+	maxBucketNameLength  = 63
+	maxIAMRoleNameLength = 64
+)
+
+// BucketStrategy configures the versioning, lifecycle rules and replication of the buckets of a tier. the settings are
+// reconciled continuously once the strategy is set, the existing settings of a bucket are left untouched otherwise
+type BucketStrategy struct {
+	// Versioning keeps the previous versions of objects, it is always enabled when objects are replicated
+	Versioning bool `json:"versioning,omitempty"`
+	// LifecycleRules expire objects, transition them to other storage classes and abort incomplete uploads
+	LifecycleRules []*BucketLifecycleRule `json:"lifecycleRules,omitempty"`
+	// Replication replicates objects to a bucket in another region, which is created by the operator
+	Replication *BucketReplicationStrategy `json:"replication,omitempty"`
+	// CORSRules are the cross-origin resource sharing rules of the buckets, unless a blob storage cr sets its own
+	CORSRules []croType.CORSRule `json:"corsRules,omitempty"`
+	// PolicyStatements are the statements of the bucket policy of the buckets
+	PolicyStatements []*BucketPolicyStatement `json:"policyStatements,omitempty"`
+	// AllowPublicPolicy relaxes the public access block of the buckets, so policy statements can grant public access
+	AllowPublicPolicy bool `json:"allowPublicPolicy,omitempty"`
+}
+
+// BucketLifecycleRule is a lifecycle rule of the objects with Prefix, or of all objects when it is empty. each action
+// is taken a number of days after an object is created, actions which are not set are not taken
+type BucketLifecycleRule struct {
+	ID                              string `json:"id"`
+	Prefix                          string `json:"prefix,omitempty"`
+	ExpirationDays                  int64  `json:"expirationDays,omitempty"`
+	NoncurrentVersionExpirationDays int64  `json:"noncurrentVersionExpirationDays,omitempty"`
+	TransitionToIADays              int64  `json:"transitionToIADays,omitempty"`
+	TransitionToGlacierDays         int64  `json:"transitionToGlacierDays,omitempty"`
+	AbortIncompleteUploadDays       int64  `json:"abortIncompleteUploadDays,omitempty"`
+}
+
+// BucketReplicationStrategy configures the region of the replica bucket, and the storage class of replicated objects
+type BucketReplicationStrategy struct {
+	Region       string `json:"region"`
+	StorageClass string `json:"storageClass,omitempty"`
+}
+
+// isPublicPolicyAllowed returns true if the strategy allows bucket policies to grant public access, which is never the
+// case without a strategy
+func (s *BucketStrategy) isPublicPolicyAllowed() bool {
+	return s != nil && s.AllowPublicPolicy
+}
+
+// isVersioningEnabled returns true if the strategy requires the objects of a bucket to be versioned
+func (s *BucketStrategy) isVersioningEnabled() bool {
+	return s.Versioning || s.Replication != nil
+}
+
+// validateBucketStrategy verifies the replication of a bucket strategy can be set up for buckets in a region
+func validateBucketStrategy(strategy *BucketStrategy, region, kmsKeyArn string) error {
+	if strategy.Replication == nil {
+		return nil
+	}
+	if strategy.Replication.Region == "" || strategy.Replication.Region == region {
+		return fmt.Errorf("objects must be replicated to a region other than %s", region)
+	}
+	// objects encrypted with a customer managed key are only replicated to a key in the replica region
+	if kmsKeyArn != "" {
+		return fmt.Errorf("replication of buckets encrypted with a customer managed kms key is not supported")
+	}
+	return nil
+}
+
+// newS3ReplicaClient returns an s3 client in the region of the replica bucket of a strategy, nil is returned if objects
+// are not replicated
+func newS3ReplicaClient(sess *session.Session, strategy *BucketStrategy) s3iface.S3API {
+	if strategy == nil || strategy.Replication == nil {
+		return nil
+	}
+	return s3.New(sess.Copy(&aws.Config{Region: aws.String(strategy.Replication.Region)}))
+}
+
+// newS3RegionClientFunc returns a function which creates s3 clients in a region, for buckets whose region is only known
+// once they are found
+func newS3RegionClientFunc(sess *session.Session) func(region string) s3iface.S3API {
+	return func(region string) s3iface.S3API {
+		return s3.New(sess.Copy(&aws.Config{Region: aws.String(region)}))
+	}
+}
+
+// reconcileS3BucketStrategy sets the versioning, lifecycle rules and replication of a bucket described by the strategy
+// of its tier. the replica bucket and the iam role objects are replicated with are created by the operator
+func (p *BlobStorageProvider) reconcileS3BucketStrategy(ctx context.Context, bs *v1alpha1.BlobStorage, bucket, region, kmsKeyArn string, strategy *BucketStrategy, s3svc, replicaSvc s3iface.S3API, iamSvc iamiface.IAMAPI) error {
+	if err := validateBucketStrategy(strategy, region, kmsKeyArn); err != nil {
+		return errorUtil.Wrap(err, "invalid bucket strategy")
+	}
+	if err := reconcileS3BucketVersioning(bucket, strategy.isVersioningEnabled(), s3svc); err != nil {
+		return err
+	}
+	rules, err := buildS3LifecycleRules(strategy.LifecycleRules)
+	if err != nil {
+		return errorUtil.Wrap(err, "invalid lifecycle rules")
+	}
+	if err := reconcileS3BucketLifecycle(bucket, rules, s3svc); err != nil {
+		return err
+	}
+	return p.reconcileS3BucketReplication(ctx, bs, bucket, strategy.Replication, s3svc, replicaSvc, iamSvc)
+}
+
+// buildS3VersioningStatus returns the desired versioning status of a bucket. versioning can not be disabled once it has
+// been enabled, it is suspended instead. an empty status is returned for a bucket which has never been versioned
+func buildS3VersioningStatus(enabled bool, observed string) string {
+	if enabled {
+		return s3.BucketVersioningStatusEnabled
+	}
+	if observed == "" {
+		return ""
+	}
+	return s3.BucketVersioningStatusSuspended
+}
+
+// reconcileS3BucketVersioning enables the versioning of a bucket, or suspends it
+func reconcileS3BucketVersioning(bucket string, enabled bool, s3svc s3iface.S3API) error {
+	output, err := s3svc.GetBucketVersioning(&s3.GetBucketVersioningInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		return errorUtil.Wrapf(err, "failed to get versioning settings of bucket %s", bucket)
+	}
+	observed := aws.StringValue(output.Status)
+	desired := buildS3VersioningStatus(enabled, observed)
+	if desired == observed {
+		return nil
+	}
+	if _, err := s3svc.PutBucketVersioning(&s3.PutBucketVersioningInput{
+		Bucket: aws.String(bucket),
+		VersioningConfiguration: &s3.VersioningConfiguration{
+			Status: aws.String(desired),
+		},
+	}); err != nil {
+		return errorUtil.Wrapf(err, "failed to set versioning settings on bucket %s", bucket)
+	}
+	return nil
+}
+
+// buildS3LifecycleRules returns the s3 lifecycle rules of the lifecycle rules of a strategy
+func buildS3LifecycleRules(rules []*BucketLifecycleRule) ([]*s3.LifecycleRule, error) {
+	var s3Rules []*s3.LifecycleRule
+	ids := map[string]bool{}
+	for _, rule := range rules {
+		if rule.ID == "" {
+			return nil, fmt.Errorf("lifecycle rules require an id")
+		}
+		if ids[rule.ID] {
+			return nil, fmt.Errorf("lifecycle rule id %s is not unique", rule.ID)
+		}
+		ids[rule.ID] = true
+
+		s3Rule := &s3.LifecycleRule{
+			ID:     aws.String(rule.ID),
+			Status: aws.String(s3.ExpirationStatusEnabled),
+			Filter: &s3.LifecycleRuleFilter{
+				Prefix: aws.String(rule.Prefix),
+			},
+		}
+		if rule.ExpirationDays > 0 {
+			s3Rule.Expiration = &s3.LifecycleExpiration{
+				Days: aws.Int64(rule.ExpirationDays),
+			}
+		}
+		if rule.NoncurrentVersionExpirationDays > 0 {
+			s3Rule.NoncurrentVersionExpiration = &s3.NoncurrentVersionExpiration{
+				NoncurrentDays: aws.Int64(rule.NoncurrentVersionExpirationDays),
+			}
+		}
+		if rule.TransitionToIADays > 0 {
+			s3Rule.Transitions = append(s3Rule.Transitions, &s3.Transition{
+				Days:         aws.Int64(rule.TransitionToIADays),
+				StorageClass: aws.String(s3.TransitionStorageClassStandardIa),
+			})
+		}
+		if rule.TransitionToGlacierDays > 0 {
+			s3Rule.Transitions = append(s3Rule.Transitions, &s3.Transition{
+				Days:         aws.Int64(rule.TransitionToGlacierDays),
+				StorageClass: aws.String(s3.TransitionStorageClassGlacier),
+			})
+		}
+		if rule.AbortIncompleteUploadDays > 0 {
+			s3Rule.AbortIncompleteMultipartUpload = &s3.AbortIncompleteMultipartUpload{
+				DaysAfterInitiation: aws.Int64(rule.AbortIncompleteUploadDays),
+			}
+		}
+		if s3Rule.Expiration == nil && s3Rule.NoncurrentVersionExpiration == nil && s3Rule.Transitions == nil && s3Rule.AbortIncompleteMultipartUpload == nil {
+			return nil, fmt.Errorf("lifecycle rule %s has no actions", rule.ID)
+		}
+		s3Rules = append(s3Rules, s3Rule)
+	}
+	return s3Rules, nil
+}
+
+// reconcileS3BucketLifecycle sets the lifecycle rules of a bucket, the lifecycle configuration of the bucket is deleted
+// if there are no rules
+func reconcileS3BucketLifecycle(bucket string, rules []*s3.LifecycleRule, s3svc s3iface.S3API) error {
+	if len(rules) == 0 {
+		if _, err := s3svc.DeleteBucketLifecycle(&s3.DeleteBucketLifecycleInput{
+			Bucket: aws.String(bucket),
+		}); err != nil {
+			return errorUtil.Wrapf(err, "failed to delete lifecycle rules of bucket %s", bucket)
+		}
+		return nil
+	}
+	if _, err := s3svc.PutBucketLifecycleConfiguration(&s3.PutBucketLifecycleConfigurationInput{
+		Bucket: aws.String(bucket),
+		LifecycleConfiguration: &s3.BucketLifecycleConfiguration{
+			Rules: rules,
+		},
+	}); err != nil {
+		return errorUtil.Wrapf(err, "failed to set lifecycle rules on bucket %s", bucket)
+	}
+	return nil
+}
+
+// reconcileS3BucketReplication replicates the objects of a bucket to its replica bucket in the region of the strategy.
+// the replication of a bucket is removed if there is no strategy, the replica bucket is kept as it holds the objects
+// which were replicated
+func (p *BlobStorageProvider) reconcileS3BucketReplication(ctx context.Context, bs *v1alpha1.BlobStorage, bucket string, strategy *BucketReplicationStrategy, s3svc, replicaSvc s3iface.S3API, iamSvc iamiface.IAMAPI) error {
+	if strategy == nil {
+		if _, err := s3svc.DeleteBucketReplication(&s3.DeleteBucketReplicationInput{
+			Bucket: aws.String(bucket),
+		}); err != nil {
+			return errorUtil.Wrapf(err, "failed to delete replication settings of bucket %s", bucket)
+		}
+		if err := deleteIAMRole(iamSvc, buildReplicationRoleName(bucket), defaultReplicationPolicyName); err != nil {
+			return errorUtil.Wrapf(err, "failed to delete replication role of bucket %s", bucket)
+		}
+		return nil
+	}
+
+	clusterID, err := resources.GetClusterID(ctx, p.Client)
+	if err != nil {
+		return errorUtil.Wrap(err, "failed to get cluster id")
+	}
+	tags := buildBlobStorageTags(bs, clusterID)
+
+	// the replica bucket is versioned and secured like the bucket it replicates
+	replica := buildReplicaBucketName(bucket)
+	buckets, err := getS3buckets(replicaSvc)
+	if err != nil {
+		return errorUtil.Wrap(err, "failed to list existing aws s3 buckets")
+	}
+	found := false
+	for _, b := range buckets {
+		if aws.StringValue(b.Name) == replica {
+			found = true
+			break
+		}
+	}
+	if !found {
+		createInput := &s3.CreateBucketInput{
+			Bucket: aws.String(replica),
+		}
+		// buckets are created in us-east-1 unless another location is set
+		if strategy.Region != regionUSEast1 {
+			createInput.CreateBucketConfiguration = &s3.CreateBucketConfiguration{
+				LocationConstraint: aws.String(strategy.Region),
+			}
+		}
+		p.Logger.Infof("creating replica bucket %s of bucket %s in region %s", replica, bucket, strategy.Region)
+		if _, err := replicaSvc.CreateBucket(createInput); err != nil {
+			return errorUtil.Wrapf(err, "failed to create replica bucket %s", replica)
+		}
+		// there is no replica bucket to configure until it is created
+		if resources.IsDryRun() {
+			return nil
+		}
+	}
+	if err := reconcileS3BucketSettings(replica, "", false, replicaSvc); err != nil {
+		return err
+	}
+	if err := reconcileS3BucketVersioning(replica, true, replicaSvc); err != nil {
+		return err
+	}
+	if _, err := replicaSvc.PutBucketTagging(&s3.PutBucketTaggingInput{
+		Bucket: aws.String(replica),
+		Tagging: &s3.Tagging{
+			TagSet: tags,
+		},
+	}); err != nil {
+		return errorUtil.Wrapf(err, "failed to add tags to replica bucket %s", replica)
+	}
+
+	// s3 replicates objects with an iam role which can read the bucket and write to the replica bucket
+	roleArn, err := reconcileReplicationRole(iamSvc, clusterID, bucket, replica, tags)
+	if err != nil {
+		return err
+	}
+	replicationRule := &s3.ReplicationRule{
+		ID:       aws.String(defaultReplicationRuleID),
+		Priority: aws.Int64(1),
+		Status:   aws.String(s3.ReplicationRuleStatusEnabled),
+		Filter: &s3.ReplicationRuleFilter{
+			Prefix: aws.String(""),
+		},
+		DeleteMarkerReplication: &s3.DeleteMarkerReplication{
+			Status: aws.String(s3.DeleteMarkerReplicationStatusDisabled),
+		},
+		Destination: &s3.Destination{
+			Bucket: aws.String(buildS3BucketArn(replica)),
+		},
+	}
+	if strategy.StorageClass != "" {
+		replicationRule.Destination.StorageClass = aws.String(strategy.StorageClass)
+	}
+	if _, err := s3svc.PutBucketReplication(&s3.PutBucketReplicationInput{
+		Bucket: aws.String(bucket),
+		ReplicationConfiguration: &s3.ReplicationConfiguration{
+			Role:  aws.String(roleArn),
+			Rules: []*s3.ReplicationRule{replicationRule},
+		},
+	}); err != nil {
+		return errorUtil.Wrapf(err, "failed to set replication settings on bucket %s", bucket)
+	}
+	return nil
+}
+
+// reconcileReplicationRole creates the iam role s3 replicates the objects of a bucket to its replica bucket with
+func reconcileReplicationRole(iamSvc iamiface.IAMAPI, clusterID, bucket, replica string, tags []*s3.Tag) (string, error) {
+	roleName := buildReplicationRoleName(bucket)
+	trustPolicy, err := buildIAMPolicyDocument(iamPolicyStatement{
+		Effect:    "Allow",
+		Principal: map[string]string{"Service": "s3.amazonaws.com"},
+		Action:    []string{"sts:AssumeRole"},
+	})
+	if err != nil {
+		return "", err
+	}
+	policy, err := buildIAMPolicyDocument(
+		iamPolicyStatement{
+			Effect:   "Allow",
+			Action:   []string{"s3:GetReplicationConfiguration", "s3:ListBucket"},
+			Resource: []string{buildS3BucketArn(bucket)},
+		},
+		iamPolicyStatement{
+			Effect:   "Allow",
+			Action:   []string{"s3:GetObjectVersionForReplication", "s3:GetObjectVersionAcl", "s3:GetObjectVersionTagging"},
+			Resource: []string{buildS3BucketArn(bucket) + "/*"},
+		},
+		iamPolicyStatement{
+			Effect:   "Allow",
+			Action:   []string{"s3:ReplicateObject", "s3:ReplicateDelete", "s3:ReplicateTags"},
+			Resource: []string{buildS3BucketArn(replica) + "/*"},
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	var roleTags []*iam.Tag
+	for _, tag := range tags {
+		roleTags = append(roleTags, &iam.Tag{Key: tag.Key, Value: tag.Value})
+	}
+	roleArn, err := reconcileIAMRole(iamSvc, clusterID, roleName, trustPolicy, defaultReplicationPolicyName, policy, roleTags)
+	if err != nil {
+		return "", errorUtil.Wrapf(err, "failed to reconcile replication role of bucket %s", bucket)
+	}
+	return roleArn, nil
+}
+
+// deleteS3BucketReplica deletes the replica bucket of a bucket, and the iam role objects were replicated with. they are
+// deleted if they exist rather than as described by the strategy, so they are also deleted once replication has been
+// removed from the tier. the replica bucket is emptied over several reconciles like the bucket, false is returned until
+// it is deleted
+func deleteS3BucketReplica(bs *v1alpha1.BlobStorage, bucket string, s3svc s3iface.S3API, newReplicaSvc func(region string) s3iface.S3API, iamSvc iamiface.IAMAPI) (bool, error) {
+	replicaCfg := &s3.CreateBucketInput{
+		Bucket: aws.String(buildReplicaBucketName(bucket)),
+	}
+	// buckets of all regions are listed, the replica bucket is emptied and deleted with a client in its own region
+	buckets, err := getS3buckets(s3svc)
+	if err != nil {
+		return false, errorUtil.Wrap(err, "failed to list existing aws s3 buckets")
+	}
+	for _, b := range buckets {
+		if aws.StringValue(b.Name) != *replicaCfg.Bucket {
+			continue
+		}
+		location, err := s3svc.GetBucketLocation(&s3.GetBucketLocationInput{
+			Bucket: replicaCfg.Bucket,
+		})
+		if err != nil {
+			return false, errorUtil.Wrapf(err, "failed to get region of replica bucket %s", *replicaCfg.Bucket)
+		}
+		replicaSvc := newReplicaSvc(s3.NormalizeBucketLocation(aws.StringValue(location.LocationConstraint)))
+		empty, err := emptyBucket(bs, replicaSvc, replicaCfg)
+		if err != nil {
+			if awsErr, ok := errorUtil.Cause(err).(awserr.Error); !ok || awsErr.Code() != s3.ErrCodeNoSuchBucket {
+				return false, errorUtil.Wrapf(err, "failed to empty replica bucket %s", *replicaCfg.Bucket)
+			}
+			empty = true
+		}
+		if !empty {
+			return false, nil
+		}
+		if err := deleteBucket(replicaSvc, replicaCfg); err != nil {
+			return false, errorUtil.Wrapf(err, "failed to delete replica bucket %s", *replicaCfg.Bucket)
+		}
+		break
+	}
+	if err := deleteIAMRole(iamSvc, buildReplicationRoleName(bucket), defaultReplicationPolicyName); err != nil {
+		return false, errorUtil.Wrapf(err, "failed to delete replication role of bucket %s", bucket)
+	}
+	return true, nil
+}
+
+// buildReplicaBucketName returns the name of the bucket the objects of a bucket are replicated to
+func buildReplicaBucketName(bucket string) string {
+	return resources.ShortenString(fmt.Sprintf("%s-replica", bucket), maxBucketNameLength)
+}
+
+// buildReplicationRoleName returns the name of the iam role the objects of a bucket are replicated with
+func buildReplicationRoleName(bucket string) string {
+	return resources.ShortenString(fmt.Sprintf("%s-replication", bucket), maxIAMRoleNameLength)
+}
+
+func buildS3BucketArn(bucket string) string {
+	return fmt.Sprintf("arn:aws:s3:::%s", bucket)
+}
+
+// getS3BucketStrategyDrift returns the versioning, lifecycle rules and replication of a bucket which differ from its
+// bucket strategy
+func getS3BucketStrategyDrift(s3svc s3iface.S3API, bucket string, strategy *BucketStrategy) ([]croType.DriftedField, error) {
+	var drift []croType.DriftedField
+
+	versioningOutput, err := s3svc.GetBucketVersioning(&s3.GetBucketVersioningInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		return nil, errorUtil.Wrapf(err, "failed to get versioning settings of bucket %s", bucket)
+	}
+	observedVersioning := aws.StringValue(versioningOutput.Status)
+	drift = appendDrift(drift, "Versioning.Status", buildS3VersioningStatus(strategy.isVersioningEnabled(), observedVersioning), observedVersioning)
+
+	var desiredRules, observedRules []*string
+	for _, rule := range strategy.LifecycleRules {
+		desiredRules = append(desiredRules, aws.String(rule.ID))
+	}
+	lifecycleOutput, err := s3svc.GetBucketLifecycleConfiguration(&s3.GetBucketLifecycleConfigurationInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		if s3err, isAwsErr := err.(awserr.Error); !isAwsErr || s3err.Code() != s3ErrCodeNoSuchLifecycleConfiguration {
+			return nil, errorUtil.Wrapf(err, "failed to get lifecycle rules of bucket %s", bucket)
+		}
+	} else {
+		for _, rule := range lifecycleOutput.Rules {
+			observedRules = append(observedRules, rule.ID)
+		}
+	}
+	drift = appendDrift(drift, "LifecycleRules", formatDriftList(desiredRules), formatDriftList(observedRules))
+
+	var desiredDestination, observedDestination string
+	if strategy.Replication != nil {
+		desiredDestination = buildS3BucketArn(buildReplicaBucketName(bucket))
+	}
+	replicationOutput, err := s3svc.GetBucketReplication(&s3.GetBucketReplicationInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		if s3err, isAwsErr := err.(awserr.Error); !isAwsErr || s3err.Code() != s3ErrCodeReplicationConfigurationNotFound {
+			return nil, errorUtil.Wrapf(err, "failed to get replication settings of bucket %s", bucket)
+		}
+	} else if replicationOutput.ReplicationConfiguration != nil {
+		for _, rule := range replicationOutput.ReplicationConfiguration.Rules {
+			if aws.StringValue(rule.ID) == defaultReplicationRuleID && rule.Destination != nil {
+				observedDestination = aws.StringValue(rule.Destination.Bucket)
+			}
+		}
+	}
+	return appendDrift(drift, "Replication.Destination", desiredDestination, observedDestination), nil
+}
@@ -0,0 +1,354 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type mockIAMClient struct {
+	iamiface.IAMAPI
+	roles           map[string]*iam.Role
+	policies        map[string]string
+	accessKeys      map[string]bool
+	managedPolicies []*iam.Policy
+}
+
+func (m *mockIAMClient) GetRole(input *iam.GetRoleInput) (*iam.GetRoleOutput, error) {
+	role, ok := m.roles[aws.StringValue(input.RoleName)]
+	if !ok {
+		return nil, awserr.New(iam.ErrCodeNoSuchEntityException, "", nil)
+	}
+	return &iam.GetRoleOutput{Role: role}, nil
+}
+
+func (m *mockIAMClient) CreateRole(input *iam.CreateRoleInput) (*iam.CreateRoleOutput, error) {
+	role := &iam.Role{
+		RoleName:                 input.RoleName,
+		Path:                     input.Path,
+		Arn:                      aws.String("arn:aws:iam::test:role" + aws.StringValue(input.Path) + aws.StringValue(input.RoleName)),
+		AssumeRolePolicyDocument: input.AssumeRolePolicyDocument,
+	}
+	if input.PermissionsBoundary != nil {
+		role.PermissionsBoundary = &iam.AttachedPermissionsBoundary{PermissionsBoundaryArn: input.PermissionsBoundary}
+	}
+	m.roles[aws.StringValue(input.RoleName)] = role
+	return &iam.CreateRoleOutput{Role: role}, nil
+}
+
+func (m *mockIAMClient) UpdateAssumeRolePolicy(input *iam.UpdateAssumeRolePolicyInput) (*iam.UpdateAssumeRolePolicyOutput, error) {
+	m.roles[aws.StringValue(input.RoleName)].AssumeRolePolicyDocument = input.PolicyDocument
+	return &iam.UpdateAssumeRolePolicyOutput{}, nil
+}
+
+func (m *mockIAMClient) PutRolePermissionsBoundary(input *iam.PutRolePermissionsBoundaryInput) (*iam.PutRolePermissionsBoundaryOutput, error) {
+	m.roles[aws.StringValue(input.RoleName)].PermissionsBoundary = &iam.AttachedPermissionsBoundary{PermissionsBoundaryArn: input.PermissionsBoundary}
+	return &iam.PutRolePermissionsBoundaryOutput{}, nil
+}
+
+func (m *mockIAMClient) ListPolicies(*iam.ListPoliciesInput) (*iam.ListPoliciesOutput, error) {
+	return &iam.ListPoliciesOutput{Policies: m.managedPolicies}, nil
+}
+
+func (m *mockIAMClient) CreatePolicy(input *iam.CreatePolicyInput) (*iam.CreatePolicyOutput, error) {
+	policy := &iam.Policy{
+		PolicyName: input.PolicyName,
+		Path:       input.Path,
+		Arn:        aws.String("arn:aws:iam::test:policy" + aws.StringValue(input.Path) + aws.StringValue(input.PolicyName)),
+	}
+	m.managedPolicies = append(m.managedPolicies, policy)
+	return &iam.CreatePolicyOutput{Policy: policy}, nil
+}
+
+func (m *mockIAMClient) PutRolePolicy(input *iam.PutRolePolicyInput) (*iam.PutRolePolicyOutput, error) {
+	m.policies[aws.StringValue(input.RoleName)] = aws.StringValue(input.PolicyDocument)
+	return &iam.PutRolePolicyOutput{}, nil
+}
+
+func (m *mockIAMClient) DeleteRolePolicy(input *iam.DeleteRolePolicyInput) (*iam.DeleteRolePolicyOutput, error) {
+	if _, ok := m.policies[aws.StringValue(input.RoleName)]; !ok {
+		return nil, awserr.New(iam.ErrCodeNoSuchEntityException, "", nil)
+	}
+	delete(m.policies, aws.StringValue(input.RoleName))
+	return &iam.DeleteRolePolicyOutput{}, nil
+}
+
+func (m *mockIAMClient) DeleteRole(input *iam.DeleteRoleInput) (*iam.DeleteRoleOutput, error) {
+	if _, ok := m.roles[aws.StringValue(input.RoleName)]; !ok {
+		return nil, awserr.New(iam.ErrCodeNoSuchEntityException, "", nil)
+	}
+	delete(m.roles, aws.StringValue(input.RoleName))
+	return &iam.DeleteRoleOutput{}, nil
+}
+
+func (m *mockIAMClient) CreateAccessKey(input *iam.CreateAccessKeyInput) (*iam.CreateAccessKeyOutput, error) {
+	keyID := fmt.Sprintf("AKIATEST%d", len(m.accessKeys))
+	m.accessKeys[keyID] = true
+	return &iam.CreateAccessKeyOutput{AccessKey: &iam.AccessKey{
+		UserName:        input.UserName,
+		AccessKeyId:     aws.String(keyID),
+		SecretAccessKey: aws.String(keyID + "-secret"),
+	}}, nil
+}
+
+func (m *mockIAMClient) DeleteAccessKey(input *iam.DeleteAccessKeyInput) (*iam.DeleteAccessKeyOutput, error) {
+	if !m.accessKeys[aws.StringValue(input.AccessKeyId)] {
+		return nil, awserr.New(iam.ErrCodeNoSuchEntityException, "", nil)
+	}
+	delete(m.accessKeys, aws.StringValue(input.AccessKeyId))
+	return &iam.DeleteAccessKeyOutput{}, nil
+}
+
+func buildMockIAMClient() *mockIAMClient {
+	return &mockIAMClient{
+		roles:      map[string]*iam.Role{},
+		policies:   map[string]string{},
+		accessKeys: map[string]bool{},
+	}
+}
+
+func Test_buildS3LifecycleRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []*BucketLifecycleRule
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "test rules with actions are built",
+			rules: []*BucketLifecycleRule{
+				{ID: "archive", Prefix: "logs/", TransitionToIADays: 30, TransitionToGlacierDays: 90, ExpirationDays: 365},
+				{ID: "abort", AbortIncompleteUploadDays: 7},
+			},
+			want: 2,
+		},
+		{
+			name:  "test no rules are built without rules",
+			rules: nil,
+			want:  0,
+		},
+		{
+			name: "test error when a rule has no id",
+			rules: []*BucketLifecycleRule{
+				{ExpirationDays: 30},
+			},
+			wantErr: true,
+		},
+		{
+			name: "test error when rule ids are not unique",
+			rules: []*BucketLifecycleRule{
+				{ID: "expire", ExpirationDays: 30},
+				{ID: "expire", ExpirationDays: 60},
+			},
+			wantErr: true,
+		},
+		{
+			name: "test error when a rule has no actions",
+			rules: []*BucketLifecycleRule{
+				{ID: "empty", Prefix: "logs/"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildS3LifecycleRules(tt.rules)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildS3LifecycleRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Errorf("buildS3LifecycleRules() built %d rules, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func Test_buildS3VersioningStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		observed string
+		want     string
+	}{
+		{name: "test versioning is enabled", enabled: true, observed: "", want: s3.BucketVersioningStatusEnabled},
+		{name: "test enabled versioning is suspended", enabled: false, observed: s3.BucketVersioningStatusEnabled, want: s3.BucketVersioningStatusSuspended},
+		{name: "test unversioned bucket is not versioned", enabled: false, observed: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildS3VersioningStatus(tt.enabled, tt.observed); got != tt.want {
+				t.Errorf("buildS3VersioningStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlobStorageProvider_reconcileS3BucketStrategy(t *testing.T) {
+	scheme, err := buildTestScheme()
+	if err != nil {
+		t.Fatal("failed to build test scheme", err)
+	}
+	tests := []struct {
+		name           string
+		strategy       *BucketStrategy
+		kmsKeyArn      string
+		s3svc          *mockS3Svc
+		replicaSvc     *mockS3Svc
+		iamSvc         *mockIAMClient
+		wantVersioning string
+		wantReplica    bool
+		wantErr        bool
+	}{
+		{
+			name: "test versioning and lifecycle rules are set",
+			strategy: &BucketStrategy{
+				Versioning: true,
+				LifecycleRules: []*BucketLifecycleRule{
+					{ID: "expire", ExpirationDays: 30},
+				},
+			},
+			s3svc:          &mockS3Svc{},
+			iamSvc:         buildMockIAMClient(),
+			wantVersioning: s3.BucketVersioningStatusEnabled,
+		},
+		{
+			name:     "test lifecycle rules are removed and versioning is suspended",
+			strategy: &BucketStrategy{},
+			s3svc: &mockS3Svc{
+				versioning:     aws.String(s3.BucketVersioningStatusEnabled),
+				lifecycleRules: []*s3.LifecycleRule{{ID: aws.String("expire")}},
+			},
+			iamSvc:         buildMockIAMClient(),
+			wantVersioning: s3.BucketVersioningStatusSuspended,
+		},
+		{
+			name: "test objects are replicated to a replica bucket",
+			strategy: &BucketStrategy{
+				Replication: &BucketReplicationStrategy{Region: "eu-west-1"},
+			},
+			s3svc:          &mockS3Svc{},
+			replicaSvc:     &mockS3Svc{bucketNames: []string{buildReplicaBucketName("test")}},
+			iamSvc:         buildMockIAMClient(),
+			wantVersioning: s3.BucketVersioningStatusEnabled,
+			wantReplica:    true,
+		},
+		{
+			name: "test error when objects are replicated to the region of the bucket",
+			strategy: &BucketStrategy{
+				Replication: &BucketReplicationStrategy{Region: "us-east-1"},
+			},
+			s3svc:   &mockS3Svc{},
+			iamSvc:  buildMockIAMClient(),
+			wantErr: true,
+		},
+		{
+			name: "test error when replicating a bucket encrypted with a customer managed key",
+			strategy: &BucketStrategy{
+				Replication: &BucketReplicationStrategy{Region: "eu-west-1"},
+			},
+			kmsKeyArn: "arn:aws:kms:us-east-1:test:key/test",
+			s3svc:     &mockS3Svc{},
+			iamSvc:    buildMockIAMClient(),
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &BlobStorageProvider{
+				Client: fake.NewFakeClientWithScheme(scheme, buildTestBlobStorageCR(), buildTestInfrastructure()),
+				Logger: testLogger,
+			}
+			err := p.reconcileS3BucketStrategy(context.TODO(), buildTestBlobStorageCR(), "test", "us-east-1", tt.kmsKeyArn, tt.strategy, tt.s3svc, tt.replicaSvc, tt.iamSvc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("reconcileS3BucketStrategy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if aws.StringValue(tt.s3svc.versioning) != tt.wantVersioning {
+				t.Errorf("reconcileS3BucketStrategy() versioning = %v, want %v", aws.StringValue(tt.s3svc.versioning), tt.wantVersioning)
+			}
+			if len(tt.s3svc.lifecycleRules) != len(tt.strategy.LifecycleRules) {
+				t.Errorf("reconcileS3BucketStrategy() set %d lifecycle rules, want %d", len(tt.s3svc.lifecycleRules), len(tt.strategy.LifecycleRules))
+			}
+			if (tt.s3svc.replication != nil) != tt.wantReplica {
+				t.Fatalf("reconcileS3BucketStrategy() replication = %v, want replication %v", tt.s3svc.replication, tt.wantReplica)
+			}
+			if !tt.wantReplica {
+				return
+			}
+			if aws.StringValue(tt.replicaSvc.versioning) != s3.BucketVersioningStatusEnabled {
+				t.Errorf("reconcileS3BucketStrategy() replica bucket versioning = %v, want %v", aws.StringValue(tt.replicaSvc.versioning), s3.BucketVersioningStatusEnabled)
+			}
+			role, ok := tt.iamSvc.roles[buildReplicationRoleName("test")]
+			if !ok {
+				t.Fatal("reconcileS3BucketStrategy() did not create the replication role")
+			}
+			if aws.StringValue(tt.s3svc.replication.Role) != aws.StringValue(role.Arn) {
+				t.Errorf("reconcileS3BucketStrategy() replication role = %v, want %v", aws.StringValue(tt.s3svc.replication.Role), aws.StringValue(role.Arn))
+			}
+		})
+	}
+}
+
+func Test_deleteS3BucketReplica(t *testing.T) {
+	roleName := buildReplicationRoleName("test")
+	replica := buildReplicaBucketName("test")
+	tests := []struct {
+		name           string
+		s3svc          *mockS3Svc
+		wantRegion     string
+		wantReplicaSvc bool
+	}{
+		{
+			name:           "test replica bucket is deleted in its own region",
+			s3svc:          &mockS3Svc{bucketNames: []string{replica}, location: aws.String("eu-west-1")},
+			wantRegion:     "eu-west-1",
+			wantReplicaSvc: true,
+		},
+		{
+			name:           "test replica bucket without location is deleted in us-east-1",
+			s3svc:          &mockS3Svc{bucketNames: []string{replica}},
+			wantRegion:     regionUSEast1,
+			wantReplicaSvc: true,
+		},
+		{
+			name:  "test replication role is deleted without a replica bucket",
+			s3svc: &mockS3Svc{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iamSvc := &mockIAMClient{
+				roles:    map[string]*iam.Role{roleName: {RoleName: aws.String(roleName)}},
+				policies: map[string]string{roleName: "{}"},
+			}
+			var gotRegion string
+			newReplicaSvc := func(region string) s3iface.S3API {
+				gotRegion = region
+				return tt.s3svc
+			}
+			deleted, err := deleteS3BucketReplica(buildTestBlobStorageCR(), "test", tt.s3svc, newReplicaSvc, iamSvc)
+			if err != nil {
+				t.Fatal("deleteS3BucketReplica() returned an error", err)
+			}
+			if !deleted {
+				t.Error("deleteS3BucketReplica() = false, want true")
+			}
+			if (gotRegion != "") != tt.wantReplicaSvc || gotRegion != tt.wantRegion {
+				t.Errorf("deleteS3BucketReplica() deleted replica bucket in region %q, want %q", gotRegion, tt.wantRegion)
+			}
+			if _, ok := iamSvc.roles[roleName]; ok {
+				t.Error("deleteS3BucketReplica() did not delete the replication role")
+			}
+		})
+	}
+}
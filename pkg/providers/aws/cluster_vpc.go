@@ -174,7 +174,9 @@ func GetPrivateSubnetIDS(ctx context.Context, c client.Client, ec2Svc ec2iface.E
 			if err != nil {
 				return nil, errorUtil.Wrap(err, "failed to created private subnet")
 			}
-			privSubs = append(privSubs, subnet)
+			if subnet != nil {
+				privSubs = append(privSubs, subnet)
+			}
 		}
 	}
 
@@ -184,7 +186,7 @@ func GetPrivateSubnetIDS(ctx context.Context, c client.Client, ec2Svc ec2iface.E
 		subIDs = append(subIDs, sub.SubnetId)
 	}
 
-	if subIDs == nil {
+	if subIDs == nil && !resources.IsDryRun() {
 		return nil, errorUtil.New("failed to get list of private subnet ids")
 	}
 
@@ -226,6 +228,10 @@ func createPrivateSubnet(ctx context.Context, c client.Client, ec2Svc ec2iface.E
 		if err != nil {
 			return nil, errorUtil.Wrap(err, "error creating new subnet")
 		}
+		// no subnet is created in dry run mode
+		if createOutput.Subnet == nil {
+			return nil, nil
+		}
 		if newErr := tagPrivateSubnet(ctx, c, ec2Svc, createOutput.Subnet); newErr != nil {
 			return nil, newErr
 		}
@@ -19,6 +19,7 @@ import (
 	"github.com/integr8ly/cloud-resource-operator/pkg/providers"
 	errorUtil "github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -41,7 +42,7 @@ const (
 	sesSMTPEndpointEUWest1 = "email-smtp.eu-west-1.amazonaws.com"
 )
 
-//DefaultConfigMapNamespace is the default namespace that Configmaps will be created in
+// DefaultConfigMapNamespace is the default namespace that Configmaps will be created in
 var DefaultConfigMapNamespace, _ = k8sutil.GetWatchNamespace()
 
 //go:generate moq -out config_moq.go . ConfigManager
@@ -63,6 +64,29 @@ type StrategyConfig struct {
 	Region         string          `json:"region"`
 	CreateStrategy json.RawMessage `json:"createStrategy"`
 	DeleteStrategy json.RawMessage `json:"deleteStrategy"`
+	KMSKey         *KMSKeyStrategy `json:"kmsKey,omitempty"`
+	// MaintenanceWindow is the weekly window pending maintenance is applied in, in the format ddd:hh24:mi-ddd:hh24:mi
+	MaintenanceWindow string `json:"maintenanceWindow,omitempty"`
+	// BackupWindow is the daily window automated backups are taken in, in the format hh24:mi-hh24:mi
+	BackupWindow string `json:"backupWindow,omitempty"`
+	// RequireApproval plans disruptive changes, which are only applied once the plan is approved, instead of applying
+	// them in the maintenance window
+	RequireApproval bool `json:"requireApproval,omitempty"`
+	// ServiceUpdates is the policy service updates of elasticache replication groups are applied with
+	ServiceUpdates *ServiceUpdatePolicy `json:"serviceUpdates,omitempty"`
+	// Bucket configures the versioning, lifecycle rules and replication of s3 buckets
+	Bucket *BucketStrategy `json:"bucket,omitempty"`
+	// Credentials configures whether the credentials of resources are static or issued for an iam role per resource
+	Credentials *CredentialStrategy `json:"credentials,omitempty"`
+}
+
+// ServiceUpdatePolicy configures which service updates are applied and when. updates of at least Severity are applied
+// inside Window, or the maintenance window of the resource when not set, if Apply is auto. updates are applied on
+// approval through an annotation otherwise
+type ServiceUpdatePolicy struct {
+	Severity string `json:"severity,omitempty"`
+	Window   string `json:"window,omitempty"`
+	Apply    string `json:"apply,omitempty"`
 }
 
 func NewConfigMapConfigManager(cm string, namespace string, client client.Client) *ConfigMapConfigManager {
@@ -159,6 +183,27 @@ func BuildInfraNameFromObject(ctx context.Context, c client.Client, om controlle
 	return resources.ShortenString(fmt.Sprintf("%s-%s-%s", clusterID, om.Namespace, om.Name), n), nil
 }
 
+// BuildResourceIdentifierFromObject returns the identifier of the cloud resource of an object, which is the identifier of
+// the existing resource it adopts if any
+func BuildResourceIdentifierFromObject(ctx context.Context, c client.Client, om controllerruntime.ObjectMeta, adoptIdentifier string, n int) (string, error) {
+	if id := resources.GetAdoptIdentifier(&om, adoptIdentifier); id != "" {
+		return id, nil
+	}
+	return BuildInfraNameFromObject(ctx, c, om, n)
+}
+
+// validateAdoption returns an error if an object adopts an existing cloud resource while it already manages another
+// cloud resource, as recorded by its resource identifier annotation. only an object which does not manage a cloud
+// resource yet can adopt one
+func validateAdoption(obj metav1.Object, adoptIdentifier string) error {
+	adoptID := resources.GetAdoptIdentifier(obj, adoptIdentifier)
+	identifier := obj.GetAnnotations()[resourceIdentifierAnnotation]
+	if adoptID == "" || identifier == "" || adoptID == identifier {
+		return nil
+	}
+	return fmt.Errorf("%s adopts %s, but it already manages %s", obj.GetName(), adoptID, identifier)
+}
+
 func buildTimestampedInfraNameFromObject(ctx context.Context, c client.Client, om controllerruntime.ObjectMeta, n int) (string, error) {
 	clusterID, err := resources.GetClusterID(ctx, c)
 	if err != nil {
@@ -188,7 +233,7 @@ func CreateSessionFromStrategy(ctx context.Context, c client.Client, keyID, secr
 	if err != nil {
 		return nil, errorUtil.Wrapf(err, "failed to create aws session from strategy, region=%s keyID=%s", region, keyID)
 	}
-	return sess, nil
+	return ConfigureDryRun(ctx, sess), nil
 }
 
 func GetRegionFromStrategyOrDefault(ctx context.Context, c client.Client, strategy *StrategyConfig) (string, error) {
@@ -213,3 +258,34 @@ func getDefaultRegion(ctx context.Context, c client.Client) (string, error) {
 	}
 	return region, nil
 }
+
+// getMaintenanceWindow returns the maintenance window declared on a cr, or by the strategy of its tier otherwise
+func getMaintenanceWindow(crWindow string, strategy *StrategyConfig) string {
+	if crWindow != "" {
+		return crWindow
+	}
+	return strategy.MaintenanceWindow
+}
+
+// getBackupWindow returns the backup window declared on a cr, or by the strategy of its tier otherwise
+func getBackupWindow(crWindow string, strategy *StrategyConfig) string {
+	if crWindow != "" {
+		return crWindow
+	}
+	return strategy.BackupWindow
+}
+
+// isDisruptiveChangeAllowed returns true if disruptive changes described by plan can be applied now. when the strategy
+// requires approval this is only the case once the returned hash of the plan is approved through an annotation,
+// otherwise disruptive changes are allowed inside the maintenance window
+func isDisruptiveChangeAllowed(obj metav1.Object, strategy *StrategyConfig, window string, plan interface{}) (bool, string, error) {
+	if !strategy.RequireApproval {
+		allowed, err := resources.IsMaintenanceAllowed(obj, window, time.Now())
+		return allowed, "", err
+	}
+	hash, err := resources.BuildPlanHash(plan)
+	if err != nil {
+		return false, "", errorUtil.Wrap(err, "failed to build plan hash")
+	}
+	return resources.IsPlanApproved(obj, hash), hash, nil
+}
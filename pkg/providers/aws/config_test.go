@@ -8,9 +8,14 @@ import (
 	"testing"
 
 	"github.com/integr8ly/cloud-resource-operator/pkg/providers"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
 
 	configv1 "github.com/integr8ly/cloud-resource-operator/pkg/apis/config/v1"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -211,3 +216,115 @@ func TestGetRegionFromStrategyOrDefault(t *testing.T) {
 		})
 	}
 }
+
+func Test_isDisruptiveChangeAllowed(t *testing.T) {
+	plan := &rds.ModifyDBInstanceInput{
+		DBInstanceIdentifier: aws.String("test"),
+		DBInstanceClass:      aws.String("db.t3.small"),
+	}
+	planHash, err := resources.BuildPlanHash(plan)
+	if err != nil {
+		t.Fatal("failed to build plan hash", err)
+	}
+	tests := []struct {
+		name        string
+		obj         metav1.Object
+		strategy    *StrategyConfig
+		window      string
+		wantAllowed bool
+		wantHash    string
+	}{
+		{
+			name:        "test disruptive change is allowed inside the maintenance window",
+			obj:         &metav1.ObjectMeta{},
+			strategy:    &StrategyConfig{},
+			window:      "sun:00:00-sat:23:59",
+			wantAllowed: true,
+		},
+		{
+			name:     "test disruptive change is planned when approval is required",
+			obj:      &metav1.ObjectMeta{},
+			strategy: &StrategyConfig{RequireApproval: true},
+			window:   "sun:00:00-sat:23:59",
+			wantHash: planHash,
+		},
+		{
+			name:     "test disruptive change is not allowed when another plan is approved",
+			obj:      &metav1.ObjectMeta{Annotations: map[string]string{resources.ApprovePlanAnnotation: "other"}},
+			strategy: &StrategyConfig{RequireApproval: true},
+			wantHash: planHash,
+		},
+		{
+			name:        "test disruptive change is allowed when its plan is approved",
+			obj:         &metav1.ObjectMeta{Annotations: map[string]string{resources.ApprovePlanAnnotation: planHash}},
+			strategy:    &StrategyConfig{RequireApproval: true},
+			wantAllowed: true,
+			wantHash:    planHash,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotAllowed, gotHash, err := isDisruptiveChangeAllowed(tt.obj, tt.strategy, tt.window, plan)
+			if err != nil {
+				t.Fatalf("isDisruptiveChangeAllowed() error = %v", err)
+			}
+			if gotAllowed != tt.wantAllowed {
+				t.Errorf("isDisruptiveChangeAllowed() allowed = %v, want %v", gotAllowed, tt.wantAllowed)
+			}
+			if gotHash != tt.wantHash {
+				t.Errorf("isDisruptiveChangeAllowed() hash = %v, want %v", gotHash, tt.wantHash)
+			}
+		})
+	}
+}
+
+func Test_validateAdoption(t *testing.T) {
+	tests := []struct {
+		name            string
+		annotations     map[string]string
+		adoptIdentifier string
+		wantErr         bool
+	}{
+		{
+			name: "test object which does not adopt is valid",
+			annotations: map[string]string{
+				resourceIdentifierAnnotation: "test-id",
+			},
+		},
+		{
+			name:            "test object which does not manage a cloud resource yet can adopt",
+			adoptIdentifier: "adopted-id",
+		},
+		{
+			name: "test object which manages the adopted cloud resource is valid",
+			annotations: map[string]string{
+				resourceIdentifierAnnotation: "adopted-id",
+			},
+			adoptIdentifier: "adopted-id",
+		},
+		{
+			name: "test error when adopting a cloud resource while managing another",
+			annotations: map[string]string{
+				resourceIdentifierAnnotation: "test-id",
+			},
+			adoptIdentifier: "adopted-id",
+			wantErr:         true,
+		},
+		{
+			name: "test error when adopting a cloud resource by annotation while managing another",
+			annotations: map[string]string{
+				resourceIdentifierAnnotation: "test-id",
+				resources.AdoptAnnotation:    "adopted-id",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &metav1.ObjectMeta{Name: "test", Annotations: tt.annotations}
+			if err := validateAdoption(obj, tt.adoptIdentifier); (err != nil) != tt.wantErr {
+				t.Errorf("validateAdoption() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
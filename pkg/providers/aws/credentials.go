@@ -3,6 +3,7 @@ package aws
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -13,6 +14,8 @@ import (
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
 	v1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
 	errorUtil "github.com/pkg/errors"
 	v12 "k8s.io/api/core/v1"
@@ -23,6 +26,10 @@ import (
 const (
 	defaultProviderCredentialName = "cloud-resources-aws-credentials"
 
+	// the names of the iam users minted by the cloud credential operator start with the infrastructure name of the
+	// cluster, truncated to this length
+	ccoUserNamePrefixLength = 37
+
 	defaultCredentialsKeyIDName = "aws_access_key_id"
 	// #nosec G101
 	defaultCredentialsSecretKeyName = "aws_secret_access_key"
@@ -39,6 +46,8 @@ var (
 				"s3:ListAllMyBuckets",
 				"s3:GetObject",
 				"s3:DeleteObject",
+				"s3:PutObject",
+				"s3:AbortMultipartUpload",
 				"s3:PutBucketTagging",
 				"ec2:DescribeVpcs",
 				"ec2:DescribeSubnets",
@@ -51,29 +60,70 @@ var (
 				"ec2:CreateTags",
 				"s3:PutBucketPublicAccessBlock",
 				"s3:PutEncryptionConfiguration",
+				"s3:GetBucketPublicAccessBlock",
+				"s3:GetEncryptionConfiguration",
+				"s3:GetBucketTagging",
+				"s3:GetBucketLocation",
+				"s3:GetBucketVersioning",
+				"s3:PutBucketVersioning",
+				"s3:ListBucketVersions",
+				"s3:DeleteObjectVersion",
+				"s3:GetLifecycleConfiguration",
+				"s3:PutLifecycleConfiguration",
+				"s3:GetReplicationConfiguration",
+				"s3:PutReplicationConfiguration",
+				"s3:GetBucketCORS",
+				"s3:PutBucketCORS",
+				"s3:GetBucketPolicy",
+				"s3:PutBucketPolicy",
+				"s3:DeleteBucketPolicy",
 				"elasticache:CreateReplicationGroup",
 				"elasticache:DeleteReplicationGroup",
 				"elasticache:DescribeReplicationGroups",
 				"elasticache:DescribeServiceUpdates",
+				"elasticache:DescribeUpdateActions",
+				"elasticache:BatchApplyUpdateAction",
 				"elasticache:AddTagsToResource",
+				"elasticache:ListTagsForResource",
 				"elasticache:DescribeSnapshots",
 				"elasticache:CreateSnapshot",
+				"elasticache:CopySnapshot",
+				"elasticache:DeleteSnapshot",
 				"elasticache:DescribeCacheClusters",
 				"elasticache:DescribeCacheSubnetGroups",
 				"elasticache:CreateCacheSubnetGroup",
 				"elasticache:ModifyReplicationGroup",
+				"elasticache:ModifyReplicationGroupShardConfiguration",
+				"elasticache:IncreaseReplicaCount",
+				"elasticache:DecreaseReplicaCount",
+				"elasticache:TestFailover",
+				"elasticache:DescribeCacheEngineVersions",
 				"rds:DescribeDBInstances",
 				"rds:CreateDBInstance",
 				"rds:DeleteDBInstance",
 				"rds:ModifyDBInstance",
 				"rds:AddTagsToResource",
+				"rds:ListTagsForResource",
 				"rds:DescribeDBSnapshots",
 				"rds:CreateDBSnapshot",
+				"rds:CopyDBSnapshot",
+				"rds:DeleteDBSnapshot",
+				"rds:RestoreDBInstanceToPointInTime",
 				"rds:DescribePendingMaintenanceActions",
+				"rds:ApplyPendingMaintenanceAction",
 				"rds:CreateDBSubnetGroup",
 				"rds:DescribeDBSubnetGroups",
+				"kms:CreateGrant",
+				"kms:DescribeKey",
+				"kms:CreateKey",
+				"kms:CreateAlias",
+				"kms:EnableKeyRotation",
+				"kms:ScheduleKeyDeletion",
+				"kms:TagResource",
 				"sts:GetCallerIdentity",
 				"iam:CreateServiceLinkedRole",
+				"iam:GetRole",
+				"iam:ListPolicies",
 			},
 			Resource: "*",
 		},
@@ -89,8 +139,51 @@ var (
 	}
 )
 
-func buildPutBucketObjectEntries(bucket string) []v1.StatementEntry {
-	return []v1.StatementEntry{
+// buildOperatorEntries returns the entries of the provider credentials. access keys can only be rotated for the iam
+// users minted by the cloud credential operator for the cluster, which are named after its infrastructure name
+// truncated to 37 characters. iam roles and their permissions boundary can only be managed, passed and assumed under
+// the iam path of the cluster
+func buildOperatorEntries(clusterID string) []v1.StatementEntry {
+	userPrefix := clusterID
+	if len(userPrefix) > ccoUserNamePrefixLength {
+		userPrefix = userPrefix[:ccoUserNamePrefixLength]
+	}
+	return append(append([]v1.StatementEntry{}, operatorEntries...),
+		v1.StatementEntry{
+			Effect: "Allow",
+			Action: []string{
+				"iam:CreateAccessKey",
+				"iam:DeleteAccessKey",
+			},
+			Resource: fmt.Sprintf("arn:aws:iam::*:user/%s-*", userPrefix),
+		},
+		v1.StatementEntry{
+			Effect: "Allow",
+			Action: []string{
+				"iam:CreateRole",
+				"iam:TagRole",
+				"iam:UpdateAssumeRolePolicy",
+				"iam:PutRolePolicy",
+				"iam:PutRolePermissionsBoundary",
+				"iam:DeleteRolePolicy",
+				"iam:DeleteRole",
+				"iam:PassRole",
+				"sts:AssumeRole",
+			},
+			Resource: fmt.Sprintf("arn:aws:iam::*:role%s*", buildIAMPath(clusterID)),
+		},
+		v1.StatementEntry{
+			Effect: "Allow",
+			Action: []string{
+				"iam:CreatePolicy",
+			},
+			Resource: fmt.Sprintf("arn:aws:iam::*:policy%s*", buildIAMPath(clusterID)),
+		},
+	)
+}
+
+func buildPutBucketObjectEntries(bucket, kmsKeyArn string) []v1.StatementEntry {
+	entries := []v1.StatementEntry{
 		{
 			Effect: "Allow",
 			Action: []string{
@@ -106,6 +199,75 @@ func buildPutBucketObjectEntries(bucket string) []v1.StatementEntry {
 			Resource: fmt.Sprintf("arn:aws:s3:::%s/*", bucket),
 		},
 	}
+	// objects in buckets encrypted with a customer managed key can only be read and written with access to the key
+	if kmsKeyArn != "" {
+		entries = append(entries, v1.StatementEntry{
+			Effect: "Allow",
+			Action: []string{
+				"kms:Decrypt",
+				"kms:GenerateDataKey",
+			},
+			Resource: kmsKeyArn,
+		})
+	}
+	return entries
+}
+
+// buildBucketAccessEntries returns the entries of a named credential of a bucket with an access level. a credential
+// restricted to a prefix can not list the bucket, as statement entries do not support conditions
+func buildBucketAccessEntries(bucket, kmsKeyArn, access, prefix string) ([]v1.StatementEntry, error) {
+	var bucketActions, objectActions, kmsActions []string
+	switch access {
+	case croType.CredentialAccessReadOnly:
+		bucketActions = []string{"s3:GetBucketLocation", "s3:ListBucket"}
+		objectActions = []string{"s3:GetObject"}
+		kmsActions = []string{"kms:Decrypt"}
+	case croType.CredentialAccessWriteOnly:
+		bucketActions = []string{"s3:GetBucketLocation"}
+		objectActions = []string{"s3:PutObject", "s3:AbortMultipartUpload", "s3:ListMultipartUploadParts"}
+		kmsActions = []string{"kms:GenerateDataKey"}
+	case croType.CredentialAccessReadWrite:
+		bucketActions = []string{"s3:GetBucketLocation", "s3:ListBucket", "s3:ListBucketMultipartUploads"}
+		objectActions = []string{"s3:GetObject", "s3:PutObject", "s3:DeleteObject", "s3:AbortMultipartUpload", "s3:ListMultipartUploadParts"}
+		kmsActions = []string{"kms:Decrypt", "kms:GenerateDataKey"}
+	default:
+		return nil, fmt.Errorf("unsupported access level %q, expected %s, %s or %s", access, croType.CredentialAccessReadOnly, croType.CredentialAccessWriteOnly, croType.CredentialAccessReadWrite)
+	}
+	if prefix != "" {
+		bucketActions = []string{"s3:GetBucketLocation"}
+	}
+	entries := []v1.StatementEntry{
+		{
+			Effect:   "Allow",
+			Action:   bucketActions,
+			Resource: fmt.Sprintf("arn:aws:s3:::%s", bucket),
+		},
+		{
+			Effect:   "Allow",
+			Action:   objectActions,
+			Resource: fmt.Sprintf("arn:aws:s3:::%s/%s*", bucket, prefix),
+		},
+	}
+	if kmsKeyArn != "" {
+		entries = append(entries, v1.StatementEntry{
+			Effect:   "Allow",
+			Action:   kmsActions,
+			Resource: kmsKeyArn,
+		})
+	}
+	return entries, nil
+}
+
+func buildRDSConnectEntries(dbUserArn string) []v1.StatementEntry {
+	return []v1.StatementEntry{
+		{
+			Effect: "Allow",
+			Action: []string{
+				"rds-db:connect",
+			},
+			Resource: dbUserArn,
+		},
+	}
 }
 
 type Credentials struct {
@@ -119,7 +281,7 @@ type Credentials struct {
 type CredentialManager interface {
 	ReconcileProviderCredentials(ctx context.Context, ns string) (*Credentials, error)
 	ReconcileSESCredentials(ctx context.Context, name, ns string) (*Credentials, error)
-	ReoncileBucketOwnerCredentials(ctx context.Context, name, ns, bucket string) (*Credentials, *v1.CredentialsRequest, error)
+	ReoncileBucketOwnerCredentials(ctx context.Context, name, ns, bucket, kmsKeyArn string) (*Credentials, *v1.CredentialsRequest, error)
 	ReconcileCredentials(ctx context.Context, name string, ns string, entries []v1.StatementEntry) (*v1.CredentialsRequest, *Credentials, error)
 }
 
@@ -140,10 +302,18 @@ func NewCredentialMinterCredentialManager(client client.Client) *CredentialMinte
 
 //ReconcileProviderCredentials Ensure the credentials the AWS provider requires are available
 func (m *CredentialMinterCredentialManager) ReconcileProviderCredentials(ctx context.Context, ns string) (*Credentials, error) {
-	_, creds, err := m.ReconcileCredentials(ctx, m.ProviderCredentialName, ns, operatorEntries)
+	clusterID, err := resources.GetClusterID(ctx, m.Client)
+	if err != nil {
+		return nil, errorUtil.Wrap(err, "failed to get cluster id")
+	}
+	_, creds, err := m.ReconcileCredentials(ctx, m.ProviderCredentialName, ns, buildOperatorEntries(clusterID))
 	if err != nil {
 		return nil, err
 	}
+	// the provider can not read the cloud resources to plan its changes without credentials
+	if creds.AccessKeyID == "" {
+		return nil, errorUtil.Errorf("provider credentials %s are not minted in dry run mode", m.ProviderCredentialName)
+	}
 	return creds, nil
 }
 
@@ -155,8 +325,8 @@ func (m *CredentialMinterCredentialManager) ReconcileSESCredentials(ctx context.
 	return creds, nil
 }
 
-func (m *CredentialMinterCredentialManager) ReoncileBucketOwnerCredentials(ctx context.Context, name, ns, bucket string) (*Credentials, *v1.CredentialsRequest, error) {
-	cr, creds, err := m.ReconcileCredentials(ctx, name, ns, buildPutBucketObjectEntries(bucket))
+func (m *CredentialMinterCredentialManager) ReoncileBucketOwnerCredentials(ctx context.Context, name, ns, bucket, kmsKeyArn string) (*Credentials, *v1.CredentialsRequest, error) {
+	cr, creds, err := m.ReconcileCredentials(ctx, name, ns, buildPutBucketObjectEntries(bucket, kmsKeyArn))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -164,6 +334,10 @@ func (m *CredentialMinterCredentialManager) ReoncileBucketOwnerCredentials(ctx c
 }
 
 func (m *CredentialMinterCredentialManager) ReconcileCredentials(ctx context.Context, name string, ns string, entries []v1.StatementEntry) (*v1.CredentialsRequest, *Credentials, error) {
+	// credentials requests mint iam users, they are not created or updated in dry run mode
+	if resources.IsDryRun() {
+		return m.getDryRunCredentials(ctx, name, ns, entries)
+	}
 	cr, err := m.reconcileCredentialRequest(ctx, name, ns, entries)
 	if err != nil {
 		return nil, nil, errorUtil.Wrapf(err, "failed to reconcile aws credential request %s", name)
@@ -180,20 +354,69 @@ func (m *CredentialMinterCredentialManager) ReconcileCredentials(ctx context.Con
 	if err != nil {
 		return nil, nil, errorUtil.Wrap(err, "timed out waiting for credential request to become provisioned")
 	}
+	creds, err := m.getProvisionedCredentials(ctx, cr)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cr, creds, nil
+}
+
+// getDryRunCredentials returns the credentials of an existing credentials request without creating or updating it, the
+// changes to the request are recorded as planned actions instead. the credentials of a request which does not exist or
+// is not provisioned yet are empty
+func (m *CredentialMinterCredentialManager) getDryRunCredentials(ctx context.Context, name string, ns string, entries []v1.StatementEntry) (*v1.CredentialsRequest, *Credentials, error) {
+	cr := &v1.CredentialsRequest{}
+	if err := m.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: ns}, cr); err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, nil, errorUtil.Wrapf(err, "failed to get credential request %s", name)
+		}
+		resources.RecordDryRunAction(ctx, "cloudcredential:CreateCredentialsRequest")
+		return &v1.CredentialsRequest{
+			ObjectMeta: controllerruntime.ObjectMeta{
+				Name:      name,
+				Namespace: ns,
+			},
+		}, &Credentials{}, nil
+	}
 
 	codec, err := v1.NewCodec()
 	if err != nil {
-		return nil, nil, errorUtil.Wrap(err, "failed to create credentials codec")
+		return nil, nil, errorUtil.Wrap(err, "failed to create provider codec")
+	}
+	providerSpec := &v1.AWSProviderSpec{}
+	if cr.Spec.ProviderSpec != nil {
+		if err := codec.DecodeProviderSpec(cr.Spec.ProviderSpec, providerSpec); err != nil {
+			return nil, nil, errorUtil.Wrapf(err, "failed to decode provider spec of credentials request %s", cr.Name)
+		}
+	}
+	if !reflect.DeepEqual(providerSpec.StatementEntries, entries) {
+		resources.RecordDryRunAction(ctx, "cloudcredential:UpdateCredentialsRequest")
+	}
+	if !cr.Status.Provisioned {
+		return cr, &Credentials{}, nil
+	}
+	creds, err := m.getProvisionedCredentials(ctx, cr)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cr, creds, nil
+}
+
+// getProvisionedCredentials returns the credentials minted for a provisioned credentials request
+func (m *CredentialMinterCredentialManager) getProvisionedCredentials(ctx context.Context, cr *v1.CredentialsRequest) (*Credentials, error) {
+	codec, err := v1.NewCodec()
+	if err != nil {
+		return nil, errorUtil.Wrap(err, "failed to create credentials codec")
 	}
 	awsProvStatus := &v1.AWSProviderStatus{}
 	if err = codec.DecodeProviderSpec(cr.Status.ProviderStatus, awsProvStatus); err != nil {
-		return nil, nil, errorUtil.Wrapf(err, "failed to decode credentials request %s", cr.Name)
+		return nil, errorUtil.Wrapf(err, "failed to decode credentials request %s", cr.Name)
 	}
 	accessKeyID, secAccessKey, err := m.reconcileAWSCredentials(ctx, cr)
 	if err != nil {
-		return nil, nil, errorUtil.Wrapf(err, "failed to reconcile aws credentials from credential request %s", cr.Name)
+		return nil, errorUtil.Wrapf(err, "failed to reconcile aws credentials from credential request %s", cr.Name)
 	}
-	return cr, &Credentials{
+	return &Credentials{
 		Username:        awsProvStatus.User,
 		PolicyName:      awsProvStatus.Policy,
 		AccessKeyID:     accessKeyID,
@@ -251,3 +474,22 @@ func (m *CredentialMinterCredentialManager) reconcileAWSCredentials(ctx context.
 	}
 	return awsAccessKeyID, awsSecretAccessKey, nil
 }
+
+// deleteCredentialsRequest deletes a credentials request created by a provider, which removes the iam user minted for
+// it. a request which does not exist is ignored, nothing is deleted in dry run mode
+func deleteCredentialsRequest(ctx context.Context, c client.Client, name, ns string) error {
+	if resources.IsDryRun() {
+		resources.RecordDryRunAction(ctx, "cloudcredential:DeleteCredentialsRequest")
+		return nil
+	}
+	credsReq := &v1.CredentialsRequest{
+		ObjectMeta: controllerruntime.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+		},
+	}
+	if err := c.Delete(ctx, credsReq); err != nil && !errors.IsNotFound(err) {
+		return errorUtil.Wrapf(err, "failed to delete credential request %s", name)
+	}
+	return nil
+}
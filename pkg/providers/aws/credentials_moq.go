@@ -35,7 +35,7 @@ var _ CredentialManager = &CredentialManagerMock{}
 //             ReconcileSESCredentialsFunc: func(ctx context.Context, name string, ns string) (*Credentials, error) {
 // 	               panic("mock out the ReconcileSESCredentials method")
 //             },
-//             ReoncileBucketOwnerCredentialsFunc: func(ctx context.Context, name string, ns string, bucket string) (*Credentials, *v1.CredentialsRequest, error) {
+//             ReoncileBucketOwnerCredentialsFunc: func(ctx context.Context, name string, ns string, bucket string, kmsKeyArn string) (*Credentials, *v1.CredentialsRequest, error) {
 // 	               panic("mock out the ReoncileBucketOwnerCredentials method")
 //             },
 //         }
@@ -55,7 +55,7 @@ type CredentialManagerMock struct {
 	ReconcileSESCredentialsFunc func(ctx context.Context, name string, ns string) (*Credentials, error)
 
 	// ReoncileBucketOwnerCredentialsFunc mocks the ReoncileBucketOwnerCredentials method.
-	ReoncileBucketOwnerCredentialsFunc func(ctx context.Context, name string, ns string, bucket string) (*Credentials, *v1.CredentialsRequest, error)
+	ReoncileBucketOwnerCredentialsFunc func(ctx context.Context, name string, ns string, bucket string, kmsKeyArn string) (*Credentials, *v1.CredentialsRequest, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
@@ -96,6 +96,8 @@ type CredentialManagerMock struct {
 			Ns string
 			// Bucket is the bucket argument value.
 			Bucket string
+			// KmsKeyArn is the kmsKeyArn argument value.
+			KmsKeyArn string
 		}
 	}
 }
@@ -218,41 +220,45 @@ func (mock *CredentialManagerMock) ReconcileSESCredentialsCalls() []struct {
 }
 
 // ReoncileBucketOwnerCredentials calls ReoncileBucketOwnerCredentialsFunc.
-func (mock *CredentialManagerMock) ReoncileBucketOwnerCredentials(ctx context.Context, name string, ns string, bucket string) (*Credentials, *v1.CredentialsRequest, error) {
+func (mock *CredentialManagerMock) ReoncileBucketOwnerCredentials(ctx context.Context, name string, ns string, bucket string, kmsKeyArn string) (*Credentials, *v1.CredentialsRequest, error) {
 	if mock.ReoncileBucketOwnerCredentialsFunc == nil {
 		panic("CredentialManagerMock.ReoncileBucketOwnerCredentialsFunc: method is nil but CredentialManager.ReoncileBucketOwnerCredentials was just called")
 	}
 	callInfo := struct {
-		Ctx    context.Context
-		Name   string
-		Ns     string
-		Bucket string
+		Ctx       context.Context
+		Name      string
+		Ns        string
+		Bucket    string
+		KmsKeyArn string
 	}{
-		Ctx:    ctx,
-		Name:   name,
-		Ns:     ns,
-		Bucket: bucket,
+		Ctx:       ctx,
+		Name:      name,
+		Ns:        ns,
+		Bucket:    bucket,
+		KmsKeyArn: kmsKeyArn,
 	}
 	lockCredentialManagerMockReoncileBucketOwnerCredentials.Lock()
 	mock.calls.ReoncileBucketOwnerCredentials = append(mock.calls.ReoncileBucketOwnerCredentials, callInfo)
 	lockCredentialManagerMockReoncileBucketOwnerCredentials.Unlock()
-	return mock.ReoncileBucketOwnerCredentialsFunc(ctx, name, ns, bucket)
+	return mock.ReoncileBucketOwnerCredentialsFunc(ctx, name, ns, bucket, kmsKeyArn)
 }
 
 // ReoncileBucketOwnerCredentialsCalls gets all the calls that were made to ReoncileBucketOwnerCredentials.
 // Check the length with:
 //     len(mockedCredentialManager.ReoncileBucketOwnerCredentialsCalls())
 func (mock *CredentialManagerMock) ReoncileBucketOwnerCredentialsCalls() []struct {
-	Ctx    context.Context
-	Name   string
-	Ns     string
-	Bucket string
+	Ctx       context.Context
+	Name      string
+	Ns        string
+	Bucket    string
+	KmsKeyArn string
 } {
 	var calls []struct {
-		Ctx    context.Context
-		Name   string
-		Ns     string
-		Bucket string
+		Ctx       context.Context
+		Name      string
+		Ns        string
+		Bucket    string
+		KmsKeyArn string
 	}
 	lockCredentialManagerMockReoncileBucketOwnerCredentials.RLock()
 	calls = mock.calls.ReoncileBucketOwnerCredentials
@@ -0,0 +1,233 @@
+package aws
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+	v1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
+	errorUtil "github.com/pkg/errors"
+)
+
+// credential modes of a tier, the credentials of resources are long-lived iam user access keys unless a role based mode
+// is used
+const (
+	CredentialModeStatic      = "static"
+	CredentialModeSTS         = "sts"
+	CredentialModeWebIdentity = "web-identity"
+
+	defaultRolePolicyName          = "cro-resource-access"
+	defaultSessionDurationSeconds  = 3600
+	minSessionDurationSeconds      = 900
+	defaultWebIdentityTokenFile    = "/var/run/secrets/eks.amazonaws.com/serviceaccount/token"
+	defaultWebIdentityAudience     = "sts.amazonaws.com"
+	oidcProviderArnResourcePrefix  = "oidc-provider/"
+	assumedRoleArnResourcePrefix   = "assumed-role/"
+	maxRoleSessionNameLength       = 64
+	serviceAccountSubjectFormat    = "system:serviceaccount:%s:%s"
+	credentialExpirationTimeFormat = time.RFC3339
+)
+
+// CredentialStrategy is how the credentials of resources of a tier are issued. in the sts mode the operator creates an
+// iam role per resource and periodically refreshes short-lived credentials of the role, in the web-identity mode pods
+// assume the role with the token of a service account
+type CredentialStrategy struct {
+	Mode string `json:"mode,omitempty"`
+	// SessionDurationSeconds is how long the credentials of the sts mode are valid, between 900 and 3600 seconds
+	SessionDurationSeconds int64 `json:"sessionDurationSeconds,omitempty"`
+	// OIDCProviderArn is the arn of the iam oidc provider of the cluster, ServiceAccount is the service account in the
+	// namespace of the resource which can assume the role in the web-identity mode
+	OIDCProviderArn string `json:"oidcProviderArn,omitempty"`
+	ServiceAccount  string `json:"serviceAccount,omitempty"`
+	// TokenFile is the path the token of the service account is mounted at in pods
+	TokenFile string `json:"tokenFile,omitempty"`
+	// RotationGracePeriod is how long the previous access key of static credentials stays valid after they are
+	// rotated, e.g. 1h. defaults to 24h
+	RotationGracePeriod string `json:"rotationGracePeriod,omitempty"`
+}
+
+// RoleCredentials are the credentials of an iam role of a resource. the access keys, session token and expiration are
+// only set in the sts mode, the token file only in the web-identity mode
+type RoleCredentials struct {
+	RoleArn              string
+	AccessKeyID          string
+	SecretAccessKey      string
+	SessionToken         string
+	Expiration           string
+	WebIdentityTokenFile string
+}
+
+// isRoleBased returns whether the credentials of resources are issued for an iam role per resource
+func (s *CredentialStrategy) isRoleBased() bool {
+	return s != nil && (s.Mode == CredentialModeSTS || s.Mode == CredentialModeWebIdentity)
+}
+
+// getSessionDuration returns how long the credentials of the sts mode are valid
+func (s *CredentialStrategy) getSessionDuration() time.Duration {
+	if s.SessionDurationSeconds == 0 {
+		return time.Second * defaultSessionDurationSeconds
+	}
+	return time.Second * time.Duration(s.SessionDurationSeconds)
+}
+
+// validateCredentialStrategy verifies the mode of a credential strategy is known and has the settings it requires
+func validateCredentialStrategy(s *CredentialStrategy) error {
+	if s == nil {
+		return nil
+	}
+	if err := validateRotationGracePeriod(s); err != nil {
+		return err
+	}
+	switch s.Mode {
+	case "", CredentialModeStatic:
+		return nil
+	case CredentialModeSTS:
+		if s.SessionDurationSeconds != 0 && (s.SessionDurationSeconds < minSessionDurationSeconds || s.SessionDurationSeconds > defaultSessionDurationSeconds) {
+			return fmt.Errorf("session duration %d must be between %d and %d seconds", s.SessionDurationSeconds, minSessionDurationSeconds, defaultSessionDurationSeconds)
+		}
+		return nil
+	case CredentialModeWebIdentity:
+		if !strings.Contains(s.OIDCProviderArn, oidcProviderArnResourcePrefix) || s.ServiceAccount == "" {
+			return fmt.Errorf("credential mode %s requires an oidc provider arn and a service account", s.Mode)
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported credential mode %q, expected %s, %s or %s", s.Mode, CredentialModeStatic, CredentialModeSTS, CredentialModeWebIdentity)
+}
+
+// buildRoleTrustPolicy returns the trust policy of the iam role of a resource. in the sts mode the role is assumed by
+// the operator, in the web-identity mode by pods with the token of the service account of the strategy
+func buildRoleTrustPolicy(s *CredentialStrategy, operatorArn, ns string) (string, error) {
+	if s.Mode == CredentialModeWebIdentity {
+		issuer := s.OIDCProviderArn[strings.Index(s.OIDCProviderArn, oidcProviderArnResourcePrefix)+len(oidcProviderArnResourcePrefix):]
+		return buildIAMPolicyDocument(iamPolicyStatement{
+			Effect:    "Allow",
+			Principal: map[string]string{"Federated": s.OIDCProviderArn},
+			Action:    []string{"sts:AssumeRoleWithWebIdentity"},
+			Condition: map[string]map[string]string{
+				"StringEquals": {
+					issuer + ":sub": fmt.Sprintf(serviceAccountSubjectFormat, ns, s.ServiceAccount),
+					issuer + ":aud": defaultWebIdentityAudience,
+				},
+			},
+		})
+	}
+	return buildIAMPolicyDocument(iamPolicyStatement{
+		Effect:    "Allow",
+		Principal: map[string]string{"AWS": operatorArn},
+		Action:    []string{"sts:AssumeRole"},
+	})
+}
+
+// buildRolePolicy returns the inline policy of the iam role of a resource from the entries of its credentials request
+func buildRolePolicy(entries []v1.StatementEntry) (string, error) {
+	var statements []iamPolicyStatement
+	for _, entry := range entries {
+		statements = append(statements, iamPolicyStatement{
+			Effect:   entry.Effect,
+			Action:   entry.Action,
+			Resource: []string{entry.Resource},
+		})
+	}
+	return buildIAMPolicyDocument(statements...)
+}
+
+// getOperatorPrincipalArn returns the arn of the iam principal of the operator. the caller identity of an operator which
+// runs with the credentials of an assumed role is the session of the role, which can not be trusted by a role, so the arn
+// of the role is returned instead. it is described rather than built, as the arn of a role includes its path
+func getOperatorPrincipalArn(iamSvc iamiface.IAMAPI, stsSvc stsiface.STSAPI) (string, error) {
+	identity, err := stsSvc.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", errorUtil.Wrap(err, "failed to get operator identity")
+	}
+	callerArn := aws.StringValue(identity.Arn)
+	// arn:<partition>:sts::<account>:assumed-role/<role name>/<session name>
+	parts := strings.SplitN(callerArn, ":", 6)
+	if len(parts) != 6 || parts[2] != "sts" || !strings.HasPrefix(parts[5], assumedRoleArnResourcePrefix) {
+		return callerArn, nil
+	}
+	roleName := strings.SplitN(strings.TrimPrefix(parts[5], assumedRoleArnResourcePrefix), "/", 2)[0]
+	role, err := iamSvc.GetRole(&iam.GetRoleInput{
+		RoleName: aws.String(roleName),
+	})
+	if err != nil {
+		return "", errorUtil.Wrapf(err, "failed to get iam role %s of operator session %s", roleName, callerArn)
+	}
+	return aws.StringValue(role.Role.Arn), nil
+}
+
+// reconcileRoleCredentials creates the iam role of a resource with the access of the entries, and returns credentials
+// of the role. in the sts mode the previous credentials are returned until half of their session has passed, then the
+// role is assumed again so the credentials are refreshed before they expire
+func reconcileRoleCredentials(iamSvc iamiface.IAMAPI, stsSvc stsiface.STSAPI, s *CredentialStrategy, clusterID, roleName, ns string, entries []v1.StatementEntry, tags []*iam.Tag, previous *RoleCredentials) (*RoleCredentials, error) {
+	operatorArn, err := getOperatorPrincipalArn(iamSvc, stsSvc)
+	if err != nil {
+		return nil, err
+	}
+	trustPolicy, err := buildRoleTrustPolicy(s, operatorArn, ns)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := buildRolePolicy(entries)
+	if err != nil {
+		return nil, err
+	}
+	roleArn, err := reconcileIAMRole(iamSvc, clusterID, roleName, trustPolicy, defaultRolePolicyName, policy, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Mode == CredentialModeWebIdentity {
+		tokenFile := s.TokenFile
+		if tokenFile == "" {
+			tokenFile = defaultWebIdentityTokenFile
+		}
+		return &RoleCredentials{
+			RoleArn:              roleArn,
+			WebIdentityTokenFile: tokenFile,
+		}, nil
+	}
+
+	if previous != nil && previous.RoleArn == roleArn && previous.AccessKeyID != "" && !isCredentialRefreshDue(previous.Expiration, s.getSessionDuration()) {
+		return previous, nil
+	}
+	// no credentials are issued in dry run mode, the role does not exist yet if it would be created. the previous
+	// credentials are kept
+	if roleArn == "" || resources.IsDryRun() {
+		if previous != nil {
+			return previous, nil
+		}
+		return &RoleCredentials{RoleArn: roleArn}, nil
+	}
+	assumed, err := stsSvc.AssumeRole(&sts.AssumeRoleInput{
+		RoleArn:         aws.String(roleArn),
+		RoleSessionName: aws.String(resources.ShortenString(roleName, maxRoleSessionNameLength)),
+		DurationSeconds: aws.Int64(int64(s.getSessionDuration().Seconds())),
+	})
+	if err != nil {
+		return nil, errorUtil.Wrapf(err, "failed to assume iam role %s", roleName)
+	}
+	return &RoleCredentials{
+		RoleArn:         roleArn,
+		AccessKeyID:     aws.StringValue(assumed.Credentials.AccessKeyId),
+		SecretAccessKey: aws.StringValue(assumed.Credentials.SecretAccessKey),
+		SessionToken:    aws.StringValue(assumed.Credentials.SessionToken),
+		Expiration:      aws.TimeValue(assumed.Credentials.Expiration).UTC().Format(credentialExpirationTimeFormat),
+	}, nil
+}
+
+// isCredentialRefreshDue returns whether credentials which expire at expiration should be refreshed, which is once half
+// of their session has passed. credentials without a valid expiration are always refreshed
+func isCredentialRefreshDue(expiration string, sessionDuration time.Duration) bool {
+	expiresAt, err := time.Parse(credentialExpirationTimeFormat, expiration)
+	if err != nil {
+		return true
+	}
+	return time.Until(expiresAt) < sessionDuration/2
+}
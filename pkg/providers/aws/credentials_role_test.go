@@ -0,0 +1,263 @@
+package aws
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+	v1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
+)
+
+func Test_validateCredentialStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy *CredentialStrategy
+		wantErr  bool
+	}{
+		{name: "test no strategy is valid", strategy: nil},
+		{name: "test static mode is valid", strategy: &CredentialStrategy{Mode: CredentialModeStatic}},
+		{name: "test sts mode is valid", strategy: &CredentialStrategy{Mode: CredentialModeSTS, SessionDurationSeconds: 1800}},
+		{name: "test error when the session is too short", strategy: &CredentialStrategy{Mode: CredentialModeSTS, SessionDurationSeconds: 60}, wantErr: true},
+		{name: "test web-identity mode is valid", strategy: &CredentialStrategy{Mode: CredentialModeWebIdentity, OIDCProviderArn: "arn:aws:iam::test:oidc-provider/oidc.example.com/id/TEST", ServiceAccount: "app"}},
+		{name: "test error when web-identity mode has no oidc provider", strategy: &CredentialStrategy{Mode: CredentialModeWebIdentity, ServiceAccount: "app"}, wantErr: true},
+		{name: "test error with an unknown mode", strategy: &CredentialStrategy{Mode: "vault"}, wantErr: true},
+		{name: "test static mode with a rotation grace period is valid", strategy: &CredentialStrategy{Mode: CredentialModeStatic, RotationGracePeriod: "1h"}},
+		{name: "test error when the rotation grace period is invalid", strategy: &CredentialStrategy{RotationGracePeriod: "-1h"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateCredentialStrategy(tt.strategy); (err != nil) != tt.wantErr {
+				t.Errorf("validateCredentialStrategy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_buildRoleTrustPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		strategy      *CredentialStrategy
+		wantPrincipal map[string]string
+		wantAction    string
+		wantCondition map[string]string
+	}{
+		{
+			name:          "test role is assumed by the operator in sts mode",
+			strategy:      &CredentialStrategy{Mode: CredentialModeSTS},
+			wantPrincipal: map[string]string{"AWS": "arn:aws:iam::test:user/operator"},
+			wantAction:    "sts:AssumeRole",
+		},
+		{
+			name:          "test role is assumed by a service account in web-identity mode",
+			strategy:      &CredentialStrategy{Mode: CredentialModeWebIdentity, OIDCProviderArn: "arn:aws:iam::test:oidc-provider/oidc.example.com/id/TEST", ServiceAccount: "app"},
+			wantPrincipal: map[string]string{"Federated": "arn:aws:iam::test:oidc-provider/oidc.example.com/id/TEST"},
+			wantAction:    "sts:AssumeRoleWithWebIdentity",
+			wantCondition: map[string]string{
+				"oidc.example.com/id/TEST:sub": "system:serviceaccount:test:app",
+				"oidc.example.com/id/TEST:aud": "sts.amazonaws.com",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := buildRoleTrustPolicy(tt.strategy, "arn:aws:iam::test:user/operator", "test")
+			if err != nil {
+				t.Fatal("buildRoleTrustPolicy() returned an error", err)
+			}
+			document := &iamPolicyDocument{}
+			if err := json.Unmarshal([]byte(policy), document); err != nil {
+				t.Fatal("failed to unmarshal trust policy", err)
+			}
+			statement := document.Statement[0]
+			for k, v := range tt.wantPrincipal {
+				if statement.Principal[k] != v {
+					t.Errorf("buildRoleTrustPolicy() principal = %v, want %v", statement.Principal, tt.wantPrincipal)
+				}
+			}
+			if statement.Action[0] != tt.wantAction {
+				t.Errorf("buildRoleTrustPolicy() action = %v, want %v", statement.Action, tt.wantAction)
+			}
+			for k, v := range tt.wantCondition {
+				if statement.Condition["StringEquals"][k] != v {
+					t.Errorf("buildRoleTrustPolicy() condition = %v, want %v", statement.Condition, tt.wantCondition)
+				}
+			}
+		})
+	}
+}
+
+func Test_reconcileRoleCredentials(t *testing.T) {
+	entries := []v1.StatementEntry{{Effect: "Allow", Action: []string{"s3:*"}, Resource: "arn:aws:s3:::test"}}
+	roleArn := "arn:aws:iam::test:role" + buildIAMPath("test") + "test-access"
+	tests := []struct {
+		name          string
+		strategy      *CredentialStrategy
+		previous      *RoleCredentials
+		wantAssumed   bool
+		wantKeyID     string
+		wantTokenFile string
+	}{
+		{
+			name:        "test credentials of the role are issued in sts mode",
+			strategy:    &CredentialStrategy{Mode: CredentialModeSTS},
+			wantAssumed: true,
+			wantKeyID:   "ASIATEST",
+		},
+		{
+			name:     "test previous credentials are kept until half of their session has passed",
+			strategy: &CredentialStrategy{Mode: CredentialModeSTS},
+			previous: &RoleCredentials{
+				RoleArn:     roleArn,
+				AccessKeyID: "ASIAPREVIOUS",
+				Expiration:  time.Now().Add(time.Minute * 50).UTC().Format(credentialExpirationTimeFormat),
+			},
+			wantKeyID: "ASIAPREVIOUS",
+		},
+		{
+			name:     "test previous credentials are refreshed before they expire",
+			strategy: &CredentialStrategy{Mode: CredentialModeSTS},
+			previous: &RoleCredentials{
+				RoleArn:     roleArn,
+				AccessKeyID: "ASIAPREVIOUS",
+				Expiration:  time.Now().Add(time.Minute * 10).UTC().Format(credentialExpirationTimeFormat),
+			},
+			wantAssumed: true,
+			wantKeyID:   "ASIATEST",
+		},
+		{
+			name:          "test token file is returned in web-identity mode",
+			strategy:      &CredentialStrategy{Mode: CredentialModeWebIdentity, OIDCProviderArn: "arn:aws:iam::test:oidc-provider/oidc.example.com", ServiceAccount: "app"},
+			wantTokenFile: defaultWebIdentityTokenFile,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iamSvc := buildMockIAMClient()
+			stsSvc := &mockStsClient{}
+			got, err := reconcileRoleCredentials(iamSvc, stsSvc, tt.strategy, "test", "test-access", "test", entries, nil, tt.previous)
+			if err != nil {
+				t.Fatal("reconcileRoleCredentials() returned an error", err)
+			}
+			if got.RoleArn != roleArn {
+				t.Errorf("reconcileRoleCredentials() role = %v, want %v", got.RoleArn, roleArn)
+			}
+			if _, ok := iamSvc.policies["test-access"]; !ok {
+				t.Error("reconcileRoleCredentials() did not set the policy of the role")
+			}
+			if (len(stsSvc.assumedRoles) > 0) != tt.wantAssumed {
+				t.Errorf("reconcileRoleCredentials() assumed roles %v, want assumed %v", stsSvc.assumedRoles, tt.wantAssumed)
+			}
+			if got.AccessKeyID != tt.wantKeyID {
+				t.Errorf("reconcileRoleCredentials() access key = %v, want %v", got.AccessKeyID, tt.wantKeyID)
+			}
+			if got.WebIdentityTokenFile != tt.wantTokenFile {
+				t.Errorf("reconcileRoleCredentials() token file = %v, want %v", got.WebIdentityTokenFile, tt.wantTokenFile)
+			}
+			if tt.wantAssumed && got.Expiration == "" {
+				t.Error("reconcileRoleCredentials() did not return the expiration of the credentials")
+			}
+		})
+	}
+}
+
+// mock iam client of a dry run, mutating calls return empty outputs as they are not sent
+type mockDryRunIAMClient struct {
+	*mockIAMClient
+}
+
+func (m *mockDryRunIAMClient) CreateRole(*iam.CreateRoleInput) (*iam.CreateRoleOutput, error) {
+	return &iam.CreateRoleOutput{}, nil
+}
+
+func (m *mockDryRunIAMClient) CreatePolicy(*iam.CreatePolicyInput) (*iam.CreatePolicyOutput, error) {
+	return &iam.CreatePolicyOutput{}, nil
+}
+
+func (m *mockDryRunIAMClient) PutRolePolicy(*iam.PutRolePolicyInput) (*iam.PutRolePolicyOutput, error) {
+	return &iam.PutRolePolicyOutput{}, nil
+}
+
+func Test_reconcileRoleCredentialsDryRun(t *testing.T) {
+	if err := os.Setenv(resources.EnvDryRun, "true"); err != nil {
+		t.Fatal("failed to enable dry run mode", err)
+	}
+	defer os.Unsetenv(resources.EnvDryRun)
+
+	entries := []v1.StatementEntry{{Effect: "Allow", Action: []string{"s3:*"}, Resource: "arn:aws:s3:::test"}}
+	previous := &RoleCredentials{RoleArn: "arn:aws:iam::test:role/test-access", AccessKeyID: "ASIAPREVIOUS"}
+	tests := []struct {
+		name      string
+		previous  *RoleCredentials
+		wantKeyID string
+	}{
+		{
+			name: "test no credentials are issued when the role would be created",
+		},
+		{
+			name:      "test previous credentials are kept",
+			previous:  previous,
+			wantKeyID: "ASIAPREVIOUS",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stsSvc := &mockStsClient{}
+			got, err := reconcileRoleCredentials(&mockDryRunIAMClient{buildMockIAMClient()}, stsSvc, &CredentialStrategy{Mode: CredentialModeSTS}, "test", "test-access", "test", entries, nil, tt.previous)
+			if err != nil {
+				t.Fatal("reconcileRoleCredentials() returned an error", err)
+			}
+			if len(stsSvc.assumedRoles) > 0 {
+				t.Errorf("reconcileRoleCredentials() assumed roles %v in dry run mode", stsSvc.assumedRoles)
+			}
+			if got.AccessKeyID != tt.wantKeyID {
+				t.Errorf("reconcileRoleCredentials() access key = %v, want %v", got.AccessKeyID, tt.wantKeyID)
+			}
+		})
+	}
+}
+
+func Test_getOperatorPrincipalArn(t *testing.T) {
+	tests := []struct {
+		name      string
+		callerArn string
+		roles     map[string]*iam.Role
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "test arn of an iam user is returned",
+			callerArn: "arn:aws:iam::test:user/operator",
+			want:      "arn:aws:iam::test:user/operator",
+		},
+		{
+			name:      "test assumed role session is resolved to the arn of its role",
+			callerArn: "arn:aws:sts::test:assumed-role/operator/session",
+			roles:     map[string]*iam.Role{"operator": {RoleName: aws.String("operator"), Arn: aws.String("arn:aws:iam::test:role/operators/operator")}},
+			want:      "arn:aws:iam::test:role/operators/operator",
+		},
+		{
+			name:      "test assumed role which can not be described fails",
+			callerArn: "arn:aws:sts::test:assumed-role/operator/session",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iamSvc := buildMockIAMClient()
+			for name, role := range tt.roles {
+				iamSvc.roles[name] = role
+			}
+			got, err := getOperatorPrincipalArn(iamSvc, &mockStsClient{callerArn: tt.callerArn})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getOperatorPrincipalArn() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("getOperatorPrincipalArn() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+	errorUtil "github.com/pkg/errors"
+	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// the rotation of the access key of a minted iam user is recorded in annotations of the secret of its credentials
+// request, the previous access key is revoked once the grace period has passed since the rotation
+const (
+	credentialsRotatedAtAnnotation   = "credentialsRotatedAt"
+	credentialsPreviousKeyAnnotation = "credentialsPreviousAccessKeyID"
+	defaultRotationGracePeriod       = time.Hour * 24
+)
+
+// reconcileAccessKeyRotation rotates the access key of the iam user minted for the credentials request name, once the
+// rotation interval has passed since the last rotation or a rotation is requested through an annotation of obj. the new
+// access key is written to the secret of the credentials request and returned, the previous access key stays valid for
+// the grace period so consumers can reload the credentials. an iam user can only have two access keys, a previous key
+// still in its grace period is revoked early when another rotation is due. the credentials are returned unchanged if
+// no rotation is due
+func reconcileAccessKeyRotation(ctx context.Context, c client.Client, iamSvc iamiface.IAMAPI, obj metav1.Object, interval string, gracePeriod time.Duration, creds *Credentials, name, ns string) (*Credentials, error) {
+	if resources.IsDryRun() {
+		return creds, nil
+	}
+	sec := &v12.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: ns}, sec); err != nil {
+		// the secret is created by the cloud credential operator, nothing is rotated until it exists
+		if errors.IsNotFound(err) {
+			return creds, nil
+		}
+		return nil, errorUtil.Wrapf(err, "failed to get aws credentials secret %s", name)
+	}
+
+	lastRotation := sec.CreationTimestamp.Time
+	if rotatedAt, ok := sec.Annotations[credentialsRotatedAtAnnotation]; ok {
+		t, err := time.Parse(time.RFC3339, rotatedAt)
+		if err != nil {
+			return nil, errorUtil.Wrapf(err, "failed to parse rotation time of aws credentials secret %s", name)
+		}
+		lastRotation = t
+	}
+	due, err := resources.IsRotationDue(obj, interval, lastRotation)
+	if err != nil {
+		return nil, err
+	}
+
+	if previousKeyID, ok := sec.Annotations[credentialsPreviousKeyAnnotation]; ok && (due || time.Since(lastRotation) >= gracePeriod) {
+		if err := revokeAccessKey(iamSvc, creds.Username, previousKeyID); err != nil {
+			return nil, err
+		}
+		delete(sec.Annotations, credentialsPreviousKeyAnnotation)
+		if err := c.Update(ctx, sec); err != nil {
+			return nil, errorUtil.Wrapf(err, "failed to remove previous access key from aws credentials secret %s", name)
+		}
+	}
+	if !due {
+		return creds, nil
+	}
+
+	if creds.Username == "" {
+		return nil, errorUtil.Errorf("iam user of aws credentials secret %s is unknown", name)
+	}
+	created, err := iamSvc.CreateAccessKey(&iam.CreateAccessKeyInput{
+		UserName: aws.String(creds.Username),
+	})
+	if err != nil {
+		return nil, errorUtil.Wrapf(err, "failed to create access key for iam user %s", creds.Username)
+	}
+	rotated := &Credentials{
+		Username:        creds.Username,
+		PolicyName:      creds.PolicyName,
+		AccessKeyID:     aws.StringValue(created.AccessKey.AccessKeyId),
+		SecretAccessKey: aws.StringValue(created.AccessKey.SecretAccessKey),
+	}
+
+	if sec.Annotations == nil {
+		sec.Annotations = map[string]string{}
+	}
+	sec.Annotations[credentialsPreviousKeyAnnotation] = string(sec.Data[defaultCredentialsKeyIDName])
+	sec.Annotations[credentialsRotatedAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	sec.Data[defaultCredentialsKeyIDName] = []byte(rotated.AccessKeyID)
+	sec.Data[defaultCredentialsSecretKeyName] = []byte(rotated.SecretAccessKey)
+	if err := c.Update(ctx, sec); err != nil {
+		// revoke the new access key so it does not take up the second access key of the user
+		if revokeErr := revokeAccessKey(iamSvc, creds.Username, rotated.AccessKeyID); revokeErr != nil {
+			return nil, errorUtil.Wrapf(revokeErr, "failed to revoke access key after failing to store it: %v", err)
+		}
+		return nil, errorUtil.Wrapf(err, "failed to store rotated access key in aws credentials secret %s", name)
+	}
+	return rotated, nil
+}
+
+// stampRotationRequest records the time a rotation of the credentials of obj was requested at in the annotation
+// requesting it, before any of the credentials are rotated. credentials rotated in a reconcile which fails before the
+// annotation is removed are then not rotated again, which would revoke their previous access key early
+func stampRotationRequest(ctx context.Context, c client.Client, obj runtime.Object) error {
+	if resources.IsDryRun() {
+		return nil
+	}
+	metaObj, err := meta.Accessor(obj)
+	if err != nil {
+		return errorUtil.Wrap(err, "failed to retrieve metadata of object")
+	}
+	if !resources.StampRotationRequest(metaObj) {
+		return nil
+	}
+	if err := c.Update(ctx, obj); err != nil {
+		return errorUtil.Wrapf(err, "failed to record rotation request time of %s", metaObj.GetName())
+	}
+	return nil
+}
+
+// revokeAccessKey deletes an access key of an iam user, an access key which does not exist is ignored
+func revokeAccessKey(iamSvc iamiface.IAMAPI, user, keyID string) error {
+	if _, err := iamSvc.DeleteAccessKey(&iam.DeleteAccessKeyInput{
+		UserName:    aws.String(user),
+		AccessKeyId: aws.String(keyID),
+	}); err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == iam.ErrCodeNoSuchEntityException {
+			return nil
+		}
+		return errorUtil.Wrapf(err, "failed to revoke access key %s of iam user %s", keyID, user)
+	}
+	return nil
+}
+
+// getRotationGracePeriod returns how long the previous access key stays valid after the credentials are rotated
+func (s *CredentialStrategy) getRotationGracePeriod() time.Duration {
+	if s == nil || s.RotationGracePeriod == "" {
+		return defaultRotationGracePeriod
+	}
+	d, err := time.ParseDuration(s.RotationGracePeriod)
+	if err != nil {
+		return defaultRotationGracePeriod
+	}
+	return d
+}
+
+// validateRotationGracePeriod verifies the rotation grace period of a credential strategy is a positive duration
+func validateRotationGracePeriod(s *CredentialStrategy) error {
+	if s == nil || s.RotationGracePeriod == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(s.RotationGracePeriod)
+	if err != nil {
+		return errorUtil.Wrapf(err, "failed to parse rotation grace period %s", s.RotationGracePeriod)
+	}
+	if d <= 0 {
+		return fmt.Errorf("rotation grace period %s must be greater than zero", s.RotationGracePeriod)
+	}
+	return nil
+}
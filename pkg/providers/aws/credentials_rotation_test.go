@@ -0,0 +1,151 @@
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+	v12 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_reconcileAccessKeyRotation(t *testing.T) {
+	scheme, err := buildTestScheme()
+	if err != nil {
+		t.Fatal("failed to build test scheme", err)
+	}
+	buildSecret := func(annotations map[string]string) *v12.Secret {
+		return &v12.Secret{
+			ObjectMeta: v1.ObjectMeta{
+				Name:              "test",
+				Namespace:         "test",
+				Annotations:       annotations,
+				CreationTimestamp: v1.NewTime(time.Now().Add(-time.Hour)),
+			},
+			Data: map[string][]byte{
+				defaultCredentialsKeyIDName:     []byte("AKIACURRENT"),
+				defaultCredentialsSecretKeyName: []byte("current"),
+			},
+		}
+	}
+	rotatedAt := func(d time.Duration) string {
+		return time.Now().Add(-d).UTC().Format(time.RFC3339)
+	}
+	tests := []struct {
+		name            string
+		annotations     map[string]string
+		interval        string
+		secret          *v12.Secret
+		accessKeys      []string
+		wantRotated     bool
+		wantPreviousKey string
+		wantAccessKeys  []string
+	}{
+		{
+			name:           "test credentials are not rotated before the interval has passed",
+			interval:       "720h",
+			secret:         buildSecret(nil),
+			accessKeys:     []string{"AKIACURRENT"},
+			wantAccessKeys: []string{"AKIACURRENT"},
+		},
+		{
+			name:            "test credentials are rotated once the interval has passed",
+			interval:        "30m",
+			secret:          buildSecret(nil),
+			accessKeys:      []string{"AKIACURRENT"},
+			wantRotated:     true,
+			wantPreviousKey: "AKIACURRENT",
+			wantAccessKeys:  []string{"AKIACURRENT", "AKIATEST1"},
+		},
+		{
+			name:            "test credentials are rotated on request",
+			annotations:     map[string]string{resources.RotateCredentialsAnnotation: "true"},
+			secret:          buildSecret(nil),
+			accessKeys:      []string{"AKIACURRENT"},
+			wantRotated:     true,
+			wantPreviousKey: "AKIACURRENT",
+			wantAccessKeys:  []string{"AKIACURRENT", "AKIATEST1"},
+		},
+		{
+			name:        "test credentials rotated since the request are not rotated again",
+			annotations: map[string]string{resources.RotateCredentialsAnnotation: rotatedAt(time.Hour * 2)},
+			secret: buildSecret(map[string]string{
+				credentialsPreviousKeyAnnotation: "AKIAPREVIOUS",
+				credentialsRotatedAtAnnotation:   rotatedAt(time.Hour),
+			}),
+			accessKeys:      []string{"AKIAPREVIOUS", "AKIACURRENT"},
+			wantPreviousKey: "AKIAPREVIOUS",
+			wantAccessKeys:  []string{"AKIAPREVIOUS", "AKIACURRENT"},
+		},
+		{
+			name: "test previous access key is kept during the grace period",
+			secret: buildSecret(map[string]string{
+				credentialsPreviousKeyAnnotation: "AKIAPREVIOUS",
+				credentialsRotatedAtAnnotation:   rotatedAt(time.Hour),
+			}),
+			accessKeys:      []string{"AKIAPREVIOUS", "AKIACURRENT"},
+			wantPreviousKey: "AKIAPREVIOUS",
+			wantAccessKeys:  []string{"AKIAPREVIOUS", "AKIACURRENT"},
+		},
+		{
+			name: "test previous access key is revoked after the grace period",
+			secret: buildSecret(map[string]string{
+				credentialsPreviousKeyAnnotation: "AKIAPREVIOUS",
+				credentialsRotatedAtAnnotation:   rotatedAt(time.Hour * 25),
+			}),
+			accessKeys:     []string{"AKIAPREVIOUS", "AKIACURRENT"},
+			wantAccessKeys: []string{"AKIACURRENT"},
+		},
+		{
+			name:        "test previous access key is revoked early when rotation is requested again",
+			annotations: map[string]string{resources.RotateCredentialsAnnotation: "true"},
+			secret: buildSecret(map[string]string{
+				credentialsPreviousKeyAnnotation: "AKIAPREVIOUS",
+				credentialsRotatedAtAnnotation:   rotatedAt(time.Hour),
+			}),
+			accessKeys:      []string{"AKIAPREVIOUS", "AKIACURRENT"},
+			wantRotated:     true,
+			wantPreviousKey: "AKIACURRENT",
+			wantAccessKeys:  []string{"AKIACURRENT", "AKIATEST1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewFakeClientWithScheme(scheme, tt.secret)
+			iamSvc := buildMockIAMClient()
+			for _, keyID := range tt.accessKeys {
+				iamSvc.accessKeys[keyID] = true
+			}
+			obj := &v1.ObjectMeta{Name: "test", Namespace: "test", Annotations: tt.annotations}
+			creds := &Credentials{Username: "test", AccessKeyID: "AKIACURRENT", SecretAccessKey: "current"}
+			got, err := reconcileAccessKeyRotation(context.TODO(), client, iamSvc, obj, tt.interval, defaultRotationGracePeriod, creds, "test", "test")
+			if err != nil {
+				t.Fatal("reconcileAccessKeyRotation() returned an error", err)
+			}
+			if (got.AccessKeyID != creds.AccessKeyID) != tt.wantRotated {
+				t.Errorf("reconcileAccessKeyRotation() access key = %s, want rotated %v", got.AccessKeyID, tt.wantRotated)
+			}
+			sec := &v12.Secret{}
+			if err := client.Get(context.TODO(), types.NamespacedName{Name: "test", Namespace: "test"}, sec); err != nil {
+				t.Fatal("failed to get secret", err)
+			}
+			if string(sec.Data[defaultCredentialsKeyIDName]) != got.AccessKeyID || string(sec.Data[defaultCredentialsSecretKeyName]) != got.SecretAccessKey {
+				t.Errorf("reconcileAccessKeyRotation() secret has access key %s, want %s", sec.Data[defaultCredentialsKeyIDName], got.AccessKeyID)
+			}
+			if sec.Annotations[credentialsPreviousKeyAnnotation] != tt.wantPreviousKey {
+				t.Errorf("reconcileAccessKeyRotation() previous access key = %q, want %q", sec.Annotations[credentialsPreviousKeyAnnotation], tt.wantPreviousKey)
+			}
+			if len(iamSvc.accessKeys) != len(tt.wantAccessKeys) {
+				t.Fatalf("reconcileAccessKeyRotation() access keys = %v, want %v", iamSvc.accessKeys, tt.wantAccessKeys)
+			}
+			for _, keyID := range tt.wantAccessKeys {
+				if !iamSvc.accessKeys[keyID] {
+					t.Errorf("reconcileAccessKeyRotation() access keys = %v, want %v", iamSvc.accessKeys, tt.wantAccessKeys)
+				}
+			}
+		})
+	}
+}
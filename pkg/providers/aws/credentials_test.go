@@ -2,11 +2,16 @@ package aws
 
 import (
 	"context"
+	"os"
+	"reflect"
 	"testing"
 
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
 	v1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
 	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -81,3 +86,79 @@ func TestCredentialManager_ReconcileCredentials(t *testing.T) {
 		})
 	}
 }
+
+func TestCredentialManager_ReconcileCredentialsDryRun(t *testing.T) {
+	scheme, err := buildTestScheme()
+	if err != nil {
+		t.Fatal("failed to build scheme", err)
+	}
+	if err := os.Setenv(resources.EnvDryRun, "true"); err != nil {
+		t.Fatal("failed to set dry run env var", err)
+	}
+	defer os.Unsetenv(resources.EnvDryRun)
+
+	ctx := resources.NewDryRunContext(context.TODO())
+	fakeClient := fake.NewFakeClientWithScheme(scheme, buildTestInfrastructure())
+	cm := NewCredentialMinterCredentialManager(fakeClient)
+	_, awsCreds, err := cm.ReconcileCredentials(ctx, "test", "test", []v1.StatementEntry{})
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if awsCreds.AccessKeyID != "" || awsCreds.SecretAccessKey != "" {
+		t.Fatalf("unexpected credentials minted in dry run mode %v", awsCreds)
+	}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: "test", Namespace: "test"}, &v1.CredentialsRequest{}); !errors.IsNotFound(err) {
+		t.Fatalf("credentials request was created in dry run mode, err = %v", err)
+	}
+	want := []string{"cloudcredential:CreateCredentialsRequest"}
+	if got := resources.GetDryRunRecorder(ctx).Actions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("planned actions = %v, want %v", got, want)
+	}
+	if _, err := cm.ReconcileProviderCredentials(ctx, "test"); err == nil {
+		t.Error("expected error for provider credentials which are not minted in dry run mode")
+	}
+}
+
+func Test_buildOperatorEntries(t *testing.T) {
+	tests := []struct {
+		name      string
+		clusterID string
+		want      string
+		wantRole  string
+	}{
+		{
+			name:      "test access keys can be rotated for the users of the cluster",
+			clusterID: "test-a1b2c",
+			want:      "arn:aws:iam::*:user/test-a1b2c-*",
+			wantRole:  "arn:aws:iam::*:role/cloud-resource-operator/test-a1b2c/*",
+		},
+		{
+			name:      "test cluster id is truncated like the user names of the cloud credential operator",
+			clusterID: "abcdefghijabcdefghijabcdefghijabcdefghij",
+			want:      "arn:aws:iam::*:user/abcdefghijabcdefghijabcdefghijabcdefg-*",
+			wantRole:  "arn:aws:iam::*:role/cloud-resource-operator/abcdefghijabcdefghijabcdefghijabcdefghij/*",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := buildOperatorEntries(tt.clusterID)
+			for _, entry := range entries {
+				for _, action := range entry.Action {
+					if (action == "iam:CreateAccessKey" || action == "iam:DeleteAccessKey") && entry.Resource != tt.want {
+						t.Errorf("buildOperatorEntries() %s resource = %s, want %s", action, entry.Resource, tt.want)
+					}
+				}
+			}
+			for _, entry := range entries {
+				for _, action := range entry.Action {
+					if (action == "iam:CreateRole" || action == "iam:PassRole" || action == "sts:AssumeRole") && entry.Resource != tt.wantRole {
+						t.Errorf("buildOperatorEntries() %s resource = %s, want %s", action, entry.Resource, tt.wantRole)
+					}
+				}
+			}
+			if len(entries) != len(operatorEntries)+3 {
+				t.Errorf("buildOperatorEntries() returned %d entries, want %d", len(entries), len(operatorEntries)+3)
+			}
+		})
+	}
+}
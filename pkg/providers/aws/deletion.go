@@ -0,0 +1,75 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+	errorUtil "github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// deletionAction is what is done with the cloud resource of a cr which is being deleted
+type deletionAction int
+
+const (
+	// deletionActionDelete deletes the cloud resource now
+	deletionActionDelete deletionAction = iota
+	// deletionActionRetain leaves the cloud resource in place and lets the deletion of the cr complete
+	deletionActionRetain
+	// deletionActionDelay leaves the cloud resource in place until the deletion grace period has passed
+	deletionActionDelay
+)
+
+// reconcileDeletionPolicy determines what is done with the cloud resource of a cr which is being deleted, as described
+// by the deletion policy and deletion grace period of the cr. cloud resources which are retained, or whose deletion is delayed, are recorded in
+// the inventory of the namespace of the cr
+func reconcileDeletionPolicy(ctx context.Context, c client.Client, obj metav1.Object, policy, gracePeriod string, entry resources.InventoryEntry) (deletionAction, croType.StatusMessage, error) {
+	entry.Name = obj.GetName()
+	entry.DeletionPolicy = policy
+	if deletedAt := obj.GetDeletionTimestamp(); deletedAt != nil {
+		entry.RetainedAt = deletedAt.UTC().Format(time.RFC3339)
+	}
+
+	switch policy {
+	case "", resources.DeletionPolicyDelete, resources.DeletionPolicySnapshot:
+		return deletionActionDelete, croType.StatusEmpty, nil
+	case resources.DeletionPolicyRetain:
+		if err := resources.AddToInventory(ctx, c, obj.GetNamespace(), entry); err != nil {
+			errMsg := fmt.Sprintf("failed to add %s to inventory", entry.Identifier)
+			return deletionActionRetain, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+		return deletionActionRetain, croType.StatusMessage(fmt.Sprintf("retained %s", entry.Identifier)), nil
+	case resources.DeletionPolicyDelayedDelete:
+		deleteAt, err := resources.GetDelayedDeletionTime(obj, gracePeriod)
+		if err != nil {
+			errMsg := fmt.Sprintf("failed to get deletion time of %s", entry.Identifier)
+			return deletionActionDelay, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+		if time.Now().After(deleteAt) {
+			return deletionActionDelete, croType.StatusEmpty, nil
+		}
+		entry.DeleteAfter = deleteAt.UTC().Format(time.RFC3339)
+		if err := resources.AddToInventory(ctx, c, obj.GetNamespace(), entry); err != nil {
+			errMsg := fmt.Sprintf("failed to add %s to inventory", entry.Identifier)
+			return deletionActionDelay, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+		return deletionActionDelay, croType.StatusMessage(fmt.Sprintf("deletion of %s delayed until %s", entry.Identifier, entry.DeleteAfter)), nil
+	default:
+		errMsg := fmt.Sprintf("deletion policy %s of %s is not supported", policy, obj.GetName())
+		return deletionActionDelay, croType.StatusMessage(errMsg), fmt.Errorf(errMsg)
+	}
+}
+
+// buildFinalSnapshotName returns the name of the snapshot cr which records the final snapshot taken when the cloud
+// resource of a cr is deleted, it is unique to each deletion of a cr with the same name
+func buildFinalSnapshotName(om metav1.ObjectMeta) string {
+	var deletedAt int64
+	if om.DeletionTimestamp != nil {
+		deletedAt = om.DeletionTimestamp.Unix()
+	}
+	return fmt.Sprintf("%s-final-%d", om.Name, deletedAt)
+}
@@ -0,0 +1,80 @@
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_reconcileDeletionPolicy(t *testing.T) {
+	recentlyDeleted := metav1.NewTime(time.Now().Add(-time.Hour))
+	tests := []struct {
+		name          string
+		policy        string
+		gracePeriod   string
+		want          deletionAction
+		wantErr       bool
+		wantInventory bool
+	}{
+		{
+			name: "test resource is deleted when no deletion policy is set",
+			want: deletionActionDelete,
+		},
+		{
+			name:   "test resource is deleted with snapshot deletion policy",
+			policy: resources.DeletionPolicySnapshot,
+			want:   deletionActionDelete,
+		},
+		{
+			name:          "test resource is retained with retain deletion policy",
+			policy:        resources.DeletionPolicyRetain,
+			want:          deletionActionRetain,
+			wantInventory: true,
+		},
+		{
+			name:          "test deletion is delayed during grace period",
+			policy:        resources.DeletionPolicyDelayedDelete,
+			want:          deletionActionDelay,
+			wantInventory: true,
+		},
+		{
+			name:        "test resource is deleted after grace period",
+			policy:      resources.DeletionPolicyDelayedDelete,
+			gracePeriod: "30m",
+			want:        deletionActionDelete,
+		},
+		{
+			name:    "test error with unknown deletion policy",
+			policy:  "Orphan",
+			want:    deletionActionDelay,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewFakeClient()
+			obj := &metav1.ObjectMeta{Name: "test", Namespace: "test", DeletionTimestamp: &recentlyDeleted}
+			got, _, err := reconcileDeletionPolicy(context.TODO(), c, obj, tt.policy, tt.gracePeriod, resources.InventoryEntry{
+				ResourceType: "postgres",
+				Identifier:   "test-id",
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("reconcileDeletionPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("reconcileDeletionPolicy() = %v, want %v", got, tt.want)
+			}
+			cm := &v1.ConfigMap{}
+			inventoryErr := c.Get(context.TODO(), types.NamespacedName{Name: resources.InventoryConfigMapName, Namespace: "test"}, cm)
+			if gotInventory := inventoryErr == nil && cm.Data["postgres.test-id"] != ""; gotInventory != tt.wantInventory {
+				t.Errorf("reconcileDeletionPolicy() recorded in inventory = %v, want %v", gotInventory, tt.wantInventory)
+			}
+		})
+	}
+}
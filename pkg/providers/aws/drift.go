@@ -0,0 +1,76 @@
+package aws
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+)
+
+const driftTagFieldPrefix = "Tags."
+
+// appendDrift appends a field to the drift of a resource if its observed value differs from its desired value. fields
+// which have already been recorded are ignored
+func appendDrift(drift []croType.DriftedField, field, desired, observed string) []croType.DriftedField {
+	if desired == observed {
+		return drift
+	}
+	for _, d := range drift {
+		if d.Field == field {
+			return drift
+		}
+	}
+	return append(drift, croType.DriftedField{Field: field, Desired: desired, Observed: observed})
+}
+
+// appendTagDrift appends the desired tags of a resource which are missing or have another value
+func appendTagDrift(drift []croType.DriftedField, desired, observed map[string]string) []croType.DriftedField {
+	keys := make([]string, 0, len(desired))
+	for k := range desired {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		drift = appendDrift(drift, driftTagFieldPrefix+k, desired[k], observed[k])
+	}
+	return drift
+}
+
+// formatDriftList formats a list of values so it can be compared regardless of its order
+func formatDriftList(values []*string) string {
+	list := aws.StringValueSlice(values)
+	sort.Strings(list)
+	return strings.Join(list, ",")
+}
+
+// exposeDriftMetric sets the drift metric of each drifted field of a resource, fields which were drifted when the
+// resource was last reconciled and are no longer are reset
+func exposeDriftMetric(labels map[string]string, previous, current []croType.DriftedField) {
+	for _, d := range previous {
+		if !containsDriftedField(current, d.Field) {
+			resources.SetMetric(resources.DefaultResourceDriftMetricName, buildDriftMetricLabels(labels, d.Field), 0)
+		}
+	}
+	for _, d := range current {
+		resources.SetMetric(resources.DefaultResourceDriftMetricName, buildDriftMetricLabels(labels, d.Field), 1)
+	}
+}
+
+func buildDriftMetricLabels(genericLabels map[string]string, field string) map[string]string {
+	labels := map[string]string{"field": field}
+	for k, v := range genericLabels {
+		labels[k] = v
+	}
+	return labels
+}
+
+func containsDriftedField(drift []croType.DriftedField, field string) bool {
+	for _, d := range drift {
+		if d.Field == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+)
+
+const dryRunHandlerName = "cro.DryRunHandler"
+
+// operations with these prefixes only read from aws and are still performed in dry run mode
+var readOnlyOperationPrefixes = []string{"Describe", "List", "Get", "Head"}
+
+// ConfigureDryRun prevents a session from performing mutating calls against aws when the operator is in dry run mode,
+// the calls are recorded as planned actions in the dry run recorder of the context instead
+func ConfigureDryRun(ctx context.Context, sess *session.Session) *session.Session {
+	if !resources.IsDryRun() {
+		return sess
+	}
+	sess.Handlers.Build.PushBackNamed(request.NamedHandler{
+		Name: dryRunHandlerName,
+		Fn: func(r *request.Request) {
+			if isReadOnlyOperation(r.Operation.Name) {
+				return
+			}
+			// request parameters are not logged, they can contain credentials
+			resources.RecordDryRunAction(ctx, fmt.Sprintf("%s:%s", r.ClientInfo.ServiceName, r.Operation.Name))
+			r.Handlers.Send.Clear()
+			r.Handlers.UnmarshalMeta.Clear()
+			r.Handlers.ValidateResponse.Clear()
+			r.Handlers.UnmarshalError.Clear()
+			r.Handlers.Unmarshal.Clear()
+		},
+	})
+	return sess
+}
+
+func isReadOnlyOperation(name string) bool {
+	for _, prefix := range readOnlyOperationPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package aws
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+)
+
+func TestConfigureDryRun(t *testing.T) {
+	if err := os.Setenv(resources.EnvDryRun, "true"); err != nil {
+		t.Fatal("failed to set dry run env var", err)
+	}
+	defer os.Unsetenv(resources.EnvDryRun)
+
+	ctx := resources.NewDryRunContext(context.TODO())
+	// the endpoint is unreachable, a call which is not suppressed fails
+	sess := ConfigureDryRun(ctx, session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String("eu-west-1"),
+		Endpoint:    aws.String("http://127.0.0.1:1"),
+		MaxRetries:  aws.Int(0),
+		Credentials: credentials.NewStaticCredentials("test", "test", ""),
+	})))
+	rdsSvc := rds.New(sess)
+	if _, err := rdsSvc.CreateDBInstance(&rds.CreateDBInstanceInput{
+		DBInstanceIdentifier: aws.String("test"),
+		DBInstanceClass:      aws.String("db.t3.small"),
+		Engine:               aws.String("postgres"),
+	}); err != nil {
+		t.Fatal("mutating call was not suppressed in dry run mode", err)
+	}
+	if _, err := rdsSvc.DescribeDBInstances(&rds.DescribeDBInstancesInput{}); err == nil {
+		t.Fatal("read only call was suppressed in dry run mode")
+	}
+	want := []string{"rds:CreateDBInstance"}
+	if got := resources.GetDryRunRecorder(ctx).Actions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("planned actions = %v, want %v", got, want)
+	}
+}
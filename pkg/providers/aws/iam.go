@@ -0,0 +1,186 @@
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	errorUtil "github.com/pkg/errors"
+)
+
+const (
+	iamPolicyVersion = "2012-10-17"
+
+	// iamPathFormat is the path of the iam roles and policies created for a cluster, the provider credentials can only
+	// manage the roles and policies under the path of their cluster
+	iamPathFormat = "/cloud-resource-operator/%s/"
+	// defaultRoleBoundaryPolicyName is the name of the managed policy which is the permissions boundary of the roles
+	defaultRoleBoundaryPolicyName = "role-boundary"
+)
+
+// roleBoundaryStatements are the permissions boundary of the roles created for a cluster, a role is granted no more
+// than the access to buckets and keys of bucket and replication roles, whatever its inline policy
+var roleBoundaryStatements = []iamPolicyStatement{
+	{
+		Effect:   "Allow",
+		Action:   []string{"s3:*"},
+		Resource: []string{"*"},
+	},
+	{
+		Effect:   "Allow",
+		Action:   []string{"kms:Decrypt", "kms:GenerateDataKey"},
+		Resource: []string{"*"},
+	},
+}
+
+// iamPolicyDocument is an iam policy, or the trust policy of an iam role
+type iamPolicyDocument struct {
+	Version   string               `json:"Version"`
+	Statement []iamPolicyStatement `json:"Statement"`
+}
+
+// iamPolicyStatement is a statement of an iam policy
+type iamPolicyStatement struct {
+	Sid       string                       `json:"Sid,omitempty"`
+	Effect    string                       `json:"Effect"`
+	Principal map[string]string            `json:"Principal,omitempty"`
+	Action    []string                     `json:"Action"`
+	Resource  []string                     `json:"Resource,omitempty"`
+	Condition map[string]map[string]string `json:"Condition,omitempty"`
+}
+
+// buildIAMPolicyDocument returns the json of a policy with statements
+func buildIAMPolicyDocument(statements ...iamPolicyStatement) (string, error) {
+	document, err := json.Marshal(&iamPolicyDocument{
+		Version:   iamPolicyVersion,
+		Statement: statements,
+	})
+	if err != nil {
+		return "", errorUtil.Wrap(err, "failed to marshal iam policy document")
+	}
+	return string(document), nil
+}
+
+// buildIAMPath returns the path of the iam roles and policies created for a cluster
+func buildIAMPath(clusterID string) string {
+	return fmt.Sprintf(iamPathFormat, clusterID)
+}
+
+// reconcileIAMRoleBoundary creates the managed policy which is the permissions boundary of the roles created for a
+// cluster, the arn of the policy is returned. no policy is described in dry run mode
+func reconcileIAMRoleBoundary(iamSvc iamiface.IAMAPI, clusterID string) (string, error) {
+	path := buildIAMPath(clusterID)
+	listOutput, err := iamSvc.ListPolicies(&iam.ListPoliciesInput{
+		Scope:      aws.String(iam.PolicyScopeTypeLocal),
+		PathPrefix: aws.String(path),
+	})
+	if err != nil {
+		return "", errorUtil.Wrapf(err, "failed to list iam policies with path %s", path)
+	}
+	for _, policy := range listOutput.Policies {
+		if aws.StringValue(policy.PolicyName) == defaultRoleBoundaryPolicyName {
+			return aws.StringValue(policy.Arn), nil
+		}
+	}
+	document, err := buildIAMPolicyDocument(roleBoundaryStatements...)
+	if err != nil {
+		return "", err
+	}
+	createOutput, err := iamSvc.CreatePolicy(&iam.CreatePolicyInput{
+		PolicyName:     aws.String(defaultRoleBoundaryPolicyName),
+		Path:           aws.String(path),
+		PolicyDocument: aws.String(document),
+		Description:    aws.String("permissions boundary of the iam roles created by the cloud resource operator"),
+	})
+	if err != nil {
+		return "", errorUtil.Wrapf(err, "failed to create iam policy %s", defaultRoleBoundaryPolicyName)
+	}
+	if createOutput.Policy == nil {
+		return "", nil
+	}
+	return aws.StringValue(createOutput.Policy.Arn), nil
+}
+
+// reconcileIAMRole creates an iam role of a cluster which can be assumed as described by the trust policy, and sets
+// its inline policy. the role is created under the path of the cluster, with the permissions boundary of the cluster.
+// the trust policy and permissions boundary of an existing role are updated, the arn of the role is returned
+func reconcileIAMRole(iamSvc iamiface.IAMAPI, clusterID, roleName, trustPolicy, policyName, policy string, tags []*iam.Tag) (string, error) {
+	boundaryArn, err := reconcileIAMRoleBoundary(iamSvc, clusterID)
+	if err != nil {
+		return "", err
+	}
+	var role *iam.Role
+	getOutput, err := iamSvc.GetRole(&iam.GetRoleInput{
+		RoleName: aws.String(roleName),
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); !ok || awsErr.Code() != iam.ErrCodeNoSuchEntityException {
+			return "", errorUtil.Wrapf(err, "failed to get iam role %s", roleName)
+		}
+		createInput := &iam.CreateRoleInput{
+			RoleName:                 aws.String(roleName),
+			Path:                     aws.String(buildIAMPath(clusterID)),
+			AssumeRolePolicyDocument: aws.String(trustPolicy),
+			Tags:                     tags,
+		}
+		if boundaryArn != "" {
+			createInput.PermissionsBoundary = aws.String(boundaryArn)
+		}
+		createOutput, err := iamSvc.CreateRole(createInput)
+		if err != nil {
+			return "", errorUtil.Wrapf(err, "failed to create iam role %s", roleName)
+		}
+		role = createOutput.Role
+	} else {
+		role = getOutput.Role
+		if _, err := iamSvc.UpdateAssumeRolePolicy(&iam.UpdateAssumeRolePolicyInput{
+			RoleName:       aws.String(roleName),
+			PolicyDocument: aws.String(trustPolicy),
+		}); err != nil {
+			return "", errorUtil.Wrapf(err, "failed to update trust policy of iam role %s", roleName)
+		}
+		if boundaryArn != "" && (role.PermissionsBoundary == nil || aws.StringValue(role.PermissionsBoundary.PermissionsBoundaryArn) != boundaryArn) {
+			if _, err := iamSvc.PutRolePermissionsBoundary(&iam.PutRolePermissionsBoundaryInput{
+				RoleName:            aws.String(roleName),
+				PermissionsBoundary: aws.String(boundaryArn),
+			}); err != nil {
+				return "", errorUtil.Wrapf(err, "failed to set permissions boundary of iam role %s", roleName)
+			}
+		}
+	}
+	if _, err := iamSvc.PutRolePolicy(&iam.PutRolePolicyInput{
+		RoleName:       aws.String(roleName),
+		PolicyName:     aws.String(policyName),
+		PolicyDocument: aws.String(policy),
+	}); err != nil {
+		return "", errorUtil.Wrapf(err, "failed to put policy %s of iam role %s", policyName, roleName)
+	}
+	// no role is described in dry run mode
+	if role == nil {
+		return "", nil
+	}
+	return aws.StringValue(role.Arn), nil
+}
+
+// deleteIAMRole deletes an iam role and its inline policy, a role which does not exist is ignored
+func deleteIAMRole(iamSvc iamiface.IAMAPI, roleName, policyName string) error {
+	if _, err := iamSvc.DeleteRolePolicy(&iam.DeleteRolePolicyInput{
+		RoleName:   aws.String(roleName),
+		PolicyName: aws.String(policyName),
+	}); err != nil {
+		if awsErr, ok := err.(awserr.Error); !ok || awsErr.Code() != iam.ErrCodeNoSuchEntityException {
+			return errorUtil.Wrapf(err, "failed to delete policy %s of iam role %s", policyName, roleName)
+		}
+	}
+	if _, err := iamSvc.DeleteRole(&iam.DeleteRoleInput{
+		RoleName: aws.String(roleName),
+	}); err != nil {
+		if awsErr, ok := err.(awserr.Error); !ok || awsErr.Code() != iam.ErrCodeNoSuchEntityException {
+			return errorUtil.Wrapf(err, "failed to delete iam role %s", roleName)
+		}
+	}
+	return nil
+}
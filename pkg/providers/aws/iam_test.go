@@ -0,0 +1,58 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+)
+
+func Test_reconcileIAMRole(t *testing.T) {
+	boundaryArn := "arn:aws:iam::test:policy" + buildIAMPath("test") + defaultRoleBoundaryPolicyName
+	tests := []struct {
+		name   string
+		iamSvc *mockIAMClient
+	}{
+		{
+			name:   "test role is created under the path of the cluster with the permissions boundary",
+			iamSvc: buildMockIAMClient(),
+		},
+		{
+			name: "test permissions boundary is set on an existing role",
+			iamSvc: func() *mockIAMClient {
+				iamSvc := buildMockIAMClient()
+				iamSvc.roles["test-role"] = &iam.Role{
+					RoleName: aws.String("test-role"),
+					Arn:      aws.String("arn:aws:iam::test:role" + buildIAMPath("test") + "test-role"),
+				}
+				return iamSvc
+			}(),
+		},
+		{
+			name: "test existing permissions boundary is used",
+			iamSvc: func() *mockIAMClient {
+				iamSvc := buildMockIAMClient()
+				iamSvc.managedPolicies = []*iam.Policy{{PolicyName: aws.String(defaultRoleBoundaryPolicyName), Arn: aws.String(boundaryArn)}}
+				return iamSvc
+			}(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roleArn, err := reconcileIAMRole(tt.iamSvc, "test", "test-role", "{}", "test-policy", "{}", nil)
+			if err != nil {
+				t.Fatal("reconcileIAMRole() returned an error", err)
+			}
+			if want := "arn:aws:iam::test:role" + buildIAMPath("test") + "test-role"; roleArn != want {
+				t.Errorf("reconcileIAMRole() role = %v, want %v", roleArn, want)
+			}
+			role := tt.iamSvc.roles["test-role"]
+			if role.PermissionsBoundary == nil || aws.StringValue(role.PermissionsBoundary.PermissionsBoundaryArn) != boundaryArn {
+				t.Errorf("reconcileIAMRole() permissions boundary = %v, want %v", role.PermissionsBoundary, boundaryArn)
+			}
+			if len(tt.iamSvc.managedPolicies) != 1 {
+				t.Errorf("reconcileIAMRole() managed policies = %v, want one permissions boundary", tt.iamSvc.managedPolicies)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/integr8ly/cloud-resource-operator/pkg/providers"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	errorUtil "github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultKMSAliasPrefix    = "alias/"
+	defaultKMSKeyDescription = "cloud resource operator %s encryption key for cluster %s"
+	// shortest waiting period before a kms key is deleted
+	defaultKMSKeyDeletionWindowDays = 7
+)
+
+// KMSKeyStrategy configures the customer managed kms key resources of a tier are encrypted with. an existing key is
+// used when KeyID is set, otherwise a key per cluster and resource type is created when Create is set
+type KMSKeyStrategy struct {
+	KeyID  string `json:"keyId,omitempty"`
+	Create bool   `json:"create,omitempty"`
+}
+
+// reconcileKMSKey returns the arn of the customer managed kms key described by the strategy, creating it if requested.
+// an empty arn is returned when no strategy is set, in which case the aws managed key is used
+func reconcileKMSKey(ctx context.Context, c client.Client, kmsSvc kmsiface.KMSAPI, rt providers.ResourceType, strategy *KMSKeyStrategy) (string, error) {
+	if strategy == nil || (strategy.KeyID == "" && !strategy.Create) {
+		return "", nil
+	}
+	if strategy.KeyID != "" {
+		key, err := describeKMSKey(kmsSvc, strategy.KeyID)
+		if err != nil {
+			return "", errorUtil.Wrapf(err, "failed to describe kms key %s", strategy.KeyID)
+		}
+		if key == nil {
+			return "", errorUtil.New(fmt.Sprintf("kms key %s not found", strategy.KeyID))
+		}
+		if aws.StringValue(key.KeyState) != kms.KeyStateEnabled {
+			return "", errorUtil.New(fmt.Sprintf("kms key %s is %s", strategy.KeyID, aws.StringValue(key.KeyState)))
+		}
+		return aws.StringValue(key.Arn), nil
+	}
+
+	// keys are created once per cluster and resource type and found again by their alias
+	aliasName, err := BuildInfraName(ctx, c, string(rt), DefaultAwsIdentifierLength)
+	if err != nil {
+		return "", errorUtil.Wrap(err, "failed to build kms key alias")
+	}
+	alias := defaultKMSAliasPrefix + aliasName
+	key, err := describeKMSKey(kmsSvc, alias)
+	if err != nil {
+		return "", errorUtil.Wrapf(err, "failed to describe kms key %s", alias)
+	}
+	if key != nil {
+		return aws.StringValue(key.Arn), nil
+	}
+
+	clusterID, err := resources.GetClusterID(ctx, c)
+	if err != nil {
+		return "", errorUtil.Wrap(err, "failed to get cluster id")
+	}
+	defaultOrganizationTag := resources.GetOrganizationTag()
+	created, err := kmsSvc.CreateKey(&kms.CreateKeyInput{
+		Description: aws.String(fmt.Sprintf(defaultKMSKeyDescription, rt, clusterID)),
+		Tags: []*kms.Tag{
+			{
+				TagKey:   aws.String(defaultOrganizationTag + "clusterID"),
+				TagValue: aws.String(clusterID),
+			},
+		},
+	})
+	if err != nil {
+		return "", errorUtil.Wrapf(err, "failed to create kms key for %s", alias)
+	}
+	// no key is created in dry run mode
+	if created.KeyMetadata == nil {
+		return "", nil
+	}
+	// the alias is created last, a key found by its alias is fully configured. a key which fails to be configured is
+	// scheduled for deletion, it can not be found again and would be leaked by the next attempt
+	keyID := aws.StringValue(created.KeyMetadata.KeyId)
+	if _, err = kmsSvc.EnableKeyRotation(&kms.EnableKeyRotationInput{
+		KeyId: created.KeyMetadata.KeyId,
+	}); err != nil {
+		return "", scheduleKMSKeyDeletion(kmsSvc, keyID, errorUtil.Wrapf(err, "failed to enable rotation of kms key %s", keyID))
+	}
+	if _, err = kmsSvc.CreateAlias(&kms.CreateAliasInput{
+		AliasName:   aws.String(alias),
+		TargetKeyId: created.KeyMetadata.KeyId,
+	}); err != nil {
+		return "", scheduleKMSKeyDeletion(kmsSvc, keyID, errorUtil.Wrapf(err, "failed to create kms key alias %s", alias))
+	}
+	return aws.StringValue(created.KeyMetadata.Arn), nil
+}
+
+// scheduleKMSKeyDeletion schedules the deletion of a kms key which failed to be configured after the shortest waiting
+// period, cause is returned with any error scheduling the deletion
+func scheduleKMSKeyDeletion(kmsSvc kmsiface.KMSAPI, keyID string, cause error) error {
+	if _, err := kmsSvc.ScheduleKeyDeletion(&kms.ScheduleKeyDeletionInput{
+		KeyId:               aws.String(keyID),
+		PendingWindowInDays: aws.Int64(defaultKMSKeyDeletionWindowDays),
+	}); err != nil {
+		return errorUtil.Wrapf(cause, "failed to schedule deletion of kms key %s: %v", keyID, err)
+	}
+	return cause
+}
+
+func describeKMSKey(kmsSvc kmsiface.KMSAPI, keyID string) (*kms.KeyMetadata, error) {
+	out, err := kmsSvc.DescribeKey(&kms.DescribeKeyInput{
+		KeyId: aws.String(keyID),
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == kms.ErrCodeNotFoundException {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return out.KeyMetadata, nil
+}
@@ -0,0 +1,149 @@
+package aws
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/integr8ly/cloud-resource-operator/pkg/providers"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type mockKMSClient struct {
+	kmsiface.KMSAPI
+	keys           map[string]*kms.KeyMetadata
+	createdAliases []string
+	rotatedKeys    []string
+	deletedKeys    []string
+	aliasErr       error
+}
+
+func (m *mockKMSClient) DescribeKey(input *kms.DescribeKeyInput) (*kms.DescribeKeyOutput, error) {
+	key, ok := m.keys[*input.KeyId]
+	if !ok {
+		return nil, awserr.New(kms.ErrCodeNotFoundException, "key not found", nil)
+	}
+	return &kms.DescribeKeyOutput{KeyMetadata: key}, nil
+}
+
+func (m *mockKMSClient) CreateKey(*kms.CreateKeyInput) (*kms.CreateKeyOutput, error) {
+	return &kms.CreateKeyOutput{
+		KeyMetadata: &kms.KeyMetadata{
+			KeyId: aws.String("created"),
+			Arn:   aws.String("arn:aws:kms:eu-west-1:test:key/created"),
+		},
+	}, nil
+}
+
+func (m *mockKMSClient) CreateAlias(input *kms.CreateAliasInput) (*kms.CreateAliasOutput, error) {
+	if m.aliasErr != nil {
+		return nil, m.aliasErr
+	}
+	m.createdAliases = append(m.createdAliases, *input.AliasName)
+	return &kms.CreateAliasOutput{}, nil
+}
+
+func (m *mockKMSClient) EnableKeyRotation(input *kms.EnableKeyRotationInput) (*kms.EnableKeyRotationOutput, error) {
+	m.rotatedKeys = append(m.rotatedKeys, *input.KeyId)
+	return &kms.EnableKeyRotationOutput{}, nil
+}
+
+func (m *mockKMSClient) ScheduleKeyDeletion(input *kms.ScheduleKeyDeletionInput) (*kms.ScheduleKeyDeletionOutput, error) {
+	m.deletedKeys = append(m.deletedKeys, *input.KeyId)
+	return &kms.ScheduleKeyDeletionOutput{}, nil
+}
+
+func buildTestKMSKey(id, state string) *kms.KeyMetadata {
+	return &kms.KeyMetadata{
+		KeyId:    aws.String(id),
+		Arn:      aws.String("arn:aws:kms:eu-west-1:test:key/" + id),
+		KeyState: aws.String(state),
+	}
+}
+
+func TestReconcileKMSKey(t *testing.T) {
+	scheme, err := buildTestScheme()
+	if err != nil {
+		t.Fatal("failed to build scheme", err)
+	}
+	tests := []struct {
+		name        string
+		strategy    *KMSKeyStrategy
+		kmsSvc      *mockKMSClient
+		want        string
+		wantAliases []string
+		wantDeleted []string
+		wantErr     bool
+	}{
+		{
+			name:     "test no key when strategy is not set",
+			strategy: nil,
+			kmsSvc:   &mockKMSClient{},
+			want:     "",
+		},
+		{
+			name:     "test arn of configured key is returned",
+			strategy: &KMSKeyStrategy{KeyID: "alias/test"},
+			kmsSvc:   &mockKMSClient{keys: map[string]*kms.KeyMetadata{"alias/test": buildTestKMSKey("existing", kms.KeyStateEnabled)}},
+			want:     "arn:aws:kms:eu-west-1:test:key/existing",
+		},
+		{
+			name:     "test error when configured key is not found",
+			strategy: &KMSKeyStrategy{KeyID: "alias/test"},
+			kmsSvc:   &mockKMSClient{},
+			wantErr:  true,
+		},
+		{
+			name:     "test error when configured key is disabled",
+			strategy: &KMSKeyStrategy{KeyID: "alias/test"},
+			kmsSvc:   &mockKMSClient{keys: map[string]*kms.KeyMetadata{"alias/test": buildTestKMSKey("existing", kms.KeyStateDisabled)}},
+			wantErr:  true,
+		},
+		{
+			name:        "test key is created with an alias per cluster and resource type",
+			strategy:    &KMSKeyStrategy{Create: true},
+			kmsSvc:      &mockKMSClient{},
+			want:        "arn:aws:kms:eu-west-1:test:key/created",
+			wantAliases: []string{"alias/testpostgres"},
+		},
+		{
+			name:     "test created key is found by its alias",
+			strategy: &KMSKeyStrategy{Create: true},
+			kmsSvc:   &mockKMSClient{keys: map[string]*kms.KeyMetadata{"alias/testpostgres": buildTestKMSKey("created", kms.KeyStateEnabled)}},
+			want:     "arn:aws:kms:eu-west-1:test:key/created",
+		},
+		{
+			name:        "test created key is scheduled for deletion when its alias fails to be created",
+			strategy:    &KMSKeyStrategy{Create: true},
+			kmsSvc:      &mockKMSClient{aliasErr: awserr.New(kms.ErrCodeLimitExceededException, "limit exceeded", nil)},
+			wantDeleted: []string{"created"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := reconcileKMSKey(context.TODO(), fake.NewFakeClientWithScheme(scheme, buildTestInfra()), tt.kmsSvc, providers.PostgresResourceType, tt.strategy)
+			if !reflect.DeepEqual(tt.kmsSvc.deletedKeys, tt.wantDeleted) {
+				t.Errorf("reconcileKMSKey() deleted keys = %v, want %v", tt.kmsSvc.deletedKeys, tt.wantDeleted)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("reconcileKMSKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("reconcileKMSKey() got = %v, want %v", got, tt.want)
+			}
+			if len(tt.kmsSvc.createdAliases) != len(tt.wantAliases) || (len(tt.wantAliases) != 0 && tt.kmsSvc.createdAliases[0] != tt.wantAliases[0]) {
+				t.Errorf("reconcileKMSKey() created aliases = %v, want %v", tt.kmsSvc.createdAliases, tt.wantAliases)
+			}
+			if len(tt.wantAliases) != 0 && len(tt.kmsSvc.rotatedKeys) != 1 {
+				t.Errorf("reconcileKMSKey() rotation not enabled for created key")
+			}
+		})
+	}
+}
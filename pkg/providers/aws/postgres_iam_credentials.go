@@ -0,0 +1,131 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	"github.com/integr8ly/cloud-resource-operator/pkg/providers"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	errorUtil "github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	postgresIAMCredentialProviderName = "aws-rds-iam"
+	defaultIAMCredSuffix              = "-aws-rds-iam-credentials"
+)
+
+var _ providers.PostgresIAMCredentialProvider = (*PostgresIAMCredentialProvider)(nil)
+
+// PostgresIAMCredentialProvider mints credentials per postgres user, which are only allowed to connect to an rds
+// instance as that user. the credentials of a single user can be revoked by deleting its credentials request
+type PostgresIAMCredentialProvider struct {
+	Client            client.Client
+	Logger            *logrus.Entry
+	CredentialManager CredentialManager
+	ConfigManager     ConfigManager
+}
+
+func NewAWSPostgresIAMCredentialProvider(client client.Client, logger *logrus.Entry) *PostgresIAMCredentialProvider {
+	return &PostgresIAMCredentialProvider{
+		Client:            client,
+		Logger:            logger.WithFields(logrus.Fields{"provider": postgresIAMCredentialProviderName}),
+		CredentialManager: NewCredentialMinterCredentialManager(client),
+		ConfigManager:     NewDefaultConfigMapConfigManager(client),
+	}
+}
+
+func (p *PostgresIAMCredentialProvider) GetName() string {
+	return postgresIAMCredentialProviderName
+}
+
+func (p *PostgresIAMCredentialProvider) SupportsStrategy(d string) bool {
+	return d == providers.AWSDeploymentStrategy
+}
+
+func (p *PostgresIAMCredentialProvider) ReconcileIAMCredentials(ctx context.Context, ps *v1alpha1.Postgres, pu *v1alpha1.PostgresUser, role string) (*providers.PostgresIAMCredentials, croType.StatusMessage, error) {
+	stratCfg, err := p.ConfigManager.ReadStorageStrategy(ctx, providers.PostgresResourceType, ps.Spec.Tier)
+	if err != nil {
+		errMsg := "failed to read aws strategy config"
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	region, err := GetRegionFromStrategyOrDefault(ctx, p.Client, stratCfg)
+	if err != nil {
+		errMsg := "failed to get region for rds instance"
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
+	providerCreds, err := p.CredentialManager.ReconcileProviderCredentials(ctx, ps.Namespace)
+	if err != nil {
+		errMsg := "failed to reconcile rds credentials"
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	sess, err := CreateSessionFromStrategy(ctx, p.Client, providerCreds.AccessKeyID, providerCreds.SecretAccessKey, stratCfg)
+	if err != nil {
+		errMsg := "failed to create aws session to get rds db instance"
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	return p.reconcileIAMCredentials(ctx, ps, pu, role, region, rds.New(sess), sts.New(sess))
+}
+
+func (p *PostgresIAMCredentialProvider) reconcileIAMCredentials(ctx context.Context, ps *v1alpha1.Postgres, pu *v1alpha1.PostgresUser, role, region string, rdsSvc rdsiface.RDSAPI, stsSvc stsiface.STSAPI) (*providers.PostgresIAMCredentials, croType.StatusMessage, error) {
+	instanceID := ps.Annotations[resourceIdentifierAnnotation]
+	if instanceID == "" {
+		errMsg := fmt.Sprintf("postgres instance %s has no %s annotation", ps.Name, resourceIdentifierAnnotation)
+		return nil, croType.StatusMessage(errMsg), errorUtil.New(errMsg)
+	}
+	instances, err := rdsSvc.DescribeDBInstances(&rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: aws.String(instanceID),
+	})
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to describe rds instance %s", instanceID)
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	if len(instances.DBInstances) == 0 {
+		errMsg := fmt.Sprintf("rds instance %s not found", instanceID)
+		return nil, croType.StatusMessage(errMsg), errorUtil.New(errMsg)
+	}
+	instance := instances.DBInstances[0]
+	if !aws.BoolValue(instance.IAMDatabaseAuthenticationEnabled) {
+		errMsg := fmt.Sprintf("iam database authentication is not enabled for rds instance %s", instanceID)
+		return nil, croType.StatusMessage(errMsg), errorUtil.New(errMsg)
+	}
+
+	id, err := stsSvc.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		errMsg := "failed to get account identity"
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
+	// need arn in the following format arn:aws:rds-db:us-east-1:1234567890:dbuser:db-ABCDEFGHIJKL01234/db_user
+	dbUserArn := fmt.Sprintf("arn:aws:rds-db:%s:%s:dbuser:%s/%s", region, aws.StringValue(id.Account), aws.StringValue(instance.DbiResourceId), role)
+	credsName := buildIAMCredentialsName(pu)
+	p.Logger.Infof("reconciling iam credentials %s for role %s on rds instance %s", credsName, role, instanceID)
+	_, creds, err := p.CredentialManager.ReconcileCredentials(ctx, credsName, pu.Namespace, buildRDSConnectEntries(dbUserArn))
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to reconcile iam credentials for role %s", role)
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	return &providers.PostgresIAMCredentials{
+		Region:          region,
+		AccessKeyID:     creds.AccessKeyID,
+		SecretAccessKey: creds.SecretAccessKey,
+	}, croType.StatusEmpty, nil
+}
+
+// DeleteIAMCredentials removes the credentials request of a postgres user, revoking its access to the rds instance
+func (p *PostgresIAMCredentialProvider) DeleteIAMCredentials(ctx context.Context, pu *v1alpha1.PostgresUser) error {
+	return deleteCredentialsRequest(ctx, p.Client, buildIAMCredentialsName(pu), pu.Namespace)
+}
+
+func buildIAMCredentialsName(pu *v1alpha1.PostgresUser) string {
+	return pu.Name + defaultIAMCredSuffix
+}
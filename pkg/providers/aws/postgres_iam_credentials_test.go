@@ -0,0 +1,154 @@
+package aws
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/cloud-resource-operator/pkg/providers"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+	v1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func buildTestPostgresUserCR() *v1alpha1.PostgresUser {
+	return &v1alpha1.PostgresUser{
+		ObjectMeta: controllerruntime.ObjectMeta{
+			Name:      "test-user",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.PostgresUserSpec{
+			ResourceName:      "test",
+			DatabaseName:      "test_db",
+			IAMAuthentication: true,
+		},
+	}
+}
+
+func buildIAMEnabledDBInstance(testID string) []*rds.DBInstance {
+	instances := buildAvailableDBInstance(testID)
+	instances[0].IAMDatabaseAuthenticationEnabled = aws.Bool(true)
+	instances[0].DbiResourceId = aws.String("db-test")
+	return instances
+}
+
+func buildTestIAMCredentialManager(wantEntries []v1.StatementEntry) *CredentialManagerMock {
+	return &CredentialManagerMock{
+		ReconcileCredentialsFunc: func(ctx context.Context, name string, ns string, entries []v1.StatementEntry) (*v1.CredentialsRequest, *Credentials, error) {
+			if !reflect.DeepEqual(entries, wantEntries) {
+				return nil, nil, k8serr.NewBadRequest("unexpected statement entries")
+			}
+			return &v1.CredentialsRequest{}, &Credentials{
+				AccessKeyID:     "test-id",
+				SecretAccessKey: "test-key",
+			}, nil
+		},
+	}
+}
+
+func TestPostgresIAMCredentialProvider_reconcileIAMCredentials(t *testing.T) {
+	testIdentifier := "test-identifier"
+	buildAnnotatedPostgresCR := func() *v1alpha1.Postgres {
+		ps := buildTestPostgresCR()
+		ps.Annotations = map[string]string{resourceIdentifierAnnotation: testIdentifier}
+		return ps
+	}
+	tests := []struct {
+		name    string
+		ps      *v1alpha1.Postgres
+		rdsSvc  *mockRdsClient
+		want    *providers.PostgresIAMCredentials
+		wantErr bool
+	}{
+		{
+			name:   "test credentials are minted for the rds db user",
+			ps:     buildAnnotatedPostgresCR(),
+			rdsSvc: &mockRdsClient{dbInstances: buildIAMEnabledDBInstance(testIdentifier)},
+			want: &providers.PostgresIAMCredentials{
+				Region:          "eu-west-1",
+				AccessKeyID:     "test-id",
+				SecretAccessKey: "test-key",
+			},
+		},
+		{
+			name:    "test error when iam database authentication is not enabled",
+			ps:      buildAnnotatedPostgresCR(),
+			rdsSvc:  &mockRdsClient{dbInstances: buildAvailableDBInstance(testIdentifier)},
+			wantErr: true,
+		},
+		{
+			name:    "test error when rds instance is not found",
+			ps:      buildAnnotatedPostgresCR(),
+			rdsSvc:  &mockRdsClient{wantEmpty: true},
+			wantErr: true,
+		},
+		{
+			name:    "test error when postgres instance has no resource identifier",
+			ps:      buildTestPostgresCR(),
+			rdsSvc:  &mockRdsClient{dbInstances: buildIAMEnabledDBInstance(testIdentifier)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &PostgresIAMCredentialProvider{
+				Logger:            testLogger,
+				CredentialManager: buildTestIAMCredentialManager(buildRDSConnectEntries("arn:aws:rds-db:eu-west-1:test:dbuser:db-test/test_user")),
+			}
+			got, _, err := p.reconcileIAMCredentials(context.TODO(), tt.ps, buildTestPostgresUserCR(), "test_user", "eu-west-1", tt.rdsSvc, &mockStsClient{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("reconcileIAMCredentials() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("reconcileIAMCredentials() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostgresIAMCredentialProvider_DeleteIAMCredentials(t *testing.T) {
+	scheme, err := buildTestSchemePostgresql()
+	if err != nil {
+		t.Fatal("failed to build scheme", err)
+	}
+	pu := buildTestPostgresUserCR()
+	credsReq := &v1.CredentialsRequest{
+		ObjectMeta: controllerruntime.ObjectMeta{
+			Name:      buildIAMCredentialsName(pu),
+			Namespace: pu.Namespace,
+		},
+	}
+	tests := []struct {
+		name    string
+		p       *PostgresIAMCredentialProvider
+		wantErr bool
+	}{
+		{
+			name: "test credentials request is deleted",
+			p:    &PostgresIAMCredentialProvider{Client: fake.NewFakeClientWithScheme(scheme, credsReq.DeepCopy()), Logger: testLogger},
+		},
+		{
+			name: "test no error when credentials request is already deleted",
+			p:    &PostgresIAMCredentialProvider{Client: fake.NewFakeClientWithScheme(scheme), Logger: testLogger},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.p.DeleteIAMCredentials(context.TODO(), pu); (err != nil) != tt.wantErr {
+				t.Errorf("DeleteIAMCredentials() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			err := tt.p.Client.Get(context.TODO(), types.NamespacedName{Name: credsReq.Name, Namespace: credsReq.Namespace}, &v1.CredentialsRequest{})
+			if !k8serr.IsNotFound(err) {
+				t.Errorf("DeleteIAMCredentials() credentials request still exists, err = %v", err)
+			}
+		})
+	}
+}
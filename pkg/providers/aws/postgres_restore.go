@@ -0,0 +1,76 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+
+	errorUtil "github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// BuildRestoredPostgres builds the postgres resource which manages an instance restored from another, along with the
+// identifier the restored instance must use. the resource is annotated with the identifier so the provider adopts the
+// restored instance instead of creating a new one
+func BuildRestoredPostgres(ctx context.Context, c client.Client, source *v1alpha1.Postgres, name string, secretRef *croType.SecretRef) (*v1alpha1.Postgres, string, error) {
+	labels := map[string]string{}
+	for k, v := range source.Labels {
+		labels[k] = v
+	}
+	target := &v1alpha1.Postgres{
+		ObjectMeta: controllerruntime.ObjectMeta{
+			Name:      name,
+			Namespace: source.Namespace,
+			Labels:    labels,
+		},
+		Spec: v1alpha1.PostgresSpec{
+			Type:      source.Spec.Type,
+			Tier:      source.Spec.Tier,
+			SecretRef: secretRef,
+		},
+	}
+	instanceID, err := BuildInfraNameFromObject(ctx, c, target.ObjectMeta, DefaultAwsIdentifierLength)
+	if err != nil {
+		return nil, "", errorUtil.Wrap(err, "failed to build restored rds instance identifier")
+	}
+	target.Annotations = map[string]string{
+		resourceIdentifierAnnotation: instanceID,
+	}
+	return target, instanceID, nil
+}
+
+// ReconcileRestoredRDSCredentials ensures the credentials secret of a restored postgres resource contains the master
+// credentials of the source instance, which are kept by the restored instance
+func ReconcileRestoredRDSCredentials(ctx context.Context, c client.Client, source *v1alpha1.Postgres, target *v1alpha1.Postgres) error {
+	sourceSec := &v1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Name: source.Name + defaultCredSecSuffix, Namespace: source.Namespace}, sourceSec); err != nil {
+		return errorUtil.Wrapf(err, "failed to get rds credential secret of postgres instance %s", source.Name)
+	}
+	sec := &v1.Secret{
+		ObjectMeta: controllerruntime.ObjectMeta{
+			Name:      target.Name + defaultCredSecSuffix,
+			Namespace: target.Namespace,
+		},
+		Type: v1.SecretTypeOpaque,
+	}
+	or, err := controllerutil.CreateOrUpdate(ctx, c, sec, func() error {
+		// the password may have since been rotated by the provider of the restored instance
+		if len(sec.Data[defaultPostgresPasswordKey]) != 0 {
+			return nil
+		}
+		sec.Data = map[string][]byte{
+			defaultPostgresUserKey:     sourceSec.Data[defaultPostgresUserKey],
+			defaultPostgresPasswordKey: sourceSec.Data[defaultPostgresPasswordKey],
+		}
+		return nil
+	})
+	if err != nil {
+		return errorUtil.Wrapf(err, "failed to create or update secret %s, action was %s", sec.Name, or)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package aws
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/cloud-resource-operator/pkg/providers"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+
+	// register the postgres driver used to connect with the master credentials
+	_ "github.com/lib/pq"
+	errorUtil "github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	postgresSQLRunnerName = "aws-rds-sql"
+	postgresDriverName    = "postgres"
+)
+
+var _ providers.PostgresSQLRunner = (*PostgresSQLRunner)(nil)
+
+// PostgresSQLRunner connects to an rds instance over the network using the master credentials from its result secret
+type PostgresSQLRunner struct {
+	Client client.Client
+	Logger *logrus.Entry
+}
+
+func NewAWSPostgresSQLRunner(client client.Client, logger *logrus.Entry) *PostgresSQLRunner {
+	return &PostgresSQLRunner{
+		Client: client,
+		Logger: logger.WithFields(logrus.Fields{"provider": postgresSQLRunnerName}),
+	}
+}
+
+func (r *PostgresSQLRunner) GetName() string {
+	return postgresSQLRunnerName
+}
+
+func (r *PostgresSQLRunner) SupportsStrategy(d string) bool {
+	return d == providers.AWSDeploymentStrategy
+}
+
+func (r *PostgresSQLRunner) DatabaseExists(ctx context.Context, ps *v1alpha1.Postgres, database string) (bool, error) {
+	db, err := r.openDatabase(ctx, ps, "")
+	if err != nil {
+		return false, err
+	}
+	defer db.Close()
+
+	exists := false
+	if err := db.QueryRowContext(ctx, "SELECT EXISTS (SELECT FROM pg_catalog.pg_database WHERE datname = $1)", database).Scan(&exists); err != nil {
+		return false, errorUtil.Wrapf(err, "failed to check if database %s exists", database)
+	}
+	return exists, nil
+}
+
+func (r *PostgresSQLRunner) ExecStatements(ctx context.Context, ps *v1alpha1.Postgres, database string, stmts []string) error {
+	// statements change the roles and databases of the instance, they are not executed in dry run mode
+	if resources.IsDryRun() {
+		resources.RecordDryRunAction(ctx, "postgres:ExecStatements")
+		return nil
+	}
+	db, err := r.openDatabase(ctx, ps, database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// statements are run one at a time as statements such as create database can not be run in a transaction
+	for _, stmt := range stmts {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return errorUtil.Wrapf(err, "failed to execute statement in database %s", database)
+		}
+	}
+	return nil
+}
+
+func (r *PostgresSQLRunner) openDatabase(ctx context.Context, ps *v1alpha1.Postgres, database string) (*sql.DB, error) {
+	master, err := providers.GetPostgresDeploymentDetails(ctx, r.Client, ps)
+	if err != nil {
+		return nil, errorUtil.Wrap(err, "failed to get master credentials")
+	}
+	if database == "" {
+		database = master.Database
+	}
+	db, err := sql.Open(postgresDriverName, buildPostgresConnectionString(master, database))
+	if err != nil {
+		return nil, errorUtil.Wrapf(err, "failed to open connection to database %s", database)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, errorUtil.Wrapf(err, "failed to connect to database %s on %s", database, master.Host)
+	}
+	return db, nil
+}
+
+// buildPostgresConnectionString builds a key/value connection string, quoting values so passwords can contain any character
+func buildPostgresConnectionString(details *providers.PostgresDeploymentDetails, database string) string {
+	quote := func(v string) string {
+		return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
+	}
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=require", quote(details.Host), details.Port, quote(details.Username), quote(details.Password), quote(database))
+}
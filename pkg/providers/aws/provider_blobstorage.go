@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/integr8ly/cloud-resource-operator/pkg/annotations"
 	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
@@ -12,12 +13,6 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
 
-	"github.com/aws/aws-sdk-go/service/s3/s3manager"
-
-	v1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
-	controllerruntime "sigs.k8s.io/controller-runtime"
-
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -28,7 +23,11 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/kms"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/sts"
 
 	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
 	errorUtil "github.com/pkg/errors"
@@ -43,8 +42,16 @@ const (
 	DetailsBlobStorageBucketRegion        = "bucketRegion"
 	DetailsBlobStorageCredentialKeyID     = "credentialKeyID"
 	DetailsBlobStorageCredentialSecretKey = "credentialSecretKey"
+	DetailsBlobStorageSessionToken        = "credentialSessionToken"
+	DetailsBlobStorageExpiration          = "credentialExpiration"
+	DetailsBlobStorageRoleArn             = "roleArn"
+	DetailsBlobStorageTokenFile           = "webIdentityTokenFile"
 	defaultForceBucketDeletion            = false
 
+	// buckets are emptied in batches of pages of object versions, each page of at most 1000 object versions
+	defaultEmptyBucketBatches = 20
+	emptyBucketReconcileTime  = time.Second * 10
+
 	// bucket accessibility defaults
 	defaultBlockPublicAcls       = true
 	defaultBlockPublicPolicy     = true
@@ -53,6 +60,17 @@ const (
 
 	// bucket encryption defaults
 	defaultEncryptionSSEAlgorithm = s3.ServerSideEncryptionAes256
+
+	dryRunBucketCreateMsg croType.StatusMessage = "dry run, s3 bucket would be created"
+
+	// s3 error codes which are not defined by the sdk
+	s3ErrCodeNoSuchPublicAccessBlockConfiguration      = "NoSuchPublicAccessBlockConfiguration"
+	s3ErrCodeServerSideEncryptionConfigurationNotFound = "ServerSideEncryptionConfigurationNotFoundError"
+	s3ErrCodeNoSuchTagSet                              = "NoSuchTagSet"
+	s3ErrCodeNoSuchLifecycleConfiguration              = "NoSuchLifecycleConfiguration"
+	s3ErrCodeReplicationConfigurationNotFound          = "ReplicationConfigurationNotFoundError"
+	s3ErrCodeNoSuchCORSConfiguration                   = "NoSuchCORSConfiguration"
+	s3ErrCodeNoSuchBucketPolicy                        = "NoSuchBucketPolicy"
 )
 
 // BlobStorageDeploymentDetails Provider-specific details about the AWS S3 bucket created
@@ -61,15 +79,28 @@ type BlobStorageDeploymentDetails struct {
 	BucketRegion        string
 	CredentialKeyID     string
 	CredentialSecretKey string
+	// RoleCredentials are set when the credentials are issued for an iam role of the bucket
+	RoleCredentials *RoleCredentials
 }
 
 func (d *BlobStorageDeploymentDetails) Data() map[string][]byte {
-	return map[string][]byte{
+	data := map[string][]byte{
 		DetailsBlobStorageBucketName:          []byte(d.BucketName),
 		DetailsBlobStorageBucketRegion:        []byte(d.BucketRegion),
 		DetailsBlobStorageCredentialKeyID:     []byte(d.CredentialKeyID),
 		DetailsBlobStorageCredentialSecretKey: []byte(d.CredentialSecretKey),
 	}
+	if d.RoleCredentials != nil {
+		data[DetailsBlobStorageRoleArn] = []byte(d.RoleCredentials.RoleArn)
+		if d.RoleCredentials.SessionToken != "" {
+			data[DetailsBlobStorageSessionToken] = []byte(d.RoleCredentials.SessionToken)
+			data[DetailsBlobStorageExpiration] = []byte(d.RoleCredentials.Expiration)
+		}
+		if d.RoleCredentials.WebIdentityTokenFile != "" {
+			data[DetailsBlobStorageTokenFile] = []byte(d.RoleCredentials.WebIdentityTokenFile)
+		}
+	}
+	return data
 }
 
 var _ providers.BlobStorageProvider = (*BlobStorageProvider)(nil)
@@ -100,6 +131,10 @@ func (p *BlobStorageProvider) SupportsStrategy(d string) bool {
 }
 
 func (p *BlobStorageProvider) GetReconcileTime(bs *v1alpha1.BlobStorage) time.Duration {
+	// a bucket which is being emptied is deleted in batches, which are reconciled without waiting
+	if bs.Status.Deletion != nil && bs.GetDeletionTimestamp() != nil {
+		return emptyBucketReconcileTime
+	}
 	if bs.Status.Phase != croType.PhaseComplete {
 		return time.Second * 60
 	}
@@ -115,6 +150,18 @@ type S3DeleteStrat struct {
 
 // CreateStorage Create S3 bucket from strategy config and credentials to interact with it
 func (p *BlobStorageProvider) CreateStorage(ctx context.Context, bs *v1alpha1.BlobStorage) (*providers.BlobStorageInstance, croType.StatusMessage, error) {
+	// s3 buckets can not be snapshotted, unsupported deletion policies are rejected before the bucket is created
+	if err := resources.ValidateDeletionPolicy(bs.Spec.DeletionPolicy, resources.DeletionPolicyDelete, resources.DeletionPolicyRetain, resources.DeletionPolicyDelayedDelete); err != nil {
+		errMsg := fmt.Sprintf("invalid deletion policy of blob storage instance %s", bs.Name)
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
+	// a cr which manages a cloud resource can not adopt another one, the conflict is reported in the status of the cr
+	if err := validateAdoption(bs, bs.Spec.AdoptIdentifier); err != nil {
+		errMsg := fmt.Sprintf("invalid adoption of blob storage instance %s", bs.Name)
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
 	// handle provider-specific finalizer
 	if err := resources.CreateFinalizer(ctx, p.Client, bs, DefaultFinalizer); err != nil {
 		return nil, "failed to set finalizer", err
@@ -127,14 +174,9 @@ func (p *BlobStorageProvider) CreateStorage(ctx context.Context, bs *v1alpha1.Bl
 		errMsg := "failed to build s3 bucket config"
 		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
 	}
-
-	// create the credentials to be used by the end-user, whoever created the blobstorage instance
-	endUserCredsName := buildEndUserCredentialsNameFromBucket(*bucketCreateCfg.Bucket)
-	p.Logger.Infof("creating end-user credentials with name %s for managing s3 bucket %s", endUserCredsName, *bucketCreateCfg.Bucket)
-	endUserCreds, _, err := p.CredentialManager.ReoncileBucketOwnerCredentials(ctx, endUserCredsName, bs.Namespace, *bucketCreateCfg.Bucket)
-	if err != nil {
-		errMsg := fmt.Sprintf("failed to reconcile s3 end-user credentials for blob storage instance %s", bs.Name)
-		return nil, croType.StatusMessage(errMsg), errorUtil.Wrapf(err, errMsg)
+	if err := validateCredentialStrategy(stratCfg.Credentials); err != nil {
+		errMsg := "invalid credential strategy"
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
 	}
 
 	// create the credentials to be used by the aws resource providers, not to be used by end-user
@@ -154,12 +196,64 @@ func (p *BlobStorageProvider) CreateStorage(ctx context.Context, bs *v1alpha1.Bl
 	}
 	s3Client := s3.New(sess)
 
+	// encrypt the bucket with the customer managed kms key of the strategy, if any
+	kmsKeyArn, err := reconcileKMSKey(ctx, p.Client, kms.New(sess), providers.BlobStorageResourceType, stratCfg.KMSKey)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to reconcile kms key for blob storage instance %s", bs.Name)
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
+	// adopt an existing bucket, it is managed like a bucket created by the operator from then on
+	if msg, err := p.reconcileBucketAdoption(ctx, bs, s3Client, *bucketCreateCfg.Bucket, stratCfg.Region); err != nil {
+		return nil, msg, err
+	}
+
+	// a rotation requested through an annotation is completed over several reconciles if one of them fails
+	if err := stampRotationRequest(ctx, p.Client, bs); err != nil {
+		errMsg := "failed to record credentials rotation request"
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
+	// create the credentials to be used by the end-user, whoever created the blobstorage instance
+	endUserCreds, roleCreds, err := p.reconcileEndUserCredentials(ctx, bs, *bucketCreateCfg.Bucket, kmsKeyArn, stratCfg.Credentials, iam.New(sess), sts.New(sess))
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to reconcile s3 end-user credentials for blob storage instance %s", bs.Name)
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrapf(err, errMsg)
+	}
+
+	// record the drift of an existing bucket from the desired configuration before it is corrected
+	drift, err := p.getS3BucketDrift(ctx, bs, s3Client, *bucketCreateCfg.Bucket, kmsKeyArn, stratCfg.Bucket)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to check drift of s3 bucket %s", *bucketCreateCfg.Bucket)
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
 	// create bucket if it doesn't already exist, if it does exist then use the existing bucket
 	p.Logger.Infof("reconciling aws s3 bucket %s", *bucketCreateCfg.Bucket)
-	msg, err := p.reconcileBucketCreate(ctx, bs, s3Client, bucketCreateCfg)
+	msg, err := p.reconcileBucketCreate(ctx, bs, s3Client, bucketCreateCfg, kmsKeyArn, stratCfg.Bucket.isPublicPolicyAllowed())
 	if err != nil {
 		return nil, msg, errorUtil.Wrapf(err, string(msg))
 	}
+	// there is no bucket to connect to until it is created
+	if msg == dryRunBucketCreateMsg {
+		return nil, msg, nil
+	}
+
+	// the cors rules and policy of the bucket are always reconciled, they are removed if neither the cr nor the tier
+	// declares them
+	if err := reconcileS3BucketAccess(bs, *bucketCreateCfg.Bucket, stratCfg.Bucket, s3Client); err != nil {
+		errMsg := fmt.Sprintf("failed to reconcile cors rules and policy of s3 bucket %s", *bucketCreateCfg.Bucket)
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
+	// versioning, lifecycle rules and replication are only reconciled for tiers which declare them
+	if stratCfg.Bucket != nil {
+		p.Logger.Infof("reconciling bucket strategy of aws s3 bucket %s", *bucketCreateCfg.Bucket)
+		if err := p.reconcileS3BucketStrategy(ctx, bs, *bucketCreateCfg.Bucket, stratCfg.Region, kmsKeyArn, stratCfg.Bucket, s3Client, newS3ReplicaClient(sess, stratCfg.Bucket), iam.New(sess)); err != nil {
+			errMsg := fmt.Sprintf("failed to reconcile bucket strategy of s3 bucket %s", *bucketCreateCfg.Bucket)
+			return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+	}
 
 	// blobstorageinstance that will be returned if everything is successful
 	bsi := &providers.BlobStorageInstance{
@@ -168,9 +262,28 @@ func (p *BlobStorageProvider) CreateStorage(ctx context.Context, bs *v1alpha1.Bl
 			BucketRegion:        stratCfg.Region,
 			CredentialKeyID:     endUserCreds.AccessKeyID,
 			CredentialSecretKey: endUserCreds.SecretAccessKey,
+			RoleCredentials:     roleCreds,
 		},
 	}
 
+	// the named credentials of the cr are minted with narrower access than the end-user credentials
+	if len(bs.Spec.Credentials) > 0 || len(bs.Status.Credentials) > 0 {
+		bsi.Credentials, err = p.reconcileBucketCredentials(ctx, bs, *bucketCreateCfg.Bucket, stratCfg.Region, kmsKeyArn, iam.New(sess), stratCfg.Credentials.getRotationGracePeriod())
+		if err != nil {
+			errMsg := fmt.Sprintf("failed to reconcile named credentials for blob storage instance %s", bs.Name)
+			return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+	}
+
+	// all credentials have been rotated if requested, the request is complete
+	if annotations.Has(bs, resources.RotateCredentialsAnnotation) && !resources.IsDryRun() {
+		annotations.Remove(bs, resources.RotateCredentialsAnnotation)
+		if err := p.Client.Update(ctx, bs); err != nil {
+			errMsg := "failed to remove rotate credentials annotation"
+			return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+	}
+
 	// Adding tags to s3
 	msg, err = p.TagBlobStorage(ctx, *bucketCreateCfg.Bucket, bs, stratCfg.Region, s3Client)
 	if err != nil {
@@ -178,6 +291,11 @@ func (p *BlobStorageProvider) CreateStorage(ctx context.Context, bs *v1alpha1.Bl
 		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
 	}
 
+	// the status is persisted with the phase of the cr by the controller
+	p.exposeBlobStorageDriftMetric(ctx, bs, *bucketCreateCfg.Bucket, drift)
+	p.exposeBlobStorageMetrics(ctx, bs, *bucketCreateCfg.Bucket, s3Client)
+	bs.Status.Drift = drift
+
 	p.Logger.Infof("creation handler for blob storage instance %s in namespace %s finished successfully", bs.Name, bs.Namespace)
 	return bsi, msg, nil
 }
@@ -186,12 +304,32 @@ func (p *BlobStorageProvider) TagBlobStorage(ctx context.Context, bucketName str
 	p.Logger.Infof("bucket %s found, Adding tags to bucket", bucketName)
 
 	// set tag values that will always be added
-	defaultOrganizationTag := resources.GetOrganizationTag()
 	clusterID, err := resources.GetClusterID(ctx, p.Client)
 	if err != nil {
 		errMsg := "failed to get cluster id"
 		return croType.StatusMessage(errMsg), errorUtil.Wrapf(err, errMsg)
 	}
+	bucketTags := buildBlobStorageTags(bs, clusterID)
+
+	// adding the tags to S3
+	_, err = s3svc.PutBucketTagging(&s3.PutBucketTaggingInput{
+		Bucket: aws.String(bucketName),
+		Tagging: &s3.Tagging{
+			TagSet: bucketTags,
+		},
+	})
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to add tags to S3 bucket: %s", err)
+		return croType.StatusMessage(errMsg), errorUtil.Wrapf(err, errMsg)
+	}
+
+	logrus.Infof("successfully created or updated tags to s3 bucket %s", bucketName)
+	return "successfully created and tagged", nil
+}
+
+// buildBlobStorageTags returns the tags which are always added to s3 buckets
+func buildBlobStorageTags(bs *v1alpha1.BlobStorage, clusterID string) []*s3.Tag {
+	defaultOrganizationTag := resources.GetOrganizationTag()
 	bucketTags := []*s3.Tag{
 		{
 			Key:   aws.String(defaultOrganizationTag + "clusterID"),
@@ -215,27 +353,19 @@ func (p *BlobStorageProvider) TagBlobStorage(ctx context.Context, bucketName str
 		}
 		bucketTags = append(bucketTags, productTag)
 	}
-
-	// adding the tags to S3
-	_, err = s3svc.PutBucketTagging(&s3.PutBucketTaggingInput{
-		Bucket: aws.String(bucketName),
-		Tagging: &s3.Tagging{
-			TagSet: bucketTags,
-		},
-	})
-	if err != nil {
-		errMsg := fmt.Sprintf("failed to add tags to S3 bucket: %s", err)
-		return croType.StatusMessage(errMsg), errorUtil.Wrapf(err, errMsg)
-	}
-
-	logrus.Infof("successfully created or updated tags to s3 bucket %s", bucketName)
-	return "successfully created and tagged", nil
+	return bucketTags
 }
 
 // DeleteStorage Delete S3 bucket and credentials to add objects to it
 func (p *BlobStorageProvider) DeleteStorage(ctx context.Context, bs *v1alpha1.BlobStorage) (croType.StatusMessage, error) {
 	p.Logger.Infof("deleting blob storage instance %s via aws s3", bs.Name)
 
+	// the cloud resource of a cr with an adoption conflict is not deleted, it could be the adopted one
+	if err := validateAdoption(bs, bs.Spec.AdoptIdentifier); err != nil {
+		errMsg := fmt.Sprintf("invalid adoption of blob storage instance %s", bs.Name)
+		return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
 	// resolve bucket information for bucket created by provider
 	p.Logger.Infof("getting aws s3 bucket config for blob storage instance %s", bs.Name)
 	bucketCreateCfg, bucketDeleteCfg, stratCfg, err := p.buildS3BucketConfig(ctx, bs)
@@ -260,11 +390,11 @@ func (p *BlobStorageProvider) DeleteStorage(ctx context.Context, bs *v1alpha1.Bl
 		return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
 	}
 
-	// delete the bucket that was created by the provider
-	return p.reconcileBucketDelete(ctx, bs, s3.New(sess), bucketCreateCfg, bucketDeleteCfg)
+	// delete the bucket that was created by the provider, and its replica
+	return p.reconcileBucketDelete(ctx, bs, s3.New(sess), newS3RegionClientFunc(sess), iam.New(sess), bucketCreateCfg, bucketDeleteCfg, stratCfg)
 }
 
-func (p *BlobStorageProvider) reconcileBucketDelete(ctx context.Context, bs *v1alpha1.BlobStorage, s3svc s3iface.S3API, bucketCfg *s3.CreateBucketInput, bucketDeleteCfg *S3DeleteStrat) (croType.StatusMessage, error) {
+func (p *BlobStorageProvider) reconcileBucketDelete(ctx context.Context, bs *v1alpha1.BlobStorage, s3svc s3iface.S3API, newReplicaSvc func(region string) s3iface.S3API, iamSvc iamiface.IAMAPI, bucketCfg *s3.CreateBucketInput, bucketDeleteCfg *S3DeleteStrat, stratCfg *StrategyConfig) (croType.StatusMessage, error) {
 	buckets, err := getS3buckets(s3svc)
 	if err != nil {
 		return "error getting s3 buckets", err
@@ -280,31 +410,85 @@ func (p *BlobStorageProvider) reconcileBucketDelete(ctx context.Context, bs *v1a
 	}
 
 	if foundBucket == nil {
-		if err := p.removeCredsAndFinalizer(ctx, bs, s3svc, bucketCfg, bucketDeleteCfg); err != nil {
+		bucketUsages.evict(*bucketCfg.Bucket)
+		// a bucket whose deletion was delayed is no longer recorded in the inventory once it is deleted
+		if err := resources.RemoveFromInventory(ctx, p.Client, bs.Namespace, string(providers.BlobStorageResourceType), *bucketCfg.Bucket); err != nil {
+			errMsg := fmt.Sprintf("failed to remove s3 bucket %s from inventory", *bucketCfg.Bucket)
+			return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+		// the replica bucket may still be emptied after the bucket was deleted
+		if msg, err := reconcileBucketReplicaDelete(bs, *bucketCfg.Bucket, s3svc, newReplicaSvc, iamSvc); msg != croType.StatusEmpty || err != nil {
+			return msg, err
+		}
+		if err := p.removeCredsAndFinalizer(ctx, bs, s3svc, iamSvc, bucketCfg, bucketDeleteCfg, stratCfg); err != nil {
 			errMsg := fmt.Sprintf("unable to remove credential secrets and finalizer for %s", *bucketCfg.Bucket)
 			return croType.StatusMessage(errMsg), errorUtil.Wrapf(err, errMsg)
 		}
+		return croType.StatusEmpty, nil
 	}
 
-	bucketSize, err := getBucketSize(s3svc, bucketCfg)
+	// s3 buckets can not be snapshotted
+	if bs.Spec.DeletionPolicy == resources.DeletionPolicySnapshot {
+		errMsg := fmt.Sprintf("deletion policy %s is not supported for s3 bucket %s", bs.Spec.DeletionPolicy, *bucketCfg.Bucket)
+		return croType.StatusMessage(errMsg), fmt.Errorf(errMsg)
+	}
+
+	// retain the bucket or delay its deletion as described by the deletion policy of the cr
+	action, msg, err := reconcileDeletionPolicy(ctx, p.Client, bs, bs.Spec.DeletionPolicy, bs.Spec.DeletionGracePeriod, resources.InventoryEntry{
+		ResourceType: string(providers.BlobStorageResourceType),
+		Identifier:   *bucketCfg.Bucket,
+		Region:       stratCfg.Region,
+	})
 	if err != nil {
-		errMsg := fmt.Sprintf("unable to get bucket size : %s", *bucketCfg.Bucket)
-		return croType.StatusMessage(errMsg), errorUtil.Wrapf(err, errMsg)
+		return msg, err
+	}
+	switch action {
+	case deletionActionRetain:
+		p.Logger.Infof("retaining s3 bucket %s", *bucketCfg.Bucket)
+		if err := p.removeCredsAndFinalizer(ctx, bs, s3svc, iamSvc, bucketCfg, bucketDeleteCfg, stratCfg); err != nil {
+			errMsg := fmt.Sprintf("unable to remove credential secrets and finalizer for %s", *bucketCfg.Bucket)
+			return croType.StatusMessage(errMsg), errorUtil.Wrapf(err, errMsg)
+		}
+		return croType.StatusEmpty, nil
+	case deletionActionDelay:
+		return msg, nil
 	}
 
-	if *bucketDeleteCfg.ForceBucketDeletion || bucketSize == 0 {
-		if err := emptyBucket(s3svc, bucketCfg); err != nil {
+	forceDeletion := *bucketDeleteCfg.ForceBucketDeletion
+	hasObjects := false
+	if !forceDeletion {
+		hasObjects, err = bucketHasObjects(s3svc, bucketCfg)
+		if err != nil {
+			errMsg := fmt.Sprintf("unable to get bucket size : %s", *bucketCfg.Bucket)
+			return croType.StatusMessage(errMsg), errorUtil.Wrapf(err, errMsg)
+		}
+	}
+	if forceDeletion || !hasObjects {
+		empty, err := emptyBucket(bs, s3svc, bucketCfg)
+		if err != nil {
 			errMsg := fmt.Sprintf("unable to empty bucket : %q", *bucketCfg.Bucket)
 			return croType.StatusMessage(errMsg), errorUtil.Wrapf(err, errMsg)
 		}
+		// the bucket is emptied over several reconciles, the progress is persisted with the phase of the cr
+		if !empty {
+			return buildEmptyBucketMessage(bs, *bucketCfg.Bucket), nil
+		}
 
 		if err := deleteBucket(s3svc, bucketCfg); err != nil {
 			errMsg := fmt.Sprintf("unable to delete bucket : %s", *bucketCfg.Bucket)
 			return croType.StatusMessage(errMsg), errorUtil.Wrapf(err, errMsg)
 		}
+		// the usage of the deleted bucket is no longer exposed
+		bucketUsages.evict(*bucketCfg.Bucket)
+
+		// the replica bucket is emptied after the bucket, so objects are no longer replicated to it
+		bs.Status.Deletion = nil
+		if msg, err := reconcileBucketReplicaDelete(bs, *bucketCfg.Bucket, s3svc, newReplicaSvc, iamSvc); msg != croType.StatusEmpty || err != nil {
+			return msg, err
+		}
 	}
 
-	if err := p.removeCredsAndFinalizer(ctx, bs, s3svc, bucketCfg, bucketDeleteCfg); err != nil {
+	if err := p.removeCredsAndFinalizer(ctx, bs, s3svc, iamSvc, bucketCfg, bucketDeleteCfg, stratCfg); err != nil {
 		errMsg := fmt.Sprintf("unable to remove credential secrets and finalizer for %s", *bucketCfg.Bucket)
 		return croType.StatusMessage(errMsg), errorUtil.Wrapf(err, errMsg)
 	}
@@ -312,24 +496,30 @@ func (p *BlobStorageProvider) reconcileBucketDelete(ctx context.Context, bs *v1a
 	return croType.StatusEmpty, nil
 }
 
-func (p *BlobStorageProvider) removeCredsAndFinalizer(ctx context.Context, bs *v1alpha1.BlobStorage, s3svc s3iface.S3API, bucketCfg *s3.CreateBucketInput, bucketDeleteCfg *S3DeleteStrat) error {
+func (p *BlobStorageProvider) removeCredsAndFinalizer(ctx context.Context, bs *v1alpha1.BlobStorage, s3svc s3iface.S3API, iamSvc iamiface.IAMAPI, bucketCfg *s3.CreateBucketInput, bucketDeleteCfg *S3DeleteStrat, stratCfg *StrategyConfig) error {
 	// build end user credential name
 	endUserCredsName := buildEndUserCredentialsNameFromBucket(*bucketCfg.Bucket)
 
 	// remove the credentials request created by the provider
-	p.Logger.Infof("deleting end-user credential request %s in namespace %s", endUserCredsName, bs.Namespace)
-	endUserCredsReq := &v1.CredentialsRequest{
-		ObjectMeta: controllerruntime.ObjectMeta{
-			Name:      endUserCredsName,
-			Namespace: bs.Namespace,
-		},
+	if err := p.deleteCredentialRequest(ctx, endUserCredsName, bs.Namespace); err != nil {
+		return err
+	}
+	// remove the iam role credentials were issued for
+	if stratCfg.Credentials.isRoleBased() {
+		if err := deleteIAMRole(iamSvc, buildBucketRoleName(*bucketCfg.Bucket), defaultRolePolicyName); err != nil {
+			return errorUtil.Wrapf(err, "failed to delete iam role of bucket %s", *bucketCfg.Bucket)
+		}
 	}
-	if err := p.Client.Delete(ctx, endUserCredsReq); err != nil {
-		if !errors.IsNotFound(err) {
-			errMsg := fmt.Sprintf("failed to delete credential request %s", endUserCredsName)
-			return errorUtil.Wrapf(err, errMsg)
+	// remove the credentials requests of the named credentials
+	for _, credential := range bs.Status.Credentials {
+		if err := p.deleteCredentialRequest(ctx, buildNamedCredentialsNameFromBucket(*bucketCfg.Bucket, credential.Name), bs.Namespace); err != nil {
+			return err
 		}
-		p.Logger.Infof("could not find credential request %s, already deleted, continuing", endUserCredsName)
+	}
+
+	// the credentials requests are not deleted in dry run mode, so the finalizer is kept
+	if resources.IsDryRun() {
+		return nil
 	}
 
 	// remove the finalizer
@@ -352,41 +542,158 @@ func deleteBucket(s3svc s3iface.S3API, bucketCfg *s3.CreateBucketInput) error {
 	return nil
 }
 
-func emptyBucket(s3svc s3iface.S3API, bucketCfg *s3.CreateBucketInput) error {
-	size, err := getBucketSize(s3svc, bucketCfg)
-	if err != nil {
-		return err
+// emptyBucket deletes the objects of a bucket in batches, including the previous versions of objects and delete
+// markers of versioned buckets. a bucket with millions of objects can not be emptied in a single reconcile, so the
+// progress of emptying the bucket is recorded in the status of the cr. true is returned once the bucket is empty
+func emptyBucket(bs *v1alpha1.BlobStorage, s3svc s3iface.S3API, bucketCfg *s3.CreateBucketInput) (bool, error) {
+	progress := croType.ResourceDeletionStatus{
+		StartedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	if bs.Status.Deletion != nil {
+		progress = *bs.Status.Deletion
+	}
+	// objects are not deleted in dry run mode, so only the deletion of the first batch is planned
+	batches := defaultEmptyBucketBatches
+	if resources.IsDryRun() {
+		batches = 1
 	}
 
-	if size == 0 {
-		return nil
+	empty := false
+	for i := 0; i < batches && !empty; i++ {
+		// the listing of a versioned bucket includes object versions and delete markers, the listing of an unversioned
+		// bucket includes its objects with a null version
+		input := &s3.ListObjectVersionsInput{
+			Bucket: bucketCfg.Bucket,
+		}
+		if progress.KeyMarker != "" {
+			input.KeyMarker = aws.String(progress.KeyMarker)
+			if progress.VersionIDMarker != "" {
+				input.VersionIdMarker = aws.String(progress.VersionIDMarker)
+			}
+		}
+		page, err := s3svc.ListObjectVersions(input)
+		if err != nil {
+			return false, errorUtil.Wrapf(err, "unable to list object versions in bucket %q", *bucketCfg.Bucket)
+		}
+		var objects []*s3.ObjectIdentifier
+		for _, v := range page.Versions {
+			objects = append(objects, &s3.ObjectIdentifier{Key: v.Key, VersionId: v.VersionId})
+		}
+		for _, m := range page.DeleteMarkers {
+			objects = append(objects, &s3.ObjectIdentifier{Key: m.Key, VersionId: m.VersionId})
+		}
+		if len(objects) > 0 {
+			output, err := s3svc.DeleteObjects(&s3.DeleteObjectsInput{
+				Bucket: bucketCfg.Bucket,
+				Delete: &s3.Delete{
+					Objects: objects,
+					Quiet:   aws.Bool(true),
+				},
+			})
+			if err != nil {
+				return false, errorUtil.Wrapf(err, "unable to delete object versions from bucket %q", *bucketCfg.Bucket)
+			}
+			// objects which can not be deleted are reported in the output of a successful request
+			if len(output.Errors) > 0 {
+				return false, fmt.Errorf("unable to delete %d object versions from bucket %q, %s: %s", len(output.Errors), *bucketCfg.Bucket, aws.StringValue(output.Errors[0].Key), aws.StringValue(output.Errors[0].Message))
+			}
+			progress.DeletedObjects += int64(len(objects))
+		}
+
+		if aws.BoolValue(page.IsTruncated) {
+			progress.KeyMarker = aws.StringValue(page.NextKeyMarker)
+			progress.VersionIDMarker = aws.StringValue(page.NextVersionIdMarker)
+			continue
+		}
+		// the bucket is empty once a listing from the start finds no objects, otherwise the listing starts over to find
+		// objects written behind the marker while the bucket was emptied
+		empty = progress.KeyMarker == "" && len(objects) == 0
+		progress.KeyMarker = ""
+		progress.VersionIDMarker = ""
 	}
 
-	// Setup BatchDeleteIterator to iterate through a list of objects.
-	iter := s3manager.NewDeleteListIterator(s3svc, &s3.ListObjectsInput{
-		Bucket: aws.String(*bucketCfg.Bucket),
-	})
+	if !resources.IsDryRun() {
+		progress.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+		bs.Status.Deletion = &progress
+	}
+	return empty, nil
+}
 
-	// Traverse iterator deleting each object
-	if err := s3manager.NewBatchDeleteWithClient(s3svc).Delete(aws.BackgroundContext(), iter); err != nil {
-		errMsg := fmt.Sprintf("unable to delete objects from bucket")
-		return errorUtil.Wrapf(err, errMsg)
+// reconcileBucketReplicaDelete deletes the replica bucket of a bucket, an empty message is returned once it is deleted
+func reconcileBucketReplicaDelete(bs *v1alpha1.BlobStorage, bucket string, s3svc s3iface.S3API, newReplicaSvc func(region string) s3iface.S3API, iamSvc iamiface.IAMAPI) (croType.StatusMessage, error) {
+	deleted, err := deleteS3BucketReplica(bs, bucket, s3svc, newReplicaSvc, iamSvc)
+	if err != nil {
+		errMsg := fmt.Sprintf("unable to delete replica of bucket : %s", bucket)
+		return croType.StatusMessage(errMsg), errorUtil.Wrapf(err, errMsg)
+	}
+	if !deleted {
+		return buildEmptyBucketMessage(bs, buildReplicaBucketName(bucket)), nil
 	}
+	return croType.StatusEmpty, nil
+}
 
-	return nil
+// buildEmptyBucketMessage returns the status message of a bucket which is being emptied
+func buildEmptyBucketMessage(bs *v1alpha1.BlobStorage, bucket string) croType.StatusMessage {
+	// no progress is recorded in dry run mode
+	if bs.Status.Deletion == nil {
+		return croType.StatusMessage(fmt.Sprintf("emptying s3 bucket %s", bucket))
+	}
+	return croType.StatusMessage(fmt.Sprintf("emptying s3 bucket %s, %d object versions deleted", bucket, bs.Status.Deletion.DeletedObjects))
 }
 
-func getBucketSize(s3svc s3iface.S3API, bucketCfg *s3.CreateBucketInput) (int, error) {
-	// get bucket items
-	resp, err := s3svc.ListObjectsV2(&s3.ListObjectsV2Input{Bucket: aws.String(*bucketCfg.Bucket)})
+// bucketHasObjects returns whether a bucket has any objects, previous versions of objects are not considered
+func bucketHasObjects(s3svc s3iface.S3API, bucketCfg *s3.CreateBucketInput) (bool, error) {
+	resp, err := s3svc.ListObjectsV2(&s3.ListObjectsV2Input{
+		Bucket:  aws.String(*bucketCfg.Bucket),
+		MaxKeys: aws.Int64(1),
+	})
 	if err != nil {
 		errMsg := fmt.Sprintf("unable to list items in bucket %q", *bucketCfg.Bucket)
-		return 0, errorUtil.Wrapf(err, errMsg)
+		return false, errorUtil.Wrapf(err, errMsg)
+	}
+	return len(resp.Contents) > 0, nil
+}
+
+// reconcileBucketAdoption verifies an existing bucket adopted by a blob storage cr exists in the region of the tier,
+// and annotates the cr with the bucket so it is treated as a bucket created by the operator. a bucket which is adopted
+// is never created
+func (p *BlobStorageProvider) reconcileBucketAdoption(ctx context.Context, bs *v1alpha1.BlobStorage, s3svc s3iface.S3API, bucket string, region string) (croType.StatusMessage, error) {
+	adoptID := resources.GetAdoptIdentifier(bs, bs.Spec.AdoptIdentifier)
+	if adoptID == "" || annotations.Has(bs, resourceIdentifierAnnotation) {
+		return croType.StatusEmpty, nil
+	}
+
+	location, err := s3svc.GetBucketLocation(&s3.GetBucketLocationInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == s3.ErrCodeNoSuchBucket {
+			errMsg := fmt.Sprintf("BlobStorage CR %s in %s namespace adopts S3 Bucket %s, but it was not found", bs.Name, bs.Namespace, bucket)
+			return croType.StatusMessage(errMsg), fmt.Errorf(errMsg)
+		}
+		errMsg := fmt.Sprintf("failed to get location of s3 bucket %s", bucket)
+		return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	if bucketRegion := s3.NormalizeBucketLocation(aws.StringValue(location.LocationConstraint)); bucketRegion != region {
+		errMsg := fmt.Sprintf("failed to adopt s3 bucket %s, it is in region %s, expected %s", bucket, bucketRegion, region)
+		return croType.StatusMessage(errMsg), fmt.Errorf(errMsg)
+	}
+
+	// a bucket which was retained is no longer recorded in the inventory once it is adopted
+	if err := resources.RemoveFromInventory(ctx, p.Client, bs.Namespace, string(providers.BlobStorageResourceType), bucket); err != nil {
+		errMsg := fmt.Sprintf("failed to remove s3 bucket %s from inventory", bucket)
+		return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
 	}
-	return len(resp.Contents), nil
+	annotations.Add(bs, resourceIdentifierAnnotation, bucket)
+	if err := p.Client.Update(ctx, bs); err != nil {
+		errMsg := fmt.Sprintf("failed to add annotation to blob storage instance %s", bs.Name)
+		return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	p.Logger.Infof("adopted s3 bucket %s", bucket)
+	return croType.StatusEmpty, nil
 }
 
-func (p *BlobStorageProvider) reconcileBucketCreate(ctx context.Context, bs *v1alpha1.BlobStorage, s3svc s3iface.S3API, bucketCfg *s3.CreateBucketInput) (croType.StatusMessage, error) {
+func (p *BlobStorageProvider) reconcileBucketCreate(ctx context.Context, bs *v1alpha1.BlobStorage, s3svc s3iface.S3API, bucketCfg *s3.CreateBucketInput, kmsKeyArn string, allowPublicPolicy bool) (croType.StatusMessage, error) {
 	// the aws access key can sometimes still not be registered in aws on first try, so loop
 	p.Logger.Infof("listing existing aws s3 buckets")
 	buckets, err := getS3buckets(s3svc)
@@ -405,7 +712,7 @@ func (p *BlobStorageProvider) reconcileBucketCreate(ctx context.Context, bs *v1a
 		}
 	}
 	if foundBucket != nil {
-		if err = reconcileS3BucketSettings(aws.StringValue(foundBucket.Name), s3svc); err != nil {
+		if err = reconcileS3BucketSettings(aws.StringValue(foundBucket.Name), kmsKeyArn, allowPublicPolicy, s3svc); err != nil {
 			errMsg := fmt.Sprintf("failed to set s3 bucket settings %s", *foundBucket.Name)
 			return croType.StatusMessage(errMsg), errorUtil.Wrapf(err, errMsg)
 		}
@@ -429,6 +736,9 @@ func (p *BlobStorageProvider) reconcileBucketCreate(ctx context.Context, bs *v1a
 		errMsg := fmt.Sprintf("failed to create s3 bucket %s", *bucketCfg.Bucket)
 		return croType.StatusMessage(errMsg), errorUtil.Wrapf(err, errMsg)
 	}
+	if resources.IsDryRun() {
+		return dryRunBucketCreateMsg, nil
+	}
 
 	annotations.Add(bs, resourceIdentifierAnnotation, *bucketCfg.Bucket)
 	if err := p.Client.Update(ctx, bs); err != nil {
@@ -436,7 +746,7 @@ func (p *BlobStorageProvider) reconcileBucketCreate(ctx context.Context, bs *v1a
 		return croType.StatusMessage(errMsg), errorUtil.Wrapf(err, errMsg)
 	}
 
-	if err = reconcileS3BucketSettings(aws.StringValue(bucketCfg.Bucket), s3svc); err != nil {
+	if err = reconcileS3BucketSettings(aws.StringValue(bucketCfg.Bucket), kmsKeyArn, allowPublicPolicy, s3svc); err != nil {
 		errMsg := fmt.Sprintf("failed to set s3 bucket settings on bucket creation %s", aws.StringValue(bucketCfg.Bucket))
 		return croType.StatusMessage(errMsg), errorUtil.Wrapf(err, errMsg)
 	}
@@ -461,27 +771,29 @@ func getS3buckets(s3svc s3iface.S3API) ([]*s3.Bucket, error) {
 	return existingBuckets, nil
 }
 
-func reconcileS3BucketSettings(bucket string, s3svc s3iface.S3API) error {
+func reconcileS3BucketSettings(bucket, kmsKeyArn string, allowPublicPolicy bool, s3svc s3iface.S3API) error {
 	_, err := s3svc.PutPublicAccessBlock(&s3.PutPublicAccessBlockInput{
-		Bucket: aws.String(bucket),
-		PublicAccessBlockConfiguration: &s3.PublicAccessBlockConfiguration{
-			BlockPublicAcls:       aws.Bool(defaultBlockPublicAcls),
-			BlockPublicPolicy:     aws.Bool(defaultBlockPublicPolicy),
-			IgnorePublicAcls:      aws.Bool(defaultIgnorePublicAcls),
-			RestrictPublicBuckets: aws.Bool(defaultRestrictPublicBuckets),
-		},
+		Bucket:                         aws.String(bucket),
+		PublicAccessBlockConfiguration: buildS3PublicAccessBlock(allowPublicPolicy),
 	})
 	if err != nil {
 		return errorUtil.Wrapf(err, "failed to set public access settings on bucket %s", bucket)
 	}
+	sseDefault := &s3.ServerSideEncryptionByDefault{
+		SSEAlgorithm: aws.String(defaultEncryptionSSEAlgorithm),
+	}
+	if kmsKeyArn != "" {
+		sseDefault = &s3.ServerSideEncryptionByDefault{
+			SSEAlgorithm:   aws.String(s3.ServerSideEncryptionAwsKms),
+			KMSMasterKeyID: aws.String(kmsKeyArn),
+		}
+	}
 	_, err = s3svc.PutBucketEncryption(&s3.PutBucketEncryptionInput{
 		Bucket: aws.String(bucket),
 		ServerSideEncryptionConfiguration: &s3.ServerSideEncryptionConfiguration{
 			Rules: []*s3.ServerSideEncryptionRule{
 				{
-					ApplyServerSideEncryptionByDefault: &s3.ServerSideEncryptionByDefault{
-						SSEAlgorithm: aws.String(defaultEncryptionSSEAlgorithm),
-					},
+					ApplyServerSideEncryptionByDefault: sseDefault,
 				},
 			},
 		},
@@ -492,6 +804,114 @@ func reconcileS3BucketSettings(bucket string, s3svc s3iface.S3API) error {
 	return nil
 }
 
+// getS3BucketDrift returns the public access, encryption, bucket strategy, cors, policy and tag settings of a bucket which differ from
+// the desired configuration. no drift is returned if the bucket does not exist
+func (p *BlobStorageProvider) getS3BucketDrift(ctx context.Context, bs *v1alpha1.BlobStorage, s3svc s3iface.S3API, bucket, kmsKeyArn string, bucketStrat *BucketStrategy) ([]croType.DriftedField, error) {
+	var drift []croType.DriftedField
+
+	observedAccess := &s3.PublicAccessBlockConfiguration{}
+	accessOutput, err := s3svc.GetPublicAccessBlock(&s3.GetPublicAccessBlockInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		s3err, isAwsErr := err.(awserr.Error)
+		if isAwsErr && s3err.Code() == s3.ErrCodeNoSuchBucket {
+			return nil, nil
+		}
+		if !isAwsErr || s3err.Code() != s3ErrCodeNoSuchPublicAccessBlockConfiguration {
+			return nil, errorUtil.Wrapf(err, "failed to get public access settings of bucket %s", bucket)
+		}
+	} else if accessOutput.PublicAccessBlockConfiguration != nil {
+		observedAccess = accessOutput.PublicAccessBlockConfiguration
+	}
+	desiredAccess := buildS3PublicAccessBlock(bucketStrat.isPublicPolicyAllowed())
+	drift = appendDrift(drift, "PublicAccessBlock.BlockPublicAcls", strconv.FormatBool(aws.BoolValue(desiredAccess.BlockPublicAcls)), strconv.FormatBool(aws.BoolValue(observedAccess.BlockPublicAcls)))
+	drift = appendDrift(drift, "PublicAccessBlock.BlockPublicPolicy", strconv.FormatBool(aws.BoolValue(desiredAccess.BlockPublicPolicy)), strconv.FormatBool(aws.BoolValue(observedAccess.BlockPublicPolicy)))
+	drift = appendDrift(drift, "PublicAccessBlock.IgnorePublicAcls", strconv.FormatBool(aws.BoolValue(desiredAccess.IgnorePublicAcls)), strconv.FormatBool(aws.BoolValue(observedAccess.IgnorePublicAcls)))
+	drift = appendDrift(drift, "PublicAccessBlock.RestrictPublicBuckets", strconv.FormatBool(aws.BoolValue(desiredAccess.RestrictPublicBuckets)), strconv.FormatBool(aws.BoolValue(observedAccess.RestrictPublicBuckets)))
+
+	observedEncryption := &s3.ServerSideEncryptionByDefault{}
+	encryptionOutput, err := s3svc.GetBucketEncryption(&s3.GetBucketEncryptionInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		if s3err, isAwsErr := err.(awserr.Error); !isAwsErr || s3err.Code() != s3ErrCodeServerSideEncryptionConfigurationNotFound {
+			return nil, errorUtil.Wrapf(err, "failed to get encryption settings of bucket %s", bucket)
+		}
+	} else if encryptionOutput.ServerSideEncryptionConfiguration != nil {
+		for _, rule := range encryptionOutput.ServerSideEncryptionConfiguration.Rules {
+			if rule.ApplyServerSideEncryptionByDefault != nil {
+				observedEncryption = rule.ApplyServerSideEncryptionByDefault
+				break
+			}
+		}
+	}
+	if kmsKeyArn != "" {
+		drift = appendDrift(drift, "ServerSideEncryption.SSEAlgorithm", s3.ServerSideEncryptionAwsKms, aws.StringValue(observedEncryption.SSEAlgorithm))
+		drift = appendDrift(drift, "ServerSideEncryption.KMSMasterKeyID", kmsKeyArn, aws.StringValue(observedEncryption.KMSMasterKeyID))
+	} else {
+		drift = appendDrift(drift, "ServerSideEncryption.SSEAlgorithm", defaultEncryptionSSEAlgorithm, aws.StringValue(observedEncryption.SSEAlgorithm))
+	}
+
+	if bucketStrat != nil {
+		strategyDrift, err := getS3BucketStrategyDrift(s3svc, bucket, bucketStrat)
+		if err != nil {
+			return nil, err
+		}
+		drift = append(drift, strategyDrift...)
+	}
+	accessDrift, err := getS3BucketAccessDrift(bs, s3svc, bucket, bucketStrat)
+	if err != nil {
+		return nil, err
+	}
+	drift = append(drift, accessDrift...)
+
+	observedTags := map[string]string{}
+	taggingOutput, err := s3svc.GetBucketTagging(&s3.GetBucketTaggingInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		if s3err, isAwsErr := err.(awserr.Error); !isAwsErr || s3err.Code() != s3ErrCodeNoSuchTagSet {
+			return nil, errorUtil.Wrapf(err, "failed to get tags of bucket %s", bucket)
+		}
+	} else {
+		for _, tag := range taggingOutput.TagSet {
+			observedTags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+	}
+	clusterID, err := resources.GetClusterID(ctx, p.Client)
+	if err != nil {
+		return nil, errorUtil.Wrap(err, "failed to get cluster id")
+	}
+	desiredTags := map[string]string{}
+	for _, tag := range buildBlobStorageTags(bs, clusterID) {
+		desiredTags[*tag.Key] = aws.StringValue(tag.Value)
+	}
+	return appendTagDrift(drift, desiredTags, observedTags), nil
+}
+
+// exposeBlobStorageDriftMetric exposes the drift of a bucket, the drift of the last reconcile is read from the status of
+// the cr
+func (p *BlobStorageProvider) exposeBlobStorageDriftMetric(ctx context.Context, bs *v1alpha1.BlobStorage, bucket string, drift []croType.DriftedField) {
+	clusterID, err := resources.GetClusterID(ctx, p.Client)
+	if err != nil {
+		p.Logger.Errorf("failed to get cluster id while exposing drift metric for %s", bucket)
+		return
+	}
+	exposeDriftMetric(buildBlobStorageGenericMetricLabels(bs, bucket, clusterID), bs.Status.Drift, drift)
+}
+
+func buildBlobStorageGenericMetricLabels(bs *v1alpha1.BlobStorage, bucket, clusterID string) map[string]string {
+	labels := map[string]string{}
+	labels["clusterID"] = clusterID
+	labels["resourceID"] = bs.Name
+	labels["namespace"] = bs.Namespace
+	labels["instanceID"] = bucket
+	labels["productName"] = bs.Labels["productName"]
+	labels["strategy"] = blobstorageProviderName
+	return labels
+}
+
 func (p *BlobStorageProvider) buildS3BucketConfig(ctx context.Context, bs *v1alpha1.BlobStorage) (*s3.CreateBucketInput, *S3DeleteStrat, *StrategyConfig, error) {
 	// info about the bucket to be created
 	p.Logger.Infof("getting aws s3 bucket config for blob storage instance %s", bs.Name)
@@ -544,6 +964,11 @@ func (p *BlobStorageProvider) getS3BucketConfig(ctx context.Context, bs *v1alpha
 		return nil, nil, nil, errorUtil.Wrapf(err, "failed to unmarshal aws s3 delete strat configuration")
 	}
 
+	// an adopted bucket is identified by the name of the existing bucket
+	if adoptID := resources.GetAdoptIdentifier(bs, bs.Spec.AdoptIdentifier); adoptID != "" {
+		s3createConfig.Bucket = aws.String(adoptID)
+	}
+
 	return s3createConfig, s3deleteConfig, stratCfg, nil
 }
 
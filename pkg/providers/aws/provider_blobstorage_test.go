@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
 
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -16,6 +18,7 @@ import (
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
@@ -30,8 +33,25 @@ type mockS3Svc struct {
 	wantErrDelete     bool
 	wantErrWaitDelete bool
 	bucketNames       []string
+	publicAccessBlock *s3.PublicAccessBlockConfiguration
+	encryption        *s3.ServerSideEncryptionConfiguration
+	tags              []*s3.Tag
+	location          *string
+	versioning        *string
+	versions          []*s3.ObjectVersion
+	deletedVersions   []*s3.ObjectIdentifier
+	lifecycleRules    []*s3.LifecycleRule
+	replication       *s3.ReplicationConfiguration
+	corsRules         []*s3.CORSRule
+	policy            *string
+	objects           []*s3.Object
+	wantErrHead       bool
+	// wantErrDeleteObjects reports the objects of a delete request as failed to delete
+	wantErrDeleteObjects bool
 }
 
+const mockVersionsPageSize = 2
+
 func (s *mockS3Svc) ListBuckets(lbi *s3.ListBucketsInput) (*s3.ListBucketsOutput, error) {
 	if s.wantErrList {
 		return nil, errors.New("mock aws s3 client error")
@@ -67,6 +87,23 @@ func (s *mockS3Svc) ListObjectsV2(*s3.ListObjectsV2Input) (*s3.ListObjectsV2Outp
 	return &s3.ListObjectsV2Output{}, nil
 }
 
+func (s *mockS3Svc) ListObjectsV2Pages(input *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool) error {
+	// each object is listed on its own page
+	for i, object := range s.objects {
+		if !fn(&s3.ListObjectsV2Output{Contents: []*s3.Object{object}}, i == len(s.objects)-1) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *mockS3Svc) HeadBucket(*s3.HeadBucketInput) (*s3.HeadBucketOutput, error) {
+	if s.wantErrHead {
+		return nil, awserr.New(s3.ErrCodeNoSuchBucket, "", nil)
+	}
+	return &s3.HeadBucketOutput{}, nil
+}
+
 func (s *mockS3Svc) PutBucketTagging(*s3.PutBucketTaggingInput) (*s3.PutBucketTaggingOutput, error) {
 	return &s3.PutBucketTaggingOutput{}, nil
 }
@@ -79,6 +116,160 @@ func (s *mockS3Svc) PutBucketEncryption(input *s3.PutBucketEncryptionInput) (*s3
 	return &s3.PutBucketEncryptionOutput{}, nil
 }
 
+func (s *mockS3Svc) GetPublicAccessBlock(*s3.GetPublicAccessBlockInput) (*s3.GetPublicAccessBlockOutput, error) {
+	return &s3.GetPublicAccessBlockOutput{
+		PublicAccessBlockConfiguration: s.publicAccessBlock,
+	}, nil
+}
+
+func (s *mockS3Svc) GetBucketEncryption(*s3.GetBucketEncryptionInput) (*s3.GetBucketEncryptionOutput, error) {
+	return &s3.GetBucketEncryptionOutput{
+		ServerSideEncryptionConfiguration: s.encryption,
+	}, nil
+}
+
+func (s *mockS3Svc) GetBucketTagging(*s3.GetBucketTaggingInput) (*s3.GetBucketTaggingOutput, error) {
+	return &s3.GetBucketTaggingOutput{
+		TagSet: s.tags,
+	}, nil
+}
+
+func (s *mockS3Svc) GetBucketLocation(input *s3.GetBucketLocationInput) (*s3.GetBucketLocationOutput, error) {
+	if !resources.Contains(s.bucketNames, aws.StringValue(input.Bucket)) {
+		return nil, awserr.New(s3.ErrCodeNoSuchBucket, "", nil)
+	}
+	return &s3.GetBucketLocationOutput{
+		LocationConstraint: s.location,
+	}, nil
+}
+
+func (s *mockS3Svc) GetBucketVersioning(*s3.GetBucketVersioningInput) (*s3.GetBucketVersioningOutput, error) {
+	return &s3.GetBucketVersioningOutput{
+		Status: s.versioning,
+	}, nil
+}
+
+func (s *mockS3Svc) PutBucketVersioning(input *s3.PutBucketVersioningInput) (*s3.PutBucketVersioningOutput, error) {
+	s.versioning = input.VersioningConfiguration.Status
+	return &s3.PutBucketVersioningOutput{}, nil
+}
+
+func (s *mockS3Svc) ListObjectVersions(input *s3.ListObjectVersionsInput) (*s3.ListObjectVersionsOutput, error) {
+	// versions are listed in pages of two, continuing after the version of the markers
+	start := 0
+	if input.KeyMarker != nil {
+		marker := aws.StringValue(input.KeyMarker) + "/" + aws.StringValue(input.VersionIdMarker)
+		for start < len(s.versions) && aws.StringValue(s.versions[start].Key)+"/"+aws.StringValue(s.versions[start].VersionId) <= marker {
+			start++
+		}
+	}
+	end := start + mockVersionsPageSize
+	if end >= len(s.versions) {
+		return &s3.ListObjectVersionsOutput{Versions: s.versions[start:], IsTruncated: aws.Bool(false)}, nil
+	}
+	return &s3.ListObjectVersionsOutput{
+		Versions:            s.versions[start:end],
+		IsTruncated:         aws.Bool(true),
+		NextKeyMarker:       s.versions[end-1].Key,
+		NextVersionIdMarker: s.versions[end-1].VersionId,
+	}, nil
+}
+
+func (s *mockS3Svc) DeleteObjects(input *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error) {
+	if s.wantErrDeleteObjects {
+		return &s3.DeleteObjectsOutput{Errors: []*s3.Error{{Key: input.Delete.Objects[0].Key, Message: aws.String("access denied")}}}, nil
+	}
+	s.deletedVersions = append(s.deletedVersions, input.Delete.Objects...)
+	var remaining []*s3.ObjectVersion
+	for _, v := range s.versions {
+		deleted := false
+		for _, o := range input.Delete.Objects {
+			deleted = deleted || (aws.StringValue(v.Key) == aws.StringValue(o.Key) && aws.StringValue(v.VersionId) == aws.StringValue(o.VersionId))
+		}
+		if !deleted {
+			remaining = append(remaining, v)
+		}
+	}
+	s.versions = remaining
+	return &s3.DeleteObjectsOutput{}, nil
+}
+
+func (s *mockS3Svc) GetBucketLifecycleConfiguration(*s3.GetBucketLifecycleConfigurationInput) (*s3.GetBucketLifecycleConfigurationOutput, error) {
+	if s.lifecycleRules == nil {
+		return nil, awserr.New(s3ErrCodeNoSuchLifecycleConfiguration, "", nil)
+	}
+	return &s3.GetBucketLifecycleConfigurationOutput{
+		Rules: s.lifecycleRules,
+	}, nil
+}
+
+func (s *mockS3Svc) PutBucketLifecycleConfiguration(input *s3.PutBucketLifecycleConfigurationInput) (*s3.PutBucketLifecycleConfigurationOutput, error) {
+	s.lifecycleRules = input.LifecycleConfiguration.Rules
+	return &s3.PutBucketLifecycleConfigurationOutput{}, nil
+}
+
+func (s *mockS3Svc) DeleteBucketLifecycle(*s3.DeleteBucketLifecycleInput) (*s3.DeleteBucketLifecycleOutput, error) {
+	s.lifecycleRules = nil
+	return &s3.DeleteBucketLifecycleOutput{}, nil
+}
+
+func (s *mockS3Svc) GetBucketReplication(*s3.GetBucketReplicationInput) (*s3.GetBucketReplicationOutput, error) {
+	if s.replication == nil {
+		return nil, awserr.New(s3ErrCodeReplicationConfigurationNotFound, "", nil)
+	}
+	return &s3.GetBucketReplicationOutput{
+		ReplicationConfiguration: s.replication,
+	}, nil
+}
+
+func (s *mockS3Svc) PutBucketReplication(input *s3.PutBucketReplicationInput) (*s3.PutBucketReplicationOutput, error) {
+	s.replication = input.ReplicationConfiguration
+	return &s3.PutBucketReplicationOutput{}, nil
+}
+
+func (s *mockS3Svc) DeleteBucketReplication(*s3.DeleteBucketReplicationInput) (*s3.DeleteBucketReplicationOutput, error) {
+	s.replication = nil
+	return &s3.DeleteBucketReplicationOutput{}, nil
+}
+
+func (s *mockS3Svc) GetBucketCors(*s3.GetBucketCorsInput) (*s3.GetBucketCorsOutput, error) {
+	if s.corsRules == nil {
+		return nil, awserr.New(s3ErrCodeNoSuchCORSConfiguration, "", nil)
+	}
+	return &s3.GetBucketCorsOutput{
+		CORSRules: s.corsRules,
+	}, nil
+}
+
+func (s *mockS3Svc) PutBucketCors(input *s3.PutBucketCorsInput) (*s3.PutBucketCorsOutput, error) {
+	s.corsRules = input.CORSConfiguration.CORSRules
+	return &s3.PutBucketCorsOutput{}, nil
+}
+
+func (s *mockS3Svc) DeleteBucketCors(*s3.DeleteBucketCorsInput) (*s3.DeleteBucketCorsOutput, error) {
+	s.corsRules = nil
+	return &s3.DeleteBucketCorsOutput{}, nil
+}
+
+func (s *mockS3Svc) GetBucketPolicy(*s3.GetBucketPolicyInput) (*s3.GetBucketPolicyOutput, error) {
+	if s.policy == nil {
+		return nil, awserr.New(s3ErrCodeNoSuchBucketPolicy, "", nil)
+	}
+	return &s3.GetBucketPolicyOutput{
+		Policy: s.policy,
+	}, nil
+}
+
+func (s *mockS3Svc) PutBucketPolicy(input *s3.PutBucketPolicyInput) (*s3.PutBucketPolicyOutput, error) {
+	s.policy = input.Policy
+	return &s3.PutBucketPolicyOutput{}, nil
+}
+
+func (s *mockS3Svc) DeleteBucketPolicy(*s3.DeleteBucketPolicyInput) (*s3.DeleteBucketPolicyOutput, error) {
+	s.policy = nil
+	return &s3.DeleteBucketPolicyOutput{}, nil
+}
+
 func buildTestBlobStorageCR() *v1alpha1.BlobStorage {
 	return &v1alpha1.BlobStorage{
 		ObjectMeta: v1.ObjectMeta{
@@ -159,13 +350,102 @@ func TestBlobStorageProvider_reconcileBucket(t *testing.T) {
 				ConfigManager:     tt.fields.ConfigManager,
 			}
 			dummyBlobStorage := &v1alpha1.BlobStorage{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"}}
-			if _, err := p.reconcileBucketCreate(tt.args.ctx, dummyBlobStorage, tt.args.s3svc, tt.args.bucketCfg); (err != nil) != tt.wantErr {
+			if _, err := p.reconcileBucketCreate(tt.args.ctx, dummyBlobStorage, tt.args.s3svc, tt.args.bucketCfg, "", false); (err != nil) != tt.wantErr {
 				t.Errorf("reconcileBucket() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
+func TestBlobStorageProvider_CreateStorageDeletionPolicy(t *testing.T) {
+	scheme, err := buildTestScheme()
+	if err != nil {
+		t.Fatal("failed to build test scheme", err)
+	}
+	bs := buildTestBlobStorageCR()
+	bs.Spec.DeletionPolicy = resources.DeletionPolicySnapshot
+	c := fake.NewFakeClientWithScheme(scheme, bs)
+	p := &BlobStorageProvider{
+		Client:            c,
+		Logger:            logrus.WithFields(logrus.Fields{}),
+		CredentialManager: &CredentialManagerMock{},
+		ConfigManager:     &ConfigManagerMock{},
+	}
+	if _, _, err := p.CreateStorage(context.TODO(), bs); err == nil {
+		t.Fatal("CreateStorage() did not reject the Snapshot deletion policy")
+	}
+	// the bucket is not created, so the cr can still be deleted
+	found := &v1alpha1.BlobStorage{}
+	if err := c.Get(context.TODO(), client.ObjectKey{Name: bs.Name, Namespace: bs.Namespace}, found); err != nil {
+		t.Fatal("failed to get blob storage cr", err)
+	}
+	if resources.HasFinalizer(&found.ObjectMeta, DefaultFinalizer) {
+		t.Error("CreateStorage() added the finalizer to a cr with an unsupported deletion policy")
+	}
+}
+
+func TestBlobStorageProvider_reconcileBucketAdoption(t *testing.T) {
+	scheme, err := buildTestScheme()
+	if err != nil {
+		t.Fatal("failed to build test scheme", err)
+	}
+	buildAdoptingBlobStorageCR := func() *v1alpha1.BlobStorage {
+		bs := buildTestBlobStorageCR()
+		bs.Spec.AdoptIdentifier = "test"
+		return bs
+	}
+	tests := []struct {
+		name           string
+		bs             *v1alpha1.BlobStorage
+		s3svc          s3iface.S3API
+		wantErr        bool
+		wantAnnotation bool
+	}{
+		{
+			name:  "test nothing is done when no bucket is adopted",
+			bs:    buildTestBlobStorageCR(),
+			s3svc: &mockS3Svc{},
+		},
+		{
+			name:    "test error when adopted bucket does not exist",
+			bs:      buildAdoptingBlobStorageCR(),
+			s3svc:   &mockS3Svc{},
+			wantErr: true,
+		},
+		{
+			name: "test error when adopted bucket is in a different region",
+			bs:   buildAdoptingBlobStorageCR(),
+			s3svc: &mockS3Svc{
+				bucketNames: []string{"test"},
+				location:    aws.String("eu-west-1"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "test existing bucket is adopted",
+			bs:   buildAdoptingBlobStorageCR(),
+			s3svc: &mockS3Svc{
+				bucketNames: []string{"test"},
+			},
+			wantAnnotation: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &BlobStorageProvider{
+				Client: fake.NewFakeClientWithScheme(scheme, tt.bs),
+				Logger: testLogger,
+			}
+			if _, err := p.reconcileBucketAdoption(context.TODO(), tt.bs, tt.s3svc, "test", "us-east-1"); (err != nil) != tt.wantErr {
+				t.Errorf("reconcileBucketAdoption() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := tt.bs.Annotations[resourceIdentifierAnnotation] == "test"; got != tt.wantAnnotation {
+				t.Errorf("reconcileBucketAdoption() annotated = %v, want %v", got, tt.wantAnnotation)
+			}
+		})
+	}
+}
+
 func TestBlobStorageProvider_reconcileBucketDelete(t *testing.T) {
 	scheme, err := buildTestScheme()
 	if err != nil {
@@ -245,13 +525,97 @@ func TestBlobStorageProvider_reconcileBucketDelete(t *testing.T) {
 				CredentialManager: tt.fields.CredentialManager,
 				ConfigManager:     tt.fields.ConfigManager,
 			}
-			if _, err := p.reconcileBucketDelete(tt.args.ctx, tt.args.bs, tt.args.s3svc, tt.args.bucketCfg, tt.args.bucketDeleteCfg); (err != nil) != tt.wantErr {
+			if _, err := p.reconcileBucketDelete(tt.args.ctx, tt.args.bs, tt.args.s3svc, nil, &mockIAMClient{}, tt.args.bucketCfg, tt.args.bucketDeleteCfg, &StrategyConfig{}); (err != nil) != tt.wantErr {
 				t.Errorf("reconcileBucketDelete() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
+func Test_emptyBucket(t *testing.T) {
+	buildVersions := func(n int) []*s3.ObjectVersion {
+		var versions []*s3.ObjectVersion
+		for i := 0; i < n; i++ {
+			versions = append(versions, &s3.ObjectVersion{Key: aws.String(fmt.Sprintf("object-%03d", i)), VersionId: aws.String("null")})
+		}
+		return versions
+	}
+	tests := []struct {
+		name        string
+		s3svc       *mockS3Svc
+		reconciles  int
+		wantDeleted int64
+		wantErr     bool
+	}{
+		{
+			name:        "test objects of a bucket are deleted in a single reconcile",
+			s3svc:       &mockS3Svc{versions: buildVersions(3)},
+			reconciles:  1,
+			wantDeleted: 3,
+		},
+		{
+			name: "test object versions of a versioned bucket are deleted",
+			s3svc: &mockS3Svc{versions: []*s3.ObjectVersion{
+				{Key: aws.String("a"), VersionId: aws.String("1")},
+				{Key: aws.String("a"), VersionId: aws.String("2")},
+			}},
+			reconciles:  1,
+			wantDeleted: 2,
+		},
+		{
+			name:        "test large bucket is emptied over several reconciles",
+			s3svc:       &mockS3Svc{versions: buildVersions(defaultEmptyBucketBatches*mockVersionsPageSize + 5)},
+			reconciles:  2,
+			wantDeleted: defaultEmptyBucketBatches*mockVersionsPageSize + 5,
+		},
+		{
+			name:        "test empty bucket is empty",
+			s3svc:       &mockS3Svc{},
+			reconciles:  1,
+			wantDeleted: 0,
+		},
+		{
+			name:    "test error when objects fail to delete",
+			s3svc:   &mockS3Svc{versions: buildVersions(1), wantErrDeleteObjects: true},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bs := buildTestBlobStorageCR()
+			reconciles := 0
+			for {
+				reconciles++
+				empty, err := emptyBucket(bs, tt.s3svc, &s3.CreateBucketInput{Bucket: aws.String("test")})
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("emptyBucket() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if tt.wantErr {
+					return
+				}
+				if empty {
+					break
+				}
+				if bs.Status.Deletion == nil {
+					t.Fatal("emptyBucket() did not record its progress")
+				}
+				if reconciles > tt.reconciles {
+					t.Fatalf("emptyBucket() did not empty the bucket in %d reconciles", tt.reconciles)
+				}
+			}
+			if reconciles != tt.reconciles {
+				t.Errorf("emptyBucket() emptied the bucket in %d reconciles, want %d", reconciles, tt.reconciles)
+			}
+			if len(tt.s3svc.versions) != 0 {
+				t.Errorf("emptyBucket() left %d object versions", len(tt.s3svc.versions))
+			}
+			if bs.Status.Deletion.DeletedObjects != tt.wantDeleted {
+				t.Errorf("emptyBucket() recorded %d deleted objects, want %d", bs.Status.Deletion.DeletedObjects, tt.wantDeleted)
+			}
+		})
+	}
+}
+
 func TestBlobStorageProvider_GetReconcileTime(t *testing.T) {
 	type args struct {
 		b *v1alpha1.BlobStorage
@@ -266,7 +630,9 @@ func TestBlobStorageProvider_GetReconcileTime(t *testing.T) {
 			args: args{
 				b: &v1alpha1.BlobStorage{
 					Status: v1alpha1.BlobStorageStatus{
-						Phase: types.PhaseInProgress,
+						ResourceTypeStatus: types.ResourceTypeStatus{
+							Phase: types.PhaseInProgress,
+						},
 					},
 				},
 			},
@@ -277,12 +643,31 @@ func TestBlobStorageProvider_GetReconcileTime(t *testing.T) {
 			args: args{
 				b: &v1alpha1.BlobStorage{
 					Status: v1alpha1.BlobStorageStatus{
-						Phase: types.PhaseComplete,
+						ResourceTypeStatus: types.ResourceTypeStatus{
+							Phase: types.PhaseComplete,
+						},
 					},
 				},
 			},
 			want: defaultReconcileTime,
 		},
+		{
+			name: "test fast reconcile while the bucket of a deleted cr is emptied",
+			args: args{
+				b: &v1alpha1.BlobStorage{
+					ObjectMeta: v1.ObjectMeta{
+						DeletionTimestamp: &v1.Time{Time: time.Now()},
+					},
+					Status: v1alpha1.BlobStorageStatus{
+						ResourceTypeStatus: types.ResourceTypeStatus{
+							Phase: types.PhaseDeleteInProgress,
+						},
+						Deletion: &types.ResourceDeletionStatus{DeletedObjects: 1000},
+					},
+				},
+			},
+			want: emptyBucketReconcileTime,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -359,3 +744,118 @@ func TestBlobStorageProvider_TagBlobStorage(t *testing.T) {
 		})
 	}
 }
+
+func TestBlobStorageProvider_getS3BucketDrift(t *testing.T) {
+	scheme, err := buildTestScheme()
+	if err != nil {
+		t.Fatal("failed to build test scheme", err)
+	}
+	organizationTag := resources.GetOrganizationTag()
+	tests := []struct {
+		name        string
+		s3svc       s3iface.S3API
+		bucketStrat *BucketStrategy
+		want        []types.DriftedField
+	}{
+		{
+			name: "test no drift when bucket is as expected",
+			s3svc: &mockS3Svc{
+				publicAccessBlock: &s3.PublicAccessBlockConfiguration{
+					BlockPublicAcls:       aws.Bool(true),
+					BlockPublicPolicy:     aws.Bool(true),
+					IgnorePublicAcls:      aws.Bool(true),
+					RestrictPublicBuckets: aws.Bool(true),
+				},
+				encryption: &s3.ServerSideEncryptionConfiguration{
+					Rules: []*s3.ServerSideEncryptionRule{
+						{
+							ApplyServerSideEncryptionByDefault: &s3.ServerSideEncryptionByDefault{
+								SSEAlgorithm: aws.String(s3.ServerSideEncryptionAes256),
+							},
+						},
+					},
+				},
+				tags: []*s3.Tag{
+					{Key: aws.String(organizationTag + "clusterID"), Value: aws.String("test")},
+					{Key: aws.String(organizationTag + "resource-type"), Value: aws.String("")},
+					{Key: aws.String(organizationTag + "resource-name"), Value: aws.String("test")},
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "test drift when public access is allowed and settings are missing",
+			s3svc: &mockS3Svc{
+				publicAccessBlock: &s3.PublicAccessBlockConfiguration{
+					BlockPublicAcls:       aws.Bool(true),
+					BlockPublicPolicy:     aws.Bool(false),
+					IgnorePublicAcls:      aws.Bool(true),
+					RestrictPublicBuckets: aws.Bool(true),
+				},
+			},
+			want: []types.DriftedField{
+				{Field: "PublicAccessBlock.BlockPublicPolicy", Desired: "true", Observed: "false"},
+				{Field: "ServerSideEncryption.SSEAlgorithm", Desired: s3.ServerSideEncryptionAes256, Observed: ""},
+				{Field: driftTagFieldPrefix + organizationTag + "clusterID", Desired: "test", Observed: ""},
+				{Field: driftTagFieldPrefix + organizationTag + "resource-name", Desired: "test", Observed: ""},
+			},
+		},
+		{
+			name: "test drift when bucket strategy settings are missing",
+			s3svc: &mockS3Svc{
+				publicAccessBlock: &s3.PublicAccessBlockConfiguration{
+					BlockPublicAcls:       aws.Bool(true),
+					BlockPublicPolicy:     aws.Bool(true),
+					IgnorePublicAcls:      aws.Bool(true),
+					RestrictPublicBuckets: aws.Bool(true),
+				},
+				encryption: &s3.ServerSideEncryptionConfiguration{
+					Rules: []*s3.ServerSideEncryptionRule{
+						{
+							ApplyServerSideEncryptionByDefault: &s3.ServerSideEncryptionByDefault{
+								SSEAlgorithm: aws.String(s3.ServerSideEncryptionAes256),
+							},
+						},
+					},
+				},
+				tags: []*s3.Tag{
+					{Key: aws.String(organizationTag + "clusterID"), Value: aws.String("test")},
+					{Key: aws.String(organizationTag + "resource-type"), Value: aws.String("")},
+					{Key: aws.String(organizationTag + "resource-name"), Value: aws.String("test")},
+				},
+				lifecycleRules: []*s3.LifecycleRule{
+					{ID: aws.String("expire")},
+				},
+			},
+			bucketStrat: &BucketStrategy{
+				LifecycleRules: []*BucketLifecycleRule{
+					{ID: "expire", ExpirationDays: 30},
+					{ID: "abort", AbortIncompleteUploadDays: 7},
+				},
+				Replication: &BucketReplicationStrategy{
+					Region: "eu-west-1",
+				},
+			},
+			want: []types.DriftedField{
+				{Field: "Versioning.Status", Desired: s3.BucketVersioningStatusEnabled, Observed: ""},
+				{Field: "LifecycleRules", Desired: "abort,expire", Observed: "expire"},
+				{Field: "Replication.Destination", Desired: buildS3BucketArn(buildReplicaBucketName("test")), Observed: ""},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &BlobStorageProvider{
+				Client: fake.NewFakeClientWithScheme(scheme, buildTestBlobStorageCR(), buildTestInfrastructure()),
+				Logger: testLogger,
+			}
+			got, err := p.getS3BucketDrift(context.TODO(), buildTestBlobStorageCR(), tt.s3svc, "test", "", tt.bucketStrat)
+			if err != nil {
+				t.Fatal("getS3BucketDrift() returned an error", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getS3BucketDrift() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/service/ec2"
@@ -26,6 +28,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
 	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
 
+	"github.com/aws/aws-sdk-go/service/kms"
 	"github.com/aws/aws-sdk-go/service/rds"
 	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
 	"github.com/integr8ly/cloud-resource-operator/pkg/providers"
@@ -58,7 +61,10 @@ const (
 	defaultCredSecSuffix                 = "-aws-rds-credentials"
 	defaultPostgresUserKey               = "user"
 	defaultPostgresPasswordKey           = "password"
+	defaultPostgresPendingPasswordKey    = "pendingPassword"
+	defaultPostgresPasswordRotatedAtKey  = "passwordRotatedAt"
 	defaultStorageEncrypted              = true
+	rdsOptInTypeImmediate                = "immediate"
 )
 
 var (
@@ -102,6 +108,18 @@ func (p *PostgresProvider) GetReconcileTime(pg *v1alpha1.Postgres) time.Duration
 
 // CreatePostgres creates an RDS Instance from strategy config
 func (p *PostgresProvider) CreatePostgres(ctx context.Context, pg *v1alpha1.Postgres) (*providers.PostgresInstance, croType.StatusMessage, error) {
+	// unsupported deletion policies are rejected before the rds instance is created
+	if err := resources.ValidateDeletionPolicy(pg.Spec.DeletionPolicy, resources.DeletionPolicyDelete, resources.DeletionPolicySnapshot, resources.DeletionPolicyRetain, resources.DeletionPolicyDelayedDelete); err != nil {
+		errMsg := fmt.Sprintf("invalid deletion policy of postgres instance %s", pg.Name)
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
+	// a cr which manages a cloud resource can not adopt another one, the conflict is reported in the status of the cr
+	if err := validateAdoption(pg, pg.Spec.AdoptIdentifier); err != nil {
+		errMsg := fmt.Sprintf("invalid adoption of postgres instance %s", pg.Name)
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
 	// handle provider-specific finalizer
 	if err := resources.CreateFinalizer(ctx, p.Client, pg, DefaultFinalizer); err != nil {
 		return nil, "failed to set finalizer", err
@@ -137,11 +155,24 @@ func (p *PostgresProvider) CreatePostgres(ctx context.Context, pg *v1alpha1.Post
 		errMsg := "failed to create aws session to create rds db instance"
 		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
 	}
+
+	// encrypt the instance with the customer managed kms key of the strategy, if any
+	if rdsCfg.KmsKeyId == nil {
+		kmsKeyArn, err := reconcileKMSKey(ctx, p.Client, kms.New(sess), providers.PostgresResourceType, stratCfg.KMSKey)
+		if err != nil {
+			errMsg := "failed to reconcile kms key for rds db instance"
+			return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+		if kmsKeyArn != "" {
+			rdsCfg.KmsKeyId = aws.String(kmsKeyArn)
+		}
+	}
+
 	// create the aws RDS instance
-	return p.createRDSInstance(ctx, pg, rds.New(sess), ec2.New(sess), rdsCfg)
+	return p.createRDSInstance(ctx, pg, rds.New(sess), ec2.New(sess), rdsCfg, stratCfg)
 }
 
-func (p *PostgresProvider) createRDSInstance(ctx context.Context, cr *v1alpha1.Postgres, rdsSvc rdsiface.RDSAPI, ec2Svc ec2iface.EC2API, rdsCfg *rds.CreateDBInstanceInput) (*providers.PostgresInstance, croType.StatusMessage, error) {
+func (p *PostgresProvider) createRDSInstance(ctx context.Context, cr *v1alpha1.Postgres, rdsSvc rdsiface.RDSAPI, ec2Svc ec2iface.EC2API, rdsCfg *rds.CreateDBInstanceInput, stratCfg *StrategyConfig) (*providers.PostgresInstance, croType.StatusMessage, error) {
 	// the aws access key can sometimes still not be registered in aws on first try, so loop
 	pi, err := getRDSInstances(rdsSvc)
 	if err != nil {
@@ -175,6 +206,14 @@ func (p *PostgresProvider) createRDSInstance(ctx context.Context, cr *v1alpha1.P
 		return nil, croType.StatusMessage(msg), errorUtil.Wrap(err, msg)
 	}
 
+	// windows declared on the cr or tier take precedence over the create strategy
+	if window := getMaintenanceWindow(cr.Spec.MaintenanceWindow, stratCfg); window != "" {
+		rdsCfg.PreferredMaintenanceWindow = aws.String(window)
+	}
+	if window := getBackupWindow(cr.Spec.BackupWindow, stratCfg); window != "" {
+		rdsCfg.PreferredBackupWindow = aws.String(window)
+	}
+
 	// verify and build rds create config
 	if err := p.buildRDSCreateStrategy(ctx, cr, ec2Svc, rdsCfg, postgresPass); err != nil {
 		msg := "failed to build and verify aws rds instance configuration"
@@ -190,8 +229,13 @@ func (p *PostgresProvider) createRDSInstance(ctx context.Context, cr *v1alpha1.P
 		}
 	}
 
-	// create rds instance if it doesn't exist
+	// create rds instance if it doesn't exist, an instance which is adopted is never created
+	adoptID := resources.GetAdoptIdentifier(cr, cr.Spec.AdoptIdentifier)
 	if foundInstance == nil {
+		if adoptID != "" {
+			errMsg := fmt.Sprintf("Postgres CR %s in %s namespace adopts RDS instance %s, but it was not found", cr.Name, cr.Namespace, adoptID)
+			return nil, croType.StatusMessage(errMsg), fmt.Errorf(errMsg)
+		}
 		if annotations.Has(cr, resourceIdentifierAnnotation) {
 			errMsg := fmt.Sprintf("Postgres CR %s in %s namespace has %s annotation with value %s, but no corresponding RDS instance was found",
 				cr.Name, cr.Namespace, resourceIdentifierAnnotation, cr.ObjectMeta.Annotations[resourceIdentifierAnnotation])
@@ -202,6 +246,9 @@ func (p *PostgresProvider) createRDSInstance(ctx context.Context, cr *v1alpha1.P
 		if _, err := rdsSvc.CreateDBInstance(rdsCfg); err != nil {
 			return nil, croType.StatusMessage(fmt.Sprintf("error creating rds instance %s", err)), err
 		}
+		if resources.IsDryRun() {
+			return nil, "dry run, rds instance would be created", nil
+		}
 
 		annotations.Add(cr, resourceIdentifierAnnotation, *rdsCfg.DBInstanceIdentifier)
 		if err := p.Client.Update(ctx, cr); err != nil {
@@ -210,6 +257,27 @@ func (p *PostgresProvider) createRDSInstance(ctx context.Context, cr *v1alpha1.P
 		return nil, "started rds provision", nil
 	}
 
+	// adopt the existing instance, it is managed like an instance created by the operator from then on
+	if adoptID != "" && !annotations.Has(cr, resourceIdentifierAnnotation) {
+		if err := verifyRDSAdoption(foundInstance); err != nil {
+			errMsg := fmt.Sprintf("failed to adopt rds instance %s", adoptID)
+			return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+		// an instance which was retained is no longer recorded in the inventory once it is adopted
+		if err := resources.RemoveFromInventory(ctx, p.Client, cr.Namespace, string(providers.PostgresResourceType), adoptID); err != nil {
+			errMsg := fmt.Sprintf("failed to remove rds instance %s from inventory", adoptID)
+			return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+		// the master password of an adopted instance is unknown, it is reset to the generated password by rotating it
+		annotations.Add(cr, resourceIdentifierAnnotation, adoptID)
+		annotations.Add(cr, resources.RotateCredentialsAnnotation, "true")
+		if err := p.Client.Update(ctx, cr); err != nil {
+			return nil, croType.StatusMessage("failed to add annotation"), err
+		}
+		p.Logger.Infof("adopted rds instance %s", adoptID)
+		return nil, croType.StatusMessage(fmt.Sprintf("adopted rds instance %s", adoptID)), nil
+	}
+
 	// expose pending maintenance metric
 	defer p.setPostgresServiceMaintenanceMetric(ctx, cr, rdsSvc, foundInstance)
 
@@ -225,18 +293,60 @@ func (p *PostgresProvider) createRDSInstance(ctx context.Context, cr *v1alpha1.P
 		return nil, croType.StatusMessage(fmt.Sprintf("createRDSInstance() in progress, current aws rds resource status is %s", *foundInstance.DBInstanceStatus)), nil
 	}
 
+	// record the drift from the desired configuration before it is corrected
+	drift, err := p.getRDSDrift(ctx, cr, rdsSvc, rdsCfg, foundInstance)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to check drift of rds instance %s", *foundInstance.DBInstanceIdentifier)
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
 	// check if found instance and user strategy differs, and modify instance
 	logrus.Infof("found existing rds instance: %s", *foundInstance.DBInstanceIdentifier)
 	mi := buildRDSUpdateStrategy(rdsCfg, foundInstance)
 	if mi == nil {
 		logrus.Infof("rds instance %s is as expected", *foundInstance.DBInstanceIdentifier)
 	}
+	var pendingModifications []croType.PendingModification
+	var pendingPlanHash string
 	if mi != nil {
-		if _, err = rdsSvc.ModifyDBInstance(mi); err != nil {
-			errMsg := fmt.Sprintf("error experienced trying to modify db instance: %s", *foundInstance.DBInstanceIdentifier)
-			return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		// online changes are modified as before, disruptive changes are deferred to the maintenance window or until
+		// the plan is approved, and are then applied immediately
+		online, disruptive := splitRDSUpdateStrategy(mi, foundInstance)
+		planApproved := false
+		if disruptive != nil {
+			window := aws.StringValue(foundInstance.PreferredMaintenanceWindow)
+			if rdsCfg.PreferredMaintenanceWindow != nil {
+				window = *rdsCfg.PreferredMaintenanceWindow
+			}
+			allowed, planHash, err := isDisruptiveChangeAllowed(cr, stratCfg, window, disruptive)
+			if err != nil {
+				errMsg := fmt.Sprintf("failed to check if disruptive modifications of rds instance %s are allowed", *foundInstance.DBInstanceIdentifier)
+				return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+			}
+			if allowed {
+				online = mi
+				online.ApplyImmediately = aws.Bool(true)
+				planApproved = planHash != ""
+			} else {
+				logrus.Infof("deferring disruptive modifications of rds instance %s", *foundInstance.DBInstanceIdentifier)
+				pendingModifications = buildRDSPendingModificationStatus(disruptive)
+				pendingPlanHash = planHash
+			}
+		}
+		if online != nil {
+			if _, err = rdsSvc.ModifyDBInstance(online); err != nil {
+				errMsg := fmt.Sprintf("error experienced trying to modify db instance: %s", *foundInstance.DBInstanceIdentifier)
+				return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+			}
+			logrus.Infof("set pending modifications for rds instance: %s", *foundInstance.DBInstanceIdentifier)
+		}
+		if planApproved {
+			annotations.Remove(cr, resources.ApprovePlanAnnotation)
+			if err := p.Client.Update(ctx, cr); err != nil {
+				errMsg := fmt.Sprintf("failed to remove %s annotation from postgres instance %s", resources.ApprovePlanAnnotation, cr.Name)
+				return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+			}
 		}
-		logrus.Infof("set pending modifications for rds instance: %s", *foundInstance.DBInstanceIdentifier)
 	}
 
 	// Add Tags to Aws Postgres resources
@@ -246,29 +356,227 @@ func (p *PostgresProvider) createRDSInstance(ctx context.Context, cr *v1alpha1.P
 		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
 	}
 
+	// rotate the master password if requested or the rotation interval has passed, the password is never rotated in dry
+	// run mode as it would be promoted without having been set
+	rotationMsg, err := p.reconcileMasterPasswordRotation(ctx, cr, rdsSvc, foundInstance, credSec)
+	if err != nil {
+		return nil, rotationMsg, err
+	}
+	if rotationMsg != croType.StatusEmpty {
+		return nil, rotationMsg, nil
+	}
+
+	// apply pending maintenance inside the maintenance window or when approved
+	if msg, err := p.reconcilePendingMaintenance(ctx, cr, rdsSvc, foundInstance, stratCfg); err != nil {
+		return nil, msg, err
+	}
+
+	// the status is persisted with the phase of the cr by the controller
+	cr.Status.PendingModifications = pendingModifications
+	cr.Status.PendingPlanHash = pendingPlanHash
+	p.exposePostgresDriftMetric(ctx, cr, foundInstance, drift)
+	cr.Status.Drift = drift
+
 	pdd := &providers.PostgresDeploymentDetails{
-		Username: *foundInstance.MasterUsername,
-		Password: postgresPass,
-		Host:     *foundInstance.Endpoint.Address,
-		Database: *foundInstance.DBName,
-		Port:     int(*foundInstance.Endpoint.Port),
+		Username:          *foundInstance.MasterUsername,
+		Password:          string(credSec.Data[defaultPostgresPasswordKey]),
+		Host:              *foundInstance.Endpoint.Address,
+		Database:          getRDSDatabaseName(foundInstance),
+		Port:              int(*foundInstance.Endpoint.Port),
+		PasswordRotatedAt: string(credSec.Data[defaultPostgresPasswordRotatedAtKey]),
 	}
 
 	// return secret information
 	return &providers.PostgresInstance{DeploymentDetails: pdd}, croType.StatusMessage(fmt.Sprintf("%s, aws rds status is %s", msg, *foundInstance.DBInstanceStatus)), nil
 }
 
-// TagRDSPostgres Tags RDS resources
-func (p *PostgresProvider) TagRDSPostgres(ctx context.Context, cr *v1alpha1.Postgres, rdsSvc rdsiface.RDSAPI, foundInstance *rds.DBInstance) (croType.StatusMessage, error) {
-	logrus.Infof("adding tags to rds instance %s", *foundInstance.DBInstanceIdentifier)
+// verifyRDSAdoption verifies an existing rds instance can be adopted by a postgres cr
+func verifyRDSAdoption(instance *rds.DBInstance) error {
+	if aws.StringValue(instance.Engine) != defaultAwsEngine {
+		return fmt.Errorf("rds instance %s has engine %s, expected %s", aws.StringValue(instance.DBInstanceIdentifier), aws.StringValue(instance.Engine), defaultAwsEngine)
+	}
+	return nil
+}
+
+// getRDSDatabaseName returns the name of the database of an rds instance, instances created without a database name,
+// which may be the case for adopted instances, only have the default database
+func getRDSDatabaseName(instance *rds.DBInstance) string {
+	if aws.StringValue(instance.DBName) == "" {
+		return defaultAwsPostgresDatabase
+	}
+	return *instance.DBName
+}
+
+// reconcilePendingMaintenance records the maintenance actions pending on the rds instance in the status of the cr, and
+// opts in to applying them immediately inside the maintenance window of the cr or tier, or when approved through an
+// annotation. maintenance which has been opted in to remains pending until aws has applied it
+func (p *PostgresProvider) reconcilePendingMaintenance(ctx context.Context, cr *v1alpha1.Postgres, rdsSvc rdsiface.RDSAPI, foundInstance *rds.DBInstance, stratCfg *StrategyConfig) (croType.StatusMessage, error) {
+	pendingActions, err := getRDSPendingMaintenanceActions(rdsSvc, foundInstance)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to get pending maintenance for rds instance %s", *foundInstance.DBInstanceIdentifier)
+		return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
+	allowed, err := resources.IsMaintenanceAllowed(cr, getMaintenanceWindow(cr.Spec.MaintenanceWindow, stratCfg), time.Now())
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to check maintenance window of postgres instance %s", cr.Name)
+		return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	if allowed {
+		for _, pma := range pendingActions {
+			if aws.StringValue(pma.OptInStatus) == rdsOptInTypeImmediate {
+				continue
+			}
+			logrus.Infof("applying pending maintenance action %s to rds instance %s", aws.StringValue(pma.Action), *foundInstance.DBInstanceIdentifier)
+			if _, err := rdsSvc.ApplyPendingMaintenanceAction(&rds.ApplyPendingMaintenanceActionInput{
+				ResourceIdentifier: foundInstance.DBInstanceArn,
+				ApplyAction:        pma.Action,
+				OptInType:          aws.String(rdsOptInTypeImmediate),
+			}); err != nil {
+				errMsg := fmt.Sprintf("failed to apply pending maintenance action %s to rds instance %s", aws.StringValue(pma.Action), *foundInstance.DBInstanceIdentifier)
+				return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+			}
+			pma.OptInStatus = aws.String(rdsOptInTypeImmediate)
+		}
+		if annotations.Has(cr, resources.ApplyMaintenanceAnnotation) {
+			annotations.Remove(cr, resources.ApplyMaintenanceAnnotation)
+			if err := p.Client.Update(ctx, cr); err != nil {
+				errMsg := fmt.Sprintf("failed to remove %s annotation from postgres instance %s", resources.ApplyMaintenanceAnnotation, cr.Name)
+				return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+			}
+		}
+	}
+
+	// the status is persisted with the phase of the cr by the controller
+	cr.Status.PendingMaintenance = buildRDSPendingMaintenanceStatus(pendingActions)
+	return croType.StatusEmpty, nil
+}
+
+func getRDSPendingMaintenanceActions(rdsSvc rdsiface.RDSAPI, instance *rds.DBInstance) ([]*rds.PendingMaintenanceAction, error) {
+	output, err := rdsSvc.DescribePendingMaintenanceActions(&rds.DescribePendingMaintenanceActionsInput{
+		ResourceIdentifier: instance.DBInstanceArn,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var pendingActions []*rds.PendingMaintenanceAction
+	for _, rpma := range output.PendingMaintenanceActions {
+		if aws.StringValue(rpma.ResourceIdentifier) != aws.StringValue(instance.DBInstanceArn) {
+			continue
+		}
+		pendingActions = append(pendingActions, rpma.PendingMaintenanceActionDetails...)
+	}
+	return pendingActions, nil
+}
+
+func buildRDSPendingMaintenanceStatus(pendingActions []*rds.PendingMaintenanceAction) []croType.PendingMaintenanceAction {
+	var status []croType.PendingMaintenanceAction
+	for _, pma := range pendingActions {
+		action := croType.PendingMaintenanceAction{
+			Action:      aws.StringValue(pma.Action),
+			Description: aws.StringValue(pma.Description),
+			OptInStatus: aws.StringValue(pma.OptInStatus),
+		}
+		if pma.AutoAppliedAfterDate != nil {
+			action.AutoAppliedAfterDate = pma.AutoAppliedAfterDate.UTC().Format(time.RFC3339)
+		}
+		if pma.ForcedApplyDate != nil {
+			action.ForcedApplyDate = pma.ForcedApplyDate.UTC().Format(time.RFC3339)
+		}
+		status = append(status, action)
+	}
+	return status
+}
+
+// reconcileMasterPasswordRotation sets a new master password on the rds instance when a rotation is requested or due.
+// the new password is kept as pending in the credential secret until aws has applied it, so the result secret is only
+// updated with a password which is in use. an empty status message is returned once no rotation is in progress
+func (p *PostgresProvider) reconcileMasterPasswordRotation(ctx context.Context, cr *v1alpha1.Postgres, rdsSvc rdsiface.RDSAPI, foundInstance *rds.DBInstance, credSec *v1.Secret) (croType.StatusMessage, error) {
+	if resources.IsDryRun() {
+		return croType.StatusEmpty, nil
+	}
+	if pendingPass, ok := credSec.Data[defaultPostgresPendingPasswordKey]; ok {
+		if foundInstance.PendingModifiedValues != nil && foundInstance.PendingModifiedValues.MasterUserPassword != nil {
+			return "master password rotation in progress", nil
+		}
+
+		// aws has applied the new password, promote it
+		credSec.Data[defaultPostgresPasswordKey] = pendingPass
+		credSec.Data[defaultPostgresPasswordRotatedAtKey] = []byte(time.Now().UTC().Format(time.RFC3339))
+		delete(credSec.Data, defaultPostgresPendingPasswordKey)
+		if err := p.Client.Update(ctx, credSec); err != nil {
+			errMsg := "failed to promote rotated master password"
+			return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+		if annotations.Has(cr, resources.RotateCredentialsAnnotation) {
+			annotations.Remove(cr, resources.RotateCredentialsAnnotation)
+			if err := p.Client.Update(ctx, cr); err != nil {
+				errMsg := "failed to remove rotate credentials annotation"
+				return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+			}
+		}
+		p.Logger.Infof("rotated master password for rds instance %s", *foundInstance.DBInstanceIdentifier)
+		return croType.StatusEmpty, nil
+	}
+
+	// the creation time of the instance is used if the password has never been rotated
+	lastRotation := time.Time{}
+	if foundInstance.InstanceCreateTime != nil {
+		lastRotation = *foundInstance.InstanceCreateTime
+	}
+	if rotatedAt, ok := credSec.Data[defaultPostgresPasswordRotatedAtKey]; ok {
+		t, err := time.Parse(time.RFC3339, string(rotatedAt))
+		if err != nil {
+			errMsg := "failed to parse master password rotation time"
+			return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+		lastRotation = t
+	}
+	due, err := resources.IsRotationDue(cr, cr.Spec.RotationInterval, lastRotation)
+	if err != nil {
+		errMsg := "failed to check if master password rotation is due"
+		return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	if !due {
+		return croType.StatusEmpty, nil
+	}
+
+	// store the new password before it is set, so it is not lost if the operator restarts
+	pendingPass, err := resources.GeneratePassword()
+	if err != nil {
+		errMsg := "failed to generate master password"
+		return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	credSec.Data[defaultPostgresPendingPasswordKey] = []byte(pendingPass)
+	if err := p.Client.Update(ctx, credSec); err != nil {
+		errMsg := "failed to store pending master password"
+		return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
+	if _, err := rdsSvc.ModifyDBInstance(&rds.ModifyDBInstanceInput{
+		DBInstanceIdentifier: foundInstance.DBInstanceIdentifier,
+		MasterUserPassword:   aws.String(pendingPass),
+		ApplyImmediately:     aws.Bool(true),
+	}); err != nil {
+		// discard the pending password so it is not promoted without being set
+		delete(credSec.Data, defaultPostgresPendingPasswordKey)
+		if updateErr := p.Client.Update(ctx, credSec); updateErr != nil {
+			p.Logger.Errorf("failed to discard pending master password: %v", updateErr)
+		}
+		errMsg := fmt.Sprintf("failed to set new master password on rds instance %s", *foundInstance.DBInstanceIdentifier)
+		return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	p.Logger.Infof("started master password rotation for rds instance %s", *foundInstance.DBInstanceIdentifier)
+	return "started master password rotation", nil
+}
+
+// buildRDSTags returns the tags which are always added to rds resources
+func buildRDSTags(cr *v1alpha1.Postgres, clusterID string) []*rds.Tag {
 	// get the environment from the CR
 	// set the tag values that will always be added
 	defaultOrganizationTag := resources.GetOrganizationTag()
 
-	//get Cluster Id
-	clusterID, _ := resources.GetClusterID(ctx, p.Client)
 	// Set the Tag values
-
 	rdsTag := []*rds.Tag{
 		{
 			Key:   aws.String(defaultOrganizationTag + "clusterID"),
@@ -290,6 +598,16 @@ func (p *PostgresProvider) TagRDSPostgres(ctx context.Context, cr *v1alpha1.Post
 		}
 		rdsTag = append(rdsTag, productTag)
 	}
+	return rdsTag
+}
+
+// TagRDSPostgres Tags RDS resources
+func (p *PostgresProvider) TagRDSPostgres(ctx context.Context, cr *v1alpha1.Postgres, rdsSvc rdsiface.RDSAPI, foundInstance *rds.DBInstance) (croType.StatusMessage, error) {
+	logrus.Infof("adding tags to rds instance %s", *foundInstance.DBInstanceIdentifier)
+
+	//get Cluster Id
+	clusterID, _ := resources.GetClusterID(ctx, p.Client)
+	rdsTag := buildRDSTags(cr, clusterID)
 
 	// adding tags to rds postgres instance
 	_, err := rdsSvc.AddTagsToResource(&rds.AddTagsToResourceInput{
@@ -330,6 +648,12 @@ func (p *PostgresProvider) TagRDSPostgres(ctx context.Context, cr *v1alpha1.Post
 }
 
 func (p *PostgresProvider) DeletePostgres(ctx context.Context, r *v1alpha1.Postgres) (croType.StatusMessage, error) {
+	// the cloud resource of a cr with an adoption conflict is not deleted, it could be the adopted one
+	if err := validateAdoption(r, r.Spec.AdoptIdentifier); err != nil {
+		errMsg := fmt.Sprintf("invalid adoption of postgres instance %s", r.Name)
+		return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
 	// resolve postgres information for postgres created by provider
 	rdsCreateConfig, rdsDeleteConfig, stratCfg, err := p.getRDSConfig(ctx, r)
 	if err != nil {
@@ -350,10 +674,10 @@ func (p *PostgresProvider) DeletePostgres(ctx context.Context, r *v1alpha1.Postg
 		return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
 	}
 
-	return p.deleteRDSInstance(ctx, r, rds.New(sess), rdsCreateConfig, rdsDeleteConfig)
+	return p.deleteRDSInstance(ctx, r, rds.New(sess), rdsCreateConfig, rdsDeleteConfig, stratCfg)
 }
 
-func (p *PostgresProvider) deleteRDSInstance(ctx context.Context, pg *v1alpha1.Postgres, instanceSvc rdsiface.RDSAPI, rdsCreateConfig *rds.CreateDBInstanceInput, rdsDeleteConfig *rds.DeleteDBInstanceInput) (croType.StatusMessage, error) {
+func (p *PostgresProvider) deleteRDSInstance(ctx context.Context, pg *v1alpha1.Postgres, instanceSvc rdsiface.RDSAPI, rdsCreateConfig *rds.CreateDBInstanceInput, rdsDeleteConfig *rds.DeleteDBInstanceInput, stratCfg *StrategyConfig) (croType.StatusMessage, error) {
 	// the aws access key can sometimes still not be registered in aws on first try, so loop
 	pgs, err := getRDSInstances(instanceSvc)
 	if err != nil {
@@ -377,26 +701,29 @@ func (p *PostgresProvider) deleteRDSInstance(ctx context.Context, pg *v1alpha1.P
 
 	// check if instance does not exist, delete finalizer and credential secret
 	if foundInstance == nil {
-		// delete credential secret
-		p.Logger.Info("deleting rds secret")
-		sec := &v1.Secret{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      pg.Name + defaultCredSecSuffix,
-				Namespace: pg.Namespace,
-			},
-		}
-		err = p.Client.Delete(ctx, sec)
-		if err != nil && !k8serr.IsNotFound(err) {
-			msg := "failed to deleted rds secrets"
+		// an instance whose deletion was delayed is no longer recorded in the inventory once it is deleted
+		if err := resources.RemoveFromInventory(ctx, p.Client, pg.Namespace, string(providers.PostgresResourceType), *rdsDeleteConfig.DBInstanceIdentifier); err != nil {
+			msg := "failed to remove rds instance from inventory"
 			return croType.StatusMessage(msg), errorUtil.Wrap(err, msg)
 		}
+		return p.removeRDSSecretAndFinalizer(ctx, pg)
+	}
 
-		resources.RemoveFinalizer(&pg.ObjectMeta, DefaultFinalizer)
-		if err := p.Client.Update(ctx, pg); err != nil {
-			msg := "failed to update instance as part of finalizer reconcile"
-			return croType.StatusMessage(msg), errorUtil.Wrapf(err, msg)
-		}
-		return croType.StatusEmpty, nil
+	// retain the instance or delay its deletion as described by the deletion policy of the cr
+	action, msg, err := reconcileDeletionPolicy(ctx, p.Client, pg, pg.Spec.DeletionPolicy, pg.Spec.DeletionGracePeriod, resources.InventoryEntry{
+		ResourceType: string(providers.PostgresResourceType),
+		Identifier:   *foundInstance.DBInstanceIdentifier,
+		Region:       stratCfg.Region,
+	})
+	if err != nil {
+		return msg, err
+	}
+	switch action {
+	case deletionActionRetain:
+		p.Logger.Infof("retaining rds instance %s", *foundInstance.DBInstanceIdentifier)
+		return p.removeRDSSecretAndFinalizer(ctx, pg)
+	case deletionActionDelay:
+		return msg, nil
 	}
 
 	// set status metric
@@ -409,6 +736,14 @@ func (p *PostgresProvider) deleteRDSInstance(ctx context.Context, pg *v1alpha1.P
 
 	// delete rds instance if deletion protection is false
 	if !*foundInstance.DeletionProtection {
+		// the final snapshot is recorded before the instance is deleted, the identifier of the final snapshot changes until
+		// the deletion is started
+		if !aws.BoolValue(rdsDeleteConfig.SkipFinalSnapshot) && rdsDeleteConfig.FinalDBSnapshotIdentifier != nil && !resources.IsDryRun() {
+			if err := p.reconcileFinalSnapshot(ctx, pg, *rdsDeleteConfig.FinalDBSnapshotIdentifier, stratCfg); err != nil {
+				msg := "failed to record final rds snapshot"
+				return croType.StatusMessage(msg), errorUtil.Wrap(err, msg)
+			}
+		}
 		_, err = instanceSvc.DeleteDBInstance(rdsDeleteConfig)
 		rdsErr, isAwsErr := err.(awserr.Error)
 		if err != nil && (!isAwsErr || rdsErr.Code() != rds.ErrCodeDBInstanceNotFoundFault) {
@@ -431,6 +766,61 @@ func (p *PostgresProvider) deleteRDSInstance(ctx context.Context, pg *v1alpha1.P
 	return croType.StatusMessage(fmt.Sprintf("deletion protection detected, modifyDBInstance() in progress, current aws rds status is %s", *foundInstance.DBInstanceStatus)), nil
 }
 
+// reconcileFinalSnapshot records the final snapshot taken when the rds instance of a postgres cr is deleted in a
+// postgres snapshot cr, so it can be listed, copied and deleted like any other snapshot
+func (p *PostgresProvider) reconcileFinalSnapshot(ctx context.Context, pg *v1alpha1.Postgres, snapshotID string, stratCfg *StrategyConfig) error {
+	region := stratCfg.Region
+	if region == "" {
+		defaultRegion, err := getDefaultRegion(ctx, p.Client)
+		if err != nil {
+			return errorUtil.Wrap(err, "failed to get region of final snapshot")
+		}
+		region = defaultRegion
+	}
+	snapshot := &v1alpha1.PostgresSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      buildFinalSnapshotName(pg.ObjectMeta),
+			Namespace: pg.Namespace,
+		},
+	}
+	or, err := controllerutil.CreateOrUpdate(ctx, p.Client, snapshot, func() error {
+		snapshot.Labels = pg.Labels
+		snapshot.Spec.ResourceName = pg.Name
+		snapshot.Spec.SnapshotID = snapshotID
+		snapshot.Spec.Region = region
+		return nil
+	})
+	if err != nil {
+		return errorUtil.Wrapf(err, "failed to create or update postgres snapshot %s, action was %s", snapshot.Name, or)
+	}
+	return nil
+}
+
+// removeRDSSecretAndFinalizer deletes the credential secret of a postgres cr and removes its finalizer, once its rds
+// instance has been deleted or retained
+func (p *PostgresProvider) removeRDSSecretAndFinalizer(ctx context.Context, pg *v1alpha1.Postgres) (croType.StatusMessage, error) {
+	// delete credential secret
+	p.Logger.Info("deleting rds secret")
+	sec := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pg.Name + defaultCredSecSuffix,
+			Namespace: pg.Namespace,
+		},
+	}
+	err := p.Client.Delete(ctx, sec)
+	if err != nil && !k8serr.IsNotFound(err) {
+		msg := "failed to deleted rds secrets"
+		return croType.StatusMessage(msg), errorUtil.Wrap(err, msg)
+	}
+
+	resources.RemoveFinalizer(&pg.ObjectMeta, DefaultFinalizer)
+	if err := p.Client.Update(ctx, pg); err != nil {
+		msg := "failed to update instance as part of finalizer reconcile"
+		return croType.StatusMessage(msg), errorUtil.Wrapf(err, msg)
+	}
+	return croType.StatusEmpty, nil
+}
+
 // function to get rds instances, used to check/wait on AWS credentials
 func getRDSInstances(cacheSvc rdsiface.RDSAPI) ([]*rds.DBInstance, error) {
 	var pi []*rds.DBInstance
@@ -472,6 +862,12 @@ func (p *PostgresProvider) getRDSConfig(ctx context.Context, r *v1alpha1.Postgre
 	if err := json.Unmarshal(stratCfg.DeleteStrategy, rdsDeleteConfig); err != nil {
 		return nil, nil, nil, errorUtil.Wrap(err, "failed to unmarshal aws rds cluster configuration")
 	}
+
+	// an adopted instance is identified by the identifier of the existing instance
+	if adoptID := resources.GetAdoptIdentifier(r, r.Spec.AdoptIdentifier); adoptID != "" {
+		rdsCreateConfig.DBInstanceIdentifier = aws.String(adoptID)
+		rdsDeleteConfig.DBInstanceIdentifier = aws.String(adoptID)
+	}
 	return rdsCreateConfig, rdsDeleteConfig, stratCfg, nil
 }
 
@@ -515,12 +911,141 @@ func buildRDSUpdateStrategy(rdsConfig *rds.CreateDBInstanceInput, foundConfig *r
 		mi.MultiAZ = rdsConfig.MultiAZ
 		updateFound = true
 	}
+	if rdsConfig.EnableIAMDatabaseAuthentication != nil && *rdsConfig.EnableIAMDatabaseAuthentication != aws.BoolValue(foundConfig.IAMDatabaseAuthenticationEnabled) {
+		mi.EnableIAMDatabaseAuthentication = rdsConfig.EnableIAMDatabaseAuthentication
+		updateFound = true
+	}
+	if rdsConfig.PreferredMaintenanceWindow != nil && !strings.EqualFold(*rdsConfig.PreferredMaintenanceWindow, aws.StringValue(foundConfig.PreferredMaintenanceWindow)) {
+		mi.PreferredMaintenanceWindow = rdsConfig.PreferredMaintenanceWindow
+		updateFound = true
+	}
+	if rdsConfig.PreferredBackupWindow != nil && *rdsConfig.PreferredBackupWindow != aws.StringValue(foundConfig.PreferredBackupWindow) {
+		mi.PreferredBackupWindow = rdsConfig.PreferredBackupWindow
+		updateFound = true
+	}
 	if !updateFound || !verifyPendingModification(mi, foundConfig.PendingModifiedValues) {
 		return nil
 	}
 	return mi
 }
 
+// getRDSDrift returns the fields of an rds instance, including its tags, which differ from the desired configuration
+func (p *PostgresProvider) getRDSDrift(ctx context.Context, cr *v1alpha1.Postgres, rdsSvc rdsiface.RDSAPI, rdsConfig *rds.CreateDBInstanceInput, foundConfig *rds.DBInstance) ([]croType.DriftedField, error) {
+	drift := buildRDSDrift(rdsConfig, foundConfig)
+
+	clusterID, err := resources.GetClusterID(ctx, p.Client)
+	if err != nil {
+		return nil, errorUtil.Wrap(err, "failed to get cluster id")
+	}
+	output, err := rdsSvc.ListTagsForResource(&rds.ListTagsForResourceInput{
+		ResourceName: foundConfig.DBInstanceArn,
+	})
+	if err != nil {
+		return nil, errorUtil.Wrapf(err, "failed to list tags of rds instance %s", *foundConfig.DBInstanceIdentifier)
+	}
+	desiredTags := map[string]string{}
+	for _, tag := range buildRDSTags(cr, clusterID) {
+		desiredTags[*tag.Key] = aws.StringValue(tag.Value)
+	}
+	observedTags := map[string]string{}
+	for _, tag := range output.TagList {
+		observedTags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return appendTagDrift(drift, desiredTags, observedTags), nil
+}
+
+// buildRDSDrift returns the fields of an rds instance which differ from the desired configuration, including the fields
+// which are not updated by the operator
+func buildRDSDrift(rdsConfig *rds.CreateDBInstanceInput, foundConfig *rds.DBInstance) []croType.DriftedField {
+	var drift []croType.DriftedField
+	drift = appendDrift(drift, "DeletionProtection", strconv.FormatBool(aws.BoolValue(rdsConfig.DeletionProtection)), strconv.FormatBool(aws.BoolValue(foundConfig.DeletionProtection)))
+	if foundConfig.Endpoint != nil {
+		drift = appendDrift(drift, "DBPortNumber", strconv.FormatInt(aws.Int64Value(rdsConfig.Port), 10), strconv.FormatInt(aws.Int64Value(foundConfig.Endpoint.Port), 10))
+	}
+	drift = appendDrift(drift, "BackupRetentionPeriod", strconv.FormatInt(aws.Int64Value(rdsConfig.BackupRetentionPeriod), 10), strconv.FormatInt(aws.Int64Value(foundConfig.BackupRetentionPeriod), 10))
+	drift = appendDrift(drift, "DBInstanceClass", aws.StringValue(rdsConfig.DBInstanceClass), aws.StringValue(foundConfig.DBInstanceClass))
+	drift = appendDrift(drift, "PubliclyAccessible", strconv.FormatBool(aws.BoolValue(rdsConfig.PubliclyAccessible)), strconv.FormatBool(aws.BoolValue(foundConfig.PubliclyAccessible)))
+	drift = appendDrift(drift, "AllocatedStorage", strconv.FormatInt(aws.Int64Value(rdsConfig.AllocatedStorage), 10), strconv.FormatInt(aws.Int64Value(foundConfig.AllocatedStorage), 10))
+	drift = appendDrift(drift, "EngineVersion", aws.StringValue(rdsConfig.EngineVersion), aws.StringValue(foundConfig.EngineVersion))
+	drift = appendDrift(drift, "MultiAZ", strconv.FormatBool(aws.BoolValue(rdsConfig.MultiAZ)), strconv.FormatBool(aws.BoolValue(foundConfig.MultiAZ)))
+	if rdsConfig.EnableIAMDatabaseAuthentication != nil {
+		drift = appendDrift(drift, "EnableIAMDatabaseAuthentication", strconv.FormatBool(*rdsConfig.EnableIAMDatabaseAuthentication), strconv.FormatBool(aws.BoolValue(foundConfig.IAMDatabaseAuthenticationEnabled)))
+	}
+	if rdsConfig.PreferredMaintenanceWindow != nil && !strings.EqualFold(*rdsConfig.PreferredMaintenanceWindow, aws.StringValue(foundConfig.PreferredMaintenanceWindow)) {
+		drift = appendDrift(drift, "PreferredMaintenanceWindow", *rdsConfig.PreferredMaintenanceWindow, aws.StringValue(foundConfig.PreferredMaintenanceWindow))
+	}
+	if rdsConfig.PreferredBackupWindow != nil {
+		drift = appendDrift(drift, "PreferredBackupWindow", *rdsConfig.PreferredBackupWindow, aws.StringValue(foundConfig.PreferredBackupWindow))
+	}
+
+	// encryption and security groups can not be changed by modifying the instance, they are only reported
+	if rdsConfig.StorageEncrypted != nil {
+		drift = appendDrift(drift, "StorageEncrypted", strconv.FormatBool(*rdsConfig.StorageEncrypted), strconv.FormatBool(aws.BoolValue(foundConfig.StorageEncrypted)))
+	}
+	if rdsConfig.KmsKeyId != nil {
+		drift = appendDrift(drift, "KmsKeyId", *rdsConfig.KmsKeyId, aws.StringValue(foundConfig.KmsKeyId))
+	}
+	if rdsConfig.VpcSecurityGroupIds != nil {
+		var foundSecurityGroupIds []*string
+		for _, sg := range foundConfig.VpcSecurityGroups {
+			foundSecurityGroupIds = append(foundSecurityGroupIds, sg.VpcSecurityGroupId)
+		}
+		drift = appendDrift(drift, "VpcSecurityGroupIds", formatDriftList(rdsConfig.VpcSecurityGroupIds), formatDriftList(foundSecurityGroupIds))
+	}
+	return drift
+}
+
+// splitRDSUpdateStrategy splits a modification of an rds instance into the changes which are applied online and the
+// disruptive changes, which cause downtime. nil is returned for either if it has no changes
+func splitRDSUpdateStrategy(mi *rds.ModifyDBInstanceInput, foundConfig *rds.DBInstance) (*rds.ModifyDBInstanceInput, *rds.ModifyDBInstanceInput) {
+	online := *mi
+	disruptive := &rds.ModifyDBInstanceInput{DBInstanceIdentifier: mi.DBInstanceIdentifier}
+	disruptiveFound := false
+
+	// changing the instance class, engine version or port restarts the instance
+	if mi.DBInstanceClass != nil {
+		disruptive.DBInstanceClass, online.DBInstanceClass = mi.DBInstanceClass, nil
+		disruptiveFound = true
+	}
+	if mi.EngineVersion != nil {
+		disruptive.EngineVersion, online.EngineVersion = mi.EngineVersion, nil
+		disruptiveFound = true
+	}
+	if mi.DBPortNumber != nil {
+		disruptive.DBPortNumber, online.DBPortNumber = mi.DBPortNumber, nil
+		disruptiveFound = true
+	}
+	// enabling or disabling automated backups restarts the instance
+	if mi.BackupRetentionPeriod != nil && (*mi.BackupRetentionPeriod == 0) != (aws.Int64Value(foundConfig.BackupRetentionPeriod) == 0) {
+		disruptive.BackupRetentionPeriod, online.BackupRetentionPeriod = mi.BackupRetentionPeriod, nil
+		disruptiveFound = true
+	}
+	if !disruptiveFound {
+		return mi, nil
+	}
+	if reflect.DeepEqual(online, rds.ModifyDBInstanceInput{DBInstanceIdentifier: mi.DBInstanceIdentifier}) {
+		return nil, disruptive
+	}
+	return &online, disruptive
+}
+
+func buildRDSPendingModificationStatus(mi *rds.ModifyDBInstanceInput) []croType.PendingModification {
+	var status []croType.PendingModification
+	if mi.DBInstanceClass != nil {
+		status = append(status, croType.PendingModification{Field: "DBInstanceClass", Value: *mi.DBInstanceClass})
+	}
+	if mi.EngineVersion != nil {
+		status = append(status, croType.PendingModification{Field: "EngineVersion", Value: *mi.EngineVersion})
+	}
+	if mi.DBPortNumber != nil {
+		status = append(status, croType.PendingModification{Field: "DBPortNumber", Value: strconv.FormatInt(*mi.DBPortNumber, 10)})
+	}
+	if mi.BackupRetentionPeriod != nil {
+		status = append(status, croType.PendingModification{Field: "BackupRetentionPeriod", Value: strconv.FormatInt(*mi.BackupRetentionPeriod, 10)})
+	}
+	return status
+}
+
 // returns true if modify input is not pending
 func verifyPendingModification(mi *rds.ModifyDBInstanceInput, pm *rds.PendingModifiedValues) bool {
 	pendingModifications := true
@@ -662,6 +1187,11 @@ func (p *PostgresProvider) buildRDSDeleteConfig(ctx context.Context, pg *v1alpha
 	if rdsDeleteConfig.DeleteAutomatedBackups == nil {
 		rdsDeleteConfig.DeleteAutomatedBackups = aws.Bool(defaultAwsDeleteAutomatedBackups)
 	}
+	// the Snapshot deletion policy of the cr always takes a final snapshot, the Delete deletion policy keeps the final
+	// snapshot settings of the delete strategy
+	if pg.Spec.DeletionPolicy == resources.DeletionPolicySnapshot {
+		rdsDeleteConfig.SkipFinalSnapshot = aws.Bool(false)
+	}
 	if rdsDeleteConfig.SkipFinalSnapshot == nil {
 		rdsDeleteConfig.SkipFinalSnapshot = aws.Bool(defaultAwsSkipFinalSnapshot)
 	}
@@ -795,6 +1325,17 @@ func (p *PostgresProvider) exposePostgresMetrics(ctx context.Context, cr *v1alph
 	resources.SetMetric(resources.DefaultPostgresAvailMetricName, genericLabels, 1)
 }
 
+// exposePostgresDriftMetric exposes the drift of an rds instance, the drift of the last reconcile is read from the status
+// of the cr
+func (p *PostgresProvider) exposePostgresDriftMetric(ctx context.Context, cr *v1alpha1.Postgres, instance *rds.DBInstance, drift []croType.DriftedField) {
+	clusterID, err := resources.GetClusterID(ctx, p.Client)
+	if err != nil {
+		logrus.Error(fmt.Sprintf("failed to get cluster id while exposing drift metric for %s", *instance.DBInstanceIdentifier))
+		return
+	}
+	exposeDriftMetric(buildPostgresGenericMetricLabels(cr, instance, clusterID), cr.Status.Drift, drift)
+}
+
 func (p *PostgresProvider) setPostgresServiceMaintenanceMetric(ctx context.Context, cr *v1alpha1.Postgres, rdsSession rdsiface.RDSAPI, instance *rds.DBInstance) {
 	logrus.Info("checking for pending postgres service updates")
 	clusterID, err := resources.GetClusterID(ctx, p.Client)
@@ -803,32 +1344,31 @@ func (p *PostgresProvider) setPostgresServiceMaintenanceMetric(ctx context.Conte
 		return
 	}
 
-	// Retrieve service maintenance updates, create and export Prometheus metrics
-	output, err := rdsSession.DescribePendingMaintenanceActions(&rds.DescribePendingMaintenanceActionsInput{})
+	// Retrieve service maintenance updates of the instance, create and export Prometheus metrics
+	pendingActions, err := getRDSPendingMaintenanceActions(rdsSession, instance)
 	if err != nil {
 		logrus.Error(fmt.Sprintf("failed to get maintenance information while exposing maintenance metric for %s", *instance.DBInstanceIdentifier))
 		return
 	}
 
-	logrus.Infof("rds serviceupdates: %d available", len(output.PendingMaintenanceActions))
-	for _, su := range output.PendingMaintenanceActions {
+	logrus.Infof("rds serviceupdates: %d available for %s", len(pendingActions), *instance.DBInstanceIdentifier)
+	for _, pma := range pendingActions {
+		// actions without an apply date are only applied when opted in to
+		if pma.AutoAppliedAfterDate == nil || pma.CurrentApplyDate == nil {
+			continue
+		}
 		metricLabels := map[string]string{}
 
 		metricLabels["clusterID"] = clusterID
-		metricLabels["ResourceIdentifier"] = *su.ResourceIdentifier
-
-		for _, pma := range su.PendingMaintenanceActionDetails {
+		metricLabels["ResourceIdentifier"] = aws.StringValue(instance.DBInstanceArn)
+		metricLabels["AutoAppliedAfterDate"] = strconv.FormatInt((*pma.AutoAppliedAfterDate).Unix(), 10)
+		metricLabels["CurrentApplyDate"] = strconv.FormatInt((*pma.CurrentApplyDate).Unix(), 10)
+		metricLabels["Description"] = aws.StringValue(pma.Description)
 
-			metricLabels["AutoAppliedAfterDate"] = strconv.FormatInt((*pma.AutoAppliedAfterDate).Unix(), 10)
-			metricLabels["CurrentApplyDate"] = strconv.FormatInt((*pma.CurrentApplyDate).Unix(), 10)
-			metricLabels["Description"] = *pma.Description
+		metricEpochTimestamp := (*pma.AutoAppliedAfterDate).Unix()
 
-			metricEpochTimestamp := (*pma.AutoAppliedAfterDate).Unix()
-
-			resources.SetMetric(resources.DefaultPostgresMaintenanceMetricName, metricLabels, float64(metricEpochTimestamp))
-		}
+		resources.SetMetric(resources.DefaultPostgresMaintenanceMetricName, metricLabels, float64(metricEpochTimestamp))
 	}
-
 }
 
 // tests to see if a simple tcp connection can be made to rds and creates a metric based on this
@@ -4,12 +4,15 @@ import (
 	"context"
 	v12 "github.com/integr8ly/cloud-resource-operator/pkg/apis/config/v1"
 	"reflect"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/integr8ly/cloud-resource-operator/pkg/annotations"
 	"github.com/integr8ly/cloud-resource-operator/pkg/providers"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/rds"
@@ -24,6 +27,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	apimachinery "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -36,6 +40,10 @@ type mockRdsClient struct {
 	wantErrDelete bool
 	wantEmpty     bool
 	dbInstances   []*rds.DBInstance
+	// pending maintenance actions keyed by resource arn
+	pendingMaintenance map[string][]*rds.PendingMaintenanceAction
+	appliedMaintenance []string
+	modifyCalls        []*rds.ModifyDBInstanceInput
 }
 
 type mockEc2Client struct {
@@ -79,7 +87,8 @@ func (m *mockRdsClient) CreateDBInstance(*rds.CreateDBInstanceInput) (*rds.Creat
 	return &rds.CreateDBInstanceOutput{}, nil
 }
 
-func (m *mockRdsClient) ModifyDBInstance(*rds.ModifyDBInstanceInput) (*rds.ModifyDBInstanceOutput, error) {
+func (m *mockRdsClient) ModifyDBInstance(input *rds.ModifyDBInstanceInput) (*rds.ModifyDBInstanceOutput, error) {
+	m.modifyCalls = append(m.modifyCalls, input)
 	return &rds.ModifyDBInstanceOutput{}, nil
 }
 
@@ -91,12 +100,31 @@ func (m *mockRdsClient) AddTagsToResource(input *rds.AddTagsToResourceInput) (*r
 	return &rds.AddTagsToResourceOutput{}, nil
 }
 
+func (m *mockRdsClient) ListTagsForResource(input *rds.ListTagsForResourceInput) (*rds.ListTagsForResourceOutput, error) {
+	return &rds.ListTagsForResourceOutput{}, nil
+}
+
 func (m *mockRdsClient) DescribeDBSnapshots(input *rds.DescribeDBSnapshotsInput) (*rds.DescribeDBSnapshotsOutput, error) {
 	return &rds.DescribeDBSnapshotsOutput{}, nil
 }
 
-func (m *mockRdsClient) DescribePendingMaintenanceActions(*rds.DescribePendingMaintenanceActionsInput) (*rds.DescribePendingMaintenanceActionsOutput, error) {
-	return &rds.DescribePendingMaintenanceActionsOutput{}, nil
+func (m *mockRdsClient) DescribePendingMaintenanceActions(input *rds.DescribePendingMaintenanceActionsInput) (*rds.DescribePendingMaintenanceActionsOutput, error) {
+	output := &rds.DescribePendingMaintenanceActionsOutput{}
+	for arn, actions := range m.pendingMaintenance {
+		if input.ResourceIdentifier != nil && *input.ResourceIdentifier != arn {
+			continue
+		}
+		output.PendingMaintenanceActions = append(output.PendingMaintenanceActions, &rds.ResourcePendingMaintenanceActions{
+			ResourceIdentifier:              aws.String(arn),
+			PendingMaintenanceActionDetails: actions,
+		})
+	}
+	return output, nil
+}
+
+func (m *mockRdsClient) ApplyPendingMaintenanceAction(input *rds.ApplyPendingMaintenanceActionInput) (*rds.ApplyPendingMaintenanceActionOutput, error) {
+	m.appliedMaintenance = append(m.appliedMaintenance, *input.ApplyAction)
+	return &rds.ApplyPendingMaintenanceActionOutput{}, nil
 }
 
 func (m *mockRdsClient) DescribeDBSubnetGroups(*rds.DescribeDBSubnetGroupsInput) (*rds.DescribeDBSubnetGroupsOutput, error) {
@@ -155,6 +183,18 @@ func buildTestPostgresCR() *v1alpha1.Postgres {
 	}
 }
 
+func buildTestAdoptingPostgresCR(adoptID string) *v1alpha1.Postgres {
+	cr := buildTestPostgresCR()
+	cr.Spec.AdoptIdentifier = adoptID
+	return cr
+}
+
+func buildTestRetainedPostgresCR() *v1alpha1.Postgres {
+	cr := buildTestPostgresCR()
+	cr.Spec.DeletionPolicy = resources.DeletionPolicyRetain
+	return cr
+}
+
 func buildTestInfra() *v12.Infrastructure {
 	return &v12.Infrastructure{
 		ObjectMeta: controllerruntime.ObjectMeta{
@@ -539,6 +579,48 @@ func TestAWSPostgresProvider_createPostgresInstance(t *testing.T) {
 			want:    nil,
 			wantErr: false,
 		},
+		{
+			name: "test error when adopted rds instance does not exist",
+			args: args{
+				rdsSvc: &mockRdsClient{dbInstances: []*rds.DBInstance{}},
+				ec2Svc: &mockEc2Client{vpcs: buildVpcs(), subnets: buildSubnets(), secGroups: buildSecurityGroups(secName), azs: buildAZ()},
+				ctx:    context.TODO(),
+				cr:     buildTestAdoptingPostgresCR(testIdentifier),
+				postgresCfg: &rds.CreateDBInstanceInput{
+					DBInstanceIdentifier: aws.String(testIdentifier),
+				},
+			},
+			fields: fields{
+				Client:            fake.NewFakeClientWithScheme(scheme, buildTestAdoptingPostgresCR(testIdentifier), builtTestCredSecret(), buildTestInfra()),
+				Logger:            testLogger,
+				CredentialManager: nil,
+				ConfigManager:     nil,
+				TCPPinger:         buildMockConnectionTester(),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "test existing rds instance is adopted",
+			args: args{
+				rdsSvc: &mockRdsClient{dbInstances: buildAvailableDBInstance(testIdentifier)},
+				ec2Svc: &mockEc2Client{vpcs: buildVpcs(), subnets: buildSubnets(), secGroups: buildSecurityGroups(secName), azs: buildAZ()},
+				ctx:    context.TODO(),
+				cr:     buildTestAdoptingPostgresCR(testIdentifier),
+				postgresCfg: &rds.CreateDBInstanceInput{
+					DBInstanceIdentifier: aws.String(testIdentifier),
+				},
+			},
+			fields: fields{
+				Client:            fake.NewFakeClientWithScheme(scheme, buildTestAdoptingPostgresCR(testIdentifier), builtTestCredSecret(), buildTestInfra()),
+				Logger:            testLogger,
+				CredentialManager: nil,
+				ConfigManager:     nil,
+				TCPPinger:         buildMockConnectionTester(),
+			},
+			want:    nil,
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -549,7 +631,7 @@ func TestAWSPostgresProvider_createPostgresInstance(t *testing.T) {
 				ConfigManager:     tt.fields.ConfigManager,
 				TCPPinger:         tt.fields.TCPPinger,
 			}
-			got, _, err := p.createRDSInstance(tt.args.ctx, tt.args.cr, tt.args.rdsSvc, tt.args.ec2Svc, tt.args.postgresCfg)
+			got, _, err := p.createRDSInstance(tt.args.ctx, tt.args.cr, tt.args.rdsSvc, tt.args.ec2Svc, tt.args.postgresCfg, &StrategyConfig{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("createRDSInstance() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -561,6 +643,116 @@ func TestAWSPostgresProvider_createPostgresInstance(t *testing.T) {
 	}
 }
 
+func TestAWSPostgresProvider_createRDSInstanceModifications(t *testing.T) {
+	scheme, err := buildTestSchemePostgresql()
+	if err != nil {
+		t.Fatal("failed to build scheme", err)
+	}
+	testIdentifier := "test-identifier"
+	secName, err := BuildInfraName(context.TODO(), fake.NewFakeClientWithScheme(scheme, buildTestInfra()), defaultSecurityGroupPostfix, DefaultAwsIdentifierLength)
+	if err != nil {
+		t.Fatal("failed to build security name", err)
+	}
+	buildMaintenanceCR := func() *v1alpha1.Postgres {
+		cr := buildTestPostgresCR()
+		cr.Annotations = map[string]string{resources.ApplyMaintenanceAnnotation: "true"}
+		return cr
+	}
+	tests := []struct {
+		name                 string
+		cr                   *v1alpha1.Postgres
+		postgresCfg          *rds.CreateDBInstanceInput
+		wantModified         bool
+		wantApplyImmediately bool
+	}{
+		{
+			name: "test online changes are not applied immediately",
+			cr:   buildTestPostgresCR(),
+			postgresCfg: func() *rds.CreateDBInstanceInput {
+				cfg := buildAvailableCreateInput(testIdentifier)
+				cfg.BackupRetentionPeriod = aws.Int64(7)
+				return cfg
+			}(),
+			wantModified: true,
+		},
+		{
+			name: "test disruptive changes are deferred outside the maintenance window",
+			cr:   buildTestPostgresCR(),
+			postgresCfg: func() *rds.CreateDBInstanceInput {
+				cfg := buildAvailableCreateInput(testIdentifier)
+				cfg.DBInstanceClass = aws.String("db.t3.small")
+				return cfg
+			}(),
+		},
+		{
+			name: "test allowed disruptive changes are applied immediately",
+			cr:   buildMaintenanceCR(),
+			postgresCfg: func() *rds.CreateDBInstanceInput {
+				cfg := buildAvailableCreateInput(testIdentifier)
+				cfg.DBInstanceClass = aws.String("db.t3.small")
+				return cfg
+			}(),
+			wantModified:         true,
+			wantApplyImmediately: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rdsSvc := &mockRdsClient{dbInstances: buildAvailableDBInstance(testIdentifier)}
+			p := &PostgresProvider{
+				Client:    fake.NewFakeClientWithScheme(scheme, tt.cr, builtTestCredSecret(), buildTestInfra()),
+				Logger:    testLogger,
+				TCPPinger: buildMockConnectionTester(),
+			}
+			ec2Svc := &mockEc2Client{vpcs: buildVpcs(), subnets: buildSubnets(), secGroups: buildSecurityGroups(secName), azs: buildAZ()}
+			if _, _, err := p.createRDSInstance(context.TODO(), tt.cr, rdsSvc, ec2Svc, tt.postgresCfg, &StrategyConfig{}); err != nil {
+				t.Fatal("createRDSInstance() returned an error", err)
+			}
+			if !tt.wantModified {
+				if len(rdsSvc.modifyCalls) != 0 {
+					t.Errorf("createRDSInstance() unexpected modifications %v", rdsSvc.modifyCalls)
+				}
+				return
+			}
+			if len(rdsSvc.modifyCalls) != 1 {
+				t.Fatalf("createRDSInstance() modifications = %v, want one", rdsSvc.modifyCalls)
+			}
+			if got := aws.BoolValue(rdsSvc.modifyCalls[0].ApplyImmediately); got != tt.wantApplyImmediately {
+				t.Errorf("createRDSInstance() apply immediately = %v, want %v", got, tt.wantApplyImmediately)
+			}
+		})
+	}
+}
+
+func Test_verifyRDSAdoption(t *testing.T) {
+	tests := []struct {
+		name     string
+		instance *rds.DBInstance
+		wantErr  bool
+	}{
+		{
+			name:     "test postgres instance can be adopted",
+			instance: buildAvailableDBInstance("test-id")[0],
+			wantErr:  false,
+		},
+		{
+			name: "test instance with a different engine can not be adopted",
+			instance: &rds.DBInstance{
+				DBInstanceIdentifier: aws.String("test-id"),
+				Engine:               aws.String("mysql"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := verifyRDSAdoption(tt.instance); (err != nil) != tt.wantErr {
+				t.Errorf("verifyRDSAdoption() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestAWSPostgresProvider_deletePostgresInstance(t *testing.T) {
 	scheme, err := buildTestSchemePostgresql()
 	testIdentifier := "test-id"
@@ -652,6 +844,22 @@ func TestAWSPostgresProvider_deletePostgresInstance(t *testing.T) {
 			},
 			want:    croType.StatusMessage("deletion protection detected, modifyDBInstance() in progress, current aws rds status is available"),
 			wantErr: false,
+		}, {
+			name: "test existing available postgres is retained with retain deletion policy",
+			args: args{
+				postgresDeleteConfig: &rds.DeleteDBInstanceInput{DBInstanceIdentifier: aws.String(testIdentifier)},
+				postgresCreateConfig: &rds.CreateDBInstanceInput{DBInstanceIdentifier: aws.String(testIdentifier)},
+				pg:                   buildTestRetainedPostgresCR(),
+				instanceSvc:          &mockRdsClient{dbInstances: buildDbInstanceGroupAvailable()},
+			},
+			fields: fields{
+				Client:            fake.NewFakeClientWithScheme(scheme, buildTestRetainedPostgresCR(), buildTestInfra(), buildTestPostgresqlPrometheusRule()),
+				Logger:            testLogger,
+				CredentialManager: &CredentialManagerMock{},
+				ConfigManager:     &ConfigManagerMock{},
+			},
+			want:    croType.StatusEmpty,
+			wantErr: false,
 		},
 	}
 	for _, tt := range tests {
@@ -662,7 +870,7 @@ func TestAWSPostgresProvider_deletePostgresInstance(t *testing.T) {
 				CredentialManager: tt.fields.CredentialManager,
 				ConfigManager:     tt.fields.ConfigManager,
 			}
-			got, err := p.deleteRDSInstance(tt.args.ctx, tt.args.pg, tt.args.instanceSvc, tt.args.postgresCreateConfig, tt.args.postgresDeleteConfig)
+			got, err := p.deleteRDSInstance(tt.args.ctx, tt.args.pg, tt.args.instanceSvc, tt.args.postgresCreateConfig, tt.args.postgresDeleteConfig, &StrategyConfig{Region: "eu-west-1"})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("deleteRDSInstance() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -674,6 +882,61 @@ func TestAWSPostgresProvider_deletePostgresInstance(t *testing.T) {
 	}
 }
 
+func TestAWSPostgresProvider_buildRDSDeleteConfig(t *testing.T) {
+	scheme, err := buildTestSchemePostgresql()
+	if err != nil {
+		t.Fatal("failed to build scheme", err)
+	}
+	tests := []struct {
+		name              string
+		deletionPolicy    string
+		skipFinalSnapshot *bool
+		want              bool
+	}{
+		{
+			name: "test final snapshot is taken by default",
+			want: false,
+		},
+		{
+			name:              "test final snapshot is skipped as set by the delete strategy",
+			skipFinalSnapshot: aws.Bool(true),
+			want:              true,
+		},
+		{
+			name:              "test delete deletion policy keeps the final snapshot of the delete strategy",
+			deletionPolicy:    resources.DeletionPolicyDelete,
+			skipFinalSnapshot: aws.Bool(false),
+			want:              false,
+		},
+		{
+			name:              "test snapshot deletion policy takes a final snapshot",
+			deletionPolicy:    resources.DeletionPolicySnapshot,
+			skipFinalSnapshot: aws.Bool(true),
+			want:              false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &PostgresProvider{
+				Client: fake.NewFakeClientWithScheme(scheme, buildTestInfra()),
+				Logger: testLogger,
+			}
+			pg := buildTestPostgresCR()
+			pg.Spec.DeletionPolicy = tt.deletionPolicy
+			deleteConfig := &rds.DeleteDBInstanceInput{SkipFinalSnapshot: tt.skipFinalSnapshot}
+			if err := p.buildRDSDeleteConfig(context.TODO(), pg, &rds.CreateDBInstanceInput{}, deleteConfig); err != nil {
+				t.Fatal("buildRDSDeleteConfig() returned an error", err)
+			}
+			if got := aws.BoolValue(deleteConfig.SkipFinalSnapshot); got != tt.want {
+				t.Errorf("buildRDSDeleteConfig() skip final snapshot = %v, want %v", got, tt.want)
+			}
+			if !tt.want && deleteConfig.FinalDBSnapshotIdentifier == nil {
+				t.Error("buildRDSDeleteConfig() final snapshot identifier not set")
+			}
+		})
+	}
+}
+
 func TestAWSPostgresProvider_GetReconcileTime(t *testing.T) {
 	type args struct {
 		p *v1alpha1.Postgres
@@ -783,3 +1046,365 @@ func TestAWSPostgresProvider_TagRDSPostgres(t *testing.T) {
 		})
 	}
 }
+
+func TestAWSPostgresProvider_reconcileMasterPasswordRotation(t *testing.T) {
+	scheme, err := buildTestSchemePostgresql()
+	if err != nil {
+		t.Fatal("failed to build scheme", err)
+	}
+	testIdentifier := "test-identifier"
+	buildRotationRequestedCR := func() *v1alpha1.Postgres {
+		cr := buildTestPostgresCR()
+		cr.Annotations = map[string]string{resources.RotateCredentialsAnnotation: "true"}
+		return cr
+	}
+	buildPendingCredSecret := func() *v1.Secret {
+		sec := builtTestCredSecret()
+		sec.Data[defaultPostgresPendingPasswordKey] = []byte("new-password")
+		return sec
+	}
+	buildRotatingDBInstance := func() *rds.DBInstance {
+		instance := buildAvailableDBInstance(testIdentifier)[0]
+		instance.PendingModifiedValues = &rds.PendingModifiedValues{MasterUserPassword: aws.String("****")}
+		return instance
+	}
+	tests := []struct {
+		name           string
+		cr             *v1alpha1.Postgres
+		credSec        *v1.Secret
+		instance       *rds.DBInstance
+		want           croType.StatusMessage
+		wantPassword   string
+		wantPending    bool
+		wantAnnotation bool
+		wantErr        bool
+	}{
+		{
+			name:         "test no rotation when not requested and no interval is set",
+			cr:           buildTestPostgresCR(),
+			credSec:      builtTestCredSecret(),
+			instance:     buildAvailableDBInstance(testIdentifier)[0],
+			want:         croType.StatusEmpty,
+			wantPassword: "test",
+		},
+		{
+			name:           "test rotation is started when requested through annotation",
+			cr:             buildRotationRequestedCR(),
+			credSec:        builtTestCredSecret(),
+			instance:       buildAvailableDBInstance(testIdentifier)[0],
+			want:           "started master password rotation",
+			wantPassword:   "test",
+			wantPending:    true,
+			wantAnnotation: true,
+		},
+		{
+			name:           "test rotation is in progress while aws has not applied the password",
+			cr:             buildRotationRequestedCR(),
+			credSec:        buildPendingCredSecret(),
+			instance:       buildRotatingDBInstance(),
+			want:           "master password rotation in progress",
+			wantPassword:   "test",
+			wantPending:    true,
+			wantAnnotation: true,
+		},
+		{
+			name:         "test pending password is promoted once applied",
+			cr:           buildRotationRequestedCR(),
+			credSec:      buildPendingCredSecret(),
+			instance:     buildAvailableDBInstance(testIdentifier)[0],
+			want:         croType.StatusEmpty,
+			wantPassword: "new-password",
+		},
+		{
+			name: "test error when rotation interval is invalid",
+			cr: func() *v1alpha1.Postgres {
+				cr := buildTestPostgresCR()
+				cr.Spec.RotationInterval = "invalid"
+				return cr
+			}(),
+			credSec:      builtTestCredSecret(),
+			instance:     buildAvailableDBInstance(testIdentifier)[0],
+			want:         "failed to check if master password rotation is due",
+			wantPassword: "test",
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewFakeClientWithScheme(scheme, tt.cr, tt.credSec)
+			p := &PostgresProvider{
+				Client: c,
+				Logger: testLogger,
+			}
+			got, err := p.reconcileMasterPasswordRotation(context.TODO(), tt.cr, &mockRdsClient{}, tt.instance, tt.credSec)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("reconcileMasterPasswordRotation() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("reconcileMasterPasswordRotation() got = %v, want %v", got, tt.want)
+			}
+			sec := &v1.Secret{}
+			if err := c.Get(context.TODO(), types.NamespacedName{Name: tt.credSec.Name, Namespace: tt.credSec.Namespace}, sec); err != nil {
+				t.Fatal("failed to get credential secret", err)
+			}
+			if string(sec.Data[defaultPostgresPasswordKey]) != tt.wantPassword {
+				t.Errorf("reconcileMasterPasswordRotation() password = %s, want %s", sec.Data[defaultPostgresPasswordKey], tt.wantPassword)
+			}
+			if _, ok := sec.Data[defaultPostgresPendingPasswordKey]; ok != tt.wantPending {
+				t.Errorf("reconcileMasterPasswordRotation() pending password set = %v, want %v", ok, tt.wantPending)
+			}
+			if _, ok := sec.Data[defaultPostgresPasswordRotatedAtKey]; ok == (tt.wantPassword == "test") {
+				t.Errorf("reconcileMasterPasswordRotation() rotation time set = %v", ok)
+			}
+			if annotations.Has(tt.cr, resources.RotateCredentialsAnnotation) != tt.wantAnnotation {
+				t.Errorf("reconcileMasterPasswordRotation() annotation set = %v, want %v", !tt.wantAnnotation, tt.wantAnnotation)
+			}
+		})
+	}
+}
+
+func TestAWSPostgresProvider_reconcilePendingMaintenance(t *testing.T) {
+	scheme, err := buildTestSchemePostgresql()
+	if err != nil {
+		t.Fatal("failed to build scheme", err)
+	}
+	testIdentifier := "test-identifier"
+	autoApplyDate := time.Date(2019, 11, 20, 10, 0, 0, 0, time.UTC)
+	buildPendingMaintenance := func(optInStatus *string) map[string][]*rds.PendingMaintenanceAction {
+		return map[string][]*rds.PendingMaintenanceAction{
+			"arn-test": {
+				{
+					Action:               aws.String("system-update"),
+					Description:          aws.String("New Operating System update is available"),
+					AutoAppliedAfterDate: aws.Time(autoApplyDate),
+					OptInStatus:          optInStatus,
+				},
+			},
+			"arn-other": {
+				{
+					Action: aws.String("db-upgrade"),
+				},
+			},
+		}
+	}
+	buildApprovedCR := func() *v1alpha1.Postgres {
+		cr := buildTestPostgresCR()
+		cr.Annotations = map[string]string{resources.ApplyMaintenanceAnnotation: "true"}
+		return cr
+	}
+	tests := []struct {
+		name           string
+		cr             *v1alpha1.Postgres
+		stratCfg       *StrategyConfig
+		rdsSvc         *mockRdsClient
+		wantApplied    []string
+		wantStatus     []croType.PendingMaintenanceAction
+		wantAnnotation bool
+		wantErr        bool
+	}{
+		{
+			name:     "test no status when no maintenance is pending",
+			cr:       buildTestPostgresCR(),
+			stratCfg: &StrategyConfig{},
+			rdsSvc:   &mockRdsClient{},
+		},
+		{
+			name:     "test pending maintenance is not applied without a window or approval",
+			cr:       buildTestPostgresCR(),
+			stratCfg: &StrategyConfig{},
+			rdsSvc:   &mockRdsClient{pendingMaintenance: buildPendingMaintenance(nil)},
+			wantStatus: []croType.PendingMaintenanceAction{
+				{
+					Action:               "system-update",
+					Description:          "New Operating System update is available",
+					AutoAppliedAfterDate: "2019-11-20T10:00:00Z",
+				},
+			},
+		},
+		{
+			name:        "test pending maintenance is applied when approved",
+			cr:          buildApprovedCR(),
+			stratCfg:    &StrategyConfig{},
+			rdsSvc:      &mockRdsClient{pendingMaintenance: buildPendingMaintenance(nil)},
+			wantApplied: []string{"system-update"},
+			wantStatus: []croType.PendingMaintenanceAction{
+				{
+					Action:               "system-update",
+					Description:          "New Operating System update is available",
+					AutoAppliedAfterDate: "2019-11-20T10:00:00Z",
+					OptInStatus:          rdsOptInTypeImmediate,
+				},
+			},
+		},
+		{
+			name:     "test maintenance already opted in to is not applied again",
+			cr:       buildApprovedCR(),
+			stratCfg: &StrategyConfig{},
+			rdsSvc:   &mockRdsClient{pendingMaintenance: buildPendingMaintenance(aws.String(rdsOptInTypeImmediate))},
+			wantStatus: []croType.PendingMaintenanceAction{
+				{
+					Action:               "system-update",
+					Description:          "New Operating System update is available",
+					AutoAppliedAfterDate: "2019-11-20T10:00:00Z",
+					OptInStatus:          rdsOptInTypeImmediate,
+				},
+			},
+		},
+		{
+			name:     "test error when maintenance window of the tier is invalid",
+			cr:       buildTestPostgresCR(),
+			stratCfg: &StrategyConfig{MaintenanceWindow: "invalid"},
+			rdsSvc:   &mockRdsClient{pendingMaintenance: buildPendingMaintenance(nil)},
+			wantErr:  true,
+		},
+		{
+			name: "test maintenance window of the cr overrides the tier",
+			cr: func() *v1alpha1.Postgres {
+				cr := buildTestPostgresCR()
+				cr.Spec.MaintenanceWindow = "sun:00:00-sun:00:30"
+				return cr
+			}(),
+			stratCfg: &StrategyConfig{MaintenanceWindow: "invalid"},
+			rdsSvc:   &mockRdsClient{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &PostgresProvider{
+				Client: fake.NewFakeClientWithScheme(scheme, tt.cr),
+				Logger: testLogger,
+			}
+			_, err := p.reconcilePendingMaintenance(context.TODO(), tt.cr, tt.rdsSvc, buildAvailableDBInstance(testIdentifier)[0], tt.stratCfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("reconcilePendingMaintenance() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(tt.rdsSvc.appliedMaintenance, tt.wantApplied) {
+				t.Errorf("reconcilePendingMaintenance() applied = %v, want %v", tt.rdsSvc.appliedMaintenance, tt.wantApplied)
+			}
+			if !reflect.DeepEqual(tt.cr.Status.PendingMaintenance, tt.wantStatus) {
+				t.Errorf("reconcilePendingMaintenance() status = %v, want %v", tt.cr.Status.PendingMaintenance, tt.wantStatus)
+			}
+			if annotations.Has(tt.cr, resources.ApplyMaintenanceAnnotation) != tt.wantAnnotation {
+				t.Errorf("reconcilePendingMaintenance() annotation set = %v, want %v", !tt.wantAnnotation, tt.wantAnnotation)
+			}
+		})
+	}
+}
+
+func Test_splitRDSUpdateStrategy(t *testing.T) {
+	testIdentifier := aws.String("test-identifier")
+	tests := []struct {
+		name           string
+		mi             *rds.ModifyDBInstanceInput
+		wantOnline     *rds.ModifyDBInstanceInput
+		wantDisruptive *rds.ModifyDBInstanceInput
+	}{
+		{
+			name: "test online changes are not deferred",
+			mi: &rds.ModifyDBInstanceInput{
+				DBInstanceIdentifier:  testIdentifier,
+				BackupRetentionPeriod: aws.Int64(7),
+				DeletionProtection:    aws.Bool(false),
+			},
+			wantOnline: &rds.ModifyDBInstanceInput{
+				DBInstanceIdentifier:  testIdentifier,
+				BackupRetentionPeriod: aws.Int64(7),
+				DeletionProtection:    aws.Bool(false),
+			},
+		},
+		{
+			name: "test instance class and engine version changes are disruptive",
+			mi: &rds.ModifyDBInstanceInput{
+				DBInstanceIdentifier: testIdentifier,
+				DBInstanceClass:      aws.String("db.t3.small"),
+				EngineVersion:        aws.String("10.7"),
+			},
+			wantDisruptive: &rds.ModifyDBInstanceInput{
+				DBInstanceIdentifier: testIdentifier,
+				DBInstanceClass:      aws.String("db.t3.small"),
+				EngineVersion:        aws.String("10.7"),
+			},
+		},
+		{
+			name: "test disabling automated backups is disruptive",
+			mi: &rds.ModifyDBInstanceInput{
+				DBInstanceIdentifier:       testIdentifier,
+				BackupRetentionPeriod:      aws.Int64(0),
+				PreferredMaintenanceWindow: aws.String("sun:02:00-sun:03:00"),
+			},
+			wantOnline: &rds.ModifyDBInstanceInput{
+				DBInstanceIdentifier:       testIdentifier,
+				PreferredMaintenanceWindow: aws.String("sun:02:00-sun:03:00"),
+			},
+			wantDisruptive: &rds.ModifyDBInstanceInput{
+				DBInstanceIdentifier:  testIdentifier,
+				BackupRetentionPeriod: aws.Int64(0),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotOnline, gotDisruptive := splitRDSUpdateStrategy(tt.mi, buildAvailableDBInstance(*testIdentifier)[0])
+			if !reflect.DeepEqual(gotOnline, tt.wantOnline) {
+				t.Errorf("splitRDSUpdateStrategy() online = %v, want %v", gotOnline, tt.wantOnline)
+			}
+			if !reflect.DeepEqual(gotDisruptive, tt.wantDisruptive) {
+				t.Errorf("splitRDSUpdateStrategy() disruptive = %v, want %v", gotDisruptive, tt.wantDisruptive)
+			}
+		})
+	}
+}
+
+func Test_buildRDSDrift(t *testing.T) {
+	testIdentifier := "test-identifier"
+	encryptedCreateInput := buildAvailableCreateInput(testIdentifier)
+	encryptedCreateInput.StorageEncrypted = aws.Bool(true)
+	encryptedCreateInput.VpcSecurityGroupIds = []*string{aws.String("sg-2"), aws.String("sg-1")}
+	securedInstance := buildAvailableDBInstance(testIdentifier)[0]
+	securedInstance.VpcSecurityGroups = []*rds.VpcSecurityGroupMembership{
+		{VpcSecurityGroupId: aws.String("sg-1")},
+	}
+	tests := []struct {
+		name          string
+		rdsConfig     *rds.CreateDBInstanceInput
+		foundInstance *rds.DBInstance
+		want          []croType.DriftedField
+	}{
+		{
+			name:          "test no drift when instance is as expected",
+			rdsConfig:     buildAvailableCreateInput(testIdentifier),
+			foundInstance: buildAvailableDBInstance(testIdentifier)[0],
+			want:          nil,
+		},
+		{
+			name:          "test drift of updated fields",
+			rdsConfig:     buildNewRequiresModificationsCreateInput(testIdentifier),
+			foundInstance: buildAvailableDBInstance(testIdentifier)[0],
+			want: []croType.DriftedField{
+				{Field: "DBPortNumber", Desired: "123", Observed: strconv.Itoa(defaultAwsPostgresPort)},
+				{Field: "BackupRetentionPeriod", Desired: "123", Observed: strconv.Itoa(defaultAwsBackupRetentionPeriod)},
+			},
+		},
+		{
+			name:          "test drift of encryption and security groups",
+			rdsConfig:     encryptedCreateInput,
+			foundInstance: securedInstance,
+			want: []croType.DriftedField{
+				{Field: "StorageEncrypted", Desired: "true", Observed: "false"},
+				{Field: "VpcSecurityGroupIds", Desired: "sg-1,sg-2", Observed: "sg-1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildRDSDrift(tt.rdsConfig, tt.foundInstance); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildRDSDrift() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/service/ec2"
@@ -23,6 +26,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/kms"
 	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
 	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -30,7 +34,9 @@ import (
 	"github.com/integr8ly/cloud-resource-operator/pkg/providers"
 
 	errorUtil "github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 const (
@@ -38,15 +44,29 @@ const (
 	// default create params
 	defaultCacheNodeType = "cache.t2.micro"
 	// required for at rest encryption, see https://docs.aws.amazon.com/AmazonElastiCache/latest/red-ug/at-rest-encryption.html
-	defaultEngineVersion     = "3.2.6"
-	defaultDescription       = "A Redis replication group"
-	defaultNumCacheClusters  = 2
-	defaultSnapshotRetention = 31
-	defaultAtRestEncryption  = true
+	defaultEngineVersion    = "3.2.6"
+	defaultDescription      = "A Redis replication group"
+	defaultNumCacheClusters = 2
+	// cluster mode requires online resharding and at rest encryption, both are supported from 4.0.10
+	defaultClusterEngineVersion    = "4.0.10"
+	defaultClusterReplicasPerShard = 1
+	defaultSnapshotRetention       = 31
+	defaultAtRestEncryption        = true
 	// 3scale does not support in transit encryption (redis with tls)
 	defaultInTransitEncryption = false
+	// service updates are applied inside the maintenance window when auto, or on approval otherwise
+	serviceUpdateApplyAuto    = "auto"
+	serviceUpdateApplyApprove = "approve"
 )
 
+// serviceUpdateSeverities orders the severities of elasticache service updates, lowest first
+var serviceUpdateSeverities = []string{
+	elasticache.ServiceUpdateSeverityLow,
+	elasticache.ServiceUpdateSeverityMedium,
+	elasticache.ServiceUpdateSeverityImportant,
+	elasticache.ServiceUpdateSeverityCritical,
+}
+
 var _ providers.RedisProvider = (*RedisProvider)(nil)
 
 // RedisProvider implementation for AWS Elasticache
@@ -86,6 +106,18 @@ func (p *RedisProvider) GetReconcileTime(r *v1alpha1.Redis) time.Duration {
 
 // CreateRedis Create an Elasticache Replication Group from strategy config
 func (p *RedisProvider) CreateRedis(ctx context.Context, r *v1alpha1.Redis) (*providers.RedisCluster, croType.StatusMessage, error) {
+	// unsupported deletion policies are rejected before the replication group is created
+	if err := resources.ValidateDeletionPolicy(r.Spec.DeletionPolicy, resources.DeletionPolicyDelete, resources.DeletionPolicySnapshot, resources.DeletionPolicyRetain, resources.DeletionPolicyDelayedDelete); err != nil {
+		errMsg := fmt.Sprintf("invalid deletion policy of redis instance %s", r.Name)
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
+	// a cr which manages a cloud resource can not adopt another one, the conflict is reported in the status of the cr
+	if err := validateAdoption(r, r.Spec.AdoptIdentifier); err != nil {
+		errMsg := fmt.Sprintf("invalid adoption of redis instance %s", r.Name)
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
 	// handle provider-specific finalizer
 	if err := resources.CreateFinalizer(ctx, p.Client, r, DefaultFinalizer); err != nil {
 		return nil, "failed to set finalizer", err
@@ -111,6 +143,19 @@ func (p *RedisProvider) CreateRedis(ctx context.Context, r *v1alpha1.Redis) (*pr
 		errMsg := "failed to create aws session to create elasticache replication group"
 		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
 	}
+
+	// encrypt the replication group with the customer managed kms key of the strategy, if any
+	if elasticacheCreateConfig.KmsKeyId == nil {
+		kmsKeyArn, err := reconcileKMSKey(ctx, p.Client, kms.New(sess), providers.RedisResourceType, stratCfg.KMSKey)
+		if err != nil {
+			errMsg := "failed to reconcile kms key for elasticache replication group"
+			return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+		if kmsKeyArn != "" {
+			elasticacheCreateConfig.KmsKeyId = aws.String(kmsKeyArn)
+		}
+	}
+
 	// create the aws elasticache cluster
 	return p.createElasticacheCluster(ctx, r, elasticache.New(sess), sts.New(sess), ec2.New(sess), elasticacheCreateConfig, stratCfg)
 }
@@ -137,6 +182,24 @@ func (p *RedisProvider) createElasticacheCluster(ctx context.Context, r *v1alpha
 		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
 	}
 
+	// windows declared on the cr or tier take precedence over the create strategy
+	if window := getMaintenanceWindow(r.Spec.MaintenanceWindow, stratCfg); window != "" {
+		elasticacheConfig.PreferredMaintenanceWindow = aws.String(window)
+	}
+	if window := getBackupWindow(r.Spec.BackupWindow, stratCfg); window != "" {
+		elasticacheConfig.SnapshotWindow = aws.String(window)
+	}
+
+	// the engine version of the cr takes precedence over the create strategy, replication groups which exist are upgraded
+	if r.Spec.EngineVersion != "" {
+		elasticacheConfig.EngineVersion = aws.String(r.Spec.EngineVersion)
+	}
+
+	// a node type set by a scaleNodeType action takes precedence over the node type of the tier
+	if nodeType := r.GetAnnotations()[RedisNodeTypeAnnotation]; nodeType != "" {
+		elasticacheConfig.CacheNodeType = aws.String(nodeType)
+	}
+
 	// verify and build elasticache create config
 	if err := p.buildElasticacheCreateStrategy(ctx, r, ec2Svc, elasticacheConfig); err != nil {
 		errMsg := "failed to build and verify aws elasticache create strategy"
@@ -152,8 +215,13 @@ func (p *RedisProvider) createElasticacheCluster(ctx context.Context, r *v1alpha
 		}
 	}
 
-	// create elasticache cluster if it doesn't exist
+	// create elasticache cluster if it doesn't exist, a replication group which is adopted is never created
+	adoptID := resources.GetAdoptIdentifier(r, r.Spec.AdoptIdentifier)
 	if foundCache == nil {
+		if adoptID != "" {
+			errMsg := fmt.Sprintf("Redis CR %s in %s namespace adopts Elasticache replication group %s, but it was not found", r.Name, r.Namespace, adoptID)
+			return nil, croType.StatusMessage(errMsg), fmt.Errorf(errMsg)
+		}
 		if annotations.Has(r, resourceIdentifierAnnotation) {
 			errMsg := fmt.Sprintf("Redis CR %s in %s namespace has %s annotation with value %s, but no corresponding Elasticache instance was found",
 				r.Name, r.Namespace, resourceIdentifierAnnotation, r.ObjectMeta.Annotations[resourceIdentifierAnnotation])
@@ -165,6 +233,9 @@ func (p *RedisProvider) createElasticacheCluster(ctx context.Context, r *v1alpha
 			errMsg := fmt.Sprintf("error creating elasticache cluster %s", err)
 			return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
 		}
+		if resources.IsDryRun() {
+			return nil, "dry run, elasticache cluster would be created", nil
+		}
 
 		annotations.Add(r, resourceIdentifierAnnotation, *elasticacheConfig.ReplicationGroupId)
 		if err := p.Client.Update(ctx, r); err != nil {
@@ -173,6 +244,25 @@ func (p *RedisProvider) createElasticacheCluster(ctx context.Context, r *v1alpha
 		return nil, "started elasticache provision", nil
 	}
 
+	// adopt the existing replication group, it is managed like a replication group created by the operator from then on
+	if adoptID != "" && !annotations.Has(r, resourceIdentifierAnnotation) {
+		if err := verifyElasticacheAdoption(elasticacheConfig, foundCache); err != nil {
+			errMsg := fmt.Sprintf("failed to adopt elasticache replication group %s", adoptID)
+			return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+		// a replication group which was retained is no longer recorded in the inventory once it is adopted
+		if err := resources.RemoveFromInventory(ctx, p.Client, r.Namespace, string(providers.RedisResourceType), adoptID); err != nil {
+			errMsg := fmt.Sprintf("failed to remove elasticache replication group %s from inventory", adoptID)
+			return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+		annotations.Add(r, resourceIdentifierAnnotation, adoptID)
+		if err := p.Client.Update(ctx, r); err != nil {
+			return nil, croType.StatusMessage("failed to add annotation"), err
+		}
+		p.Logger.Infof("adopted elasticache replication group %s", adoptID)
+		return nil, croType.StatusMessage(fmt.Sprintf("adopted elasticache replication group %s", adoptID)), nil
+	}
+
 	// expose elasticache maintenance metric
 	defer p.setRedisServiceMaintenanceMetric(ctx, r, cacheSvc, foundCache)
 
@@ -188,46 +278,202 @@ func (p *RedisProvider) createElasticacheCluster(ctx context.Context, r *v1alpha
 		return nil, croType.StatusMessage(fmt.Sprintf("createReplicationGroup() in progress, current aws elasticache status is %s", *foundCache.Status)), nil
 	}
 
+	// run on-demand actions, the strategy is not reconciled while an action is in progress
+	if inProgress, msg, err := p.reconcileElasticacheAction(ctx, r, cacheSvc, foundCache); err != nil || inProgress {
+		return nil, msg, err
+	}
+
 	// check if found cluster and user strategy differs, and modify instance
 	logrus.Infof("found existing elasticache instance %s", *foundCache.ReplicationGroupId)
-	ec := buildElasticacheUpdateStrategy(elasticacheConfig, foundCache)
+	memberCluster, err := getElasticacheMemberCluster(cacheSvc, foundCache)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to get cache cluster of elasticache replication group %s", *foundCache.ReplicationGroupId)
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	// the maintenance window and engine version of a replication group are only described by its cache clusters
+	foundMaintenanceWindow := ""
+	foundEngineVersion := ""
+	if memberCluster != nil {
+		foundMaintenanceWindow = aws.StringValue(memberCluster.PreferredMaintenanceWindow)
+		foundEngineVersion = aws.StringValue(memberCluster.EngineVersion)
+	}
+
+	// upgrade the engine version, the strategy is not reconciled while an upgrade is in progress
+	if inProgress, msg, err := p.reconcileElasticacheUpgrade(ctx, r, cacheSvc, foundCache, foundEngineVersion); err != nil || inProgress {
+		return nil, msg, err
+	}
+
+	// reshard a cluster mode enabled replication group online when the number of shards of the strategy changes, other
+	// modifications are applied once resharding has completed
+	if reshard := buildElasticacheShardConfiguration(elasticacheConfig, foundCache); reshard != nil {
+		logrus.Infof("resharding elasticache replication group %s to %d shards", *foundCache.ReplicationGroupId, *reshard.NodeGroupCount)
+		if _, err := cacheSvc.ModifyReplicationGroupShardConfiguration(reshard); err != nil {
+			errMsg := fmt.Sprintf("failed to reshard elasticache replication group %s", *foundCache.ReplicationGroupId)
+			return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+		return nil, croType.StatusMessage(fmt.Sprintf("started resharding elasticache replication group %s to %d shards", *foundCache.ReplicationGroupId, *reshard.NodeGroupCount)), nil
+	}
+
+	// record the drift from the desired configuration before it is corrected
+	drift := buildElasticacheDrift(elasticacheConfig, foundCache, memberCluster)
+	ec := buildElasticacheUpdateStrategy(elasticacheConfig, foundCache, foundMaintenanceWindow)
 	if ec == nil {
 		logrus.Infof("elasticache replication group %s is as expected", *foundCache.ReplicationGroupId)
 	}
+	var pendingModifications []croType.PendingModification
+	var pendingPlanHash string
 	if ec != nil {
-		logrus.Infof("%s differs from expected strategy, applying pending modifications :\n%s", *foundCache.ReplicationGroupId, ec)
-		if _, err := cacheSvc.ModifyReplicationGroup(ec); err != nil {
-			errMsg := "failed to modify elasticache cluster"
-			return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		// online changes are modified as before, disruptive changes are deferred to the maintenance window or until
+		// the plan is approved, and are then applied immediately
+		online, disruptive := splitElasticacheUpdateStrategy(ec)
+		planApproved := false
+		if disruptive != nil {
+			window := foundMaintenanceWindow
+			if elasticacheConfig.PreferredMaintenanceWindow != nil {
+				window = *elasticacheConfig.PreferredMaintenanceWindow
+			}
+			allowed, planHash, err := isDisruptiveChangeAllowed(r, stratCfg, window, disruptive)
+			if err != nil {
+				errMsg := fmt.Sprintf("failed to check if disruptive modifications of elasticache replication group %s are allowed", *foundCache.ReplicationGroupId)
+				return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+			}
+			if allowed {
+				online = ec
+				online.ApplyImmediately = aws.Bool(true)
+				planApproved = planHash != ""
+			} else {
+				logrus.Infof("deferring disruptive modifications of elasticache replication group %s", *foundCache.ReplicationGroupId)
+				pendingModifications = buildElasticachePendingModificationStatus(disruptive)
+				pendingPlanHash = planHash
+			}
+		}
+		if online != nil {
+			logrus.Infof("%s differs from expected strategy, applying pending modifications :\n%s", *foundCache.ReplicationGroupId, online)
+			if _, err := cacheSvc.ModifyReplicationGroup(online); err != nil {
+				errMsg := "failed to modify elasticache cluster"
+				return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+			}
+			logrus.Infof("set pending modifications to elasticache replication group %s", *foundCache.ReplicationGroupId)
+		}
+		if planApproved {
+			annotations.Remove(r, resources.ApprovePlanAnnotation)
+			if err := p.Client.Update(ctx, r); err != nil {
+				errMsg := fmt.Sprintf("failed to remove %s annotation from redis instance %s", resources.ApprovePlanAnnotation, r.Name)
+				return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+			}
 		}
-		logrus.Infof("set pending modifications to elasticache replication group %s", *foundCache.ReplicationGroupId)
 	}
 
-	// add tags to cache nodes
-	cacheInstance := *foundCache.NodeGroups[0]
-	if *cacheInstance.Status != "available" {
-		logrus.Infof("elasticache node %s current status is %s", *cacheInstance.NodeGroupId, *cacheInstance.Status)
-		return nil, croType.StatusMessage(fmt.Sprintf("cache node status not available, current status:  %s", *foundCache.Status)), nil
-	}
+	// add tags to cache nodes, a cluster mode enabled replication group has a node group per shard
+	for _, cacheInstance := range foundCache.NodeGroups {
+		if *cacheInstance.Status != "available" {
+			logrus.Infof("elasticache node %s current status is %s", *cacheInstance.NodeGroupId, *cacheInstance.Status)
+			return nil, croType.StatusMessage(fmt.Sprintf("cache node status not available, current status:  %s", *foundCache.Status)), nil
+		}
 
-	for _, cache := range cacheInstance.NodeGroupMembers {
-		msg, err := p.TagElasticacheNode(ctx, cacheSvc, stsSvc, r, *stratCfg, cache)
-		if err != nil {
-			errMsg := fmt.Sprintf("failed to add tags to elasticache: %s", msg)
-			return nil, types.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		for _, cache := range cacheInstance.NodeGroupMembers {
+			drift, err = p.appendElasticacheNodeTagDrift(ctx, cacheSvc, stsSvc, r, cache, drift)
+			if err != nil {
+				errMsg := fmt.Sprintf("failed to check tag drift of elasticache node %s", aws.StringValue(cache.CacheClusterId))
+				return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+			}
+			msg, err := p.TagElasticacheNode(ctx, cacheSvc, stsSvc, r, *stratCfg, cache)
+			if err != nil {
+				errMsg := fmt.Sprintf("failed to add tags to elasticache: %s", msg)
+				return nil, types.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+			}
 		}
 	}
 
-	primaryEndpoint := foundCache.NodeGroups[0].PrimaryEndpoint
+	// apply service updates allowed by the policy of the strategy
+	if msg, err := p.reconcileServiceUpdates(ctx, r, cacheSvc, foundCache, stratCfg); err != nil {
+		return nil, msg, err
+	}
+
+	// the status is persisted with the phase of the cr by the controller
+	r.Status.PendingModifications = pendingModifications
+	r.Status.PendingPlanHash = pendingPlanHash
+	p.exposeRedisDriftMetric(ctx, r, foundCache, drift)
+	r.Status.Drift = drift
+
+	endpoint := getElasticacheEndpoint(foundCache)
+	if endpoint == nil {
+		return nil, croType.StatusMessage(fmt.Sprintf("elasticache replication group %s has no endpoint yet", *foundCache.ReplicationGroupId)), nil
+	}
 	rdd := &providers.RedisDeploymentDetails{
-		URI:  *primaryEndpoint.Address,
-		Port: *primaryEndpoint.Port,
+		URI:         *endpoint.Address,
+		Port:        *endpoint.Port,
+		ClusterMode: aws.BoolValue(foundCache.ClusterEnabled),
 	}
 
 	// return secret information
 	return &providers.RedisCluster{DeploymentDetails: rdd}, croType.StatusMessage(fmt.Sprintf("successfully created and tagged, aws elasticache status is %s", *foundCache.Status)), nil
 }
 
+// buildElasticacheTags returns the tags which are always added to elasticache resources
+func buildElasticacheTags(r *v1alpha1.Redis, clusterID string) []*elasticache.Tag {
+	organizationTag := resources.GetOrganizationTag()
+	cacheTags := []*elasticache.Tag{
+		{
+			Key:   aws.String(organizationTag + "clusterID"),
+			Value: aws.String(clusterID),
+		},
+		{
+			Key:   aws.String(organizationTag + "resource-type"),
+			Value: aws.String(r.Spec.Type),
+		},
+		{
+			Key:   aws.String(organizationTag + "resource-name"),
+			Value: aws.String(r.Name),
+		},
+	}
+
+	// check is product name exists on cr
+	if r.ObjectMeta.Labels["productName"] != "" {
+		productTag := &elasticache.Tag{
+			Key:   aws.String(organizationTag + "product-name"),
+			Value: aws.String(r.ObjectMeta.Labels["productName"]),
+		}
+		cacheTags = append(cacheTags, productTag)
+	}
+	return cacheTags
+}
+
+// buildElasticacheNodeArn builds the arn of a cache node, in the following format
+// arn:aws:elasticache:us-east-1:1234567890:cluster:my-mem-cluster
+func buildElasticacheNodeArn(region, account string, cache *elasticache.NodeGroupMember) string {
+	return fmt.Sprintf("arn:aws:elasticache:%s:%s:cluster:%s", region, account, *cache.CacheClusterId)
+}
+
+// appendElasticacheNodeTagDrift appends the desired tags which are missing from a cache node to the drift of a
+// replication group
+func (p *RedisProvider) appendElasticacheNodeTagDrift(ctx context.Context, cacheSvc elasticacheiface.ElastiCacheAPI, stsSvc stsiface.STSAPI, r *v1alpha1.Redis, cache *elasticache.NodeGroupMember, drift []croType.DriftedField) ([]croType.DriftedField, error) {
+	id, err := stsSvc.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, errorUtil.Wrap(err, "failed to get account identity")
+	}
+	clusterID, err := resources.GetClusterID(ctx, p.Client)
+	if err != nil {
+		return nil, errorUtil.Wrap(err, "failed to get cluster id")
+	}
+	region := (*cache.PreferredAvailabilityZone)[:len(*cache.PreferredAvailabilityZone)-1]
+	output, err := cacheSvc.ListTagsForResource(&elasticache.ListTagsForResourceInput{
+		ResourceName: aws.String(buildElasticacheNodeArn(region, *id.Account, cache)),
+	})
+	if err != nil {
+		return nil, errorUtil.Wrapf(err, "failed to list tags of elasticache node %s", *cache.CacheClusterId)
+	}
+	desiredTags := map[string]string{}
+	for _, tag := range buildElasticacheTags(r, clusterID) {
+		desiredTags[*tag.Key] = aws.StringValue(tag.Value)
+	}
+	observedTags := map[string]string{}
+	for _, tag := range output.TagList {
+		observedTags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return appendTagDrift(drift, desiredTags, observedTags), nil
+}
+
 // TagElasticacheNode Add Tags to AWS Elasticache
 func (p *RedisProvider) TagElasticacheNode(ctx context.Context, cacheSvc elasticacheiface.ElastiCacheAPI, stsSvc stsiface.STSAPI, r *v1alpha1.Redis, stratCfg StrategyConfig, cache *elasticache.NodeGroupMember) (types.StatusMessage, error) {
 	logrus.Info("creating or updating tags on elasticache nodes and snapshots")
@@ -257,41 +503,15 @@ func (p *RedisProvider) TagElasticacheNode(ctx context.Context, cacheSvc elastic
 
 	// trim availability zone to return cache region
 	region := (*cache.PreferredAvailabilityZone)[:len(*cache.PreferredAvailabilityZone)-1]
-
-	// build cluster arn
-	// need arn in the following format arn:aws:elasticache:us-east-1:1234567890:cluster:my-mem-cluster
-	arn := fmt.Sprintf("arn:aws:elasticache:%s:%s:cluster:%s", region, *id.Account, *cache.CacheClusterId)
+	arn := buildElasticacheNodeArn(region, *id.Account, cache)
 
 	// set the tag values that will always be added
-	organizationTag := resources.GetOrganizationTag()
 	clusterID, err := resources.GetClusterID(ctx, p.Client)
 	if err != nil {
 		errMsg := "failed to get cluster id"
 		return croType.StatusMessage(errMsg), errorUtil.Wrapf(err, errMsg)
 	}
-	cacheTags := []*elasticache.Tag{
-		{
-			Key:   aws.String(organizationTag + "clusterID"),
-			Value: aws.String(clusterID),
-		},
-		{
-			Key:   aws.String(organizationTag + "resource-type"),
-			Value: aws.String(r.Spec.Type),
-		},
-		{
-			Key:   aws.String(organizationTag + "resource-name"),
-			Value: aws.String(r.Name),
-		},
-	}
-
-	// check is product name exists on cr
-	if r.ObjectMeta.Labels["productName"] != "" {
-		productTag := &elasticache.Tag{
-			Key:   aws.String(organizationTag + "product-name"),
-			Value: aws.String(r.ObjectMeta.Labels["productName"]),
-		}
-		cacheTags = append(cacheTags, productTag)
-	}
+	cacheTags := buildElasticacheTags(r, clusterID)
 
 	// add tags
 	_, err = cacheSvc.AddTagsToResource(&elasticache.AddTagsToResourceInput{
@@ -330,8 +550,142 @@ func (p *RedisProvider) TagElasticacheNode(ctx context.Context, cacheSvc elastic
 	return "successfully created and tagged", nil
 }
 
+// reconcileServiceUpdates records the service updates of the replication group which are not complete in the status of
+// the cr, and applies those of at least the severity of the service update policy of the strategy. updates are applied
+// inside the maintenance window when the policy is auto, or when approved through an annotation
+func (p *RedisProvider) reconcileServiceUpdates(ctx context.Context, r *v1alpha1.Redis, cacheSvc elasticacheiface.ElastiCacheAPI, foundCache *elasticache.ReplicationGroup, stratCfg *StrategyConfig) (croType.StatusMessage, error) {
+	policy := stratCfg.ServiceUpdates
+	if policy == nil {
+		policy = &ServiceUpdatePolicy{}
+	}
+	if policy.Apply != "" && policy.Apply != serviceUpdateApplyAuto && policy.Apply != serviceUpdateApplyApprove {
+		errMsg := fmt.Sprintf("invalid service update policy apply %s, expected %s or %s", policy.Apply, serviceUpdateApplyAuto, serviceUpdateApplyApprove)
+		return croType.StatusMessage(errMsg), errorUtil.New(errMsg)
+	}
+	minSeverity := severityIndex(policy.Severity)
+	if minSeverity < 0 {
+		errMsg := fmt.Sprintf("invalid service update policy severity %s", policy.Severity)
+		return croType.StatusMessage(errMsg), errorUtil.New(errMsg)
+	}
+
+	updateActions, err := getElasticacheUpdateActions(cacheSvc, foundCache.ReplicationGroupId)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to get service updates for elasticache replication group %s", *foundCache.ReplicationGroupId)
+		return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
+	// the window of the policy takes precedence over the maintenance window of the cr and tier
+	window := getMaintenanceWindow(r.Spec.MaintenanceWindow, stratCfg)
+	if policy.Window != "" {
+		window = policy.Window
+	}
+	if policy.Apply != serviceUpdateApplyAuto {
+		window = ""
+	}
+	allowed, err := resources.IsMaintenanceAllowed(r, window, time.Now())
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to check maintenance window of redis instance %s", r.Name)
+		return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	if allowed {
+		for _, ua := range updateActions {
+			if aws.StringValue(ua.UpdateActionStatus) != elasticache.UpdateActionStatusNotApplied || severityIndex(aws.StringValue(ua.ServiceUpdateSeverity)) < minSeverity {
+				continue
+			}
+			logrus.Infof("applying service update %s to elasticache replication group %s", aws.StringValue(ua.ServiceUpdateName), *foundCache.ReplicationGroupId)
+			output, err := cacheSvc.BatchApplyUpdateAction(&elasticache.BatchApplyUpdateActionInput{
+				ReplicationGroupIds: []*string{foundCache.ReplicationGroupId},
+				ServiceUpdateName:   ua.ServiceUpdateName,
+			})
+			if err != nil {
+				errMsg := fmt.Sprintf("failed to apply service update %s to elasticache replication group %s", aws.StringValue(ua.ServiceUpdateName), *foundCache.ReplicationGroupId)
+				return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+			}
+			if len(output.UnprocessedUpdateActions) != 0 {
+				errMsg := fmt.Sprintf("failed to apply service update %s to elasticache replication group %s: %s", aws.StringValue(ua.ServiceUpdateName), *foundCache.ReplicationGroupId, aws.StringValue(output.UnprocessedUpdateActions[0].ErrorMessage))
+				return croType.StatusMessage(errMsg), errorUtil.New(errMsg)
+			}
+			ua.UpdateActionStatus = aws.String(elasticache.UpdateActionStatusWaitingToStart)
+		}
+		if annotations.Has(r, resources.ApplyMaintenanceAnnotation) {
+			annotations.Remove(r, resources.ApplyMaintenanceAnnotation)
+			if err := p.Client.Update(ctx, r); err != nil {
+				errMsg := fmt.Sprintf("failed to remove %s annotation from redis instance %s", resources.ApplyMaintenanceAnnotation, r.Name)
+				return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+			}
+		}
+	}
+
+	// the status is persisted with the phase of the cr by the controller
+	r.Status.PendingMaintenance = buildElasticacheServiceUpdateStatus(updateActions)
+	return croType.StatusEmpty, nil
+}
+
+func getElasticacheUpdateActions(cacheSvc elasticacheiface.ElastiCacheAPI, replicationGroupID *string) ([]*elasticache.UpdateAction, error) {
+	var updateActions []*elasticache.UpdateAction
+	input := &elasticache.DescribeUpdateActionsInput{
+		ReplicationGroupIds: []*string{replicationGroupID},
+		UpdateActionStatus: aws.StringSlice([]string{
+			elasticache.UpdateActionStatusNotApplied,
+			elasticache.UpdateActionStatusWaitingToStart,
+			elasticache.UpdateActionStatusInProgress,
+			elasticache.UpdateActionStatusStopping,
+			elasticache.UpdateActionStatusStopped,
+		}),
+	}
+	for {
+		output, err := cacheSvc.DescribeUpdateActions(input)
+		if err != nil {
+			return nil, err
+		}
+		updateActions = append(updateActions, output.UpdateActions...)
+		if output.Marker == nil {
+			return updateActions, nil
+		}
+		input.Marker = output.Marker
+	}
+}
+
+func buildElasticacheServiceUpdateStatus(updateActions []*elasticache.UpdateAction) []croType.PendingMaintenanceAction {
+	var status []croType.PendingMaintenanceAction
+	for _, ua := range updateActions {
+		action := croType.PendingMaintenanceAction{
+			Action:       aws.StringValue(ua.ServiceUpdateName),
+			Description:  aws.StringValue(ua.ServiceUpdateType),
+			Severity:     aws.StringValue(ua.ServiceUpdateSeverity),
+			Status:       aws.StringValue(ua.UpdateActionStatus),
+			NodesUpdated: aws.StringValue(ua.NodesUpdated),
+		}
+		if ua.ServiceUpdateRecommendedApplyByDate != nil {
+			action.RecommendedApplyByDate = ua.ServiceUpdateRecommendedApplyByDate.UTC().Format(time.RFC3339)
+		}
+		status = append(status, action)
+	}
+	return status
+}
+
+// severityIndex returns the position of a service update severity in serviceUpdateSeverities, an empty severity is
+// the lowest. -1 is returned for unknown severities
+func severityIndex(severity string) int {
+	if severity == "" {
+		return 0
+	}
+	for i, s := range serviceUpdateSeverities {
+		if s == severity {
+			return i
+		}
+	}
+	return -1
+}
+
 //DeleteRedis Delete elasticache replication group
 func (p *RedisProvider) DeleteRedis(ctx context.Context, r *v1alpha1.Redis) (croType.StatusMessage, error) {
+	// the cloud resource of a cr with an adoption conflict is not deleted, it could be the adopted one
+	if err := validateAdoption(r, r.Spec.AdoptIdentifier); err != nil {
+		errMsg := fmt.Sprintf("invalid adoption of redis instance %s", r.Name)
+		return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
 	// resolve elasticache information for elasticache created by provider
 	p.Logger.Info("getting cluster id from infrastructure for redis naming")
 	elasticacheCreateConfig, elasticacheDeleteConfig, stratCfg, err := p.getElasticacheConfig(ctx, r)
@@ -355,10 +709,10 @@ func (p *RedisProvider) DeleteRedis(ctx context.Context, r *v1alpha1.Redis) (cro
 	}
 
 	// delete the elasticache cluster
-	return p.deleteElasticacheCluster(ctx, elasticache.New(sess), elasticacheCreateConfig, elasticacheDeleteConfig, r)
+	return p.deleteElasticacheCluster(ctx, elasticache.New(sess), elasticacheCreateConfig, elasticacheDeleteConfig, r, stratCfg)
 }
 
-func (p *RedisProvider) deleteElasticacheCluster(ctx context.Context, cacheSvc elasticacheiface.ElastiCacheAPI, elasticacheCreateConfig *elasticache.CreateReplicationGroupInput, elasticacheDeleteConfig *elasticache.DeleteReplicationGroupInput, r *v1alpha1.Redis) (croType.StatusMessage, error) {
+func (p *RedisProvider) deleteElasticacheCluster(ctx context.Context, cacheSvc elasticacheiface.ElastiCacheAPI, elasticacheCreateConfig *elasticache.CreateReplicationGroupInput, elasticacheDeleteConfig *elasticache.DeleteReplicationGroupInput, r *v1alpha1.Redis, stratCfg *StrategyConfig) (croType.StatusMessage, error) {
 	// the aws access key can sometimes still not be registered in aws on first try, so loop
 	rgs, err := getReplicationGroups(cacheSvc)
 	if err != nil {
@@ -382,13 +736,29 @@ func (p *RedisProvider) deleteElasticacheCluster(ctx context.Context, cacheSvc e
 
 	// check if replication group does not exist and delete finalizer
 	if foundCache == nil {
-		// remove the finalizer added by the provider
-		resources.RemoveFinalizer(&r.ObjectMeta, DefaultFinalizer)
-		if err := p.Client.Update(ctx, r); err != nil {
-			errMsg := "failed to update instance as part of finalizer reconcile"
-			return croType.StatusMessage(errMsg), errorUtil.Wrapf(err, errMsg)
+		// a replication group whose deletion was delayed is no longer recorded in the inventory once it is deleted
+		if err := resources.RemoveFromInventory(ctx, p.Client, r.Namespace, string(providers.RedisResourceType), *elasticacheDeleteConfig.ReplicationGroupId); err != nil {
+			errMsg := "failed to remove elasticache replication group from inventory"
+			return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
 		}
-		return croType.StatusEmpty, nil
+		return p.removeElasticacheFinalizer(ctx, r)
+	}
+
+	// retain the replication group or delay its deletion as described by the deletion policy of the cr
+	action, msg, err := reconcileDeletionPolicy(ctx, p.Client, r, r.Spec.DeletionPolicy, r.Spec.DeletionGracePeriod, resources.InventoryEntry{
+		ResourceType: string(providers.RedisResourceType),
+		Identifier:   *foundCache.ReplicationGroupId,
+		Region:       stratCfg.Region,
+	})
+	if err != nil {
+		return msg, err
+	}
+	switch action {
+	case deletionActionRetain:
+		p.Logger.Infof("retaining elasticache replication group %s", *foundCache.ReplicationGroupId)
+		return p.removeElasticacheFinalizer(ctx, r)
+	case deletionActionDelay:
+		return msg, nil
 	}
 
 	// set status metric
@@ -399,6 +769,15 @@ func (p *RedisProvider) deleteElasticacheCluster(ctx context.Context, cacheSvc e
 		return croType.StatusMessage(fmt.Sprintf("delete detected, deleteReplicationGroup() in progress, current aws elasticache status is %s", *foundCache.Status)), nil
 	}
 
+	// the final snapshot is recorded before the replication group is deleted, the identifier of the final snapshot
+	// changes until the deletion is started
+	if elasticacheDeleteConfig.FinalSnapshotIdentifier != nil && !resources.IsDryRun() {
+		if err := p.reconcileFinalSnapshot(ctx, r, *elasticacheDeleteConfig.FinalSnapshotIdentifier, stratCfg); err != nil {
+			errMsg := "failed to record final elasticache snapshot"
+			return croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+	}
+
 	// delete elasticache cluster
 	_, err = cacheSvc.DeleteReplicationGroup(elasticacheDeleteConfig)
 	elasticacheErr, isAwsErr := err.(awserr.Error)
@@ -410,6 +789,48 @@ func (p *RedisProvider) deleteElasticacheCluster(ctx context.Context, cacheSvc e
 	return "delete detected, deleteReplicationGroup started", nil
 }
 
+// reconcileFinalSnapshot records the final snapshot taken when the replication group of a redis cr is deleted in a
+// redis snapshot cr, so it can be listed like any other snapshot
+func (p *RedisProvider) reconcileFinalSnapshot(ctx context.Context, r *v1alpha1.Redis, snapshotID string, stratCfg *StrategyConfig) error {
+	region := stratCfg.Region
+	if region == "" {
+		defaultRegion, err := getDefaultRegion(ctx, p.Client)
+		if err != nil {
+			return errorUtil.Wrap(err, "failed to get region of final snapshot")
+		}
+		region = defaultRegion
+	}
+	snapshot := &v1alpha1.RedisSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      buildFinalSnapshotName(r.ObjectMeta),
+			Namespace: r.Namespace,
+		},
+	}
+	or, err := controllerutil.CreateOrUpdate(ctx, p.Client, snapshot, func() error {
+		snapshot.Labels = r.Labels
+		snapshot.Spec.ResourceName = r.Name
+		snapshot.Spec.SnapshotID = snapshotID
+		snapshot.Spec.Region = region
+		return nil
+	})
+	if err != nil {
+		return errorUtil.Wrapf(err, "failed to create or update redis snapshot %s, action was %s", snapshot.Name, or)
+	}
+	return nil
+}
+
+// removeElasticacheFinalizer removes the finalizer of a redis cr, once its replication group has been deleted or
+// retained
+func (p *RedisProvider) removeElasticacheFinalizer(ctx context.Context, r *v1alpha1.Redis) (croType.StatusMessage, error) {
+	// remove the finalizer added by the provider
+	resources.RemoveFinalizer(&r.ObjectMeta, DefaultFinalizer)
+	if err := p.Client.Update(ctx, r); err != nil {
+		errMsg := "failed to update instance as part of finalizer reconcile"
+		return croType.StatusMessage(errMsg), errorUtil.Wrapf(err, errMsg)
+	}
+	return croType.StatusEmpty, nil
+}
+
 // poll for replication groups
 func getReplicationGroups(cacheSvc elasticacheiface.ElastiCacheAPI) ([]*elasticache.ReplicationGroup, error) {
 	var rgs []*elasticache.ReplicationGroup
@@ -452,11 +873,29 @@ func (p *RedisProvider) getElasticacheConfig(ctx context.Context, r *v1alpha1.Re
 	if err := json.Unmarshal(stratCfg.DeleteStrategy, elasticacheDeleteConfig); err != nil {
 		return nil, nil, nil, errorUtil.Wrap(err, "failed to unmarshal aws elasticache cluster configuration")
 	}
+
+	// an adopted replication group is identified by the identifier of the existing replication group
+	if adoptID := resources.GetAdoptIdentifier(r, r.Spec.AdoptIdentifier); adoptID != "" {
+		elasticacheCreateConfig.ReplicationGroupId = aws.String(adoptID)
+		elasticacheDeleteConfig.ReplicationGroupId = aws.String(adoptID)
+	}
 	return elasticacheCreateConfig, elasticacheDeleteConfig, stratCfg, nil
 }
 
+// verifyElasticacheAdoption verifies an existing replication group can be adopted by a redis cr, the cluster mode of the
+// replication group must match the strategy and the connection details of the redis cr do not support an auth token
+func verifyElasticacheAdoption(elasticacheConfig *elasticache.CreateReplicationGroupInput, cache *elasticache.ReplicationGroup) error {
+	if aws.BoolValue(cache.ClusterEnabled) != isElasticacheClusterMode(elasticacheConfig) {
+		return fmt.Errorf("replication group %s has cluster mode enabled set to %t, which does not match the strategy", aws.StringValue(cache.ReplicationGroupId), aws.BoolValue(cache.ClusterEnabled))
+	}
+	if aws.BoolValue(cache.AuthTokenEnabled) {
+		return fmt.Errorf("replication group %s has an auth token enabled, which is not supported", aws.StringValue(cache.ReplicationGroupId))
+	}
+	return nil
+}
+
 // checks found config vs user strategy for changes, if found returns a modify replication group
-func buildElasticacheUpdateStrategy(elasticacheConfig *elasticache.CreateReplicationGroupInput, foundConfig *elasticache.ReplicationGroup) *elasticache.ModifyReplicationGroupInput {
+func buildElasticacheUpdateStrategy(elasticacheConfig *elasticache.CreateReplicationGroupInput, foundConfig *elasticache.ReplicationGroup, foundMaintenanceWindow string) *elasticache.ModifyReplicationGroupInput {
 	logrus.Infof("verifying that %s configuration is as expected", *foundConfig.ReplicationGroupId)
 	updateFound := false
 	ec := &elasticache.ModifyReplicationGroupInput{}
@@ -470,12 +909,166 @@ func buildElasticacheUpdateStrategy(elasticacheConfig *elasticache.CreateReplica
 		ec.SnapshotRetentionLimit = elasticacheConfig.SnapshotRetentionLimit
 		updateFound = true
 	}
+	if elasticacheConfig.PreferredMaintenanceWindow != nil && !strings.EqualFold(*elasticacheConfig.PreferredMaintenanceWindow, foundMaintenanceWindow) {
+		ec.PreferredMaintenanceWindow = elasticacheConfig.PreferredMaintenanceWindow
+		updateFound = true
+	}
+	if elasticacheConfig.SnapshotWindow != nil && *elasticacheConfig.SnapshotWindow != aws.StringValue(foundConfig.SnapshotWindow) {
+		ec.SnapshotWindow = elasticacheConfig.SnapshotWindow
+		updateFound = true
+	}
 	if updateFound {
 		return ec
 	}
 	return nil
 }
 
+// splitElasticacheUpdateStrategy splits a modification of a replication group into the changes which are applied online
+// and the disruptive changes, which cause downtime. nil is returned for either if it has no changes
+func splitElasticacheUpdateStrategy(ec *elasticache.ModifyReplicationGroupInput) (*elasticache.ModifyReplicationGroupInput, *elasticache.ModifyReplicationGroupInput) {
+	// changing the node type replaces the nodes of the replication group
+	if ec.CacheNodeType == nil {
+		return ec, nil
+	}
+	online := *ec
+	online.CacheNodeType = nil
+	disruptive := &elasticache.ModifyReplicationGroupInput{
+		ReplicationGroupId: ec.ReplicationGroupId,
+		CacheNodeType:      ec.CacheNodeType,
+	}
+	if reflect.DeepEqual(online, elasticache.ModifyReplicationGroupInput{ReplicationGroupId: ec.ReplicationGroupId}) {
+		return nil, disruptive
+	}
+	return &online, disruptive
+}
+
+func buildElasticachePendingModificationStatus(ec *elasticache.ModifyReplicationGroupInput) []croType.PendingModification {
+	var status []croType.PendingModification
+	if ec.CacheNodeType != nil {
+		status = append(status, croType.PendingModification{Field: "CacheNodeType", Value: *ec.CacheNodeType})
+	}
+	return status
+}
+
+// buildElasticacheDrift returns the fields of a replication group which differ from the desired configuration, including
+// the fields which are not updated by the operator. settings only described by the cache clusters of the replication
+// group are read from a member cluster
+func buildElasticacheDrift(elasticacheConfig *elasticache.CreateReplicationGroupInput, foundConfig *elasticache.ReplicationGroup, memberCluster *elasticache.CacheCluster) []croType.DriftedField {
+	var drift []croType.DriftedField
+	drift = appendDrift(drift, "CacheNodeType", aws.StringValue(elasticacheConfig.CacheNodeType), aws.StringValue(foundConfig.CacheNodeType))
+	drift = appendDrift(drift, "SnapshotRetentionLimit", strconv.FormatInt(aws.Int64Value(elasticacheConfig.SnapshotRetentionLimit), 10), strconv.FormatInt(aws.Int64Value(foundConfig.SnapshotRetentionLimit), 10))
+	// the cluster mode of a replication group can not be changed, it is only reported
+	drift = appendDrift(drift, "ClusterEnabled", strconv.FormatBool(isElasticacheClusterMode(elasticacheConfig)), strconv.FormatBool(aws.BoolValue(foundConfig.ClusterEnabled)))
+	if elasticacheConfig.NumNodeGroups != nil && aws.BoolValue(foundConfig.ClusterEnabled) {
+		drift = appendDrift(drift, "NumNodeGroups", strconv.FormatInt(*elasticacheConfig.NumNodeGroups, 10), strconv.Itoa(len(foundConfig.NodeGroups)))
+	}
+	if elasticacheConfig.SnapshotWindow != nil {
+		drift = appendDrift(drift, "SnapshotWindow", *elasticacheConfig.SnapshotWindow, aws.StringValue(foundConfig.SnapshotWindow))
+	}
+
+	// encryption and security groups can not be changed by modifying the replication group, they are only reported
+	if elasticacheConfig.AtRestEncryptionEnabled != nil {
+		drift = appendDrift(drift, "AtRestEncryptionEnabled", strconv.FormatBool(*elasticacheConfig.AtRestEncryptionEnabled), strconv.FormatBool(aws.BoolValue(foundConfig.AtRestEncryptionEnabled)))
+	}
+	if elasticacheConfig.TransitEncryptionEnabled != nil {
+		drift = appendDrift(drift, "TransitEncryptionEnabled", strconv.FormatBool(*elasticacheConfig.TransitEncryptionEnabled), strconv.FormatBool(aws.BoolValue(foundConfig.TransitEncryptionEnabled)))
+	}
+	if elasticacheConfig.KmsKeyId != nil {
+		drift = appendDrift(drift, "KmsKeyId", *elasticacheConfig.KmsKeyId, aws.StringValue(foundConfig.KmsKeyId))
+	}
+	if memberCluster == nil {
+		return drift
+	}
+	if elasticacheConfig.PreferredMaintenanceWindow != nil && !strings.EqualFold(*elasticacheConfig.PreferredMaintenanceWindow, aws.StringValue(memberCluster.PreferredMaintenanceWindow)) {
+		drift = appendDrift(drift, "PreferredMaintenanceWindow", *elasticacheConfig.PreferredMaintenanceWindow, aws.StringValue(memberCluster.PreferredMaintenanceWindow))
+	}
+	if elasticacheConfig.SecurityGroupIds != nil {
+		var foundSecurityGroupIds []*string
+		for _, sg := range memberCluster.SecurityGroups {
+			foundSecurityGroupIds = append(foundSecurityGroupIds, sg.SecurityGroupId)
+		}
+		drift = appendDrift(drift, "SecurityGroupIds", formatDriftList(elasticacheConfig.SecurityGroupIds), formatDriftList(foundSecurityGroupIds))
+	}
+	return drift
+}
+
+// getElasticacheMemberCluster returns the first cache cluster of a replication group, nil is returned if it has none
+func getElasticacheMemberCluster(cacheSvc elasticacheiface.ElastiCacheAPI, foundCache *elasticache.ReplicationGroup) (*elasticache.CacheCluster, error) {
+	if len(foundCache.MemberClusters) == 0 {
+		return nil, nil
+	}
+	output, err := cacheSvc.DescribeCacheClusters(&elasticache.DescribeCacheClustersInput{
+		CacheClusterId: foundCache.MemberClusters[0],
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(output.CacheClusters) == 0 {
+		return nil, nil
+	}
+	return output.CacheClusters[0], nil
+}
+
+// isElasticacheClusterMode returns true if the create strategy declares shards, which enables cluster mode
+func isElasticacheClusterMode(elasticacheConfig *elasticache.CreateReplicationGroupInput) bool {
+	return elasticacheConfig.NumNodeGroups != nil || len(elasticacheConfig.NodeGroupConfiguration) > 0
+}
+
+// buildElasticacheClusterParameterGroupName returns the default parameter group with cluster mode enabled for the
+// family of an engine version, e.g. default.redis4.0.cluster.on for 4.0.10. the families of redis 6 and later are
+// named after the major version only, e.g. default.redis6.x.cluster.on for 6.x or 6.2.6
+func buildElasticacheClusterParameterGroupName(engineVersion string) string {
+	parts := strings.SplitN(engineVersion, ".", 3)
+	family := engineVersion
+	if len(parts) > 1 {
+		family = strings.Join(parts[:2], ".")
+	}
+	if major, err := strconv.Atoi(parts[0]); err == nil && major >= 6 {
+		family = fmt.Sprintf("%d.x", major)
+	}
+	return fmt.Sprintf("default.redis%s.cluster.on", family)
+}
+
+// getElasticacheEndpoint returns the endpoint clients connect to, the configuration endpoint of a cluster mode enabled
+// replication group or the primary endpoint otherwise. nil is returned if the endpoint is not available yet
+func getElasticacheEndpoint(cache *elasticache.ReplicationGroup) *elasticache.Endpoint {
+	if aws.BoolValue(cache.ClusterEnabled) {
+		return cache.ConfigurationEndpoint
+	}
+	if len(cache.NodeGroups) == 0 {
+		return nil
+	}
+	return cache.NodeGroups[0].PrimaryEndpoint
+}
+
+// buildElasticacheShardConfiguration returns the online resharding of a cluster mode enabled replication group to the
+// number of shards of the strategy, nil is returned if the number of shards is as expected. when scaling in, the shards
+// with the highest ids are removed
+func buildElasticacheShardConfiguration(elasticacheConfig *elasticache.CreateReplicationGroupInput, foundConfig *elasticache.ReplicationGroup) *elasticache.ModifyReplicationGroupShardConfigurationInput {
+	if elasticacheConfig.NumNodeGroups == nil || !aws.BoolValue(foundConfig.ClusterEnabled) {
+		return nil
+	}
+	count := *elasticacheConfig.NumNodeGroups
+	found := int64(len(foundConfig.NodeGroups))
+	if count == found || count < 1 {
+		return nil
+	}
+	reshard := &elasticache.ModifyReplicationGroupShardConfigurationInput{
+		ReplicationGroupId: foundConfig.ReplicationGroupId,
+		NodeGroupCount:     aws.Int64(count),
+		ApplyImmediately:   aws.Bool(true),
+	}
+	if count < found {
+		var nodeGroupIds []string
+		for _, ng := range foundConfig.NodeGroups {
+			nodeGroupIds = append(nodeGroupIds, aws.StringValue(ng.NodeGroupId))
+		}
+		sort.Strings(nodeGroupIds)
+		reshard.NodeGroupsToRemove = aws.StringSlice(nodeGroupIds[count:])
+	}
+	return reshard
+}
+
 // verifyRedisConfig checks elasticache config, if none exist sets values to default
 func (p *RedisProvider) buildElasticacheCreateStrategy(ctx context.Context, r *v1alpha1.Redis, ec2Svc ec2iface.EC2API, elasticacheConfig *elasticache.CreateReplicationGroupInput) error {
 
@@ -488,10 +1081,23 @@ func (p *RedisProvider) buildElasticacheCreateStrategy(ctx context.Context, r *v
 	if elasticacheConfig.ReplicationGroupDescription == nil {
 		elasticacheConfig.ReplicationGroupDescription = aws.String(defaultDescription)
 	}
+	// a strategy which declares shards creates a cluster mode enabled replication group, which has replicas per shard
+	// instead of a number of cache clusters
+	if isElasticacheClusterMode(elasticacheConfig) {
+		if elasticacheConfig.EngineVersion == nil {
+			elasticacheConfig.EngineVersion = aws.String(defaultClusterEngineVersion)
+		}
+		if elasticacheConfig.ReplicasPerNodeGroup == nil {
+			elasticacheConfig.ReplicasPerNodeGroup = aws.Int64(defaultClusterReplicasPerShard)
+		}
+		if elasticacheConfig.CacheParameterGroupName == nil {
+			elasticacheConfig.CacheParameterGroupName = aws.String(buildElasticacheClusterParameterGroupName(*elasticacheConfig.EngineVersion))
+		}
+	}
 	if elasticacheConfig.EngineVersion == nil {
 		elasticacheConfig.EngineVersion = aws.String(defaultEngineVersion)
 	}
-	if elasticacheConfig.NumCacheClusters == nil {
+	if elasticacheConfig.NumCacheClusters == nil && !isElasticacheClusterMode(elasticacheConfig) {
 		elasticacheConfig.NumCacheClusters = aws.Int64(defaultNumCacheClusters)
 	}
 	if elasticacheConfig.SnapshotRetentionLimit == nil {
@@ -655,6 +1261,17 @@ func (p *RedisProvider) exposeRedisMetrics(ctx context.Context, cr *v1alpha1.Red
 
 }
 
+// exposeRedisDriftMetric exposes the drift of a replication group, the drift of the last reconcile is read from the
+// status of the cr
+func (p *RedisProvider) exposeRedisDriftMetric(ctx context.Context, cr *v1alpha1.Redis, instance *elasticache.ReplicationGroup, drift []croType.DriftedField) {
+	clusterID, err := resources.GetClusterID(ctx, p.Client)
+	if err != nil {
+		logrus.Error(fmt.Sprintf("failed to get cluster id while exposing drift metric for %s", *instance.ReplicationGroupId))
+		return
+	}
+	exposeDriftMetric(buildRedisGenericMetricLabels(cr, instance, clusterID), cr.Status.Drift, drift)
+}
+
 // sets maintenance metric
 func (p *RedisProvider) setRedisServiceMaintenanceMetric(ctx context.Context, cr *v1alpha1.Redis, cacheSvc elasticacheiface.ElastiCacheAPI, instance *elasticache.ReplicationGroup) {
 	// info about the elasticache cluster to be created
@@ -707,14 +1324,15 @@ func (p *RedisProvider) createElasticacheConnectionMetric(ctx context.Context, c
 	genericLabels := buildRedisGenericMetricLabels(cr, cache, clusterID)
 
 	// check if the node group is available
-	if cache.NodeGroups == nil {
+	endpoint := getElasticacheEndpoint(cache)
+	if endpoint == nil {
 		resources.SetMetric(resources.DefaultRedisConnectionMetricName, genericLabels, 0)
 		logrus.Infof("node group not yet available for: %s", *cache.ReplicationGroupId)
 		return
 	}
 
 	// test the connection
-	conn := p.TCPPinger.TCPConnection(*cache.NodeGroups[0].PrimaryEndpoint.Address, int(*cache.NodeGroups[0].PrimaryEndpoint.Port))
+	conn := p.TCPPinger.TCPConnection(*endpoint.Address, int(*endpoint.Port))
 	if !conn {
 		// create failed connection metric
 		resources.SetMetric(resources.DefaultRedisConnectionMetricName, genericLabels, 0)
@@ -2,15 +2,18 @@ package aws
 
 import (
 	"context"
+	"fmt"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"reflect"
 	"time"
 
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	"github.com/integr8ly/cloud-resource-operator/pkg/annotations"
 	croApis "github.com/integr8ly/cloud-resource-operator/pkg/apis"
 	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
 	"github.com/integr8ly/cloud-resource-operator/pkg/providers"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
 
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -46,10 +49,16 @@ type mockElasticacheClient struct {
 	wantErrDelete     bool
 	wantEmpty         bool
 	replicationGroups []*elasticache.ReplicationGroup
+	updateActions     []*elasticache.UpdateAction
+	appliedUpdates    []string
+	startedActions    []string
+	engineVersions    []*elasticache.CacheEngineVersion
 }
 
 type mockStsClient struct {
 	stsiface.STSAPI
+	callerArn    string
+	assumedRoles []string
 }
 
 func buildTestSchemeRedis() (*runtime.Scheme, error) {
@@ -86,14 +95,50 @@ func (m *mockElasticacheClient) DeleteReplicationGroup(*elasticache.DeleteReplic
 
 // mock elasticache ModifyReplicationGroup output
 func (m *mockElasticacheClient) ModifyReplicationGroup(*elasticache.ModifyReplicationGroupInput) (*elasticache.ModifyReplicationGroupOutput, error) {
+	m.startedActions = append(m.startedActions, "ModifyReplicationGroup")
 	return &elasticache.ModifyReplicationGroupOutput{}, nil
 }
 
+// mock elasticache ModifyReplicationGroupShardConfiguration output
+func (m *mockElasticacheClient) ModifyReplicationGroupShardConfiguration(*elasticache.ModifyReplicationGroupShardConfigurationInput) (*elasticache.ModifyReplicationGroupShardConfigurationOutput, error) {
+	return &elasticache.ModifyReplicationGroupShardConfigurationOutput{}, nil
+}
+
+// mock elasticache IncreaseReplicaCount output
+func (m *mockElasticacheClient) IncreaseReplicaCount(*elasticache.IncreaseReplicaCountInput) (*elasticache.IncreaseReplicaCountOutput, error) {
+	m.startedActions = append(m.startedActions, "IncreaseReplicaCount")
+	return &elasticache.IncreaseReplicaCountOutput{}, nil
+}
+
+// mock elasticache DecreaseReplicaCount output
+func (m *mockElasticacheClient) DecreaseReplicaCount(*elasticache.DecreaseReplicaCountInput) (*elasticache.DecreaseReplicaCountOutput, error) {
+	m.startedActions = append(m.startedActions, "DecreaseReplicaCount")
+	return &elasticache.DecreaseReplicaCountOutput{}, nil
+}
+
+// mock elasticache TestFailover output
+func (m *mockElasticacheClient) TestFailover(*elasticache.TestFailoverInput) (*elasticache.TestFailoverOutput, error) {
+	m.startedActions = append(m.startedActions, "TestFailover")
+	return &elasticache.TestFailoverOutput{}, nil
+}
+
+// mock elasticache DescribeCacheEngineVersions output
+func (m *mockElasticacheClient) DescribeCacheEngineVersions(*elasticache.DescribeCacheEngineVersionsInput) (*elasticache.DescribeCacheEngineVersionsOutput, error) {
+	return &elasticache.DescribeCacheEngineVersionsOutput{
+		CacheEngineVersions: m.engineVersions,
+	}, nil
+}
+
 // mock elasticache AddTagsToResource output
 func (m *mockElasticacheClient) AddTagsToResource(*elasticache.AddTagsToResourceInput) (*elasticache.TagListMessage, error) {
 	return &elasticache.TagListMessage{}, nil
 }
 
+// mock elasticache ListTagsForResource output
+func (m *mockElasticacheClient) ListTagsForResource(*elasticache.ListTagsForResourceInput) (*elasticache.TagListMessage, error) {
+	return &elasticache.TagListMessage{}, nil
+}
+
 // mock elasticache DescribeSnapshots
 func (m *mockElasticacheClient) DescribeSnapshots(*elasticache.DescribeSnapshotsInput) (*elasticache.DescribeSnapshotsOutput, error) {
 	return &elasticache.DescribeSnapshotsOutput{}, nil
@@ -117,6 +162,17 @@ func (m *mockElasticacheClient) DescribeServiceUpdates(*elasticache.DescribeServ
 	return &elasticache.DescribeServiceUpdatesOutput{}, nil
 }
 
+func (m *mockElasticacheClient) DescribeUpdateActions(*elasticache.DescribeUpdateActionsInput) (*elasticache.DescribeUpdateActionsOutput, error) {
+	return &elasticache.DescribeUpdateActionsOutput{
+		UpdateActions: m.updateActions,
+	}, nil
+}
+
+func (m *mockElasticacheClient) BatchApplyUpdateAction(input *elasticache.BatchApplyUpdateActionInput) (*elasticache.BatchApplyUpdateActionOutput, error) {
+	m.appliedUpdates = append(m.appliedUpdates, *input.ServiceUpdateName)
+	return &elasticache.BatchApplyUpdateActionOutput{}, nil
+}
+
 func (m *mockElasticacheClient) DescribeCacheSubnetGroups(*elasticache.DescribeCacheSubnetGroupsInput) (*elasticache.DescribeCacheSubnetGroupsOutput, error) {
 	return &elasticache.DescribeCacheSubnetGroupsOutput{}, nil
 }
@@ -125,10 +181,28 @@ func (m *mockElasticacheClient) CreateCacheSubnetGroup(*elasticache.CreateCacheS
 	return &elasticache.CreateCacheSubnetGroupOutput{}, nil
 }
 
-// mock sts get caller identity
+// mock sts get caller identity, the operator is an iam user unless another caller is set
 func (m *mockStsClient) GetCallerIdentity(*sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error) {
+	callerArn := m.callerArn
+	if callerArn == "" {
+		callerArn = "arn:aws:iam::test:user/cloud-resources-aws-credentials"
+	}
 	return &sts.GetCallerIdentityOutput{
 		Account: aws.String("test"),
+		Arn:     aws.String(callerArn),
+	}, nil
+}
+
+// mock sts assume role, the credentials of the role are valid for the requested duration
+func (m *mockStsClient) AssumeRole(input *sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error) {
+	m.assumedRoles = append(m.assumedRoles, aws.StringValue(input.RoleArn))
+	return &sts.AssumeRoleOutput{
+		Credentials: &sts.Credentials{
+			AccessKeyId:     aws.String("ASIATEST"),
+			SecretAccessKey: aws.String("secret"),
+			SessionToken:    aws.String("token"),
+			Expiration:      aws.Time(time.Now().Add(time.Second * time.Duration(aws.Int64Value(input.DurationSeconds)))),
+		},
 	}, nil
 }
 
@@ -181,6 +255,27 @@ func buildReplicationGroupReady() []*elasticache.ReplicationGroup {
 	}
 }
 
+func buildReplicationGroupClusterMode(shards int) *elasticache.ReplicationGroup {
+	rg := &elasticache.ReplicationGroup{
+		ReplicationGroupId:     aws.String("test-id"),
+		Status:                 aws.String("available"),
+		CacheNodeType:          aws.String("test"),
+		SnapshotRetentionLimit: aws.Int64(20),
+		ClusterEnabled:         aws.Bool(true),
+		ConfigurationEndpoint: &elasticache.Endpoint{
+			Address: testAddress,
+			Port:    testPort,
+		},
+	}
+	for i := 1; i <= shards; i++ {
+		rg.NodeGroups = append(rg.NodeGroups, &elasticache.NodeGroup{
+			NodeGroupId: aws.String(fmt.Sprintf("%04d", i)),
+			Status:      aws.String("available"),
+		})
+	}
+	return rg
+}
+
 func buildTestRedisCluster() *providers.RedisCluster {
 	return &providers.RedisCluster{DeploymentDetails: &providers.RedisDeploymentDetails{
 		URI:  *testAddress,
@@ -264,6 +359,31 @@ func Test_createRedisCluster(t *testing.T) {
 			want:    buildTestRedisCluster(),
 			wantErr: false,
 		},
+		{
+			name: "test elasticache with cluster mode enabled returns the configuration endpoint",
+			args: args{
+				ctx:         context.TODO(),
+				cacheSvc:    &mockElasticacheClient{replicationGroups: []*elasticache.ReplicationGroup{buildReplicationGroupClusterMode(2)}},
+				ec2Svc:      &mockEc2Client{vpcs: buildVpcs(), subnets: buildSubnets(), secGroups: buildSecurityGroups(secName)},
+				r:           buildTestRedisCR(),
+				stsSvc:      &mockStsClient{},
+				redisConfig: &elasticache.CreateReplicationGroupInput{ReplicationGroupId: aws.String("test-id"), NumNodeGroups: aws.Int64(2)},
+				stratCfg:    &StrategyConfig{Region: "test"},
+			},
+			fields: fields{
+				ConfigManager:     nil,
+				CredentialManager: nil,
+				Logger:            testLogger,
+				TCPPinger:         buildMockConnectionTester(),
+				Client:            fake.NewFakeClientWithScheme(scheme, buildTestRedisCR(), builtTestCredSecret(), buildTestInfra(), buildTestPrometheusRule()),
+			},
+			want: &providers.RedisCluster{DeploymentDetails: &providers.RedisDeploymentDetails{
+				URI:         *testAddress,
+				Port:        *testPort,
+				ClusterMode: true,
+			}},
+			wantErr: false,
+		},
 		{
 			name: "test elasticache already exists and status is not available",
 			args: args{
@@ -440,7 +560,7 @@ func TestAWSRedisProvider_deleteRedisCluster(t *testing.T) {
 				ConfigManager:     tt.fields.ConfigManager,
 				CacheSvc:          tt.fields.CacheSvc,
 			}
-			if _, err := p.deleteElasticacheCluster(tt.args.ctx, tt.fields.CacheSvc, tt.args.redisCreateConfig, tt.args.redisDeleteConfig, tt.args.redis); (err != nil) != tt.wantErr {
+			if _, err := p.deleteElasticacheCluster(tt.args.ctx, tt.fields.CacheSvc, tt.args.redisCreateConfig, tt.args.redisDeleteConfig, tt.args.redis, &StrategyConfig{Region: "eu-west-1"}); (err != nil) != tt.wantErr {
 				t.Errorf("deleteElasticacheCluster() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -461,7 +581,9 @@ func TestAWSRedisProvider_GetReconcileTime(t *testing.T) {
 			args: args{
 				r: &v1alpha1.Redis{
 					Status: v1alpha1.RedisStatus{
-						Phase: types.PhaseInProgress,
+						ResourceTypeStatus: types.ResourceTypeStatus{
+							Phase: types.PhaseInProgress,
+						},
 					},
 				},
 			},
@@ -472,7 +594,9 @@ func TestAWSRedisProvider_GetReconcileTime(t *testing.T) {
 			args: args{
 				r: &v1alpha1.Redis{
 					Status: v1alpha1.RedisStatus{
-						Phase: types.PhaseComplete,
+						ResourceTypeStatus: types.ResourceTypeStatus{
+							Phase: types.PhaseComplete,
+						},
 					},
 				},
 			},
@@ -560,3 +684,372 @@ func TestAWSRedisProvider_TagElasticache(t *testing.T) {
 		})
 	}
 }
+
+func TestAWSRedisProvider_reconcileServiceUpdates(t *testing.T) {
+	scheme, err := buildTestSchemeRedis()
+	if err != nil {
+		t.Fatal("failed to build scheme", err)
+	}
+	applyByDate := time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)
+	buildUpdateActions := func() []*elasticache.UpdateAction {
+		return []*elasticache.UpdateAction{
+			{
+				ServiceUpdateName:                   aws.String("critical-update"),
+				ServiceUpdateSeverity:               aws.String(elasticache.ServiceUpdateSeverityCritical),
+				ServiceUpdateType:                   aws.String("security-update"),
+				ServiceUpdateRecommendedApplyByDate: aws.Time(applyByDate),
+				UpdateActionStatus:                  aws.String(elasticache.UpdateActionStatusNotApplied),
+			},
+			{
+				ServiceUpdateName:     aws.String("low-update"),
+				ServiceUpdateSeverity: aws.String(elasticache.ServiceUpdateSeverityLow),
+				UpdateActionStatus:    aws.String(elasticache.UpdateActionStatusNotApplied),
+			},
+			{
+				ServiceUpdateName:     aws.String("running-update"),
+				ServiceUpdateSeverity: aws.String(elasticache.ServiceUpdateSeverityCritical),
+				UpdateActionStatus:    aws.String(elasticache.UpdateActionStatusInProgress),
+				NodesUpdated:          aws.String("1/2"),
+			},
+		}
+	}
+	buildApprovedCR := func() *v1alpha1.Redis {
+		cr := buildTestRedisCR()
+		cr.Annotations = map[string]string{resources.ApplyMaintenanceAnnotation: "true"}
+		return cr
+	}
+	tests := []struct {
+		name        string
+		cr          *v1alpha1.Redis
+		stratCfg    *StrategyConfig
+		wantApplied []string
+		wantStatus  []string
+		wantErr     bool
+	}{
+		{
+			name:       "test service updates are reported but not applied without approval",
+			cr:         buildTestRedisCR(),
+			stratCfg:   &StrategyConfig{},
+			wantStatus: []string{elasticache.UpdateActionStatusNotApplied, elasticache.UpdateActionStatusNotApplied, elasticache.UpdateActionStatusInProgress},
+		},
+		{
+			name:        "test service updates are applied when approved",
+			cr:          buildApprovedCR(),
+			stratCfg:    &StrategyConfig{},
+			wantApplied: []string{"critical-update", "low-update"},
+			wantStatus:  []string{elasticache.UpdateActionStatusWaitingToStart, elasticache.UpdateActionStatusWaitingToStart, elasticache.UpdateActionStatusInProgress},
+		},
+		{
+			name:        "test service updates below the severity of the policy are not applied",
+			cr:          buildApprovedCR(),
+			stratCfg:    &StrategyConfig{ServiceUpdates: &ServiceUpdatePolicy{Severity: elasticache.ServiceUpdateSeverityImportant}},
+			wantApplied: []string{"critical-update"},
+			wantStatus:  []string{elasticache.UpdateActionStatusWaitingToStart, elasticache.UpdateActionStatusNotApplied, elasticache.UpdateActionStatusInProgress},
+		},
+		{
+			name:        "test service updates are applied inside an always open window when auto",
+			cr:          buildTestRedisCR(),
+			stratCfg:    &StrategyConfig{ServiceUpdates: &ServiceUpdatePolicy{Apply: serviceUpdateApplyAuto, Window: "sun:00:00-sat:23:59", Severity: elasticache.ServiceUpdateSeverityCritical}},
+			wantApplied: []string{"critical-update"},
+			wantStatus:  []string{elasticache.UpdateActionStatusWaitingToStart, elasticache.UpdateActionStatusNotApplied, elasticache.UpdateActionStatusInProgress},
+		},
+		{
+			name:       "test maintenance window is ignored when approval is required",
+			cr:         buildTestRedisCR(),
+			stratCfg:   &StrategyConfig{MaintenanceWindow: "sun:00:00-sat:23:59", ServiceUpdates: &ServiceUpdatePolicy{Apply: serviceUpdateApplyApprove}},
+			wantStatus: []string{elasticache.UpdateActionStatusNotApplied, elasticache.UpdateActionStatusNotApplied, elasticache.UpdateActionStatusInProgress},
+		},
+		{
+			name:     "test error when severity of the policy is invalid",
+			cr:       buildTestRedisCR(),
+			stratCfg: &StrategyConfig{ServiceUpdates: &ServiceUpdatePolicy{Severity: "invalid"}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cacheSvc := &mockElasticacheClient{updateActions: buildUpdateActions()}
+			p := &RedisProvider{
+				Client: fake.NewFakeClientWithScheme(scheme, tt.cr),
+				Logger: testLogger,
+			}
+			_, err := p.reconcileServiceUpdates(context.TODO(), tt.cr, cacheSvc, buildReplicationGroupReady()[0], tt.stratCfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("reconcileServiceUpdates() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(cacheSvc.appliedUpdates, tt.wantApplied) {
+				t.Errorf("reconcileServiceUpdates() applied = %v, want %v", cacheSvc.appliedUpdates, tt.wantApplied)
+			}
+			var gotStatus []string
+			for _, pm := range tt.cr.Status.PendingMaintenance {
+				gotStatus = append(gotStatus, pm.Status)
+			}
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("reconcileServiceUpdates() status = %v, want %v", gotStatus, tt.wantStatus)
+			}
+			if tt.cr.Status.PendingMaintenance[0].RecommendedApplyByDate != "2019-12-01T00:00:00Z" {
+				t.Errorf("reconcileServiceUpdates() recommended apply by date = %s", tt.cr.Status.PendingMaintenance[0].RecommendedApplyByDate)
+			}
+			if annotations.Has(tt.cr, resources.ApplyMaintenanceAnnotation) {
+				t.Errorf("reconcileServiceUpdates() approval annotation was not removed")
+			}
+		})
+	}
+}
+
+func Test_splitElasticacheUpdateStrategy(t *testing.T) {
+	testID := aws.String("test-id")
+	tests := []struct {
+		name           string
+		ec             *elasticache.ModifyReplicationGroupInput
+		wantOnline     *elasticache.ModifyReplicationGroupInput
+		wantDisruptive *elasticache.ModifyReplicationGroupInput
+	}{
+		{
+			name: "test online changes are not deferred",
+			ec: &elasticache.ModifyReplicationGroupInput{
+				ReplicationGroupId:     testID,
+				SnapshotRetentionLimit: aws.Int64(7),
+			},
+			wantOnline: &elasticache.ModifyReplicationGroupInput{
+				ReplicationGroupId:     testID,
+				SnapshotRetentionLimit: aws.Int64(7),
+			},
+		},
+		{
+			name: "test node type changes are disruptive",
+			ec: &elasticache.ModifyReplicationGroupInput{
+				ReplicationGroupId:     testID,
+				CacheNodeType:          aws.String("cache.t3.small"),
+				SnapshotRetentionLimit: aws.Int64(7),
+			},
+			wantOnline: &elasticache.ModifyReplicationGroupInput{
+				ReplicationGroupId:     testID,
+				SnapshotRetentionLimit: aws.Int64(7),
+			},
+			wantDisruptive: &elasticache.ModifyReplicationGroupInput{
+				ReplicationGroupId: testID,
+				CacheNodeType:      aws.String("cache.t3.small"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotOnline, gotDisruptive := splitElasticacheUpdateStrategy(tt.ec)
+			if !reflect.DeepEqual(gotOnline, tt.wantOnline) {
+				t.Errorf("splitElasticacheUpdateStrategy() online = %v, want %v", gotOnline, tt.wantOnline)
+			}
+			if !reflect.DeepEqual(gotDisruptive, tt.wantDisruptive) {
+				t.Errorf("splitElasticacheUpdateStrategy() disruptive = %v, want %v", gotDisruptive, tt.wantDisruptive)
+			}
+		})
+	}
+}
+
+func Test_verifyElasticacheAdoption(t *testing.T) {
+	tests := []struct {
+		name              string
+		elasticacheConfig *elasticache.CreateReplicationGroupInput
+		cache             *elasticache.ReplicationGroup
+		wantErr           bool
+	}{
+		{
+			name:              "test replication group can be adopted",
+			elasticacheConfig: &elasticache.CreateReplicationGroupInput{},
+			cache:             buildReplicationGroupReady()[0],
+			wantErr:           false,
+		},
+		{
+			name:              "test replication group with cluster mode enabled can be adopted by a cluster mode strategy",
+			elasticacheConfig: &elasticache.CreateReplicationGroupInput{NumNodeGroups: aws.Int64(2)},
+			cache:             buildReplicationGroupClusterMode(2),
+			wantErr:           false,
+		},
+		{
+			name:              "test replication group with cluster mode enabled can not be adopted by a strategy without shards",
+			elasticacheConfig: &elasticache.CreateReplicationGroupInput{},
+			cache: &elasticache.ReplicationGroup{
+				ReplicationGroupId: aws.String("test-id"),
+				ClusterEnabled:     aws.Bool(true),
+			},
+			wantErr: true,
+		},
+		{
+			name:              "test replication group with an auth token can not be adopted",
+			elasticacheConfig: &elasticache.CreateReplicationGroupInput{},
+			cache: &elasticache.ReplicationGroup{
+				ReplicationGroupId: aws.String("test-id"),
+				AuthTokenEnabled:   aws.Bool(true),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := verifyElasticacheAdoption(tt.elasticacheConfig, tt.cache); (err != nil) != tt.wantErr {
+				t.Errorf("verifyElasticacheAdoption() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_buildElasticacheDrift(t *testing.T) {
+	tests := []struct {
+		name              string
+		elasticacheConfig *elasticache.CreateReplicationGroupInput
+		foundConfig       *elasticache.ReplicationGroup
+		memberCluster     *elasticache.CacheCluster
+		want              []types.DriftedField
+	}{
+		{
+			name: "test no drift when replication group is as expected",
+			elasticacheConfig: &elasticache.CreateReplicationGroupInput{
+				CacheNodeType:          aws.String("test"),
+				SnapshotRetentionLimit: aws.Int64(20),
+			},
+			foundConfig: buildReplicationGroupReady()[0],
+			want:        nil,
+		},
+		{
+			name: "test drift of node type and encryption",
+			elasticacheConfig: &elasticache.CreateReplicationGroupInput{
+				CacheNodeType:           aws.String("cache.t3.small"),
+				SnapshotRetentionLimit:  aws.Int64(20),
+				AtRestEncryptionEnabled: aws.Bool(true),
+			},
+			foundConfig: buildReplicationGroupReady()[0],
+			want: []types.DriftedField{
+				{Field: "CacheNodeType", Desired: "cache.t3.small", Observed: "test"},
+				{Field: "AtRestEncryptionEnabled", Desired: "true", Observed: "false"},
+			},
+		},
+		{
+			name: "test drift of settings described by the member cluster",
+			elasticacheConfig: &elasticache.CreateReplicationGroupInput{
+				CacheNodeType:              aws.String("test"),
+				SnapshotRetentionLimit:     aws.Int64(20),
+				PreferredMaintenanceWindow: aws.String("Sun:02:00-Sun:03:00"),
+				SecurityGroupIds:           []*string{aws.String("sg-1")},
+			},
+			foundConfig: buildReplicationGroupReady()[0],
+			memberCluster: &elasticache.CacheCluster{
+				PreferredMaintenanceWindow: aws.String("sun:02:00-sun:03:00"),
+				SecurityGroups: []*elasticache.SecurityGroupMembership{
+					{SecurityGroupId: aws.String("sg-2")},
+				},
+			},
+			want: []types.DriftedField{
+				{Field: "SecurityGroupIds", Desired: "sg-1", Observed: "sg-2"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildElasticacheDrift(tt.elasticacheConfig, tt.foundConfig, tt.memberCluster); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildElasticacheDrift() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_buildElasticacheShardConfiguration(t *testing.T) {
+	tests := []struct {
+		name              string
+		elasticacheConfig *elasticache.CreateReplicationGroupInput
+		foundConfig       *elasticache.ReplicationGroup
+		want              *elasticache.ModifyReplicationGroupShardConfigurationInput
+	}{
+		{
+			name:              "test no resharding when the number of shards is as expected",
+			elasticacheConfig: &elasticache.CreateReplicationGroupInput{NumNodeGroups: aws.Int64(2)},
+			foundConfig:       buildReplicationGroupClusterMode(2),
+			want:              nil,
+		},
+		{
+			name:              "test no resharding without cluster mode",
+			elasticacheConfig: &elasticache.CreateReplicationGroupInput{NumNodeGroups: aws.Int64(2)},
+			foundConfig:       buildReplicationGroupReady()[0],
+			want:              nil,
+		},
+		{
+			name:              "test shards are added when scaling out",
+			elasticacheConfig: &elasticache.CreateReplicationGroupInput{NumNodeGroups: aws.Int64(3)},
+			foundConfig:       buildReplicationGroupClusterMode(2),
+			want: &elasticache.ModifyReplicationGroupShardConfigurationInput{
+				ReplicationGroupId: aws.String("test-id"),
+				NodeGroupCount:     aws.Int64(3),
+				ApplyImmediately:   aws.Bool(true),
+			},
+		},
+		{
+			name:              "test shards with the highest ids are removed when scaling in",
+			elasticacheConfig: &elasticache.CreateReplicationGroupInput{NumNodeGroups: aws.Int64(1)},
+			foundConfig:       buildReplicationGroupClusterMode(3),
+			want: &elasticache.ModifyReplicationGroupShardConfigurationInput{
+				ReplicationGroupId: aws.String("test-id"),
+				NodeGroupCount:     aws.Int64(1),
+				ApplyImmediately:   aws.Bool(true),
+				NodeGroupsToRemove: aws.StringSlice([]string{"0002", "0003"}),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildElasticacheShardConfiguration(tt.elasticacheConfig, tt.foundConfig); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildElasticacheShardConfiguration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getElasticacheEndpoint(t *testing.T) {
+	tests := []struct {
+		name  string
+		cache *elasticache.ReplicationGroup
+		want  *elasticache.Endpoint
+	}{
+		{
+			name:  "test primary endpoint is returned without cluster mode",
+			cache: buildReplicationGroupReady()[0],
+			want:  &elasticache.Endpoint{Address: testAddress, Port: testPort},
+		},
+		{
+			name:  "test configuration endpoint is returned with cluster mode",
+			cache: buildReplicationGroupClusterMode(2),
+			want:  &elasticache.Endpoint{Address: testAddress, Port: testPort},
+		},
+		{
+			name:  "test nil is returned without node groups",
+			cache: buildReplicationGroupPending()[0],
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getElasticacheEndpoint(tt.cache); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getElasticacheEndpoint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_buildElasticacheClusterParameterGroupName(t *testing.T) {
+	tests := []struct {
+		engineVersion string
+		want          string
+	}{
+		{engineVersion: "4.0.10", want: "default.redis4.0.cluster.on"},
+		{engineVersion: "5.0.6", want: "default.redis5.0.cluster.on"},
+		{engineVersion: "6.x", want: "default.redis6.x.cluster.on"},
+		{engineVersion: "6.2.6", want: "default.redis6.x.cluster.on"},
+	}
+	for _, tt := range tests {
+		if got := buildElasticacheClusterParameterGroupName(tt.engineVersion); got != tt.want {
+			t.Errorf("buildElasticacheClusterParameterGroupName(%s) = %v, want %v", tt.engineVersion, got, tt.want)
+		}
+	}
+}
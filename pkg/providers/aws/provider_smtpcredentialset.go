@@ -9,13 +9,10 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/integr8ly/cloud-resource-operator/pkg/annotations"
 	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
 
-	"k8s.io/apimachinery/pkg/api/errors"
-
-	v1 "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
-	controllerruntime "sigs.k8s.io/controller-runtime"
-
 	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
 
 	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
@@ -107,6 +104,13 @@ func (p *SMTPCredentialProvider) CreateSMTPCredentials(ctx context.Context, smtp
 		return nil, croType.StatusMessage(errMsg), errorUtil.Wrapf(err, errMsg)
 	}
 
+	// the smtp password of ses is derived from the secret access key of an iam user, temporary credentials of an iam
+	// role can not be used
+	if stratCfg.Credentials.isRoleBased() {
+		errMsg := fmt.Sprintf("credential mode %s is not supported for smtp credential instance %s, aws ses smtp credentials require static credentials", stratCfg.Credentials.Mode, smtpCreds.Name)
+		return nil, croType.StatusMessage(errMsg), errorUtil.New(errMsg)
+	}
+
 	awsRegion := stratCfg.Region
 	defRegion, err := GetRegionFromStrategyOrDefault(ctx, p.Client, stratCfg)
 	if err != nil {
@@ -137,6 +141,13 @@ func (p *SMTPCredentialProvider) CreateSMTPCredentials(ctx context.Context, smtp
 		return nil, croType.StatusMessage(errMsg), errorUtil.Wrapf(err, errMsg)
 	}
 
+	// rotate the access key of the iam user if due, the smtp password is derived from the rotated secret access key
+	sendMailCreds, err = p.reconcileSESCredentialsRotation(ctx, smtpCreds, stratCfg, sendMailCreds, credSecName)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to rotate aws ses credentials for smtp credentials instance %s", smtpCreds.Name)
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
 	p.Logger.Info("creating smtp credentials from created iam role")
 	smtpPass, err := getSMTPPasswordFromAWSSecret(sendMailCreds.SecretAccessKey)
 	if err != nil {
@@ -160,16 +171,14 @@ func (p *SMTPCredentialProvider) CreateSMTPCredentials(ctx context.Context, smtp
 
 func (p *SMTPCredentialProvider) DeleteSMTPCredentials(ctx context.Context, smtpCreds *v1alpha1.SMTPCredentialSet) (croType.StatusMessage, error) {
 	// remove the credentials request created by the provider
-	endUserCredsReq := &v1.CredentialsRequest{
-		ObjectMeta: controllerruntime.ObjectMeta{
-			Name:      smtpCreds.Name,
-			Namespace: smtpCreds.Namespace,
-		},
-	}
-	if err := p.Client.Delete(ctx, endUserCredsReq); err != nil && !errors.IsNotFound(err) {
+	if err := deleteCredentialsRequest(ctx, p.Client, smtpCreds.Name, smtpCreds.Namespace); err != nil {
 		errMsg := fmt.Sprintf("failed to delete credential request %s", smtpCreds.Name)
 		return croType.StatusMessage(errMsg), errorUtil.Wrapf(err, errMsg)
 	}
+	// the credentials request is not deleted in dry run mode, so the finalizer is kept
+	if resources.IsDryRun() {
+		return "dry run, credential request would be deleted", nil
+	}
 
 	// remove the finalizer added by the provider
 	p.Logger.Infof("deleting finalizer %s from smtp credentials %s in namespace %s", DefaultFinalizer, smtpCreds.Name, smtpCreds.Namespace)
@@ -182,6 +191,33 @@ func (p *SMTPCredentialProvider) DeleteSMTPCredentials(ctx context.Context, smtp
 	return "deletion complete", nil
 }
 
+// reconcileSESCredentialsRotation rotates the access key of the iam user of the ses credentials, and removes the
+// annotation requesting the rotation once it is complete
+func (p *SMTPCredentialProvider) reconcileSESCredentialsRotation(ctx context.Context, smtpCreds *v1alpha1.SMTPCredentialSet, stratCfg *StrategyConfig, sendMailCreds *Credentials, credSecName string) (*Credentials, error) {
+	providerCreds, err := p.CredentialManager.ReconcileProviderCredentials(ctx, smtpCreds.Namespace)
+	if err != nil {
+		return nil, errorUtil.Wrap(err, "failed to reconcile aws provider credentials")
+	}
+	sess, err := CreateSessionFromStrategy(ctx, p.Client, providerCreds.AccessKeyID, providerCreds.SecretAccessKey, stratCfg)
+	if err != nil {
+		return nil, errorUtil.Wrap(err, "failed to create aws session")
+	}
+	if err := stampRotationRequest(ctx, p.Client, smtpCreds); err != nil {
+		return nil, err
+	}
+	rotatedCreds, err := reconcileAccessKeyRotation(ctx, p.Client, iam.New(sess), smtpCreds, smtpCreds.Spec.RotationInterval, stratCfg.Credentials.getRotationGracePeriod(), sendMailCreds, credSecName, smtpCreds.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if annotations.Has(smtpCreds, resources.RotateCredentialsAnnotation) && !resources.IsDryRun() {
+		annotations.Remove(smtpCreds, resources.RotateCredentialsAnnotation)
+		if err := p.Client.Update(ctx, smtpCreds); err != nil {
+			return nil, errorUtil.Wrap(err, "failed to remove rotate credentials annotation")
+		}
+	}
+	return rotatedCreds, nil
+}
+
 // https://docs.aws.amazon.com/ses/latest/DeveloperGuide/example-create-smtp-credentials.html
 func getSMTPPasswordFromAWSSecret(secAccessKey string) (string, error) {
 	sig, err := makeHmac([]byte(secAccessKey), []byte("SendRawEmail"))
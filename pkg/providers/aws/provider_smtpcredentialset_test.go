@@ -317,6 +317,9 @@ func TestSMTPCredentialProvider_CreateSMTPCredentials(t *testing.T) {
 					ReconcileSESCredentialsFunc: func(ctx context.Context, name string, ns string) (credentials *Credentials, e error) {
 						return buildTestAWSCredentials(), nil
 					},
+					ReconcileProviderCredentialsFunc: func(ctx context.Context, ns string) (credentials *Credentials, e error) {
+						return buildTestAWSCredentials(), nil
+					},
 				},
 				ConfigManager: &ConfigManagerMock{
 					GetDefaultRegionSMTPServerMappingFunc: func() map[string]string {
@@ -360,6 +363,9 @@ func TestSMTPCredentialProvider_CreateSMTPCredentials(t *testing.T) {
 					ReconcileSESCredentialsFunc: func(ctx context.Context, name string, ns string) (credentials *Credentials, e error) {
 						return buildTestAWSCredentials(), nil
 					},
+					ReconcileProviderCredentialsFunc: func(ctx context.Context, ns string) (credentials *Credentials, e error) {
+						return buildTestAWSCredentials(), nil
+					},
 				},
 				ConfigManager: &ConfigManagerMock{
 					GetDefaultRegionSMTPServerMappingFunc: func() map[string]string {
@@ -0,0 +1,200 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/elasticache/elasticacheiface"
+	"github.com/integr8ly/cloud-resource-operator/pkg/annotations"
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	errorUtil "github.com/pkg/errors"
+)
+
+// RedisActionAnnotation can be set on a redis cr to run an on-demand action against its replication group, in the
+// format <action> or <action>=<value>. it is removed once the action has been started, the progress and result of
+// the action are recorded in the action status of the cr
+const RedisActionAnnotation = "redisAction"
+
+// RedisNodeTypeAnnotation records the node type a scaleNodeType action changed the replication group to. it takes
+// precedence over the node type of the tier, remove it to return to the node type of the tier
+const RedisNodeTypeAnnotation = "redisNodeType"
+
+const (
+	// redisActionScaleReplicas scales the number of replicas of each node group to the value
+	redisActionScaleReplicas = "scaleReplicas"
+	// redisActionScaleNodeType changes the node type of the replication group to the value online
+	redisActionScaleNodeType = "scaleNodeType"
+	// redisActionTestFailover fails over the primary of the node group with the value as id, or of the first node group
+	redisActionTestFailover = "testFailover"
+)
+
+// redisAction is an on-demand action requested on a redis cr
+type redisAction struct {
+	name  string
+	value string
+}
+
+// parseRedisAction parses the value of the redis action annotation
+func parseRedisAction(annotation string) (*redisAction, error) {
+	parts := strings.SplitN(strings.TrimSpace(annotation), "=", 2)
+	action := &redisAction{name: parts[0]}
+	if len(parts) == 2 {
+		action.value = strings.TrimSpace(parts[1])
+	}
+	switch action.name {
+	case redisActionScaleReplicas:
+		replicas, err := strconv.Atoi(action.value)
+		if err != nil || replicas < 0 {
+			return nil, fmt.Errorf("%s requires a number of replicas, got %q", action.name, action.value)
+		}
+	case redisActionScaleNodeType:
+		if action.value == "" {
+			return nil, fmt.Errorf("%s requires a node type", action.name)
+		}
+	case redisActionTestFailover:
+	default:
+		return nil, fmt.Errorf("unknown redis action %q, expected one of %s, %s or %s", action.name, redisActionScaleReplicas, redisActionScaleNodeType, redisActionTestFailover)
+	}
+	return action, nil
+}
+
+// reconcileElasticacheAction starts the action requested by the redis action annotation on an available replication
+// group, and completes an action in progress once the replication group and its node groups are available again and
+// no modifications are pending. true
+// is returned while an action is in progress, the rest of the reconcile is skipped until it has completed
+func (p *RedisProvider) reconcileElasticacheAction(ctx context.Context, r *v1alpha1.Redis, cacheSvc elasticacheiface.ElastiCacheAPI, foundCache *elasticache.ReplicationGroup) (bool, croType.StatusMessage, error) {
+	if action := r.Status.Action; action != nil && action.Phase == croType.PhaseInProgress {
+		// modifications applied immediately are reported by the status of the replication group, or as pending until
+		// they are started
+		if aws.StringValue(foundCache.Status) != "available" {
+			return true, croType.StatusMessage(fmt.Sprintf("%s in progress, replication group status is %s", action.Action, aws.StringValue(foundCache.Status))), nil
+		}
+		if pending := foundCache.PendingModifiedValues; pending != nil && *pending != (elasticache.ReplicationGroupPendingModifiedValues{}) {
+			return true, croType.StatusMessage(fmt.Sprintf("%s in progress, replication group has pending modifications", action.Action)), nil
+		}
+		for _, ng := range foundCache.NodeGroups {
+			if aws.StringValue(ng.Status) != "available" {
+				return true, croType.StatusMessage(fmt.Sprintf("%s in progress, node group %s status is %s", action.Action, aws.StringValue(ng.NodeGroupId), aws.StringValue(ng.Status))), nil
+			}
+		}
+		action.Phase = croType.PhaseComplete
+		action.Message = croType.StatusMessage(fmt.Sprintf("%s completed", action.Action))
+		action.CompletedAt = time.Now().UTC().Format(time.RFC3339)
+		p.Logger.Infof("%s of elasticache replication group %s completed", action.Action, *foundCache.ReplicationGroupId)
+	}
+
+	annotation, ok := r.GetAnnotations()[RedisActionAnnotation]
+	if !ok {
+		return false, croType.StatusEmpty, nil
+	}
+
+	// the annotation is removed before the action is started, so an action is only ever run once per request
+	annotations.Remove(r, RedisActionAnnotation)
+	if err := p.Client.Update(ctx, r); err != nil {
+		errMsg := fmt.Sprintf("failed to remove %s annotation from redis instance %s", RedisActionAnnotation, r.Name)
+		return false, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
+	action, err := parseRedisAction(annotation)
+	if err != nil {
+		errMsg := fmt.Sprintf("invalid %s annotation", RedisActionAnnotation)
+		r.Status.Action = &croType.ResourceActionStatus{
+			Action:  annotation,
+			Phase:   croType.PhaseFailed,
+			Message: croType.StatusMessage(errMsg).WrapError(err),
+		}
+		return false, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	status := &croType.ResourceActionStatus{
+		Action:    action.name,
+		Value:     action.value,
+		StartedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	msg, err := startElasticacheAction(cacheSvc, foundCache, action)
+	if err != nil {
+		status.Phase = croType.PhaseFailed
+		status.Message = msg.WrapError(err)
+		status.CompletedAt = status.StartedAt
+		r.Status.Action = status
+		return false, msg, errorUtil.Wrap(err, string(msg))
+	}
+	p.Logger.Infof("started %s of elasticache replication group %s", action.name, *foundCache.ReplicationGroupId)
+
+	// the node type is kept on the cr, otherwise the node type of the tier is reported as drift and restored
+	if action.name == redisActionScaleNodeType {
+		annotations.Add(r, RedisNodeTypeAnnotation, action.value)
+		if err := p.Client.Update(ctx, r); err != nil {
+			errMsg := fmt.Sprintf("failed to add %s annotation to redis instance %s", RedisNodeTypeAnnotation, r.Name)
+			return false, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+	}
+	status.Phase = croType.PhaseInProgress
+	status.Message = msg
+	r.Status.Action = status
+	return true, msg, nil
+}
+
+// startElasticacheAction starts an action against a replication group, the replication group is modified immediately
+func startElasticacheAction(cacheSvc elasticacheiface.ElastiCacheAPI, foundCache *elasticache.ReplicationGroup, action *redisAction) (croType.StatusMessage, error) {
+	switch action.name {
+	case redisActionScaleReplicas:
+		replicas, err := strconv.ParseInt(action.value, 10, 64)
+		if err != nil {
+			return "failed to parse number of replicas", err
+		}
+		if len(foundCache.NodeGroups) == 0 {
+			return "failed to scale replicas", fmt.Errorf("replication group %s has no node groups", *foundCache.ReplicationGroupId)
+		}
+		// every node group has a primary, the remaining members are replicas
+		current := int64(len(foundCache.NodeGroups[0].NodeGroupMembers) - 1)
+		switch {
+		case replicas > current:
+			if _, err := cacheSvc.IncreaseReplicaCount(&elasticache.IncreaseReplicaCountInput{
+				ReplicationGroupId: foundCache.ReplicationGroupId,
+				NewReplicaCount:    aws.Int64(replicas),
+				ApplyImmediately:   aws.Bool(true),
+			}); err != nil {
+				return "failed to increase replica count", err
+			}
+		case replicas < current:
+			if _, err := cacheSvc.DecreaseReplicaCount(&elasticache.DecreaseReplicaCountInput{
+				ReplicationGroupId: foundCache.ReplicationGroupId,
+				NewReplicaCount:    aws.Int64(replicas),
+				ApplyImmediately:   aws.Bool(true),
+			}); err != nil {
+				return "failed to decrease replica count", err
+			}
+		default:
+			return croType.StatusMessage(fmt.Sprintf("replication group already has %d replicas", replicas)), nil
+		}
+		return croType.StatusMessage(fmt.Sprintf("scaling replicas from %d to %d", current, replicas)), nil
+	case redisActionScaleNodeType:
+		if _, err := cacheSvc.ModifyReplicationGroup(&elasticache.ModifyReplicationGroupInput{
+			ReplicationGroupId: foundCache.ReplicationGroupId,
+			CacheNodeType:      aws.String(action.value),
+			ApplyImmediately:   aws.Bool(true),
+		}); err != nil {
+			return "failed to change node type", err
+		}
+		return croType.StatusMessage(fmt.Sprintf("changing node type from %s to %s", aws.StringValue(foundCache.CacheNodeType), action.value)), nil
+	case redisActionTestFailover:
+		nodeGroupID := action.value
+		if nodeGroupID == "" && len(foundCache.NodeGroups) > 0 {
+			nodeGroupID = aws.StringValue(foundCache.NodeGroups[0].NodeGroupId)
+		}
+		if _, err := cacheSvc.TestFailover(&elasticache.TestFailoverInput{
+			ReplicationGroupId: foundCache.ReplicationGroupId,
+			NodeGroupId:        aws.String(nodeGroupID),
+		}); err != nil {
+			return "failed to test failover", err
+		}
+		return croType.StatusMessage(fmt.Sprintf("failing over node group %s", nodeGroupID)), nil
+	}
+	return "failed to start redis action", fmt.Errorf("unknown redis action %q", action.name)
+}
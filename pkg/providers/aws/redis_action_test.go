@@ -0,0 +1,259 @@
+package aws
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func buildTestRedisActionCR(action string) *v1alpha1.Redis {
+	r := buildTestRedisCR()
+	r.Annotations = map[string]string{RedisActionAnnotation: action}
+	return r
+}
+
+func buildReplicationGroupWithReplicas(replicas int) *elasticache.ReplicationGroup {
+	rg := buildReplicationGroupReady()[0]
+	rg.NodeGroups[0].NodeGroupMembers = []*elasticache.NodeGroupMember{{CacheClusterId: aws.String("test-001")}}
+	for i := 0; i < replicas; i++ {
+		rg.NodeGroups[0].NodeGroupMembers = append(rg.NodeGroups[0].NodeGroupMembers, &elasticache.NodeGroupMember{CacheClusterId: aws.String("test-replica")})
+	}
+	return rg
+}
+
+func Test_parseRedisAction(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+		want       *redisAction
+		wantErr    bool
+	}{
+		{
+			name:       "test scale replicas is parsed",
+			annotation: "scaleReplicas=2",
+			want:       &redisAction{name: redisActionScaleReplicas, value: "2"},
+		},
+		{
+			name:       "test scale node type is parsed",
+			annotation: "scaleNodeType=cache.m5.large",
+			want:       &redisAction{name: redisActionScaleNodeType, value: "cache.m5.large"},
+		},
+		{
+			name:       "test failover without a node group is parsed",
+			annotation: "testFailover",
+			want:       &redisAction{name: redisActionTestFailover},
+		},
+		{
+			name:       "test scale replicas requires a number",
+			annotation: "scaleReplicas=many",
+			wantErr:    true,
+		},
+		{
+			name:       "test scale node type requires a node type",
+			annotation: "scaleNodeType",
+			wantErr:    true,
+		},
+		{
+			name:       "test unknown action",
+			annotation: "reboot",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRedisAction(tt.annotation)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseRedisAction() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseRedisAction() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedisProvider_reconcileElasticacheAction(t *testing.T) {
+	scheme, err := buildTestSchemeRedis()
+	if err != nil {
+		t.Fatal("failed to build scheme", err)
+	}
+	modifyingCache := buildReplicationGroupWithReplicas(1)
+	modifyingCache.NodeGroups[0].Status = aws.String("modifying")
+	// node types are changed while the node groups are still available
+	modifyingGroup := buildReplicationGroupWithReplicas(1)
+	modifyingGroup.Status = aws.String("modifying")
+	pendingCache := buildReplicationGroupWithReplicas(1)
+	pendingCache.PendingModifiedValues = &elasticache.ReplicationGroupPendingModifiedValues{PrimaryClusterId: aws.String("test-002")}
+	inProgress := func() *v1alpha1.Redis {
+		r := buildTestRedisCR()
+		r.Status.Action = &croType.ResourceActionStatus{Action: redisActionTestFailover, Phase: croType.PhaseInProgress}
+		return r
+	}
+	tests := []struct {
+		name               string
+		r                  *v1alpha1.Redis
+		foundCache         *elasticache.ReplicationGroup
+		wantInProgress     bool
+		wantPhase          croType.StatusPhase
+		wantStartedActions []string
+		wantErr            bool
+	}{
+		{
+			name:       "test nothing is done without an action",
+			r:          buildTestRedisCR(),
+			foundCache: buildReplicationGroupWithReplicas(1),
+		},
+		{
+			name:               "test replicas are increased",
+			r:                  buildTestRedisActionCR("scaleReplicas=2"),
+			foundCache:         buildReplicationGroupWithReplicas(1),
+			wantInProgress:     true,
+			wantPhase:          croType.PhaseInProgress,
+			wantStartedActions: []string{"IncreaseReplicaCount"},
+		},
+		{
+			name:               "test replicas are decreased",
+			r:                  buildTestRedisActionCR("scaleReplicas=0"),
+			foundCache:         buildReplicationGroupWithReplicas(1),
+			wantInProgress:     true,
+			wantPhase:          croType.PhaseInProgress,
+			wantStartedActions: []string{"DecreaseReplicaCount"},
+		},
+		{
+			name:               "test failover is started",
+			r:                  buildTestRedisActionCR("testFailover"),
+			foundCache:         buildReplicationGroupWithReplicas(1),
+			wantInProgress:     true,
+			wantPhase:          croType.PhaseInProgress,
+			wantStartedActions: []string{"TestFailover"},
+		},
+		{
+			name:       "test invalid action fails",
+			r:          buildTestRedisActionCR("reboot"),
+			foundCache: buildReplicationGroupWithReplicas(1),
+			wantPhase:  croType.PhaseFailed,
+			wantErr:    true,
+		},
+		{
+			name:           "test action stays in progress while a node group is not available",
+			r:              inProgress(),
+			foundCache:     modifyingCache,
+			wantInProgress: true,
+			wantPhase:      croType.PhaseInProgress,
+		},
+		{
+			name:           "test action stays in progress while the replication group is not available",
+			r:              inProgress(),
+			foundCache:     modifyingGroup,
+			wantInProgress: true,
+			wantPhase:      croType.PhaseInProgress,
+		},
+		{
+			name:           "test action stays in progress while modifications are pending",
+			r:              inProgress(),
+			foundCache:     pendingCache,
+			wantInProgress: true,
+			wantPhase:      croType.PhaseInProgress,
+		},
+		{
+			name:       "test action completes once node groups are available",
+			r:          inProgress(),
+			foundCache: buildReplicationGroupWithReplicas(1),
+			wantPhase:  croType.PhaseComplete,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cacheSvc := &mockElasticacheClient{}
+			p := &RedisProvider{
+				Client: fake.NewFakeClientWithScheme(scheme, tt.r.DeepCopy()),
+				Logger: testLogger,
+			}
+			gotInProgress, _, err := p.reconcileElasticacheAction(context.TODO(), tt.r, cacheSvc, tt.foundCache)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("reconcileElasticacheAction() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotInProgress != tt.wantInProgress {
+				t.Errorf("reconcileElasticacheAction() inProgress = %v, want %v", gotInProgress, tt.wantInProgress)
+			}
+			if !reflect.DeepEqual(cacheSvc.startedActions, tt.wantStartedActions) {
+				t.Errorf("reconcileElasticacheAction() started actions = %v, want %v", cacheSvc.startedActions, tt.wantStartedActions)
+			}
+			if tt.wantPhase == "" {
+				if tt.r.Status.Action != nil {
+					t.Errorf("reconcileElasticacheAction() action status = %v, want nil", tt.r.Status.Action)
+				}
+				return
+			}
+			if tt.r.Status.Action == nil || tt.r.Status.Action.Phase != tt.wantPhase {
+				t.Errorf("reconcileElasticacheAction() action status = %v, want phase %v", tt.r.Status.Action, tt.wantPhase)
+			}
+			if _, ok := tt.r.Annotations[RedisActionAnnotation]; ok {
+				t.Errorf("reconcileElasticacheAction() %s annotation was not removed", RedisActionAnnotation)
+			}
+		})
+	}
+}
+
+func TestRedisProvider_createElasticacheClusterAfterScaleNodeType(t *testing.T) {
+	scheme, err := buildTestSchemeRedis()
+	if err != nil {
+		t.Fatal("failed to build scheme", err)
+	}
+	secName, err := BuildInfraName(context.TODO(), fake.NewFakeClientWithScheme(scheme, buildTestInfra()), defaultSecurityGroupPostfix, DefaultAwsIdentifierLength)
+	if err != nil {
+		t.Fatal("failed to build security name", err)
+	}
+	buildTierConfig := func() *elasticache.CreateReplicationGroupInput {
+		return &elasticache.CreateReplicationGroupInput{
+			ReplicationGroupId:     aws.String("test-id"),
+			CacheNodeType:          aws.String("test"),
+			SnapshotRetentionLimit: aws.Int64(20),
+		}
+	}
+	r := buildTestRedisActionCR("scaleNodeType=cache.m5.large")
+	cacheSvc := &mockElasticacheClient{replicationGroups: buildReplicationGroupReady()}
+	ec2Svc := &mockEc2Client{vpcs: buildVpcs(), subnets: buildSubnets(), secGroups: buildSecurityGroups(secName)}
+	p := &RedisProvider{
+		Client:    fake.NewFakeClientWithScheme(scheme, r.DeepCopy(), builtTestCredSecret(), buildTestInfra(), buildTestPrometheusRule()),
+		Logger:    testLogger,
+		TCPPinger: buildMockConnectionTester(),
+	}
+
+	// start the action
+	if _, _, err := p.createElasticacheCluster(context.TODO(), r, cacheSvc, &mockStsClient{}, ec2Svc, buildTierConfig(), &StrategyConfig{Region: "test"}); err != nil {
+		t.Fatalf("createElasticacheCluster() error = %v", err)
+	}
+	if got := r.Annotations[RedisNodeTypeAnnotation]; got != "cache.m5.large" {
+		t.Fatalf("createElasticacheCluster() %s annotation = %q, want %q", RedisNodeTypeAnnotation, got, "cache.m5.large")
+	}
+
+	// reconcile once the node type has been changed
+	cacheSvc.replicationGroups[0].CacheNodeType = aws.String("cache.m5.large")
+	cacheSvc.startedActions = nil
+	if _, _, err := p.createElasticacheCluster(context.TODO(), r, cacheSvc, &mockStsClient{}, ec2Svc, buildTierConfig(), &StrategyConfig{Region: "test"}); err != nil {
+		t.Fatalf("createElasticacheCluster() error = %v", err)
+	}
+	if r.Status.Action == nil || r.Status.Action.Phase != croType.PhaseComplete {
+		t.Errorf("createElasticacheCluster() action status = %v, want phase %v", r.Status.Action, croType.PhaseComplete)
+	}
+	if cacheSvc.startedActions != nil {
+		t.Errorf("createElasticacheCluster() started actions = %v, want none", cacheSvc.startedActions)
+	}
+	if r.Status.PendingModifications != nil {
+		t.Errorf("createElasticacheCluster() pending modifications = %v, want none", r.Status.PendingModifications)
+	}
+	for _, drift := range r.Status.Drift {
+		if drift.Field == "CacheNodeType" {
+			t.Errorf("createElasticacheCluster() drift = %v, want no drift of CacheNodeType", drift)
+		}
+	}
+}
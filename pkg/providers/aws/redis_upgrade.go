@@ -0,0 +1,156 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/elasticache/elasticacheiface"
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+	errorUtil "github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// reconcileElasticacheUpgrade upgrades the engine version of a replication group to the engine version of the redis cr.
+// the target version is validated, a redis snapshot is taken and the upgrade is applied, the progress is recorded in the
+// upgrade status of the cr. true is returned while the upgrade is in progress, the rest of the reconcile is skipped
+// until it has completed. the engine version of a replication group is only described by its cache clusters
+func (p *RedisProvider) reconcileElasticacheUpgrade(ctx context.Context, r *v1alpha1.Redis, cacheSvc elasticacheiface.ElastiCacheAPI, foundCache *elasticache.ReplicationGroup, found string) (bool, croType.StatusMessage, error) {
+	target := r.Spec.EngineVersion
+	upgrade := r.Status.Upgrade
+	if target == "" || found == "" {
+		return false, croType.StatusEmpty, nil
+	}
+	// a failed upgrade is validated and started again on the next reconcile
+	if upgrade == nil || upgrade.ToVersion != target || upgrade.Phase == croType.PhaseFailed {
+		if target == found {
+			return false, croType.StatusEmpty, nil
+		}
+		upgrade = &croType.ResourceUpgradeStatus{
+			FromVersion: found,
+			ToVersion:   target,
+		}
+		r.Status.Upgrade = upgrade
+		if err := validateElasticacheUpgrade(cacheSvc, found, target); err != nil {
+			errMsg := fmt.Sprintf("failed to validate upgrade of elasticache replication group %s to %s", *foundCache.ReplicationGroupId, target)
+			upgrade.Phase = croType.PhaseFailed
+			upgrade.Message = croType.StatusMessage(errMsg).WrapError(err)
+			return false, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+		upgrade.Phase = croType.PhaseInProgress
+	}
+	if upgrade.Phase == croType.PhaseComplete {
+		return false, croType.StatusEmpty, nil
+	}
+
+	// take a snapshot of the replication group before it is upgraded
+	if upgrade.Snapshot == "" {
+		if resources.IsDryRun() {
+			return false, croType.StatusMessage(fmt.Sprintf("dry run, a snapshot would be taken before upgrading to %s", target)), nil
+		}
+		snapshotName, err := p.reconcileUpgradeSnapshot(ctx, r, target)
+		if err != nil {
+			errMsg := fmt.Sprintf("failed to create snapshot before upgrading elasticache replication group %s", *foundCache.ReplicationGroupId)
+			return false, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+		upgrade.Snapshot = snapshotName
+		upgrade.Message = croType.StatusMessage(fmt.Sprintf("taking snapshot %s before upgrading to %s", snapshotName, target))
+		return true, upgrade.Message, nil
+	}
+	snapshot := &v1alpha1.RedisSnapshot{}
+	if err := p.Client.Get(ctx, types.NamespacedName{Name: upgrade.Snapshot, Namespace: r.Namespace}, snapshot); err != nil {
+		errMsg := fmt.Sprintf("failed to get redis snapshot %s taken before the upgrade", upgrade.Snapshot)
+		return false, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	switch snapshot.Status.Phase {
+	case croType.PhaseComplete:
+	case croType.PhaseFailed:
+		errMsg := fmt.Sprintf("redis snapshot %s taken before the upgrade failed", upgrade.Snapshot)
+		upgrade.Phase = croType.PhaseFailed
+		upgrade.Message = croType.StatusMessage(errMsg)
+		return false, upgrade.Message, fmt.Errorf("%s: %s", errMsg, snapshot.Status.Message)
+	default:
+		upgrade.Message = croType.StatusMessage(fmt.Sprintf("waiting for snapshot %s before upgrading to %s", upgrade.Snapshot, target))
+		return true, upgrade.Message, nil
+	}
+
+	// apply the upgrade once the snapshot is available
+	if upgrade.StartedAt == "" {
+		modifyInput := &elasticache.ModifyReplicationGroupInput{
+			ReplicationGroupId: foundCache.ReplicationGroupId,
+			EngineVersion:      aws.String(target),
+			ApplyImmediately:   aws.Bool(true),
+		}
+		// the default parameter group of a new engine family does not enable cluster mode
+		if aws.BoolValue(foundCache.ClusterEnabled) && buildElasticacheClusterParameterGroupName(found) != buildElasticacheClusterParameterGroupName(target) {
+			modifyInput.CacheParameterGroupName = aws.String(buildElasticacheClusterParameterGroupName(target))
+		}
+		if _, err := cacheSvc.ModifyReplicationGroup(modifyInput); err != nil {
+			errMsg := fmt.Sprintf("failed to upgrade elasticache replication group %s to %s", *foundCache.ReplicationGroupId, target)
+			upgrade.Phase = croType.PhaseFailed
+			upgrade.Message = croType.StatusMessage(errMsg).WrapError(err)
+			return false, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+		}
+		p.Logger.Infof("upgrading elasticache replication group %s from %s to %s", *foundCache.ReplicationGroupId, upgrade.FromVersion, target)
+		upgrade.StartedAt = time.Now().UTC().Format(time.RFC3339)
+		upgrade.Message = croType.StatusMessage(fmt.Sprintf("upgrading from %s to %s", upgrade.FromVersion, target))
+		upgrade.RollbackGuidance = fmt.Sprintf("elasticache does not support downgrading the engine version, to roll back to %s restore snapshot %s into a new replication group with engine version %s and adopt it", upgrade.FromVersion, snapshot.Status.SnapshotID, upgrade.FromVersion)
+		return true, upgrade.Message, nil
+	}
+	if found != target {
+		return true, upgrade.Message, nil
+	}
+	p.Logger.Infof("upgraded elasticache replication group %s to %s", *foundCache.ReplicationGroupId, target)
+	upgrade.Phase = croType.PhaseComplete
+	upgrade.Message = croType.StatusMessage(fmt.Sprintf("upgraded from %s to %s", upgrade.FromVersion, target))
+	upgrade.CompletedAt = time.Now().UTC().Format(time.RFC3339)
+	return false, croType.StatusEmpty, nil
+}
+
+// validateElasticacheUpgrade verifies an engine version is newer than the current engine version and is available
+func validateElasticacheUpgrade(cacheSvc elasticacheiface.ElastiCacheAPI, found, target string) error {
+	cmp, err := resources.CompareVersions(target, found)
+	if err != nil {
+		return err
+	}
+	if cmp < 0 {
+		return fmt.Errorf("engine version %s is older than the current engine version %s, downgrades are not supported", target, found)
+	}
+	output, err := cacheSvc.DescribeCacheEngineVersions(&elasticache.DescribeCacheEngineVersionsInput{
+		Engine:        aws.String("redis"),
+		EngineVersion: aws.String(target),
+	})
+	if err != nil {
+		return errorUtil.Wrap(err, "failed to describe elasticache engine versions")
+	}
+	if len(output.CacheEngineVersions) == 0 {
+		return fmt.Errorf("engine version %s is not available", target)
+	}
+	return nil
+}
+
+// reconcileUpgradeSnapshot creates the redis snapshot taken before a redis cr is upgraded to an engine version, the
+// name of the snapshot cr is returned
+func (p *RedisProvider) reconcileUpgradeSnapshot(ctx context.Context, r *v1alpha1.Redis, version string) (string, error) {
+	snapshot := &v1alpha1.RedisSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-upgrade-%s", r.Name, strings.Replace(version, ".", "-", -1)),
+			Namespace: r.Namespace,
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, p.Client, snapshot, func() error {
+		snapshot.Labels = r.Labels
+		snapshot.Spec.ResourceName = r.Name
+		return nil
+	}); err != nil {
+		return "", errorUtil.Wrapf(err, "failed to create or update redis snapshot %s", snapshot.Name)
+	}
+	return snapshot.Name, nil
+}
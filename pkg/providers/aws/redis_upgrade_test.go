@@ -0,0 +1,208 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func buildTestUpgradingRedisCR(upgrade *croType.ResourceUpgradeStatus) *v1alpha1.Redis {
+	r := buildTestRedisCR()
+	r.Spec.EngineVersion = "5.0.6"
+	r.Status.Upgrade = upgrade
+	return r
+}
+
+func buildTestUpgradeSnapshot(phase croType.StatusPhase) *v1alpha1.RedisSnapshot {
+	return &v1alpha1.RedisSnapshot{
+		ObjectMeta: controllerruntime.ObjectMeta{
+			Name:      "test-upgrade-5-0-6",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.RedisSnapshotSpec{
+			ResourceName: "test",
+		},
+		Status: v1alpha1.RedisSnapshotStatus{
+			Phase:      phase,
+			SnapshotID: "test-snapshot",
+		},
+	}
+}
+
+func Test_validateElasticacheUpgrade(t *testing.T) {
+	available := []*elasticache.CacheEngineVersion{{EngineVersion: aws.String("5.0.6")}}
+	tests := []struct {
+		name     string
+		cacheSvc *mockElasticacheClient
+		found    string
+		target   string
+		wantErr  bool
+	}{
+		{
+			name:     "test upgrade to an available version is valid",
+			cacheSvc: &mockElasticacheClient{engineVersions: available},
+			found:    "3.2.6",
+			target:   "5.0.6",
+		},
+		{
+			name:     "test downgrade is not valid",
+			cacheSvc: &mockElasticacheClient{engineVersions: available},
+			found:    "5.0.6",
+			target:   "3.2.6",
+			wantErr:  true,
+		},
+		{
+			name:     "test upgrade to an unavailable version is not valid",
+			cacheSvc: &mockElasticacheClient{},
+			found:    "3.2.6",
+			target:   "5.0.7",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateElasticacheUpgrade(tt.cacheSvc, tt.found, tt.target); (err != nil) != tt.wantErr {
+				t.Errorf("validateElasticacheUpgrade() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRedisProvider_reconcileElasticacheUpgrade(t *testing.T) {
+	scheme, err := buildTestSchemeRedis()
+	if err != nil {
+		t.Fatal("failed to build scheme", err)
+	}
+	available := []*elasticache.CacheEngineVersion{{EngineVersion: aws.String("5.0.6")}}
+	tests := []struct {
+		name           string
+		r              *v1alpha1.Redis
+		snapshot       *v1alpha1.RedisSnapshot
+		found          string
+		wantInProgress bool
+		wantErr        bool
+		want           *croType.ResourceUpgradeStatus
+	}{
+		{
+			name:  "test nothing is done without an engine version",
+			r:     buildTestRedisCR(),
+			found: "3.2.6",
+		},
+		{
+			name:  "test nothing is done when the engine version is as expected",
+			r:     buildTestUpgradingRedisCR(nil),
+			found: "5.0.6",
+		},
+		{
+			name:           "test snapshot is taken before the upgrade",
+			r:              buildTestUpgradingRedisCR(nil),
+			found:          "3.2.6",
+			wantInProgress: true,
+			want: &croType.ResourceUpgradeStatus{
+				FromVersion: "3.2.6",
+				ToVersion:   "5.0.6",
+				Phase:       croType.PhaseInProgress,
+				Snapshot:    "test-upgrade-5-0-6",
+			},
+		},
+		{
+			name:           "test upgrade waits for the snapshot",
+			r:              buildTestUpgradingRedisCR(&croType.ResourceUpgradeStatus{FromVersion: "3.2.6", ToVersion: "5.0.6", Phase: croType.PhaseInProgress, Snapshot: "test-upgrade-5-0-6"}),
+			snapshot:       buildTestUpgradeSnapshot(croType.PhaseInProgress),
+			found:          "3.2.6",
+			wantInProgress: true,
+			want: &croType.ResourceUpgradeStatus{
+				FromVersion: "3.2.6",
+				ToVersion:   "5.0.6",
+				Phase:       croType.PhaseInProgress,
+				Snapshot:    "test-upgrade-5-0-6",
+			},
+		},
+		{
+			name:     "test upgrade fails when the snapshot fails",
+			r:        buildTestUpgradingRedisCR(&croType.ResourceUpgradeStatus{FromVersion: "3.2.6", ToVersion: "5.0.6", Phase: croType.PhaseInProgress, Snapshot: "test-upgrade-5-0-6"}),
+			snapshot: buildTestUpgradeSnapshot(croType.PhaseFailed),
+			found:    "3.2.6",
+			wantErr:  true,
+			want: &croType.ResourceUpgradeStatus{
+				FromVersion: "3.2.6",
+				ToVersion:   "5.0.6",
+				Phase:       croType.PhaseFailed,
+				Snapshot:    "test-upgrade-5-0-6",
+			},
+		},
+		{
+			name:           "test upgrade is applied once the snapshot is available",
+			r:              buildTestUpgradingRedisCR(&croType.ResourceUpgradeStatus{FromVersion: "3.2.6", ToVersion: "5.0.6", Phase: croType.PhaseInProgress, Snapshot: "test-upgrade-5-0-6"}),
+			snapshot:       buildTestUpgradeSnapshot(croType.PhaseComplete),
+			found:          "3.2.6",
+			wantInProgress: true,
+			want: &croType.ResourceUpgradeStatus{
+				FromVersion: "3.2.6",
+				ToVersion:   "5.0.6",
+				Phase:       croType.PhaseInProgress,
+				Snapshot:    "test-upgrade-5-0-6",
+			},
+		},
+		{
+			name:     "test upgrade completes once the engine version is as expected",
+			r:        buildTestUpgradingRedisCR(&croType.ResourceUpgradeStatus{FromVersion: "3.2.6", ToVersion: "5.0.6", Phase: croType.PhaseInProgress, Snapshot: "test-upgrade-5-0-6", StartedAt: "2020-01-01T00:00:00Z"}),
+			snapshot: buildTestUpgradeSnapshot(croType.PhaseComplete),
+			found:    "5.0.6",
+			want: &croType.ResourceUpgradeStatus{
+				FromVersion: "3.2.6",
+				ToVersion:   "5.0.6",
+				Phase:       croType.PhaseComplete,
+				Snapshot:    "test-upgrade-5-0-6",
+				StartedAt:   "2020-01-01T00:00:00Z",
+			},
+		},
+		{
+			name:    "test downgrade fails",
+			r:       buildTestUpgradingRedisCR(nil),
+			found:   "6.0.5",
+			wantErr: true,
+			want: &croType.ResourceUpgradeStatus{
+				FromVersion: "6.0.5",
+				ToVersion:   "5.0.6",
+				Phase:       croType.PhaseFailed,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewFakeClientWithScheme(scheme, tt.r.DeepCopy())
+			if tt.snapshot != nil {
+				client = fake.NewFakeClientWithScheme(scheme, tt.r.DeepCopy(), tt.snapshot)
+			}
+			p := &RedisProvider{
+				Client: client,
+				Logger: testLogger,
+			}
+			gotInProgress, _, err := p.reconcileElasticacheUpgrade(context.TODO(), tt.r, &mockElasticacheClient{engineVersions: available}, buildReplicationGroupReady()[0], tt.found)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("reconcileElasticacheUpgrade() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotInProgress != tt.wantInProgress {
+				t.Errorf("reconcileElasticacheUpgrade() inProgress = %v, want %v", gotInProgress, tt.wantInProgress)
+			}
+			got := tt.r.Status.Upgrade
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("reconcileElasticacheUpgrade() upgrade status = %v, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.FromVersion != tt.want.FromVersion || got.ToVersion != tt.want.ToVersion || got.Phase != tt.want.Phase || got.Snapshot != tt.want.Snapshot {
+				t.Errorf("reconcileElasticacheUpgrade() upgrade status = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package openshift
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/cloud-resource-operator/pkg/providers"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+
+	errorUtil "github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/exec"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	postgresSQLRunnerName = "openshift-postgres-exec"
+	// exit code of the exec command when a queried database does not exist
+	databaseNotFoundExitCode = 3
+)
+
+var _ providers.PostgresSQLRunner = (*PostgresSQLRunner)(nil)
+
+// PostgresSQLRunner runs psql inside the postgres deployment pod
+type PostgresSQLRunner struct {
+	Client       client.Client
+	Logger       *logrus.Entry
+	PodCommander resources.PodCommander
+}
+
+func NewOpenShiftPostgresSQLRunner(client client.Client, cs *kubernetes.Clientset, logger *logrus.Entry) *PostgresSQLRunner {
+	return &PostgresSQLRunner{
+		Client:       client,
+		Logger:       logger.WithFields(logrus.Fields{"provider": postgresSQLRunnerName}),
+		PodCommander: &resources.OpenShiftPodCommander{ClientSet: cs},
+	}
+}
+
+func (r *PostgresSQLRunner) GetName() string {
+	return postgresSQLRunnerName
+}
+
+func (r *PostgresSQLRunner) SupportsStrategy(d string) bool {
+	return d == providers.OpenShiftDeploymentStrategy
+}
+
+func (r *PostgresSQLRunner) DatabaseExists(ctx context.Context, ps *v1alpha1.Postgres, database string) (bool, error) {
+	dpl, err := r.getDeployment(ctx, ps)
+	if err != nil {
+		return false, err
+	}
+	// exit with a distinct code when the database is not found, so it is not mistaken for psql failing
+	query := fmt.Sprintf("SELECT 1 FROM pg_catalog.pg_database WHERE datname = %s", providers.QuotePostgresLiteral(database))
+	cmd := fmt.Sprintf("found=$(psql -v ON_ERROR_STOP=1 -tAc \"%s\") || exit 2; [ \"$found\" = \"1\" ] || exit %d", query, databaseNotFoundExitCode)
+	err = r.PodCommander.ExecIntoPod(dpl, cmd)
+	if err == nil {
+		return true, nil
+	}
+	if exitErr, ok := errorUtil.Cause(err).(exec.ExitError); ok && exitErr.ExitStatus() == databaseNotFoundExitCode {
+		return false, nil
+	}
+	return false, errorUtil.Wrapf(err, "failed to check if database %s exists", database)
+}
+
+func (r *PostgresSQLRunner) ExecStatements(ctx context.Context, ps *v1alpha1.Postgres, database string, stmts []string) error {
+	// statements change the roles and databases of the instance, they are not executed in dry run mode
+	if resources.IsDryRun() {
+		resources.RecordDryRunAction(ctx, "postgres:ExecStatements")
+		return nil
+	}
+	dpl, err := r.getDeployment(ctx, ps)
+	if err != nil {
+		return err
+	}
+	if err := r.PodCommander.ExecIntoPod(dpl, buildPsqlScriptCommand(database, stmts)); err != nil {
+		return errorUtil.Wrapf(err, "failed to execute statements in database %s", database)
+	}
+	return nil
+}
+
+func (r *PostgresSQLRunner) getDeployment(ctx context.Context, ps *v1alpha1.Postgres) (*appsv1.Deployment, error) {
+	dpl := &appsv1.Deployment{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: ps.Name, Namespace: ps.Namespace}, dpl); err != nil {
+		return nil, errorUtil.Wrapf(err, "failed to get postgres deployment %s", ps.Name)
+	}
+	return dpl, nil
+}
+
+// buildPsqlScriptCommand passes the statements to psql through a quoted heredoc, so the shell does not expand them.
+// psql runs each statement in its own transaction, allowing statements such as create database
+func buildPsqlScriptCommand(database string, stmts []string) string {
+	dbFlag := ""
+	if database != "" {
+		dbFlag = fmt.Sprintf(" -d %s", database)
+	}
+	return fmt.Sprintf("psql -v ON_ERROR_STOP=1%s <<'EOSQL'\n%s;\nEOSQL", dbFlag, strings.Join(stmts, ";\n"))
+}
@@ -0,0 +1,156 @@
+package openshift
+
+import (
+	"context"
+	"testing"
+
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+
+	errorUtil "github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/client-go/util/exec"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func buildTestPodCommanderWithError(err error) resources.PodCommander {
+	return &resources.PodCommanderMock{
+		ExecIntoPodFunc: func(dpl *appsv1.Deployment, cmd string) error {
+			return err
+		},
+	}
+}
+
+func TestPostgresSQLRunner_DatabaseExists(t *testing.T) {
+	scheme, err := buildTestScheme()
+	if err != nil {
+		t.Fatal("failed to build scheme", err)
+	}
+
+	type fields struct {
+		Client       client.Client
+		Logger       *logrus.Entry
+		PodCommander resources.PodCommander
+	}
+	type args struct {
+		ctx      context.Context
+		postgres *v1alpha1.Postgres
+		database string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "test database found",
+			fields: fields{
+				Client:       fake.NewFakeClientWithScheme(scheme, buildTestPostgresDeploymentReady()),
+				Logger:       testLogger,
+				PodCommander: buildTestPodCommander(),
+			},
+			args: args{
+				ctx:      context.TODO(),
+				postgres: buildTestPostgresCR(),
+				database: "test",
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "test database not found",
+			fields: fields{
+				Client:       fake.NewFakeClientWithScheme(scheme, buildTestPostgresDeploymentReady()),
+				Logger:       testLogger,
+				PodCommander: buildTestPodCommanderWithError(errorUtil.Wrap(exec.CodeExitError{Err: errorUtil.New("exit"), Code: databaseNotFoundExitCode}, "failed to exec")),
+			},
+			args: args{
+				ctx:      context.TODO(),
+				postgres: buildTestPostgresCR(),
+				database: "test",
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "test psql failure is returned",
+			fields: fields{
+				Client:       fake.NewFakeClientWithScheme(scheme, buildTestPostgresDeploymentReady()),
+				Logger:       testLogger,
+				PodCommander: buildTestPodCommanderWithError(errorUtil.Wrap(exec.CodeExitError{Err: errorUtil.New("exit"), Code: 2}, "failed to exec")),
+			},
+			args: args{
+				ctx:      context.TODO(),
+				postgres: buildTestPostgresCR(),
+				database: "test",
+			},
+			want:    false,
+			wantErr: true,
+		},
+		{
+			name: "test missing deployment",
+			fields: fields{
+				Client:       fake.NewFakeClientWithScheme(scheme),
+				Logger:       testLogger,
+				PodCommander: buildTestPodCommander(),
+			},
+			args: args{
+				ctx:      context.TODO(),
+				postgres: buildTestPostgresCR(),
+				database: "test",
+			},
+			want:    false,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &PostgresSQLRunner{
+				Client:       tt.fields.Client,
+				Logger:       tt.fields.Logger,
+				PodCommander: tt.fields.PodCommander,
+			}
+			got, err := r.DatabaseExists(tt.args.ctx, tt.args.postgres, tt.args.database)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DatabaseExists() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("DatabaseExists() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_buildPsqlScriptCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		database string
+		stmts    []string
+		want     string
+	}{
+		{
+			name:     "test default database",
+			database: "",
+			stmts:    []string{"DROP ROLE IF EXISTS \"test\""},
+			want:     "psql -v ON_ERROR_STOP=1 <<'EOSQL'\nDROP ROLE IF EXISTS \"test\";\nEOSQL",
+		},
+		{
+			name:     "test named database",
+			database: "test",
+			stmts:    []string{"GRANT USAGE ON SCHEMA public TO \"test\"", "SELECT 1"},
+			want:     "psql -v ON_ERROR_STOP=1 -d test <<'EOSQL'\nGRANT USAGE ON SCHEMA public TO \"test\";\nSELECT 1;\nEOSQL",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildPsqlScriptCommand(tt.database, tt.stmts); got != tt.want {
+				t.Errorf("buildPsqlScriptCommand() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package openshift
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
@@ -9,6 +10,7 @@ import (
 	"github.com/integr8ly/cloud-resource-operator/pkg/providers"
 	"github.com/integr8ly/cloud-resource-operator/pkg/providers/aws"
 	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+	errorUtil "github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -45,6 +47,12 @@ func (b BlobStorageProvider) GetReconcileTime(bs *v1alpha1.BlobStorage) time.Dur
 }
 
 func (b BlobStorageProvider) CreateStorage(ctx context.Context, bs *v1alpha1.BlobStorage) (*providers.BlobStorageInstance, croType.StatusMessage, error) {
+	// no bucket is created on openshift, there is nothing a deletion policy could retain
+	if err := resources.ValidateDeletionPolicy(bs.Spec.DeletionPolicy, resources.DeletionPolicyDelete); err != nil {
+		errMsg := fmt.Sprintf("invalid deletion policy of blob storage instance %s", bs.Name)
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
 	// default to an empty s3 set of credentials for now. in the future. this should determine the cloud provider being
 	// used by checking the infrastructure cr.
 	dd := &aws.BlobStorageDeploymentDetails{
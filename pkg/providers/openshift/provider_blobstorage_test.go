@@ -96,10 +96,12 @@ func TestBlobStorageProvider_CreateStorage(t *testing.T) {
 						},
 					},
 					Status: v1alpha1.BlobStorageStatus{
-						Phase: types.PhaseComplete,
-						SecretRef: &types.SecretRef{
-							Name:      "test",
-							Namespace: "test",
+						ResourceTypeStatus: types.ResourceTypeStatus{
+							Phase: types.PhaseComplete,
+							SecretRef: &types.SecretRef{
+								Name:      "test",
+								Namespace: "test",
+							},
 						},
 					},
 				},
@@ -142,10 +144,12 @@ func TestBlobStorageProvider_CreateStorage(t *testing.T) {
 						},
 					},
 					Status: v1alpha1.BlobStorageStatus{
-						Phase: types.PhaseComplete,
-						SecretRef: &types.SecretRef{
-							Name:      "test",
-							Namespace: "test",
+						ResourceTypeStatus: types.ResourceTypeStatus{
+							Phase: types.PhaseComplete,
+							SecretRef: &types.SecretRef{
+								Name:      "test",
+								Namespace: "test",
+							},
 						},
 					},
 				},
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/integr8ly/cloud-resource-operator/pkg/annotations"
 	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
 
 	"k8s.io/client-go/kubernetes"
@@ -42,6 +43,9 @@ var (
 	defaultPostgresPasswordKey = "password"
 	defaultPostgresDatabaseKey = "database"
 	defaultCredentialsSec      = "postgres-credentials"
+	// keys used while rotating the postgres password
+	defaultPostgresPendingPasswordKey   = "pendingPassword"
+	defaultPostgresPasswordRotatedAtKey = "passwordRotatedAt"
 )
 
 // PostgresStrat to be used to unmarshal strat map
@@ -88,6 +92,12 @@ func (p *PostgresProvider) GetReconcileTime(pg *v1alpha1.Postgres) time.Duration
 }
 
 func (p *PostgresProvider) CreatePostgres(ctx context.Context, ps *v1alpha1.Postgres) (*providers.PostgresInstance, croType.StatusMessage, error) {
+	// the postgres deployment and its pvc are always deleted with the cr
+	if err := resources.ValidateDeletionPolicy(ps.Spec.DeletionPolicy, resources.DeletionPolicyDelete); err != nil {
+		errMsg := fmt.Sprintf("invalid deletion policy of postgres instance %s", ps.Name)
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
 	// handle provider-specific finalizer
 	if err := resources.CreateFinalizer(ctx, p.Client, ps, DefaultFinalizer); err != nil {
 		errMsg := "failed to set finalizer"
@@ -166,18 +176,85 @@ func (p *PostgresProvider) CreatePostgres(ctx context.Context, ps *v1alpha1.Post
 		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
 	}
 
+	// rotate the user password if requested or the rotation interval has passed
+	if err := p.reconcilePasswordRotation(ctx, ps, dpl, sec, postgresCfg); err != nil {
+		errMsg := "failed to rotate postgres password"
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
 	p.Logger.Info("found postgres deployment")
 	return &providers.PostgresInstance{
 		DeploymentDetails: &providers.PostgresDeploymentDetails{
-			Username: dbUser,
-			Password: string(sec.Data["password"]),
-			Database: string(sec.Data["database"]),
-			Host:     fmt.Sprintf("%s.%s.svc.cluster.local", ps.Name, ps.Namespace),
-			Port:     defaultPostgresPort,
+			Username:          dbUser,
+			Password:          string(sec.Data["password"]),
+			Database:          string(sec.Data["database"]),
+			Host:              fmt.Sprintf("%s.%s.svc.cluster.local", ps.Name, ps.Namespace),
+			Port:              defaultPostgresPort,
+			PasswordRotatedAt: string(sec.Data[defaultPostgresPasswordRotatedAtKey]),
 		},
 	}, "creation successful", nil
 }
 
+// reconcilePasswordRotation sets a new password for the postgres user when a rotation is requested or due. the new
+// password is stored as pending before it is set, so a failed rotation is retried with the same password
+func (p *PostgresProvider) reconcilePasswordRotation(ctx context.Context, ps *v1alpha1.Postgres, dpl *appsv1.Deployment, sec *v1.Secret, postgresCfg *PostgresStrat) error {
+	// the secret data is overwritten on every reconcile when it is provided by the strategy
+	if postgresCfg.PostgresSecretData != nil {
+		return nil
+	}
+
+	pendingPass, ok := sec.Data[defaultPostgresPendingPasswordKey]
+	if !ok {
+		// the creation time of the secret is used if the password has never been rotated
+		lastRotation := sec.CreationTimestamp.Time
+		if rotatedAt, ok := sec.Data[defaultPostgresPasswordRotatedAtKey]; ok {
+			t, err := time.Parse(time.RFC3339, string(rotatedAt))
+			if err != nil {
+				return errorUtil.Wrap(err, "failed to parse password rotation time")
+			}
+			lastRotation = t
+		}
+		due, err := resources.IsRotationDue(ps, ps.Spec.RotationInterval, lastRotation)
+		if err != nil {
+			return errorUtil.Wrap(err, "failed to check if password rotation is due")
+		}
+		if !due {
+			return nil
+		}
+
+		password, err := resources.GeneratePassword()
+		if err != nil {
+			return errorUtil.Wrap(err, "failed to generate postgres password")
+		}
+		pendingPass = []byte(password)
+		sec.Data[defaultPostgresPendingPasswordKey] = pendingPass
+		if err := p.Client.Update(ctx, sec); err != nil {
+			return errorUtil.Wrap(err, "failed to store pending postgres password")
+		}
+	}
+
+	stmt := fmt.Sprintf("ALTER USER %s WITH PASSWORD %s", providers.QuotePostgresIdentifier(string(sec.Data["user"])), providers.QuotePostgresLiteral(string(pendingPass)))
+	if err := p.PodCommander.ExecIntoPod(dpl, buildPsqlScriptCommand("", []string{stmt})); err != nil {
+		return errorUtil.Wrap(err, "failed to set new postgres password")
+	}
+
+	// the new password is in use, promote it
+	sec.Data[defaultPostgresPasswordKey] = pendingPass
+	sec.Data[defaultPostgresPasswordRotatedAtKey] = []byte(time.Now().UTC().Format(time.RFC3339))
+	delete(sec.Data, defaultPostgresPendingPasswordKey)
+	if err := p.Client.Update(ctx, sec); err != nil {
+		return errorUtil.Wrap(err, "failed to promote rotated postgres password")
+	}
+	if annotations.Has(ps, resources.RotateCredentialsAnnotation) {
+		annotations.Remove(ps, resources.RotateCredentialsAnnotation)
+		if err := p.Client.Update(ctx, ps); err != nil {
+			return errorUtil.Wrap(err, "failed to remove rotate credentials annotation")
+		}
+	}
+	p.Logger.Infof("rotated password for postgres instance %s", ps.Name)
+	return nil
+}
+
 func (p *PostgresProvider) DeletePostgres(ctx context.Context, ps *v1alpha1.Postgres) (croType.StatusMessage, error) {
 	// delete service
 	p.Logger.Info("deleting postgres service")
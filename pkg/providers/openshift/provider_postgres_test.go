@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/integr8ly/cloud-resource-operator/pkg/annotations"
 	types2 "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
 
 	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
@@ -200,6 +201,25 @@ func TestOpenShiftPostgresProvider_CreatePostgres(t *testing.T) {
 			want:    buildTestPostgresInstance(),
 			wantErr: false,
 		},
+		{
+			name: "test error when deletion policy is not supported",
+			fields: fields{
+				Client:        fake.NewFakeClientWithScheme(scheme, buildTestPostgresCR()),
+				Logger:        testLogger,
+				ConfigManager: buildDefaultConfigManager(),
+				PodCommander:  buildTestPodCommander(),
+			},
+			args: args{
+				ctx: context.TODO(),
+				postgres: func() *v1alpha1.Postgres {
+					pg := buildTestPostgresCR()
+					pg.Spec.DeletionPolicy = resources.DeletionPolicyRetain
+					return pg
+				}(),
+			},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -472,3 +492,101 @@ func TestOpenShiftPostgresProvider_GetReconcileTime(t *testing.T) {
 		})
 	}
 }
+
+func TestOpenShiftPostgresProvider_reconcilePasswordRotation(t *testing.T) {
+	scheme, err := buildTestScheme()
+	if err != nil {
+		t.Fatal("failed to build scheme", err)
+	}
+	buildRotationRequestedCR := func() *v1alpha1.Postgres {
+		ps := buildTestPostgresCR()
+		ps.Annotations = map[string]string{resources.RotateCredentialsAnnotation: "true"}
+		return ps
+	}
+	buildPendingCredsSecret := func() *v1.Secret {
+		sec := buildTestCredsSecret()
+		sec.Data[defaultPostgresPendingPasswordKey] = []byte("new-password")
+		return sec
+	}
+	tests := []struct {
+		name         string
+		postgres     *v1alpha1.Postgres
+		sec          *v1.Secret
+		postgresCfg  *PostgresStrat
+		podCommander resources.PodCommander
+		wantRotated  bool
+		wantPending  bool
+		wantErr      bool
+	}{
+		{
+			name:         "test no rotation when not requested and no interval is set",
+			postgres:     buildTestPostgresCR(),
+			sec:          buildTestCredsSecret(),
+			postgresCfg:  &PostgresStrat{},
+			podCommander: buildTestPodCommander(),
+		},
+		{
+			name:         "test password is rotated when requested through annotation",
+			postgres:     buildRotationRequestedCR(),
+			sec:          buildTestCredsSecret(),
+			postgresCfg:  &PostgresStrat{},
+			podCommander: buildTestPodCommander(),
+			wantRotated:  true,
+		},
+		{
+			name:         "test no rotation when secret data is provided by the strategy",
+			postgres:     buildRotationRequestedCR(),
+			sec:          buildTestCredsSecret(),
+			postgresCfg:  &PostgresStrat{PostgresSecretData: map[string]string{"password": testPostgresPassword}},
+			podCommander: buildTestPodCommander(),
+		},
+		{
+			name:         "test pending password is kept when it fails to be set",
+			postgres:     buildRotationRequestedCR(),
+			sec:          buildTestCredsSecret(),
+			postgresCfg:  &PostgresStrat{},
+			podCommander: buildTestPodCommanderWithError(fmt.Errorf("exec failed")),
+			wantPending:  true,
+			wantErr:      true,
+		},
+		{
+			name:         "test pending password is retried and promoted",
+			postgres:     buildTestPostgresCR(),
+			sec:          buildPendingCredsSecret(),
+			postgresCfg:  &PostgresStrat{},
+			podCommander: buildTestPodCommander(),
+			wantRotated:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewFakeClientWithScheme(scheme, tt.postgres, tt.sec)
+			p := &PostgresProvider{
+				Client:       c,
+				Logger:       testLogger,
+				PodCommander: tt.podCommander,
+			}
+			err := p.reconcilePasswordRotation(context.TODO(), tt.postgres, buildTestPostgresDeploymentReady(), tt.sec, tt.postgresCfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("reconcilePasswordRotation() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			sec := &v1.Secret{}
+			if err := c.Get(context.TODO(), types.NamespacedName{Name: tt.sec.Name, Namespace: tt.sec.Namespace}, sec); err != nil {
+				t.Fatal("failed to get credentials secret", err)
+			}
+			if rotated := string(sec.Data[defaultPostgresPasswordKey]) != testPostgresPassword; rotated != tt.wantRotated {
+				t.Errorf("reconcilePasswordRotation() password rotated = %v, want %v", rotated, tt.wantRotated)
+			}
+			if _, ok := sec.Data[defaultPostgresPasswordRotatedAtKey]; ok != tt.wantRotated {
+				t.Errorf("reconcilePasswordRotation() rotation time set = %v, want %v", ok, tt.wantRotated)
+			}
+			if _, ok := sec.Data[defaultPostgresPendingPasswordKey]; ok != tt.wantPending {
+				t.Errorf("reconcilePasswordRotation() pending password set = %v, want %v", ok, tt.wantPending)
+			}
+			if tt.wantRotated && annotations.Has(tt.postgres, resources.RotateCredentialsAnnotation) {
+				t.Errorf("reconcilePasswordRotation() rotate credentials annotation was not removed")
+			}
+		})
+	}
+}
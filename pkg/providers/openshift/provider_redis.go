@@ -43,7 +43,7 @@ const (
 	redisConfigMapKey     = "redis.conf"
 	redisContainerName    = "redis"
 	redisPort             = 6379
-	redisContainerCommand = "/opt/rh/rh-redis32/root/usr/bin/redis-server"
+	redisDataMountPath    = "/var/lib/redis/data"
 )
 
 var _ providers.RedisProvider = (*RedisProvider)(nil)
@@ -78,6 +78,12 @@ func (p *RedisProvider) GetReconcileTime(r *v1alpha1.Redis) time.Duration {
 }
 
 func (p *RedisProvider) CreateRedis(ctx context.Context, r *v1alpha1.Redis) (*providers.RedisCluster, croType.StatusMessage, error) {
+	// the redis deployment and its pvc are always deleted with the cr
+	if err := resources.ValidateDeletionPolicy(r.Spec.DeletionPolicy, resources.DeletionPolicyDelete); err != nil {
+		errMsg := fmt.Sprintf("invalid deletion policy of redis instance %s", r.Name)
+		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+
 	// handle provider-specific finalizer
 	if err := resources.CreateFinalizer(ctx, p.Client, r, DefaultFinalizer); err != nil {
 		return nil, "failed to set finalizer", err
@@ -100,8 +106,12 @@ func (p *RedisProvider) CreateRedis(ctx context.Context, r *v1alpha1.Redis) (*pr
 		errMsg := "failed to create or update redis config map"
 		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
 	}
-	// deploy deployment
-	if err := p.CreateDeployment(ctx, buildDefaultRedisDeployment(r), redisConfig); err != nil {
+	// deploy deployment, at the version redis is being upgraded to
+	version, backup, msg, err := p.reconcileRedisUpgrade(ctx, r, redisConfig)
+	if err != nil {
+		return nil, msg, err
+	}
+	if err := p.CreateDeployment(ctx, buildRedisDeployment(r, version, backup), redisConfig); err != nil {
 		errMsg := "failed to create or update redis deployment"
 		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
 	}
@@ -119,6 +129,17 @@ func (p *RedisProvider) CreateRedis(ctx context.Context, r *v1alpha1.Redis) (*pr
 		return nil, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
 	}
 
+	// an upgrade is complete once the pod of the upgraded deployment is available
+	if backup && r.Status.Upgrade.Phase == croType.PhaseInProgress {
+		if !isDeploymentRolledOut(dpl) {
+			return nil, croType.StatusMessage(fmt.Sprintf("upgrade to %s in progress", version)), nil
+		}
+		p.Logger.Infof("upgraded redis %s to %s", r.Name, version)
+		r.Status.Upgrade.Phase = croType.PhaseComplete
+		r.Status.Upgrade.Message = croType.StatusMessage(fmt.Sprintf("upgraded from %s to %s", r.Status.Upgrade.FromVersion, version))
+		r.Status.Upgrade.CompletedAt = time.Now().UTC().Format(time.RFC3339)
+	}
+
 	// check if deployment is ready and return connection details
 	for _, s := range dpl.Status.Conditions {
 		if s.Type == appsv1.DeploymentAvailable && s.Status == "True" {
@@ -302,6 +323,19 @@ type RedisStrat struct {
 	RedisConfigMapData  map[string]string                `json:"configMapData"`
 }
 
+// buildRedisDeployment returns the default deployment running a version of redis. the deployment of a redis which is
+// which has been upgraded backs up the data of the previous version before redis starts
+func buildRedisDeployment(r *v1alpha1.Redis, version string, backup bool) *appsv1.Deployment {
+	depl := buildDefaultRedisDeployment(r)
+	image := redisImages[version]
+	depl.Spec.Template.Spec.Containers[0].Image = image.image
+	depl.Spec.Template.Spec.Containers[0].Command = []string{image.command}
+	if backup {
+		depl.Spec.Template.Spec.InitContainers = buildRedisBackupInitContainers(r, r.Status.Upgrade.FromVersion, image.image)
+	}
+	return depl
+}
+
 func buildDefaultRedisDeployment(r *v1alpha1.Redis) *appsv1.Deployment {
 	depl := &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{
@@ -349,11 +383,11 @@ func buildDefaultRedisDeployment(r *v1alpha1.Redis) *appsv1.Deployment {
 func buildDefaultRedisPodContainers(r *v1alpha1.Redis) []apiv1.Container {
 	return []apiv1.Container{
 		{
-			Image:           "registry.redhat.io/rhscl/redis-32-rhel7",
+			Image:           redisImages[defaultRedisVersion].image,
 			ImagePullPolicy: apiv1.PullIfNotPresent,
 			Name:            redisContainerName,
 			Command: []string{
-				redisContainerCommand,
+				redisImages[defaultRedisVersion].command,
 			},
 			Args: []string{
 				"/etc/redis.d/redis.conf",
@@ -397,7 +431,7 @@ func buildDefaultRedisPodContainers(r *v1alpha1.Redis) []apiv1.Container {
 			VolumeMounts: []apiv1.VolumeMount{
 				{
 					Name:      r.Name,
-					MountPath: "/var/lib/redis/data",
+					MountPath: redisDataMountPath,
 				},
 				{
 					Name:      redisConfigVolumeName,
@@ -273,7 +273,9 @@ func TestOpenShiftRedisProvider_GetReconcileTime(t *testing.T) {
 			args: args{
 				r: &v1alpha1.Redis{
 					Status: v1alpha1.RedisStatus{
-						Phase: types.PhaseInProgress,
+						ResourceTypeStatus: types.ResourceTypeStatus{
+							Phase: types.PhaseInProgress,
+						},
 					},
 				},
 			},
@@ -284,7 +286,9 @@ func TestOpenShiftRedisProvider_GetReconcileTime(t *testing.T) {
 			args: args{
 				r: &v1alpha1.Redis{
 					Status: v1alpha1.RedisStatus{
-						Phase: types.PhaseComplete,
+						ResourceTypeStatus: types.ResourceTypeStatus{
+							Phase: types.PhaseComplete,
+						},
 					},
 				},
 			},
@@ -0,0 +1,165 @@
+package openshift
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	"github.com/integr8ly/cloud-resource-operator/pkg/resources"
+	errorUtil "github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// defaultRedisVersion is the version of redis deployed if the cr does not set an engine version
+const defaultRedisVersion = "3.2"
+
+// redisImage is an image of a version of redis
+type redisImage struct {
+	image   string
+	command string
+}
+
+// redisImages are the versions of redis which can be deployed, and upgraded to
+var redisImages = map[string]redisImage{
+	"3.2": {image: "registry.redhat.io/rhscl/redis-32-rhel7", command: "/opt/rh/rh-redis32/root/usr/bin/redis-server"},
+	"5":   {image: "registry.redhat.io/rhscl/redis-5-rhel7", command: "/opt/rh/rh-redis5/root/usr/bin/redis-server"},
+	"6":   {image: "registry.redhat.io/rhscl/redis-6-rhel7", command: "/opt/rh/rh-redis6/root/usr/bin/redis-server"},
+}
+
+// reconcileRedisUpgrade returns the version of redis to deploy, upgrading a deployment to the engine version of the cr.
+// the target version is validated, and the upgraded deployment backs up the data of the previous version before the new
+// version starts. true is returned if the deployment backs up the data, which it does from the start of an upgrade
+// until the next upgrade, so the deployment is not rolled out again once the upgrade has completed
+func (p *RedisProvider) reconcileRedisUpgrade(ctx context.Context, r *v1alpha1.Redis, redisConfig *RedisStrat) (string, bool, croType.StatusMessage, error) {
+	dpl := &appsv1.Deployment{}
+	err := p.Client.Get(ctx, types.NamespacedName{Name: r.Name, Namespace: r.Namespace}, dpl)
+	if err != nil && !k8serr.IsNotFound(err) {
+		errMsg := "failed to get redis deployment"
+		return "", false, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	target := r.Spec.EngineVersion
+	if target != "" {
+		if _, ok := redisImages[target]; !ok {
+			errMsg := fmt.Sprintf("redis engine version %s is not supported, expected one of %s", target, strings.Join(getRedisVersions(), ", "))
+			return "", false, croType.StatusMessage(errMsg), fmt.Errorf(errMsg)
+		}
+	}
+
+	// a new deployment starts at the target version, as does a deployment of an unknown image which is not upgraded
+	current := getRedisDeploymentVersion(dpl)
+	if k8serr.IsNotFound(err) || current == "" && target == "" {
+		return resources.StringOrDefault(target, defaultRedisVersion), false, croType.StatusEmpty, nil
+	}
+	upgrade := r.Status.Upgrade
+	backup := upgrade != nil && upgrade.ToVersion == current && upgrade.Phase != croType.PhaseFailed
+	if target == "" || target == current {
+		return current, backup, croType.StatusEmpty, nil
+	}
+
+	upgrade = &croType.ResourceUpgradeStatus{
+		FromVersion: current,
+		ToVersion:   target,
+	}
+	r.Status.Upgrade = upgrade
+	if err := validateRedisUpgrade(redisConfig, current, target); err != nil {
+		errMsg := fmt.Sprintf("failed to validate upgrade of redis %s to %s", r.Name, target)
+		upgrade.Phase = croType.PhaseFailed
+		upgrade.Message = croType.StatusMessage(errMsg).WrapError(err)
+		return current, backup, croType.StatusMessage(errMsg), errorUtil.Wrap(err, errMsg)
+	}
+	backupDir := buildRedisBackupDir(current)
+	p.Logger.Infof("upgrading redis %s from %s to %s", r.Name, current, target)
+	upgrade.Phase = croType.PhaseInProgress
+	upgrade.Message = croType.StatusMessage(fmt.Sprintf("upgrading from %s to %s", current, target))
+	upgrade.Snapshot = fmt.Sprintf("%s on persistent volume claim %s", backupDir, r.Name)
+	upgrade.StartedAt = time.Now().UTC().Format(time.RFC3339)
+	upgrade.RollbackGuidance = fmt.Sprintf("to roll back to %s set skipCreate to true, scale deployment %s to 0, copy the files of %s on persistent volume claim %s back to %s and set the image of deployment %s to %s", current, r.Name, backupDir, r.Name, redisDataMountPath, r.Name, redisImages[current].image)
+	return target, true, upgrade.Message, nil
+}
+
+// validateRedisUpgrade verifies redis can be upgraded to a version, the deployment must not be declared by the strategy
+func validateRedisUpgrade(redisConfig *RedisStrat, current, target string) error {
+	if redisConfig.RedisDeploymentSpec != nil {
+		return fmt.Errorf("the deployment of the strategy can not be upgraded, update its image instead")
+	}
+	cmp, err := resources.CompareVersions(target, current)
+	if err != nil {
+		return err
+	}
+	if cmp < 0 {
+		return fmt.Errorf("engine version %s is older than the current engine version %s, downgrades are not supported", target, current)
+	}
+	return nil
+}
+
+// getRedisDeploymentVersion returns the version of the image of the redis container of a deployment, an empty string is
+// returned if the image is unknown
+func getRedisDeploymentVersion(dpl *appsv1.Deployment) string {
+	for _, c := range dpl.Spec.Template.Spec.Containers {
+		if c.Name != redisContainerName {
+			continue
+		}
+		for version, image := range redisImages {
+			if c.Image == image.image {
+				return version
+			}
+		}
+	}
+	return ""
+}
+
+// getRedisVersions returns the versions of redis which can be deployed, in order
+func getRedisVersions() []string {
+	var versions []string
+	for version := range redisImages {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// buildRedisBackupDir returns the directory the data of a version of redis is backed up to before it is upgraded
+func buildRedisBackupDir(version string) string {
+	return path.Join(redisDataMountPath, fmt.Sprintf("pre-upgrade-%s", version))
+}
+
+// buildRedisBackupInitContainers returns the init container which backs up the data of the version redis is upgraded
+// from. with the recreate strategy of the deployment the previous version has saved its data on shutdown, the data is
+// only backed up once
+func buildRedisBackupInitContainers(r *v1alpha1.Redis, fromVersion, image string) []apiv1.Container {
+	backupDir := buildRedisBackupDir(fromVersion)
+	return []apiv1.Container{
+		{
+			Name:            fmt.Sprintf("%s-backup", redisContainerName),
+			Image:           image,
+			ImagePullPolicy: apiv1.PullIfNotPresent,
+			Command: []string{
+				"/bin/sh",
+				"-c",
+				fmt.Sprintf("[ -d %[1]s ] || (mkdir -p %[1]s.tmp && find %[2]s -maxdepth 1 -type f -exec cp -a {} %[1]s.tmp/ \\; && mv %[1]s.tmp %[1]s)", backupDir, redisDataMountPath),
+			},
+			VolumeMounts: []apiv1.VolumeMount{
+				{
+					Name:      r.Name,
+					MountPath: redisDataMountPath,
+				},
+			},
+		},
+	}
+}
+
+// isDeploymentRolledOut returns true once all replicas of the latest revision of a deployment are available
+func isDeploymentRolledOut(dpl *appsv1.Deployment) bool {
+	if dpl.Status.ObservedGeneration < dpl.Generation {
+		return false
+	}
+	return dpl.Status.UpdatedReplicas > 0 && dpl.Status.UpdatedReplicas == dpl.Status.AvailableReplicas && dpl.Status.Replicas == dpl.Status.UpdatedReplicas
+}
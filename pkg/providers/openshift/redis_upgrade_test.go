@@ -0,0 +1,134 @@
+package openshift
+
+import (
+	"context"
+	"testing"
+
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func buildTestRedisDeploymentVersion(version string) *appsv1.Deployment {
+	dpl := buildRedisDeployment(buildTestRedisCR(), version, false)
+	dpl.Status = buildTestDeploymentReady().Status
+	return dpl
+}
+
+func buildTestRedisCREngineVersion(version string) *v1alpha1.Redis {
+	r := buildTestRedisCR()
+	r.Spec.EngineVersion = version
+	return r
+}
+
+func TestRedisProvider_reconcileRedisUpgrade(t *testing.T) {
+	scheme, err := buildTestScheme()
+	if err != nil {
+		t.Fatal("failed to build scheme", err)
+	}
+	tests := []struct {
+		name        string
+		r           *v1alpha1.Redis
+		redisConfig *RedisStrat
+		deployment  *appsv1.Deployment
+		wantVersion string
+		wantBackup  bool
+		wantPhase   croType.StatusPhase
+		wantErr     bool
+	}{
+		{
+			name:        "test new deployment uses the default version",
+			r:           buildTestRedisCR(),
+			redisConfig: &RedisStrat{},
+			wantVersion: defaultRedisVersion,
+		},
+		{
+			name:        "test new deployment uses the engine version of the cr",
+			r:           buildTestRedisCREngineVersion("5"),
+			redisConfig: &RedisStrat{},
+			wantVersion: "5",
+		},
+		{
+			name:        "test existing deployment keeps its version without an engine version",
+			r:           buildTestRedisCR(),
+			redisConfig: &RedisStrat{},
+			deployment:  buildTestRedisDeploymentVersion("5"),
+			wantVersion: "5",
+		},
+		{
+			name:        "test existing deployment is upgraded and backed up",
+			r:           buildTestRedisCREngineVersion("5"),
+			redisConfig: &RedisStrat{},
+			deployment:  buildTestRedisDeploymentVersion("3.2"),
+			wantVersion: "5",
+			wantBackup:  true,
+			wantPhase:   croType.PhaseInProgress,
+		},
+		{
+			name:        "test downgrade fails",
+			r:           buildTestRedisCREngineVersion("3.2"),
+			redisConfig: &RedisStrat{},
+			deployment:  buildTestRedisDeploymentVersion("5"),
+			wantVersion: "5",
+			wantPhase:   croType.PhaseFailed,
+			wantErr:     true,
+		},
+		{
+			name:        "test upgrade of a strategy deployment fails",
+			r:           buildTestRedisCREngineVersion("5"),
+			redisConfig: &RedisStrat{RedisDeploymentSpec: &appsv1.DeploymentSpec{}},
+			deployment:  buildTestRedisDeploymentVersion("3.2"),
+			wantVersion: "3.2",
+			wantPhase:   croType.PhaseFailed,
+			wantErr:     true,
+		},
+		{
+			name:        "test unsupported version fails",
+			r:           buildTestRedisCREngineVersion("4"),
+			redisConfig: &RedisStrat{},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewFakeClientWithScheme(scheme, tt.r.DeepCopy())
+			if tt.deployment != nil {
+				client = fake.NewFakeClientWithScheme(scheme, tt.r.DeepCopy(), tt.deployment)
+			}
+			p := &RedisProvider{
+				Client: client,
+				Logger: testLogger,
+			}
+			gotVersion, gotBackup, _, err := p.reconcileRedisUpgrade(context.TODO(), tt.r, tt.redisConfig)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("reconcileRedisUpgrade() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotVersion != tt.wantVersion {
+				t.Errorf("reconcileRedisUpgrade() version = %v, want %v", gotVersion, tt.wantVersion)
+			}
+			if gotBackup != tt.wantBackup {
+				t.Errorf("reconcileRedisUpgrade() backup = %v, want %v", gotBackup, tt.wantBackup)
+			}
+			if tt.wantPhase == "" {
+				return
+			}
+			if tt.r.Status.Upgrade == nil || tt.r.Status.Upgrade.Phase != tt.wantPhase {
+				t.Errorf("reconcileRedisUpgrade() upgrade status = %v, want phase %v", tt.r.Status.Upgrade, tt.wantPhase)
+			}
+		})
+	}
+}
+
+func Test_buildRedisDeployment(t *testing.T) {
+	r := buildTestRedisCR()
+	r.Status.Upgrade = &croType.ResourceUpgradeStatus{FromVersion: "3.2", ToVersion: "5"}
+	dpl := buildRedisDeployment(r, "5", true)
+	if got := getRedisDeploymentVersion(dpl); got != "5" {
+		t.Errorf("buildRedisDeployment() version = %v, want 5", got)
+	}
+	if len(dpl.Spec.Template.Spec.InitContainers) != 1 {
+		t.Errorf("buildRedisDeployment() init containers = %v, want a backup container", dpl.Spec.Template.Spec.InitContainers)
+	}
+}
@@ -0,0 +1,236 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+
+	errorUtil "github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// postgres truncates identifiers longer than 63 bytes
+	postgresMaxIdentifierLength = 63
+	// suffix of the role which owns a logical database
+	postgresOwnerRoleSuffix = "_owner"
+)
+
+var postgresIdentifierRegexp = regexp.MustCompile("^[a-z_][a-z0-9_]*$")
+
+// BuildPostgresIdentifier converts a kubernetes resource name in to a valid, unquoted postgres identifier
+func BuildPostgresIdentifier(name string) string {
+	id := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, strings.ToLower(name))
+	if id == "" || (id[0] >= '0' && id[0] <= '9') {
+		id = "_" + id
+	}
+	if len(id) > postgresMaxIdentifierLength-len(postgresOwnerRoleSuffix) {
+		id = id[:postgresMaxIdentifierLength-len(postgresOwnerRoleSuffix)]
+	}
+	return id
+}
+
+// BuildPostgresOwnerRole returns the name of the role which owns a logical database
+func BuildPostgresOwnerRole(database string) string {
+	return database + postgresOwnerRoleSuffix
+}
+
+// ValidatePostgresIdentifier ensures a database or role name is safe to be used in statements
+func ValidatePostgresIdentifier(id string) error {
+	if len(id) > postgresMaxIdentifierLength-len(postgresOwnerRoleSuffix) {
+		return errorUtil.New(fmt.Sprintf("identifier %s must be at most %d characters", id, postgresMaxIdentifierLength-len(postgresOwnerRoleSuffix)))
+	}
+	if !postgresIdentifierRegexp.MatchString(id) {
+		return errorUtil.New(fmt.Sprintf("identifier %s must start with a lowercase letter or underscore and contain only lowercase letters, digits and underscores", id))
+	}
+	return nil
+}
+
+// ValidatePostgresRole ensures a role managed by the operator can not take over the master user or a built-in role
+func ValidatePostgresRole(role, masterUser string) error {
+	if err := ValidatePostgresIdentifier(role); err != nil {
+		return err
+	}
+	if role == masterUser || role == "postgres" || strings.HasPrefix(role, "pg_") || strings.HasPrefix(role, "rds") {
+		return errorUtil.New(fmt.Sprintf("role %s is reserved", role))
+	}
+	return nil
+}
+
+// GetPostgresDeploymentDetails reads the connection details of a postgres instance from its result secret
+func GetPostgresDeploymentDetails(ctx context.Context, c client.Client, ps *v1alpha1.Postgres) (*PostgresDeploymentDetails, error) {
+	if ps.Status.SecretRef == nil || ps.Status.SecretRef.Name == "" {
+		return nil, errorUtil.New(fmt.Sprintf("postgres instance %s has no result secret", ps.Name))
+	}
+	secNs := ps.Status.SecretRef.Namespace
+	if secNs == "" {
+		secNs = ps.Namespace
+	}
+	sec := &v1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Name: ps.Status.SecretRef.Name, Namespace: secNs}, sec); err != nil {
+		return nil, errorUtil.Wrapf(err, "failed to get result secret for postgres instance %s", ps.Name)
+	}
+	port, err := strconv.Atoi(string(sec.Data["port"]))
+	if err != nil {
+		return nil, errorUtil.Wrapf(err, "failed to parse port from result secret for postgres instance %s", ps.Name)
+	}
+	return &PostgresDeploymentDetails{
+		Username: string(sec.Data["username"]),
+		Password: string(sec.Data["password"]),
+		Host:     string(sec.Data["host"]),
+		Database: string(sec.Data["database"]),
+		Port:     port,
+	}, nil
+}
+
+// QuotePostgresIdentifier quotes an identifier for use in a statement
+func QuotePostgresIdentifier(id string) string {
+	return `"` + strings.Replace(id, `"`, `""`, -1) + `"`
+}
+
+// QuotePostgresLiteral quotes a string literal for use in a statement
+func QuotePostgresLiteral(literal string) string {
+	return `'` + strings.Replace(literal, `'`, `''`, -1) + `'`
+}
+
+// BuildCreateRoleStatements creates a login role with no elevated privileges, or resets the password of an existing one.
+// the current user is made a member of the role so it can later manage and drop objects on its behalf
+func BuildCreateRoleStatements(role, password string) []string {
+	return []string{
+		fmt.Sprintf(`DO $do$
+BEGIN
+	IF NOT EXISTS (SELECT FROM pg_catalog.pg_roles WHERE rolname = %s) THEN
+		CREATE ROLE %s WITH LOGIN NOSUPERUSER NOCREATEDB NOCREATEROLE PASSWORD %s;
+	ELSE
+		ALTER ROLE %s WITH LOGIN NOCREATEDB NOCREATEROLE PASSWORD %s;
+	END IF;
+END
+$do$`, QuotePostgresLiteral(role), QuotePostgresIdentifier(role), QuotePostgresLiteral(password), QuotePostgresIdentifier(role), QuotePostgresLiteral(password)),
+		fmt.Sprintf("GRANT %s TO CURRENT_USER", QuotePostgresIdentifier(role)),
+	}
+}
+
+// BuildCreateIAMRoleStatements creates a login role with no elevated privileges which can only authenticate through rds
+// iam database authentication, removing the password of an existing role
+func BuildCreateIAMRoleStatements(role string) []string {
+	return []string{
+		fmt.Sprintf(`DO $do$
+BEGIN
+	IF NOT EXISTS (SELECT FROM pg_catalog.pg_roles WHERE rolname = %s) THEN
+		CREATE ROLE %s WITH LOGIN NOSUPERUSER NOCREATEDB NOCREATEROLE;
+	ELSE
+		ALTER ROLE %s WITH LOGIN NOCREATEDB NOCREATEROLE PASSWORD NULL;
+	END IF;
+END
+$do$`, QuotePostgresLiteral(role), QuotePostgresIdentifier(role), QuotePostgresIdentifier(role)),
+		fmt.Sprintf("GRANT rds_iam TO %s", QuotePostgresIdentifier(role)),
+		fmt.Sprintf("GRANT %s TO CURRENT_USER", QuotePostgresIdentifier(role)),
+	}
+}
+
+// BuildRevokeIAMRoleStatements allows a role which previously authenticated through rds iam database authentication to
+// authenticate with a password again. the statements do nothing on instances without iam database authentication
+func BuildRevokeIAMRoleStatements(role string) []string {
+	return []string{
+		fmt.Sprintf(`DO $do$
+BEGIN
+	IF EXISTS (SELECT FROM pg_catalog.pg_roles WHERE rolname = 'rds_iam') AND pg_catalog.pg_has_role(%s, 'rds_iam', 'MEMBER') THEN
+		EXECUTE format('REVOKE rds_iam FROM %%I', %s);
+	END IF;
+END
+$do$`, QuotePostgresLiteral(role), QuotePostgresLiteral(role)),
+	}
+}
+
+// BuildCreateDatabaseStatements creates a logical database owned by the given role
+func BuildCreateDatabaseStatements(database, owner string) []string {
+	return []string{
+		fmt.Sprintf("CREATE DATABASE %s OWNER %s", QuotePostgresIdentifier(database), QuotePostgresIdentifier(owner)),
+	}
+}
+
+// BuildRestrictDatabaseStatements ensures only the owner, and roles explicitly granted access, can connect to a database
+func BuildRestrictDatabaseStatements(database, owner string) []string {
+	return []string{
+		fmt.Sprintf("REVOKE ALL ON DATABASE %s FROM PUBLIC", QuotePostgresIdentifier(database)),
+		fmt.Sprintf("GRANT ALL PRIVILEGES ON DATABASE %s TO %s", QuotePostgresIdentifier(database), QuotePostgresIdentifier(owner)),
+	}
+}
+
+// BuildDropDatabaseStatements disconnects any open sessions then drops a logical database and its owner role
+func BuildDropDatabaseStatements(database, owner string) []string {
+	return []string{
+		fmt.Sprintf("SELECT pg_terminate_backend(pid) FROM pg_catalog.pg_stat_activity WHERE datname = %s AND pid <> pg_backend_pid()", QuotePostgresLiteral(database)),
+		fmt.Sprintf("DROP DATABASE IF EXISTS %s", QuotePostgresIdentifier(database)),
+		fmt.Sprintf("DROP ROLE IF EXISTS %s", QuotePostgresIdentifier(owner)),
+	}
+}
+
+// BuildGrantConnectStatements allows a role to connect to a logical database
+func BuildGrantConnectStatements(database, role string) []string {
+	return []string{
+		fmt.Sprintf("GRANT CONNECT ON DATABASE %s TO %s", QuotePostgresIdentifier(database), QuotePostgresIdentifier(role)),
+	}
+}
+
+// BuildGrantUserStatements grants a role access to the tables of the public schema, including tables created later by
+// the database owner. these statements must be run against the logical database the role is granted access to
+func BuildGrantUserStatements(role string, readOnly bool) []string {
+	tablePrivileges := "SELECT, INSERT, UPDATE, DELETE"
+	sequencePrivileges := "USAGE, SELECT"
+	stmts := []string{
+		fmt.Sprintf("GRANT USAGE ON SCHEMA public TO %s", QuotePostgresIdentifier(role)),
+	}
+	if readOnly {
+		tablePrivileges = "SELECT"
+		sequencePrivileges = "SELECT"
+		stmts = append(stmts,
+			fmt.Sprintf("REVOKE INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public FROM %s", QuotePostgresIdentifier(role)),
+			fmt.Sprintf("REVOKE USAGE ON ALL SEQUENCES IN SCHEMA public FROM %s", QuotePostgresIdentifier(role)),
+		)
+	}
+	return append(stmts,
+		fmt.Sprintf("GRANT %s ON ALL TABLES IN SCHEMA public TO %s", tablePrivileges, QuotePostgresIdentifier(role)),
+		fmt.Sprintf("GRANT %s ON ALL SEQUENCES IN SCHEMA public TO %s", sequencePrivileges, QuotePostgresIdentifier(role)),
+		fmt.Sprintf(`DO $do$
+BEGIN
+	EXECUTE format('ALTER DEFAULT PRIVILEGES FOR ROLE %%I IN SCHEMA public REVOKE ALL ON TABLES FROM %%I', (SELECT pg_catalog.pg_get_userbyid(datdba) FROM pg_catalog.pg_database WHERE datname = current_database()), %s);
+	EXECUTE format('ALTER DEFAULT PRIVILEGES FOR ROLE %%I IN SCHEMA public REVOKE ALL ON SEQUENCES FROM %%I', (SELECT pg_catalog.pg_get_userbyid(datdba) FROM pg_catalog.pg_database WHERE datname = current_database()), %s);
+	EXECUTE format('ALTER DEFAULT PRIVILEGES FOR ROLE %%I IN SCHEMA public GRANT %s ON TABLES TO %%I', (SELECT pg_catalog.pg_get_userbyid(datdba) FROM pg_catalog.pg_database WHERE datname = current_database()), %s);
+	EXECUTE format('ALTER DEFAULT PRIVILEGES FOR ROLE %%I IN SCHEMA public GRANT %s ON SEQUENCES TO %%I', (SELECT pg_catalog.pg_get_userbyid(datdba) FROM pg_catalog.pg_database WHERE datname = current_database()), %s);
+END
+$do$`, QuotePostgresLiteral(role), QuotePostgresLiteral(role), tablePrivileges, QuotePostgresLiteral(role), sequencePrivileges, QuotePostgresLiteral(role)),
+	)
+}
+
+// BuildRevokeUserStatements removes all privileges held by a role in a logical database, these statements must be run
+// against the logical database the role was granted access to
+func BuildRevokeUserStatements(role string) []string {
+	return []string{
+		fmt.Sprintf(`DO $do$
+BEGIN
+	IF EXISTS (SELECT FROM pg_catalog.pg_roles WHERE rolname = %s) THEN
+		EXECUTE format('DROP OWNED BY %%I', %s);
+	END IF;
+END
+$do$`, QuotePostgresLiteral(role), QuotePostgresLiteral(role)),
+	}
+}
+
+// BuildDropRoleStatements drops a role once it no longer holds any privileges
+func BuildDropRoleStatements(role string) []string {
+	return []string{
+		fmt.Sprintf("DROP ROLE IF EXISTS %s", QuotePostgresIdentifier(role)),
+	}
+}
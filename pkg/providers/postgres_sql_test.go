@@ -0,0 +1,121 @@
+package providers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis"
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBuildPostgresIdentifier(t *testing.T) {
+	cases := []struct {
+		name     string
+		resource string
+		expected string
+	}{
+		{
+			name:     "test hyphens and dots are replaced",
+			resource: "my-app.db",
+			expected: "my_app_db",
+		},
+		{
+			name:     "test leading digit is prefixed",
+			resource: "1app",
+			expected: "_1app",
+		},
+		{
+			name:     "test long names are truncated",
+			resource: "a123456789a123456789a123456789a123456789a123456789a123456789",
+			expected: "a123456789a123456789a123456789a123456789a123456789a123456",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			id := BuildPostgresIdentifier(tc.resource)
+			if id != tc.expected {
+				t.Fatalf("unexpected identifier, got %s but expected %s", id, tc.expected)
+			}
+			if err := ValidatePostgresIdentifier(id); err != nil {
+				t.Fatalf("built identifier is not valid: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidatePostgresRole(t *testing.T) {
+	cases := []struct {
+		name    string
+		role    string
+		wantErr bool
+	}{
+		{
+			name:    "test valid role",
+			role:    "app_user",
+			wantErr: false,
+		},
+		{
+			name:    "test master user is rejected",
+			role:    "master",
+			wantErr: true,
+		},
+		{
+			name:    "test built-in role is rejected",
+			role:    "pg_monitor",
+			wantErr: true,
+		},
+		{
+			name:    "test quoted identifier is rejected",
+			role:    "app\"; DROP ROLE master; --",
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := ValidatePostgresRole(tc.role, "master"); (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetPostgresDeploymentDetails(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Fatal("failed to build scheme", err)
+	}
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Fatal("failed to build scheme", err)
+	}
+	ps := &v1alpha1.Postgres{
+		ObjectMeta: controllerruntime.ObjectMeta{Name: "test", Namespace: "test"},
+		Status: v1alpha1.PostgresStatus{
+			SecretRef: &croType.SecretRef{Name: "test-sec"},
+		},
+	}
+	sec := &v1.Secret{
+		ObjectMeta: controllerruntime.ObjectMeta{Name: "test-sec", Namespace: "test"},
+		Data: (&PostgresDeploymentDetails{
+			Username: "master",
+			Password: "password",
+			Host:     "host",
+			Database: "postgres",
+			Port:     5432,
+		}).Data(),
+	}
+	got, err := GetPostgresDeploymentDetails(context.TODO(), fake.NewFakeClientWithScheme(scheme, ps, sec), ps)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	want := &PostgresDeploymentDetails{Username: "master", Password: "password", Host: "host", Database: "postgres", Port: 5432}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected details, got %+v but expected %+v", got, want)
+	}
+}
@@ -9,7 +9,7 @@ import (
 	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
 )
 
-//go:generate moq -out types_moq.go . DeploymentDetails BlobStorageProvider SMTPCredentialsProvider
+//go:generate moq -out types_moq.go . DeploymentDetails BlobStorageProvider SMTPCredentialsProvider PostgresSQLRunner PostgresIAMCredentialProvider
 type ResourceType string
 
 const (
@@ -34,6 +34,8 @@ type SMTPCredentialSetInstance struct {
 
 type BlobStorageInstance struct {
 	DeploymentDetails DeploymentDetails
+	// Credentials are the details of the named credentials of the blob storage instance, by name
+	Credentials map[string]DeploymentDetails
 }
 
 type RedisCluster struct {
@@ -76,17 +78,45 @@ type PostgresProvider interface {
 	DeletePostgres(ctx context.Context, ps *v1alpha1.Postgres) (croType.StatusMessage, error)
 }
 
+// PostgresSQLRunner executes statements against the logical databases of a postgres instance, an empty database name
+// refers to the default database of the instance
+type PostgresSQLRunner interface {
+	GetName() string
+	SupportsStrategy(s string) bool
+	DatabaseExists(ctx context.Context, ps *v1alpha1.Postgres, database string) (bool, error)
+	ExecStatements(ctx context.Context, ps *v1alpha1.Postgres, database string, stmts []string) error
+}
+
+// PostgresIAMCredentialProvider manages the cloud credentials of postgres users which authenticate through iam
+// instead of a password
+type PostgresIAMCredentialProvider interface {
+	GetName() string
+	SupportsStrategy(s string) bool
+	ReconcileIAMCredentials(ctx context.Context, ps *v1alpha1.Postgres, pu *v1alpha1.PostgresUser, role string) (*PostgresIAMCredentials, croType.StatusMessage, error)
+	DeleteIAMCredentials(ctx context.Context, pu *v1alpha1.PostgresUser) error
+}
+
+// PostgresIAMCredentials credentials allowed to connect to a postgres instance as a single role
+type PostgresIAMCredentials struct {
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
 // RedisDeploymentDetails provider specific details about the AWS Redis Cluster created
 type RedisDeploymentDetails struct {
 	URI  string
 	Port int64
+	// ClusterMode is true if the uri is the configuration endpoint of a sharded redis cluster
+	ClusterMode bool
 }
 
 //Data Redis provider Data function
 func (r *RedisDeploymentDetails) Data() map[string][]byte {
 	return map[string][]byte{
-		"uri":  []byte(r.URI),
-		"port": []byte(strconv.FormatInt(r.Port, 10)),
+		"uri":         []byte(r.URI),
+		"port":        []byte(strconv.FormatInt(r.Port, 10)),
+		"clusterMode": []byte(strconv.FormatBool(r.ClusterMode)),
 	}
 }
 
@@ -96,14 +126,42 @@ type PostgresDeploymentDetails struct {
 	Host     string
 	Database string
 	Port     int
+	// PasswordRotatedAt is the time the password was last rotated, in RFC3339 format
+	PasswordRotatedAt string
 }
 
 func (d *PostgresDeploymentDetails) Data() map[string][]byte {
-	return map[string][]byte{
+	data := map[string][]byte{
 		"username": []byte(d.Username),
 		"password": []byte(d.Password),
 		"host":     []byte(d.Host),
 		"database": []byte(d.Database),
 		"port":     []byte(strconv.Itoa(d.Port)),
 	}
+	if d.PasswordRotatedAt != "" {
+		data["passwordRotatedAt"] = []byte(d.PasswordRotatedAt)
+	}
+	return data
+}
+
+// PostgresIAMDeploymentDetails connection details of a postgres user which authenticates through iam, an
+// authentication token is generated from the credentials in place of a password
+type PostgresIAMDeploymentDetails struct {
+	Username    string
+	Host        string
+	Database    string
+	Port        int
+	Credentials *PostgresIAMCredentials
+}
+
+func (d *PostgresIAMDeploymentDetails) Data() map[string][]byte {
+	return map[string][]byte{
+		"username":            []byte(d.Username),
+		"host":                []byte(d.Host),
+		"database":            []byte(d.Database),
+		"port":                []byte(strconv.Itoa(d.Port)),
+		"region":              []byte(d.Credentials.Region),
+		"credentialKeyID":     []byte(d.Credentials.AccessKeyID),
+		"credentialSecretKey": []byte(d.Credentials.SecretAccessKey),
+	}
 }
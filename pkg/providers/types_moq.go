@@ -561,3 +561,437 @@ func (mock *SMTPCredentialsProviderMock) SupportsStrategyCalls() []struct {
 	lockSMTPCredentialsProviderMockSupportsStrategy.RUnlock()
 	return calls
 }
+
+var (
+	lockPostgresSQLRunnerMockDatabaseExists   sync.RWMutex
+	lockPostgresSQLRunnerMockExecStatements   sync.RWMutex
+	lockPostgresSQLRunnerMockGetName          sync.RWMutex
+	lockPostgresSQLRunnerMockSupportsStrategy sync.RWMutex
+)
+
+// Ensure, that PostgresSQLRunnerMock does implement PostgresSQLRunner.
+// If this is not the case, regenerate this file with moq.
+var _ PostgresSQLRunner = &PostgresSQLRunnerMock{}
+
+// PostgresSQLRunnerMock is a mock implementation of PostgresSQLRunner.
+//
+//     func TestSomethingThatUsesPostgresSQLRunner(t *testing.T) {
+//
+//         // make and configure a mocked PostgresSQLRunner
+//         mockedPostgresSQLRunner := &PostgresSQLRunnerMock{
+//             DatabaseExistsFunc: func(ctx context.Context, ps *v1alpha1.Postgres, database string) (bool, error) {
+// 	               panic("mock out the DatabaseExists method")
+//             },
+//             ExecStatementsFunc: func(ctx context.Context, ps *v1alpha1.Postgres, database string, stmts []string) error {
+// 	               panic("mock out the ExecStatements method")
+//             },
+//             GetNameFunc: func() string {
+// 	               panic("mock out the GetName method")
+//             },
+//             SupportsStrategyFunc: func(s string) bool {
+// 	               panic("mock out the SupportsStrategy method")
+//             },
+//         }
+//
+//         // use mockedPostgresSQLRunner in code that requires PostgresSQLRunner
+//         // and then make assertions.
+//
+//     }
+type PostgresSQLRunnerMock struct {
+	// DatabaseExistsFunc mocks the DatabaseExists method.
+	DatabaseExistsFunc func(ctx context.Context, ps *v1alpha1.Postgres, database string) (bool, error)
+
+	// ExecStatementsFunc mocks the ExecStatements method.
+	ExecStatementsFunc func(ctx context.Context, ps *v1alpha1.Postgres, database string, stmts []string) error
+
+	// GetNameFunc mocks the GetName method.
+	GetNameFunc func() string
+
+	// SupportsStrategyFunc mocks the SupportsStrategy method.
+	SupportsStrategyFunc func(s string) bool
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// DatabaseExists holds details about calls to the DatabaseExists method.
+		DatabaseExists []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Ps is the ps argument value.
+			Ps *v1alpha1.Postgres
+			// Database is the database argument value.
+			Database string
+		}
+		// ExecStatements holds details about calls to the ExecStatements method.
+		ExecStatements []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Ps is the ps argument value.
+			Ps *v1alpha1.Postgres
+			// Database is the database argument value.
+			Database string
+			// Stmts is the stmts argument value.
+			Stmts []string
+		}
+		// GetName holds details about calls to the GetName method.
+		GetName []struct {
+		}
+		// SupportsStrategy holds details about calls to the SupportsStrategy method.
+		SupportsStrategy []struct {
+			// S is the s argument value.
+			S string
+		}
+	}
+}
+
+// DatabaseExists calls DatabaseExistsFunc.
+func (mock *PostgresSQLRunnerMock) DatabaseExists(ctx context.Context, ps *v1alpha1.Postgres, database string) (bool, error) {
+	if mock.DatabaseExistsFunc == nil {
+		panic("PostgresSQLRunnerMock.DatabaseExistsFunc: method is nil but PostgresSQLRunner.DatabaseExists was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Ps       *v1alpha1.Postgres
+		Database string
+	}{
+		Ctx:      ctx,
+		Ps:       ps,
+		Database: database,
+	}
+	lockPostgresSQLRunnerMockDatabaseExists.Lock()
+	mock.calls.DatabaseExists = append(mock.calls.DatabaseExists, callInfo)
+	lockPostgresSQLRunnerMockDatabaseExists.Unlock()
+	return mock.DatabaseExistsFunc(ctx, ps, database)
+}
+
+// DatabaseExistsCalls gets all the calls that were made to DatabaseExists.
+// Check the length with:
+//     len(mockedPostgresSQLRunner.DatabaseExistsCalls())
+func (mock *PostgresSQLRunnerMock) DatabaseExistsCalls() []struct {
+	Ctx      context.Context
+	Ps       *v1alpha1.Postgres
+	Database string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Ps       *v1alpha1.Postgres
+		Database string
+	}
+	lockPostgresSQLRunnerMockDatabaseExists.RLock()
+	calls = mock.calls.DatabaseExists
+	lockPostgresSQLRunnerMockDatabaseExists.RUnlock()
+	return calls
+}
+
+// ExecStatements calls ExecStatementsFunc.
+func (mock *PostgresSQLRunnerMock) ExecStatements(ctx context.Context, ps *v1alpha1.Postgres, database string, stmts []string) error {
+	if mock.ExecStatementsFunc == nil {
+		panic("PostgresSQLRunnerMock.ExecStatementsFunc: method is nil but PostgresSQLRunner.ExecStatements was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Ps       *v1alpha1.Postgres
+		Database string
+		Stmts    []string
+	}{
+		Ctx:      ctx,
+		Ps:       ps,
+		Database: database,
+		Stmts:    stmts,
+	}
+	lockPostgresSQLRunnerMockExecStatements.Lock()
+	mock.calls.ExecStatements = append(mock.calls.ExecStatements, callInfo)
+	lockPostgresSQLRunnerMockExecStatements.Unlock()
+	return mock.ExecStatementsFunc(ctx, ps, database, stmts)
+}
+
+// ExecStatementsCalls gets all the calls that were made to ExecStatements.
+// Check the length with:
+//     len(mockedPostgresSQLRunner.ExecStatementsCalls())
+func (mock *PostgresSQLRunnerMock) ExecStatementsCalls() []struct {
+	Ctx      context.Context
+	Ps       *v1alpha1.Postgres
+	Database string
+	Stmts    []string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Ps       *v1alpha1.Postgres
+		Database string
+		Stmts    []string
+	}
+	lockPostgresSQLRunnerMockExecStatements.RLock()
+	calls = mock.calls.ExecStatements
+	lockPostgresSQLRunnerMockExecStatements.RUnlock()
+	return calls
+}
+
+// GetName calls GetNameFunc.
+func (mock *PostgresSQLRunnerMock) GetName() string {
+	if mock.GetNameFunc == nil {
+		panic("PostgresSQLRunnerMock.GetNameFunc: method is nil but PostgresSQLRunner.GetName was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockPostgresSQLRunnerMockGetName.Lock()
+	mock.calls.GetName = append(mock.calls.GetName, callInfo)
+	lockPostgresSQLRunnerMockGetName.Unlock()
+	return mock.GetNameFunc()
+}
+
+// GetNameCalls gets all the calls that were made to GetName.
+// Check the length with:
+//     len(mockedPostgresSQLRunner.GetNameCalls())
+func (mock *PostgresSQLRunnerMock) GetNameCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockPostgresSQLRunnerMockGetName.RLock()
+	calls = mock.calls.GetName
+	lockPostgresSQLRunnerMockGetName.RUnlock()
+	return calls
+}
+
+// SupportsStrategy calls SupportsStrategyFunc.
+func (mock *PostgresSQLRunnerMock) SupportsStrategy(s string) bool {
+	if mock.SupportsStrategyFunc == nil {
+		panic("PostgresSQLRunnerMock.SupportsStrategyFunc: method is nil but PostgresSQLRunner.SupportsStrategy was just called")
+	}
+	callInfo := struct {
+		S string
+	}{
+		S: s,
+	}
+	lockPostgresSQLRunnerMockSupportsStrategy.Lock()
+	mock.calls.SupportsStrategy = append(mock.calls.SupportsStrategy, callInfo)
+	lockPostgresSQLRunnerMockSupportsStrategy.Unlock()
+	return mock.SupportsStrategyFunc(s)
+}
+
+// SupportsStrategyCalls gets all the calls that were made to SupportsStrategy.
+// Check the length with:
+//     len(mockedPostgresSQLRunner.SupportsStrategyCalls())
+func (mock *PostgresSQLRunnerMock) SupportsStrategyCalls() []struct {
+	S string
+} {
+	var calls []struct {
+		S string
+	}
+	lockPostgresSQLRunnerMockSupportsStrategy.RLock()
+	calls = mock.calls.SupportsStrategy
+	lockPostgresSQLRunnerMockSupportsStrategy.RUnlock()
+	return calls
+}
+
+var (
+	lockPostgresIAMCredentialProviderMockDeleteIAMCredentials    sync.RWMutex
+	lockPostgresIAMCredentialProviderMockGetName                 sync.RWMutex
+	lockPostgresIAMCredentialProviderMockReconcileIAMCredentials sync.RWMutex
+	lockPostgresIAMCredentialProviderMockSupportsStrategy        sync.RWMutex
+)
+
+// Ensure, that PostgresIAMCredentialProviderMock does implement PostgresIAMCredentialProvider.
+// If this is not the case, regenerate this file with moq.
+var _ PostgresIAMCredentialProvider = &PostgresIAMCredentialProviderMock{}
+
+// PostgresIAMCredentialProviderMock is a mock implementation of PostgresIAMCredentialProvider.
+//
+//     func TestSomethingThatUsesPostgresIAMCredentialProvider(t *testing.T) {
+//
+//         // make and configure a mocked PostgresIAMCredentialProvider
+//         mockedPostgresIAMCredentialProvider := &PostgresIAMCredentialProviderMock{
+//             DeleteIAMCredentialsFunc: func(ctx context.Context, pu *v1alpha1.PostgresUser) error {
+// 	               panic("mock out the DeleteIAMCredentials method")
+//             },
+//             GetNameFunc: func() string {
+// 	               panic("mock out the GetName method")
+//             },
+//             ReconcileIAMCredentialsFunc: func(ctx context.Context, ps *v1alpha1.Postgres, pu *v1alpha1.PostgresUser, role string) (*PostgresIAMCredentials, types.StatusMessage, error) {
+// 	               panic("mock out the ReconcileIAMCredentials method")
+//             },
+//             SupportsStrategyFunc: func(s string) bool {
+// 	               panic("mock out the SupportsStrategy method")
+//             },
+//         }
+//
+//         // use mockedPostgresIAMCredentialProvider in code that requires PostgresIAMCredentialProvider
+//         // and then make assertions.
+//
+//     }
+type PostgresIAMCredentialProviderMock struct {
+	// DeleteIAMCredentialsFunc mocks the DeleteIAMCredentials method.
+	DeleteIAMCredentialsFunc func(ctx context.Context, pu *v1alpha1.PostgresUser) error
+
+	// GetNameFunc mocks the GetName method.
+	GetNameFunc func() string
+
+	// ReconcileIAMCredentialsFunc mocks the ReconcileIAMCredentials method.
+	ReconcileIAMCredentialsFunc func(ctx context.Context, ps *v1alpha1.Postgres, pu *v1alpha1.PostgresUser, role string) (*PostgresIAMCredentials, types.StatusMessage, error)
+
+	// SupportsStrategyFunc mocks the SupportsStrategy method.
+	SupportsStrategyFunc func(s string) bool
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// DeleteIAMCredentials holds details about calls to the DeleteIAMCredentials method.
+		DeleteIAMCredentials []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Pu is the pu argument value.
+			Pu *v1alpha1.PostgresUser
+		}
+		// GetName holds details about calls to the GetName method.
+		GetName []struct {
+		}
+		// ReconcileIAMCredentials holds details about calls to the ReconcileIAMCredentials method.
+		ReconcileIAMCredentials []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Ps is the ps argument value.
+			Ps *v1alpha1.Postgres
+			// Pu is the pu argument value.
+			Pu *v1alpha1.PostgresUser
+			// Role is the role argument value.
+			Role string
+		}
+		// SupportsStrategy holds details about calls to the SupportsStrategy method.
+		SupportsStrategy []struct {
+			// S is the s argument value.
+			S string
+		}
+	}
+}
+
+// DeleteIAMCredentials calls DeleteIAMCredentialsFunc.
+func (mock *PostgresIAMCredentialProviderMock) DeleteIAMCredentials(ctx context.Context, pu *v1alpha1.PostgresUser) error {
+	if mock.DeleteIAMCredentialsFunc == nil {
+		panic("PostgresIAMCredentialProviderMock.DeleteIAMCredentialsFunc: method is nil but PostgresIAMCredentialProvider.DeleteIAMCredentials was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Pu  *v1alpha1.PostgresUser
+	}{
+		Ctx: ctx,
+		Pu:  pu,
+	}
+	lockPostgresIAMCredentialProviderMockDeleteIAMCredentials.Lock()
+	mock.calls.DeleteIAMCredentials = append(mock.calls.DeleteIAMCredentials, callInfo)
+	lockPostgresIAMCredentialProviderMockDeleteIAMCredentials.Unlock()
+	return mock.DeleteIAMCredentialsFunc(ctx, pu)
+}
+
+// DeleteIAMCredentialsCalls gets all the calls that were made to DeleteIAMCredentials.
+// Check the length with:
+//     len(mockedPostgresIAMCredentialProvider.DeleteIAMCredentialsCalls())
+func (mock *PostgresIAMCredentialProviderMock) DeleteIAMCredentialsCalls() []struct {
+	Ctx context.Context
+	Pu  *v1alpha1.PostgresUser
+} {
+	var calls []struct {
+		Ctx context.Context
+		Pu  *v1alpha1.PostgresUser
+	}
+	lockPostgresIAMCredentialProviderMockDeleteIAMCredentials.RLock()
+	calls = mock.calls.DeleteIAMCredentials
+	lockPostgresIAMCredentialProviderMockDeleteIAMCredentials.RUnlock()
+	return calls
+}
+
+// GetName calls GetNameFunc.
+func (mock *PostgresIAMCredentialProviderMock) GetName() string {
+	if mock.GetNameFunc == nil {
+		panic("PostgresIAMCredentialProviderMock.GetNameFunc: method is nil but PostgresIAMCredentialProvider.GetName was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockPostgresIAMCredentialProviderMockGetName.Lock()
+	mock.calls.GetName = append(mock.calls.GetName, callInfo)
+	lockPostgresIAMCredentialProviderMockGetName.Unlock()
+	return mock.GetNameFunc()
+}
+
+// GetNameCalls gets all the calls that were made to GetName.
+// Check the length with:
+//     len(mockedPostgresIAMCredentialProvider.GetNameCalls())
+func (mock *PostgresIAMCredentialProviderMock) GetNameCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockPostgresIAMCredentialProviderMockGetName.RLock()
+	calls = mock.calls.GetName
+	lockPostgresIAMCredentialProviderMockGetName.RUnlock()
+	return calls
+}
+
+// ReconcileIAMCredentials calls ReconcileIAMCredentialsFunc.
+func (mock *PostgresIAMCredentialProviderMock) ReconcileIAMCredentials(ctx context.Context, ps *v1alpha1.Postgres, pu *v1alpha1.PostgresUser, role string) (*PostgresIAMCredentials, types.StatusMessage, error) {
+	if mock.ReconcileIAMCredentialsFunc == nil {
+		panic("PostgresIAMCredentialProviderMock.ReconcileIAMCredentialsFunc: method is nil but PostgresIAMCredentialProvider.ReconcileIAMCredentials was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Ps   *v1alpha1.Postgres
+		Pu   *v1alpha1.PostgresUser
+		Role string
+	}{
+		Ctx:  ctx,
+		Ps:   ps,
+		Pu:   pu,
+		Role: role,
+	}
+	lockPostgresIAMCredentialProviderMockReconcileIAMCredentials.Lock()
+	mock.calls.ReconcileIAMCredentials = append(mock.calls.ReconcileIAMCredentials, callInfo)
+	lockPostgresIAMCredentialProviderMockReconcileIAMCredentials.Unlock()
+	return mock.ReconcileIAMCredentialsFunc(ctx, ps, pu, role)
+}
+
+// ReconcileIAMCredentialsCalls gets all the calls that were made to ReconcileIAMCredentials.
+// Check the length with:
+//     len(mockedPostgresIAMCredentialProvider.ReconcileIAMCredentialsCalls())
+func (mock *PostgresIAMCredentialProviderMock) ReconcileIAMCredentialsCalls() []struct {
+	Ctx  context.Context
+	Ps   *v1alpha1.Postgres
+	Pu   *v1alpha1.PostgresUser
+	Role string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Ps   *v1alpha1.Postgres
+		Pu   *v1alpha1.PostgresUser
+		Role string
+	}
+	lockPostgresIAMCredentialProviderMockReconcileIAMCredentials.RLock()
+	calls = mock.calls.ReconcileIAMCredentials
+	lockPostgresIAMCredentialProviderMockReconcileIAMCredentials.RUnlock()
+	return calls
+}
+
+// SupportsStrategy calls SupportsStrategyFunc.
+func (mock *PostgresIAMCredentialProviderMock) SupportsStrategy(s string) bool {
+	if mock.SupportsStrategyFunc == nil {
+		panic("PostgresIAMCredentialProviderMock.SupportsStrategyFunc: method is nil but PostgresIAMCredentialProvider.SupportsStrategy was just called")
+	}
+	callInfo := struct {
+		S string
+	}{
+		S: s,
+	}
+	lockPostgresIAMCredentialProviderMockSupportsStrategy.Lock()
+	mock.calls.SupportsStrategy = append(mock.calls.SupportsStrategy, callInfo)
+	lockPostgresIAMCredentialProviderMockSupportsStrategy.Unlock()
+	return mock.SupportsStrategyFunc(s)
+}
+
+// SupportsStrategyCalls gets all the calls that were made to SupportsStrategy.
+// Check the length with:
+//     len(mockedPostgresIAMCredentialProvider.SupportsStrategyCalls())
+func (mock *PostgresIAMCredentialProviderMock) SupportsStrategyCalls() []struct {
+	S string
+} {
+	var calls []struct {
+		S string
+	}
+	lockPostgresIAMCredentialProviderMockSupportsStrategy.RLock()
+	calls = mock.calls.SupportsStrategy
+	lockPostgresIAMCredentialProviderMockSupportsStrategy.RUnlock()
+	return calls
+}
@@ -0,0 +1,18 @@
+package resources
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AdoptAnnotation can be set on a resource to adopt an existing cloud resource with the given identifier instead of
+// creating a new one. the adopt identifier in the spec takes precedence over the annotation
+const AdoptAnnotation = "adoptResourceIdentifier"
+
+// GetAdoptIdentifier returns the identifier of the existing cloud resource a resource adopts, or an empty string if it
+// does not adopt one
+func GetAdoptIdentifier(obj metav1.Object, specIdentifier string) string {
+	if specIdentifier != "" {
+		return specIdentifier
+	}
+	return obj.GetAnnotations()[AdoptAnnotation]
+}
@@ -0,0 +1,57 @@
+package resources
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetAdoptIdentifier(t *testing.T) {
+	type args struct {
+		obj            metav1.Object
+		specIdentifier string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "test no identifier when not adopting",
+			args: args{
+				obj: &metav1.ObjectMeta{},
+			},
+			want: "",
+		},
+		{
+			name: "test identifier from spec",
+			args: args{
+				obj:            &metav1.ObjectMeta{},
+				specIdentifier: "spec-id",
+			},
+			want: "spec-id",
+		},
+		{
+			name: "test identifier from annotation",
+			args: args{
+				obj: &metav1.ObjectMeta{Annotations: map[string]string{AdoptAnnotation: "annotation-id"}},
+			},
+			want: "annotation-id",
+		},
+		{
+			name: "test spec identifier takes precedence over annotation",
+			args: args{
+				obj:            &metav1.ObjectMeta{Annotations: map[string]string{AdoptAnnotation: "annotation-id"}},
+				specIdentifier: "spec-id",
+			},
+			want: "spec-id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetAdoptIdentifier(tt.args.obj, tt.args.specIdentifier); got != tt.want {
+				t.Errorf("GetAdoptIdentifier() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
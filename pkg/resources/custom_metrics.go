@@ -27,6 +27,12 @@ const (
 	DefaultRedisInfoMetricName           = "cro_redis_info"
 	DefaultRedisAvailMetricName          = "cro_redis_available"
 	DefaultRedisConnectionMetricName     = "cro_redis_connection"
+	DefaultBlobStorageAvailMetricName    = "cro_blobstorage_available"
+	DefaultBlobStorageObjectsMetricName  = "cro_blobstorage_objects"
+	DefaultBlobStorageBytesMetricName    = "cro_blobstorage_size_bytes"
+	DefaultBlobStorageAgeMetricName      = "cro_blobstorage_last_modified_age_seconds"
+	DefaultDryRunMetricName              = "cro_dry_run_planned_action"
+	DefaultResourceDriftMetricName       = "cro_resource_drift"
 )
 
 var (
@@ -0,0 +1,60 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// deletion policies determine what happens to the cloud resource of a resource when the resource is deleted. if no
+// deletion policy is set the cloud resource is deleted as described by the delete strategy of the tier
+const (
+	// DeletionPolicyDelete deletes the cloud resource without a final snapshot
+	DeletionPolicyDelete = "Delete"
+	// DeletionPolicySnapshot deletes the cloud resource after taking a final snapshot
+	DeletionPolicySnapshot = "Snapshot"
+	// DeletionPolicyRetain leaves the cloud resource in place and records it in the inventory
+	DeletionPolicyRetain = "Retain"
+	// DeletionPolicyDelayedDelete records the cloud resource in the inventory and deletes it once the deletion grace
+	// period has passed
+	DeletionPolicyDelayedDelete = "DelayedDelete"
+
+	DefaultDeletionGracePeriod = time.Hour * 24 * 7
+)
+
+// ValidateDeletionPolicy verifies a deletion policy is one of the policies supported by a provider, so an unsupported
+// policy is reported while the resource is reconciled rather than when it is deleted. no deletion policy is always valid
+func ValidateDeletionPolicy(policy string, supported ...string) error {
+	if policy == "" {
+		return nil
+	}
+	for _, s := range supported {
+		if policy == s {
+			return nil
+		}
+	}
+	return fmt.Errorf("deletion policy %s is not supported, expected one of %s", policy, strings.Join(supported, ", "))
+}
+
+// GetDelayedDeletionTime returns the time the cloud resource of a resource with the DelayedDelete deletion policy is
+// deleted at, which is the deletion grace period after the resource was deleted
+func GetDelayedDeletionTime(obj metav1.Object, gracePeriod string) (time.Time, error) {
+	d := DefaultDeletionGracePeriod
+	if gracePeriod != "" {
+		var err error
+		if d, err = time.ParseDuration(gracePeriod); err != nil {
+			return time.Time{}, errors.Wrapf(err, "failed to parse deletion grace period %s", gracePeriod)
+		}
+		if d < 0 {
+			return time.Time{}, errors.New("deletion grace period must not be negative")
+		}
+	}
+	deletedAt := obj.GetDeletionTimestamp()
+	if deletedAt == nil {
+		return time.Time{}, fmt.Errorf("resource %s in namespace %s is not being deleted", obj.GetName(), obj.GetNamespace())
+	}
+	return deletedAt.Add(d), nil
+}
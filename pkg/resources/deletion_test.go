@@ -0,0 +1,97 @@
+package resources
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetDelayedDeletionTime(t *testing.T) {
+	deletedAt := metav1.NewTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	type args struct {
+		obj         metav1.Object
+		gracePeriod string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name: "test default grace period is used when none is set",
+			args: args{
+				obj: &metav1.ObjectMeta{DeletionTimestamp: &deletedAt},
+			},
+			want: deletedAt.Add(DefaultDeletionGracePeriod),
+		},
+		{
+			name: "test grace period is added to deletion time",
+			args: args{
+				obj:         &metav1.ObjectMeta{DeletionTimestamp: &deletedAt},
+				gracePeriod: "72h",
+			},
+			want: deletedAt.Add(time.Hour * 72),
+		},
+		{
+			name: "test error when grace period is invalid",
+			args: args{
+				obj:         &metav1.ObjectMeta{DeletionTimestamp: &deletedAt},
+				gracePeriod: "3d",
+			},
+			wantErr: true,
+		},
+		{
+			name: "test error when resource is not being deleted",
+			args: args{
+				obj: &metav1.ObjectMeta{},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetDelayedDeletionTime(tt.args.obj, tt.args.gracePeriod)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDelayedDeletionTime() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("GetDelayedDeletionTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateDeletionPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    string
+		supported []string
+		wantErr   bool
+	}{
+		{
+			name:      "test no deletion policy is valid",
+			supported: []string{DeletionPolicyDelete},
+		},
+		{
+			name:      "test supported deletion policy is valid",
+			policy:    DeletionPolicyRetain,
+			supported: []string{DeletionPolicyDelete, DeletionPolicyRetain},
+		},
+		{
+			name:      "test error when deletion policy is not supported",
+			policy:    DeletionPolicySnapshot,
+			supported: []string{DeletionPolicyDelete, DeletionPolicyRetain},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateDeletionPolicy(tt.policy, tt.supported...); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateDeletionPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package resources
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"strconv"
+	"sync"
+
+	errorUtil "github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// EnvDryRun enables dry run mode for the whole operator when set to true. in dry run mode the actions providers
+// would take against the cloud provider are reported instead of performed
+const EnvDryRun = "DRY_RUN"
+
+type dryRunRecorderKey struct{}
+
+// DryRunRecorder records the actions planned against a cloud provider while reconciling a resource in dry run mode
+type DryRunRecorder struct {
+	mu      sync.Mutex
+	actions []string
+}
+
+// IsDryRun returns true if the operator is running in dry run mode
+func IsDryRun() bool {
+	dryRun, err := strconv.ParseBool(os.Getenv(EnvDryRun))
+	return err == nil && dryRun
+}
+
+// NewDryRunContext returns a context which records the actions planned while reconciling a resource with it in dry run
+// mode
+func NewDryRunContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunRecorderKey{}, &DryRunRecorder{})
+}
+
+// GetDryRunRecorder returns the recorder of a context created with NewDryRunContext, or nil
+func GetDryRunRecorder(ctx context.Context) *DryRunRecorder {
+	recorder, _ := ctx.Value(dryRunRecorderKey{}).(*DryRunRecorder)
+	return recorder
+}
+
+// Record records a planned action, actions which have already been recorded are ignored
+func (r *DryRunRecorder) Record(action string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if Contains(r.actions, action) {
+		return
+	}
+	r.actions = append(r.actions, action)
+}
+
+// RecordDryRunAction records an action which is skipped in dry run mode in the recorder of the context, if any. it is
+// used for changes made outside of the cloud provider sdks, such as credentials requests or sql statements
+func RecordDryRunAction(ctx context.Context, action string) {
+	logrus.Infof("dry run, skipping %s", action)
+	if recorder := GetDryRunRecorder(ctx); recorder != nil {
+		recorder.Record(action)
+	}
+}
+
+// Actions returns the recorded actions in the order they were planned
+func (r *DryRunRecorder) Actions() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.actions...)
+}
+
+// ReportDryRun sets the actions recorded for a resource in dry run mode in its status, to be persisted with its phase,
+// and exposes them as metrics. planned actions are cleared when not in dry run mode
+func ReportDryRun(ctx context.Context, inst runtime.Object) error {
+	var actions []string
+	recorder := GetDryRunRecorder(ctx)
+	if IsDryRun() && recorder != nil {
+		actions = recorder.Actions()
+	}
+	rts, err := getResourceTypeStatus(inst)
+	if err != nil {
+		return errorUtil.Wrap(err, "failed to retrieve status block from object")
+	}
+	rts.PlannedActions = actions
+
+	metaObj, err := meta.Accessor(inst)
+	if err != nil {
+		return errorUtil.Wrap(err, "failed to retrieve metadata of object")
+	}
+	for _, action := range actions {
+		SetMetric(DefaultDryRunMetricName, map[string]string{
+			"kind":      reflect.TypeOf(inst).Elem().Name(),
+			"namespace": metaObj.GetNamespace(),
+			"name":      metaObj.GetName(),
+			"action":    action,
+		}, 1)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package resources
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1"
+	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDryRunRecorder(t *testing.T) {
+	ctx := NewDryRunContext(context.TODO())
+	recorder := GetDryRunRecorder(ctx)
+	if recorder == nil {
+		t.Fatal("GetDryRunRecorder() returned nil for a dry run context")
+	}
+	recorder.Record("rds:CreateDBInstance")
+	recorder.Record("rds:AddTagsToResource")
+	recorder.Record("rds:CreateDBInstance")
+	want := []string{"rds:CreateDBInstance", "rds:AddTagsToResource"}
+	if got := recorder.Actions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Actions() = %v, want %v", got, want)
+	}
+	if GetDryRunRecorder(context.TODO()) != nil {
+		t.Error("GetDryRunRecorder() returned a recorder for a context without one")
+	}
+}
+
+func TestReportDryRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		dryRun  string
+		actions []string
+		want    []string
+	}{
+		{
+			name:    "test planned actions are reported in dry run mode",
+			dryRun:  "true",
+			actions: []string{"elasticache:CreateReplicationGroup"},
+			want:    []string{"elasticache:CreateReplicationGroup"},
+		},
+		{
+			name:    "test planned actions are cleared when not in dry run mode",
+			dryRun:  "false",
+			actions: []string{"elasticache:CreateReplicationGroup"},
+			want:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.Setenv(EnvDryRun, tt.dryRun); err != nil {
+				t.Fatal("failed to set dry run env var", err)
+			}
+			defer os.Unsetenv(EnvDryRun)
+			ctx := NewDryRunContext(context.TODO())
+			for _, action := range tt.actions {
+				GetDryRunRecorder(ctx).Record(action)
+			}
+			redis := &v1alpha1.Redis{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+			}
+			redis.Status.PlannedActions = []string{"elasticache:DeleteReplicationGroup"}
+			if err := ReportDryRun(ctx, redis); err != nil {
+				t.Fatal("ReportDryRun() returned an error", err)
+			}
+			if !reflect.DeepEqual(redis.Status.PlannedActions, tt.want) {
+				t.Errorf("ReportDryRun() planned actions = %v, want %v", redis.Status.PlannedActions, tt.want)
+			}
+		})
+	}
+}
+
+func TestReportDryRunEmbeddedStatus(t *testing.T) {
+	if err := os.Setenv(EnvDryRun, "true"); err != nil {
+		t.Fatal("failed to set dry run env var", err)
+	}
+	defer os.Unsetenv(EnvDryRun)
+	ctx := NewDryRunContext(context.TODO())
+	GetDryRunRecorder(ctx).Record("s3:CreateBucket")
+	bs := &v1alpha1.BlobStorage{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+	}
+	bs.Status.Deletion = &croType.ResourceDeletionStatus{DeletedObjects: 1}
+	if err := ReportDryRun(ctx, bs); err != nil {
+		t.Fatal("ReportDryRun() returned an error", err)
+	}
+	if want := []string{"s3:CreateBucket"}; !reflect.DeepEqual(bs.Status.PlannedActions, want) {
+		t.Errorf("ReportDryRun() planned actions = %v, want %v", bs.Status.PlannedActions, want)
+	}
+	if bs.Status.Deletion == nil || bs.Status.Deletion.DeletedObjects != 1 {
+		t.Errorf("ReportDryRun() changed the deletion status to %v", bs.Status.Deletion)
+	}
+}
@@ -0,0 +1,100 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	errorUtil "github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// InventoryConfigMapName is the name of the config map in which cloud resources which are no longer managed by a
+// resource, because they were retained when the resource was deleted, are recorded
+const InventoryConfigMapName = "cloud-resources-inventory"
+
+// InventoryEntry describes a cloud resource recorded in the inventory
+type InventoryEntry struct {
+	// ResourceType is the type of the resource the cloud resource belonged to, e.g. postgres
+	ResourceType string `json:"resourceType"`
+	// Identifier is the identifier of the cloud resource
+	Identifier string `json:"identifier"`
+	// Region is the region of the cloud resource
+	Region string `json:"region,omitempty"`
+	// Name is the name of the deleted resource the cloud resource belonged to
+	Name string `json:"name"`
+	// DeletionPolicy is the deletion policy of the deleted resource
+	DeletionPolicy string `json:"deletionPolicy"`
+	// RetainedAt is the time the resource was deleted at, in RFC3339 format
+	RetainedAt string `json:"retainedAt"`
+	// DeleteAfter is the time the cloud resource is deleted after, in RFC3339 format. cloud resources which are retained
+	// indefinitely do not have one
+	DeleteAfter string `json:"deleteAfter,omitempty"`
+}
+
+func buildInventoryKey(resourceType, identifier string) string {
+	return fmt.Sprintf("%s.%s", resourceType, identifier)
+}
+
+// AddToInventory records a cloud resource in the inventory of a namespace, replacing any existing entry for it
+func AddToInventory(ctx context.Context, c client.Client, ns string, entry InventoryEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return errorUtil.Wrapf(err, "failed to marshal inventory entry for %s", entry.Identifier)
+	}
+	cm := &v1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Name: InventoryConfigMapName, Namespace: ns}, cm); err != nil {
+		if !k8serr.IsNotFound(err) {
+			return errorUtil.Wrapf(err, "failed to get inventory in namespace %s", ns)
+		}
+		cm = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      InventoryConfigMapName,
+				Namespace: ns,
+			},
+			Data: map[string]string{
+				buildInventoryKey(entry.ResourceType, entry.Identifier): string(data),
+			},
+		}
+		if err := c.Create(ctx, cm); err != nil {
+			return errorUtil.Wrapf(err, "failed to create inventory in namespace %s", ns)
+		}
+		return nil
+	}
+	key := buildInventoryKey(entry.ResourceType, entry.Identifier)
+	if cm.Data[key] == string(data) {
+		return nil
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[key] = string(data)
+	if err := c.Update(ctx, cm); err != nil {
+		return errorUtil.Wrapf(err, "failed to update inventory in namespace %s", ns)
+	}
+	return nil
+}
+
+// RemoveFromInventory removes a cloud resource from the inventory of a namespace, if it is recorded in it
+func RemoveFromInventory(ctx context.Context, c client.Client, ns string, resourceType string, identifier string) error {
+	cm := &v1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Name: InventoryConfigMapName, Namespace: ns}, cm); err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return errorUtil.Wrapf(err, "failed to get inventory in namespace %s", ns)
+	}
+	key := buildInventoryKey(resourceType, identifier)
+	if _, ok := cm.Data[key]; !ok {
+		return nil
+	}
+	delete(cm.Data, key)
+	if err := c.Update(ctx, cm); err != nil {
+		return errorUtil.Wrapf(err, "failed to update inventory in namespace %s", ns)
+	}
+	return nil
+}
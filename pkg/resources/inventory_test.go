@@ -0,0 +1,56 @@
+package resources
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestInventory(t *testing.T) {
+	ctx := context.TODO()
+	c := fake.NewFakeClient()
+	entry := InventoryEntry{
+		ResourceType:   "postgres",
+		Identifier:     "test-id",
+		Name:           "test",
+		DeletionPolicy: DeletionPolicyRetain,
+		RetainedAt:     "2020-01-01T00:00:00Z",
+	}
+
+	// removing an entry from a missing inventory is a no-op
+	if err := RemoveFromInventory(ctx, c, "test", entry.ResourceType, entry.Identifier); err != nil {
+		t.Fatal("RemoveFromInventory() returned an error", err)
+	}
+
+	if err := AddToInventory(ctx, c, "test", entry); err != nil {
+		t.Fatal("AddToInventory() returned an error", err)
+	}
+	cm := &v1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Name: InventoryConfigMapName, Namespace: "test"}, cm); err != nil {
+		t.Fatal("failed to get inventory", err)
+	}
+	if _, ok := cm.Data["postgres.test-id"]; !ok {
+		t.Errorf("AddToInventory() did not record entry, got %v", cm.Data)
+	}
+
+	entry.Identifier = "other-id"
+	if err := AddToInventory(ctx, c, "test", entry); err != nil {
+		t.Fatal("AddToInventory() returned an error", err)
+	}
+	if err := RemoveFromInventory(ctx, c, "test", "postgres", "test-id"); err != nil {
+		t.Fatal("RemoveFromInventory() returned an error", err)
+	}
+	cm = &v1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Name: InventoryConfigMapName, Namespace: "test"}, cm); err != nil {
+		t.Fatal("failed to get inventory", err)
+	}
+	if _, ok := cm.Data["postgres.test-id"]; ok {
+		t.Errorf("RemoveFromInventory() did not remove entry, got %v", cm.Data)
+	}
+	if _, ok := cm.Data["postgres.other-id"]; !ok {
+		t.Errorf("RemoveFromInventory() removed other entry, got %v", cm.Data)
+	}
+}
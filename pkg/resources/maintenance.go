@@ -0,0 +1,73 @@
+package resources
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/integr8ly/cloud-resource-operator/pkg/annotations"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ApplyMaintenanceAnnotation can be set on a resource to apply its pending maintenance on the next reconcile, outside of
+// its maintenance window. it is removed once the maintenance has been applied
+const ApplyMaintenanceAnnotation = "applyPendingMaintenance"
+
+var maintenanceWindowDays = map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}
+
+// IsMaintenanceAllowed returns true if pending maintenance of a resource has been approved through an annotation, or
+// if t is inside the maintenance window of the resource. maintenance is only applied on approval if no window is set
+func IsMaintenanceAllowed(obj metav1.Object, window string, t time.Time) (bool, error) {
+	if annotations.Has(obj, ApplyMaintenanceAnnotation) {
+		return true, nil
+	}
+	if window == "" {
+		return false, nil
+	}
+	return IsInMaintenanceWindow(window, t)
+}
+
+// IsInMaintenanceWindow returns true if t is inside a weekly window in the format ddd:hh24:mi-ddd:hh24:mi, as used by
+// aws for maintenance windows. windows are in UTC and can wrap around the end of the week
+func IsInMaintenanceWindow(window string, t time.Time) (bool, error) {
+	parts := strings.Split(window, "-")
+	if len(parts) != 2 {
+		return false, errors.New(fmt.Sprintf("invalid maintenance window %s, expected format ddd:hh24:mi-ddd:hh24:mi", window))
+	}
+	start, err := parseMinuteOfWeek(parts[0])
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid maintenance window %s", window)
+	}
+	end, err := parseMinuteOfWeek(parts[1])
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid maintenance window %s", window)
+	}
+	t = t.UTC()
+	now := int(t.Weekday())*24*60 + t.Hour()*60 + t.Minute()
+	if start <= end {
+		return now >= start && now < end, nil
+	}
+	return now >= start || now < end, nil
+}
+
+func parseMinuteOfWeek(s string) (int, error) {
+	parts := strings.Split(strings.ToLower(s), ":")
+	if len(parts) != 3 {
+		return 0, errors.New(fmt.Sprintf("invalid time %s, expected format ddd:hh24:mi", s))
+	}
+	day, ok := maintenanceWindowDays[parts[0]]
+	if !ok {
+		return 0, errors.New(fmt.Sprintf("invalid day %s", parts[0]))
+	}
+	hour, err := strconv.Atoi(parts[1])
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, errors.New(fmt.Sprintf("invalid hour %s", parts[1]))
+	}
+	minute, err := strconv.Atoi(parts[2])
+	if err != nil || minute < 0 || minute > 59 {
+		return 0, errors.New(fmt.Sprintf("invalid minute %s", parts[2]))
+	}
+	return day*24*60 + hour*60 + minute, nil
+}
@@ -0,0 +1,107 @@
+package resources
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsMaintenanceAllowed(t *testing.T) {
+	// a wednesday
+	now := time.Date(2019, 11, 13, 10, 30, 0, 0, time.UTC)
+	type args struct {
+		obj    metav1.Object
+		window string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "test maintenance is allowed when annotation is set",
+			args: args{
+				obj:    &metav1.ObjectMeta{Annotations: map[string]string{ApplyMaintenanceAnnotation: "true"}},
+				window: "sun:01:00-sun:02:00",
+			},
+			want: true,
+		},
+		{
+			name: "test maintenance is not allowed without a window",
+			args: args{
+				obj: &metav1.ObjectMeta{},
+			},
+			want: false,
+		},
+		{
+			name: "test maintenance is allowed inside the window",
+			args: args{
+				obj:    &metav1.ObjectMeta{},
+				window: "wed:10:00-wed:11:00",
+			},
+			want: true,
+		},
+		{
+			name: "test maintenance is not allowed at the end of the window",
+			args: args{
+				obj:    &metav1.ObjectMeta{},
+				window: "wed:09:30-wed:10:30",
+			},
+			want: false,
+		},
+		{
+			name: "test maintenance is allowed inside a window spanning days",
+			args: args{
+				obj:    &metav1.ObjectMeta{},
+				window: "tue:23:00-thu:01:00",
+			},
+			want: true,
+		},
+		{
+			name: "test maintenance is allowed inside a window wrapping the end of the week",
+			args: args{
+				obj:    &metav1.ObjectMeta{},
+				window: "sat:22:00-Wed:11:00",
+			},
+			want: true,
+		},
+		{
+			name: "test maintenance is not allowed outside a window wrapping the end of the week",
+			args: args{
+				obj:    &metav1.ObjectMeta{},
+				window: "sat:22:00-sun:02:00",
+			},
+			want: false,
+		},
+		{
+			name: "test error when window is invalid",
+			args: args{
+				obj:    &metav1.ObjectMeta{},
+				window: "wed:25:00-wed:26:00",
+			},
+			wantErr: true,
+		},
+		{
+			name: "test error when window has no end",
+			args: args{
+				obj:    &metav1.ObjectMeta{},
+				window: "wed:10:00",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IsMaintenanceAllowed(tt.args.obj, tt.args.window, now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IsMaintenanceAllowed() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("IsMaintenanceAllowed() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
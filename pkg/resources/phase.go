@@ -2,6 +2,7 @@ package resources
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 
 	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
@@ -18,21 +19,35 @@ func UpdatePhase(ctx context.Context, client client.Client, inst runtime.Object,
 	if msg == croType.StatusEmpty {
 		return nil
 	}
-	rts := &croType.ResourceTypeStatus{}
-	if err := runtime.Field(reflect.ValueOf(inst).Elem(), "Status", rts); err != nil {
+	rts, err := getResourceTypeStatus(inst)
+	if err != nil {
 		return errorUtil.Wrap(err, "failed to retrieve status block from object")
 	}
 	rts.Message = msg
 	rts.Phase = phase
-	if err := runtime.SetField(*rts, reflect.ValueOf(inst).Elem(), "Status"); err != nil {
-		return errorUtil.Wrap(err, "failed to set status block of object")
-	}
 	if err := client.Status().Update(ctx, inst); err != nil {
 		return errorUtil.Wrap(err, "failed to update resource status phase and message")
 	}
 	return nil
 }
 
+// getResourceTypeStatus returns the basic status block of a resource, which is either its status or embedded in its
+// status by resources with additional status fields. changes to the returned status are made to the resource
+func getResourceTypeStatus(inst runtime.Object) (*croType.ResourceTypeStatus, error) {
+	status := reflect.ValueOf(inst).Elem().FieldByName("Status")
+	if !status.IsValid() {
+		return nil, errorUtil.New("object has no status block")
+	}
+	if embedded := status.FieldByName("ResourceTypeStatus"); embedded.IsValid() {
+		status = embedded
+	}
+	rtsType := reflect.TypeOf(croType.ResourceTypeStatus{})
+	if !status.Type().ConvertibleTo(rtsType) {
+		return nil, fmt.Errorf("status block of type %s is not a resource type status", status.Type())
+	}
+	return status.Addr().Convert(reflect.PtrTo(rtsType)).Interface().(*croType.ResourceTypeStatus), nil
+}
+
 //UpdateSnapshotPhase Updates the snapshot custom resource with the current phase
 func UpdateSnapshotPhase(ctx context.Context, client client.Client, inst runtime.Object, phase croType.StatusPhase, msg croType.StatusMessage) error {
 	if msg == croType.StatusEmpty {
@@ -0,0 +1,33 @@
+package resources
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ApprovePlanAnnotation can be set on a resource to the hash of its pending plan to apply the planned changes on the
+// next reconcile. it is removed once the plan has been applied
+const ApprovePlanAnnotation = "approvePlan"
+
+// planHashLength is the number of hex characters of the hash identifying a plan
+const planHashLength = 16
+
+// BuildPlanHash returns a short hash identifying a planned change, which changes whenever the planned change does
+func BuildPlanHash(plan interface{}) (string, error) {
+	b, err := json.Marshal(plan)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal plan")
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])[:planHashLength], nil
+}
+
+// IsPlanApproved returns true if the plan with the given hash has been approved through an annotation
+func IsPlanApproved(obj metav1.Object, hash string) bool {
+	approved, ok := obj.GetAnnotations()[ApprovePlanAnnotation]
+	return ok && hash != "" && approved == hash
+}
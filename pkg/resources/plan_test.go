@@ -0,0 +1,63 @@
+package resources
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBuildPlanHash(t *testing.T) {
+	type plan struct {
+		InstanceClass string
+	}
+	hash, err := BuildPlanHash(plan{InstanceClass: "db.t3.small"})
+	if err != nil {
+		t.Fatal("failed to build plan hash", err)
+	}
+	if len(hash) != planHashLength {
+		t.Errorf("BuildPlanHash() length = %d, want %d", len(hash), planHashLength)
+	}
+	sameHash, _ := BuildPlanHash(plan{InstanceClass: "db.t3.small"})
+	if sameHash != hash {
+		t.Errorf("BuildPlanHash() is not stable, got %s and %s", hash, sameHash)
+	}
+	otherHash, _ := BuildPlanHash(plan{InstanceClass: "db.t3.medium"})
+	if otherHash == hash {
+		t.Errorf("BuildPlanHash() is the same for different plans")
+	}
+}
+
+func TestIsPlanApproved(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  metav1.Object
+		hash string
+		want bool
+	}{
+		{
+			name: "test plan is not approved without annotation",
+			obj:  &metav1.ObjectMeta{},
+			hash: "abc",
+			want: false,
+		},
+		{
+			name: "test plan is approved when annotation matches hash",
+			obj:  &metav1.ObjectMeta{Annotations: map[string]string{ApprovePlanAnnotation: "abc"}},
+			hash: "abc",
+			want: true,
+		},
+		{
+			name: "test plan is not approved when annotation is for another plan",
+			obj:  &metav1.ObjectMeta{Annotations: map[string]string{ApprovePlanAnnotation: "def"}},
+			hash: "abc",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPlanApproved(tt.obj, tt.hash); got != tt.want {
+				t.Errorf("IsPlanApproved() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,8 +2,10 @@ package resources
 
 import (
 	"context"
+	"fmt"
 	croType "github.com/integr8ly/cloud-resource-operator/pkg/apis/integreatly/v1alpha1/types"
 	"reflect"
+	"sort"
 
 	"github.com/pkg/errors"
 
@@ -11,6 +13,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	controllerruntime "sigs.k8s.io/controller-runtime"
@@ -32,19 +35,77 @@ func NewResourceProvider(c client.Client, s *runtime.Scheme, l *logrus.Entry) *R
 }
 
 func (r *ReconcileResourceProvider) ReconcileResultSecret(ctx context.Context, o runtime.Object, d map[string][]byte) error {
-	obj := o.(metav1.Object)
-	secNs := obj.GetNamespace()
-	rts := &croType.ResourceTypeSpec{}
-	if err := runtime.Field(reflect.ValueOf(o).Elem(), "Spec", rts); err != nil {
-		return errors.Wrap(err, "failed to retrieve secret reference from instance")
+	secretRef, err := getSecretRef(o)
+	if err != nil {
+		return err
+	}
+	return r.reconcileSecret(ctx, o, secretRef, d)
+}
+
+// ReconcileCredentialSecrets writes the data of each named credential of a resource to its own secret, named after the
+// result secret with the name of the credential as suffix. the secrets of previous credentials which are no longer
+// requested are deleted, the secrets of the credentials are returned in order of name
+func (r *ReconcileResourceProvider) ReconcileCredentialSecrets(ctx context.Context, o runtime.Object, credentials map[string]map[string][]byte, previous []croType.ResourceCredentialStatus) ([]croType.ResourceCredentialStatus, error) {
+	secretRef, err := getSecretRef(o)
+	if err != nil {
+		return nil, err
 	}
-	if rts.SecretRef.Namespace != "" {
-		secNs = rts.SecretRef.Namespace
+	names := make([]string, 0, len(credentials))
+	for name := range credentials {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+	var statuses []croType.ResourceCredentialStatus
+	for _, name := range names {
+		credentialRef := &croType.SecretRef{
+			Name:      fmt.Sprintf("%s-%s", secretRef.Name, name),
+			Namespace: secretRef.Namespace,
+		}
+		if err := r.reconcileSecret(ctx, o, credentialRef, credentials[name]); err != nil {
+			return nil, errors.Wrapf(err, "failed to reconcile secret of credential %s", name)
+		}
+		statuses = append(statuses, croType.ResourceCredentialStatus{Name: name, SecretRef: *credentialRef})
+	}
+	for _, p := range previous {
+		if _, ok := credentials[p.Name]; ok {
+			continue
+		}
+		sec := &v1.Secret{
+			ObjectMeta: controllerruntime.ObjectMeta{
+				Name:      p.SecretRef.Name,
+				Namespace: p.SecretRef.Namespace,
+			},
+		}
+		if err := r.Client.Delete(ctx, sec); err != nil && !k8serr.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "failed to delete secret %s of credential %s", sec.Name, p.Name)
+		}
+	}
+	return statuses, nil
+}
+
+// getSecretRef returns the secret reference of the spec of a resource, in the namespace of the resource if not set
+func getSecretRef(o runtime.Object) (*croType.SecretRef, error) {
+	// only the secret reference is read, so any spec with a secretRef field can be used
+	secretRef := &croType.SecretRef{}
+	if err := runtime.Field(reflect.ValueOf(o).Elem().FieldByName("Spec"), "SecretRef", &secretRef); err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve secret reference from instance")
+	}
+	ref := &croType.SecretRef{
+		Name:      secretRef.Name,
+		Namespace: secretRef.Namespace,
+	}
+	if ref.Namespace == "" {
+		ref.Namespace = o.(metav1.Object).GetNamespace()
+	}
+	return ref, nil
+}
+
+func (r *ReconcileResourceProvider) reconcileSecret(ctx context.Context, o runtime.Object, secretRef *croType.SecretRef, d map[string][]byte) error {
+	obj := o.(metav1.Object)
 	sec := &v1.Secret{
 		ObjectMeta: controllerruntime.ObjectMeta{
-			Name:      rts.SecretRef.Name,
-			Namespace: secNs,
+			Name:      secretRef.Name,
+			Namespace: secretRef.Namespace,
 		},
 	}
 	_, err := controllerruntime.CreateOrUpdate(ctx, r.Client, sec, func() error {
@@ -0,0 +1,51 @@
+package resources
+
+import (
+	"time"
+
+	"github.com/integr8ly/cloud-resource-operator/pkg/annotations"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RotateCredentialsAnnotation can be set on a resource to rotate its credentials on the next reconcile, it is removed
+// once the rotation is complete. its value can be the time the rotation was requested at, in RFC3339 format, in which
+// case only credentials last rotated before that time are rotated
+const RotateCredentialsAnnotation = "rotateCredentials"
+
+// IsRotationDue returns true if the credentials of a resource have been requested to be rotated through an annotation,
+// and were not rotated since the request, or if the rotation interval has passed since the credentials were last
+// rotated
+func IsRotationDue(obj metav1.Object, interval string, lastRotation time.Time) (bool, error) {
+	if annotations.Has(obj, RotateCredentialsAnnotation) {
+		requestedAt, err := time.Parse(time.RFC3339, obj.GetAnnotations()[RotateCredentialsAnnotation])
+		if err != nil || lastRotation.Before(requestedAt) {
+			return true, nil
+		}
+	}
+	if interval == "" {
+		return false, nil
+	}
+	d, err := time.ParseDuration(interval)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse rotation interval %s", interval)
+	}
+	if d <= 0 {
+		return false, errors.New("rotation interval must be greater than zero")
+	}
+	return time.Since(lastRotation) >= d, nil
+}
+
+// StampRotationRequest sets the value of the annotation requesting a rotation of the credentials of a resource to the
+// current time, unless it is already a time. a resource with several credentials can then be reconciled again after
+// some of them were rotated without rotating them again. returns true if the annotation was changed
+func StampRotationRequest(obj metav1.Object) bool {
+	if !annotations.Has(obj, RotateCredentialsAnnotation) {
+		return false
+	}
+	if _, err := time.Parse(time.RFC3339, obj.GetAnnotations()[RotateCredentialsAnnotation]); err == nil {
+		return false
+	}
+	annotations.Add(obj, RotateCredentialsAnnotation, time.Now().UTC().Format(time.RFC3339))
+	return true
+}
@@ -0,0 +1,133 @@
+package resources
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsRotationDue(t *testing.T) {
+	type args struct {
+		obj          metav1.Object
+		interval     string
+		lastRotation time.Time
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "test rotation is due when annotation is set",
+			args: args{
+				obj:          &metav1.ObjectMeta{Annotations: map[string]string{RotateCredentialsAnnotation: "true"}},
+				lastRotation: time.Now(),
+			},
+			want: true,
+		},
+		{
+			name: "test rotation is due when last rotated before the requested time",
+			args: args{
+				obj:          &metav1.ObjectMeta{Annotations: map[string]string{RotateCredentialsAnnotation: time.Now().UTC().Format(time.RFC3339)}},
+				lastRotation: time.Now().Add(-time.Hour),
+			},
+			want: true,
+		},
+		{
+			name: "test rotation is not due when rotated since the requested time",
+			args: args{
+				obj:          &metav1.ObjectMeta{Annotations: map[string]string{RotateCredentialsAnnotation: time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)}},
+				lastRotation: time.Now(),
+			},
+			want: false,
+		},
+		{
+			name: "test rotation is not due when no interval is set",
+			args: args{
+				obj:          &metav1.ObjectMeta{},
+				lastRotation: time.Now().Add(-time.Hour * 24 * 365),
+			},
+			want: false,
+		},
+		{
+			name: "test rotation is due when interval has passed",
+			args: args{
+				obj:          &metav1.ObjectMeta{},
+				interval:     "1h",
+				lastRotation: time.Now().Add(-time.Hour * 2),
+			},
+			want: true,
+		},
+		{
+			name: "test rotation is not due before interval has passed",
+			args: args{
+				obj:          &metav1.ObjectMeta{},
+				interval:     "720h",
+				lastRotation: time.Now().Add(-time.Hour),
+			},
+			want: false,
+		},
+		{
+			name: "test error when interval is invalid",
+			args: args{
+				obj:      &metav1.ObjectMeta{},
+				interval: "30d",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IsRotationDue(tt.args.obj, tt.args.interval, tt.args.lastRotation)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IsRotationDue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("IsRotationDue() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStampRotationRequest(t *testing.T) {
+	requestedAt := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        bool
+	}{
+		{
+			name: "test nothing is stamped without a request",
+		},
+		{
+			name:        "test request is stamped with the current time",
+			annotations: map[string]string{RotateCredentialsAnnotation: "true"},
+			want:        true,
+		},
+		{
+			name:        "test request time is kept",
+			annotations: map[string]string{RotateCredentialsAnnotation: requestedAt},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &metav1.ObjectMeta{Annotations: tt.annotations}
+			if got := StampRotationRequest(obj); got != tt.want {
+				t.Errorf("StampRotationRequest() got = %v, want %v", got, tt.want)
+			}
+			value, ok := obj.Annotations[RotateCredentialsAnnotation]
+			if !ok {
+				return
+			}
+			if _, err := time.Parse(time.RFC3339, value); err != nil {
+				t.Errorf("StampRotationRequest() annotation = %s, want a time", value)
+			}
+			if !tt.want && value != requestedAt {
+				t.Errorf("StampRotationRequest() annotation = %s, want %s", value, requestedAt)
+			}
+		})
+	}
+}
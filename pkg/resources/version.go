@@ -0,0 +1,58 @@
+package resources
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CompareVersions compares two dot separated numeric versions, e.g. 3.2.6 and 5.0. -1 is returned if a is older than
+// b, 1 if a is newer and 0 if they are equal. missing trailing components are treated as 0, a trailing x matches any
+// remaining components, so 6.x is equal to 6.0.5
+func CompareVersions(a, b string) (int, error) {
+	aParts, aWildcard, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	bParts, bWildcard, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		if (aWildcard && i == len(aParts)) || (bWildcard && i == len(bParts)) {
+			return 0, nil
+		}
+		var aPart, bPart int
+		if i < len(aParts) {
+			aPart = aParts[i]
+		}
+		if i < len(bParts) {
+			bPart = bParts[i]
+		}
+		if aPart < bPart {
+			return -1, nil
+		}
+		if aPart > bPart {
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// parseVersion returns the numeric components of a version, and whether it ends in an x wildcard such as 6.x
+func parseVersion(version string) ([]int, bool, error) {
+	components := strings.Split(version, ".")
+	wildcard := len(components) > 1 && components[len(components)-1] == "x"
+	if wildcard {
+		components = components[:len(components)-1]
+	}
+	var parts []int
+	for _, s := range components {
+		part, err := strconv.Atoi(s)
+		if err != nil || part < 0 {
+			return nil, false, fmt.Errorf("invalid version %q", version)
+		}
+		parts = append(parts, part)
+	}
+	return parts, wildcard, nil
+}
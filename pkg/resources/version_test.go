@@ -0,0 +1,86 @@
+package resources
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		name    string
+		a       string
+		b       string
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "test older patch version",
+			a:    "3.2.6",
+			b:    "3.2.10",
+			want: -1,
+		},
+		{
+			name: "test newer major version",
+			a:    "5.0.6",
+			b:    "4.0.10",
+			want: 1,
+		},
+		{
+			name: "test missing components are treated as 0",
+			a:    "5",
+			b:    "5.0",
+			want: 0,
+		},
+		{
+			name: "test wildcard version matches versions of its family",
+			a:    "6.x",
+			b:    "6.0.5",
+			want: 0,
+		},
+		{
+			name: "test wildcard version is newer than an older major version",
+			a:    "6.x",
+			b:    "5.0.6",
+			want: 1,
+		},
+		{
+			name: "test older version than a wildcard version",
+			a:    "5.0.6",
+			b:    "6.x",
+			want: -1,
+		},
+		{
+			name: "test wildcard versions of the same family are equal",
+			a:    "6.x",
+			b:    "6.x",
+			want: 0,
+		},
+		{
+			name:    "test invalid version",
+			a:       "5.y",
+			b:       "5.0",
+			wantErr: true,
+		},
+		{
+			name:    "test wildcard without a major version is invalid",
+			a:       "x",
+			b:       "5.0",
+			wantErr: true,
+		},
+		{
+			name:    "test wildcard which is not the last component is invalid",
+			a:       "6.x.1",
+			b:       "6.0",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CompareVersions(tt.a, tt.b)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CompareVersions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("CompareVersions() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}